@@ -12,6 +12,9 @@ import (
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/gitlab"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/aggregator"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/importer"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/metrics"
 	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
 )
 
@@ -24,6 +27,9 @@ var (
 	syncMRs    = flag.Bool("mrs", true, "Sync open merge requests")
 	dryRun     = flag.Bool("dry-run", false, "Dry run mode (don't write to database)")
 	maxMRs     = flag.Int("max-mrs", 100, "Maximum number of MRs to sync per project")
+
+	importHistory = flag.Bool("history", false, "Import merged MR history and backfill metrics (resumable, safe to re-run)")
+	historyDays   = flag.Int("history-days", 90, "Days of merged MR history to import")
 )
 
 func main() {
@@ -115,9 +121,81 @@ func main() {
 		}
 	}
 
+	// Import merged MR history and backfill metrics
+	if *importHistory {
+		if *dryRun {
+			log.Warn().Msg("History import is not supported in dry run mode. Skipping.")
+		} else {
+			log.Info().Int("days", *historyDays).Msg("📥 Importing merged MR history...")
+			if err := importMRHistory(ctx, cfg, db, gitlabClient, reviewRepo, userRepo, resolvedGroupID, *projectID, *historyDays); err != nil {
+				log.Error().Err(err).Msg("Failed to import MR history")
+			}
+		}
+	}
+
 	log.Info().Msg("✅ Initialization complete!")
 }
 
+// importMRHistory imports merged MRs from a project or all projects of a group and backfills metrics.
+// The start date is aligned to midnight UTC so re-runs on the same day resume from the saved checkpoint.
+func importMRHistory(ctx context.Context, cfg *config.Config, db *repository.DB, gitlabClient *gitlab.Client, reviewRepo *repository.ReviewRepository, userRepo *repository.UserRepository, groupID, projectID, days int) error {
+	log := logger.Get()
+
+	var projectIDs []int
+	switch {
+	case projectID > 0:
+		projectIDs = []int{projectID}
+	case groupID > 0:
+		projects, err := gitlabClient.GetGroupProjects(groupID)
+		if err != nil {
+			return fmt.Errorf("failed to get group projects: %w", err)
+		}
+		for _, project := range projects {
+			projectIDs = append(projectIDs, project.ID)
+		}
+	default:
+		return fmt.Errorf("no group or project specified for history import")
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -days)
+
+	zl := log.GetLogger()
+	aggregatorService := aggregator.NewService(reviewRepo, repository.NewMetricsRepository(db), &zl)
+	aggregatorService.SetExcludedUsers(&cfg.ExcludedUsers)
+	aggregatorService.SetMinReviewComments(cfg.Metrics.MinReviewComments)
+	aggregatorService.SetHourly(cfg.Metrics.HourlyAggregation)
+	aggregatorService.SetEngagementCalculator(metrics.NewEngagementCalculator(cfg.Metrics.Engagement))
+	weekendLocation, err := cfg.Scheduler.GetLocation()
+	if err != nil {
+		return fmt.Errorf("invalid scheduler timezone %q: %w", cfg.Scheduler.Timezone, err)
+	}
+	aggregatorService.SetReviewClock(metrics.NewReviewClock(cfg.Metrics.ExcludeWeekends, weekendLocation))
+	importerService := importer.NewService(
+		gitlabClient,
+		reviewRepo,
+		userRepo,
+		repository.NewConfigurationRepository(db),
+		aggregatorService,
+		log,
+	)
+
+	result, err := importerService.Import(ctx, projectIDs, since)
+	if err != nil {
+		return err
+	}
+
+	log.Info().
+		Int("projects", len(projectIDs)).
+		Int("imported", result.Imported).
+		Int("skipped", result.Skipped).
+		Int("failed", result.Failed).
+		Time("since", since).
+		Msg("MR history imported")
+
+	return nil
+}
+
 // syncUsersFromGroup syncs all members of a GitLab group
 func syncUsersFromGroup(ctx context.Context, gitlabClient *gitlab.Client, userRepo *repository.UserRepository, groupID int, dryRun bool) error {
 	log := logger.Get()
@@ -153,13 +231,14 @@ func syncUsersFromGroup(ctx context.Context, gitlabClient *gitlab.Client, userRe
 
 		// Create new user
 		user := &models.User{
-			GitLabID:  member.ID,
-			Username:  member.Username,
-			Email:     member.Email,
-			Role:      detectRole(member), // Try to detect role from user info
-			Team:      "",                 // Will be updated when assigned to team
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
+			GitLabID:    member.ID,
+			Username:    member.Username,
+			DisplayName: member.Name,
+			Email:       member.Email,
+			Role:        detectRole(member), // Try to detect role from user info
+			Team:        "",                 // Will be updated when assigned to team
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
 		}
 
 		if err := userRepo.CreateOrUpdate(user); err != nil {
@@ -212,13 +291,14 @@ func syncUsersFromProject(ctx context.Context, gitlabClient *gitlab.Client, user
 		}
 
 		user := &models.User{
-			GitLabID:  member.ID,
-			Username:  member.Username,
-			Email:     member.Email,
-			Role:      detectRole(member),
-			Team:      "",
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
+			GitLabID:    member.ID,
+			Username:    member.Username,
+			DisplayName: member.Name,
+			Email:       member.Email,
+			Role:        detectRole(member),
+			Team:        "",
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
 		}
 
 		if err := userRepo.CreateOrUpdate(user); err != nil {
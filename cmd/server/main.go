@@ -14,8 +14,10 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/api/admin"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/api/dashboard"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/api/health"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/api/middleware"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/api/webhook"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/cache"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
@@ -25,16 +27,18 @@ import (
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/badges"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/gauges"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/leaderboard"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/metrics"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/roulette"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/scheduler"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/webhookqueue"
 	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
 )
 
 func main() {
 	// Load configuration
-	cfg, err := config.Load("config.yaml")
+	cfg, err := config.LoadWatched("config.yaml")
 	if err != nil {
 		fmt.Printf("Failed to load config: %v\n", err)
 		os.Exit(1)
@@ -73,7 +77,7 @@ func main() {
 	}
 
 	// Initialize Mattermost client
-	mattermostClient := mattermost.NewClient(&cfg.Mattermost, log)
+	mattermostClient := mattermost.NewClient(&cfg.Mattermost, &http.Client{Timeout: 10 * time.Second}, log)
 
 	// Initialize translator for i18n
 	translator, err := i18n.New(cfg.Server.Language)
@@ -85,6 +89,7 @@ func main() {
 	log.Info().
 		Str("language", translator.Lang()).
 		Msg("Translator initialized")
+	mattermostClient.SetTranslator(translator)
 
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db)
@@ -92,6 +97,20 @@ func main() {
 	reviewRepo := repository.NewReviewRepository(db)
 	metricsRepo := repository.NewMetricsRepository(db)
 	badgeRepo := repository.NewBadgeRepository(db)
+	projectRepo := repository.NewProjectRepository(db)
+
+	// Suppress identical consecutive Mattermost messages, e.g. a manual run right after the cron
+	if cfg.Mattermost.Dedup.Enabled {
+		mattermostClient.SetDeduplication(redisCache, time.Duration(cfg.Mattermost.Dedup.Window)*time.Second)
+	}
+
+	// Persist failed Mattermost deliveries and retry them in the background
+	if cfg.Mattermost.RetryQueue.Enabled {
+		retryQueue := webhookqueue.NewService(&cfg.Mattermost.RetryQueue, repository.NewWebhookDeliveryRepository(db), log)
+		mattermostClient.SetRetryQueue(retryQueue)
+		retryQueue.Start()
+		defer retryQueue.Stop()
+	}
 
 	// Sync users from config to database
 	if err := syncUsersFromConfig(cfg, userRepo, log); err != nil {
@@ -106,33 +125,62 @@ func main() {
 		oooRepo,
 		reviewRepo,
 		redisCache,
+		mattermostClient,
 		log,
 	)
 
 	metricsService := metrics.NewService(metricsRepo)
+	metricsService.SetEngagementCalculator(metrics.NewEngagementCalculator(cfg.Metrics.Engagement))
+	metricsService.SetExcludedUsers(&cfg.ExcludedUsers)
+	metricsService.SetMinReviewComments(cfg.Metrics.MinReviewComments)
+	weekendLocation, err := cfg.Scheduler.GetLocation()
+	if err != nil {
+		log.Fatal().Err(err).Str("timezone", cfg.Scheduler.Timezone).Msg("Invalid scheduler timezone")
+	}
+	metricsService.SetReviewClock(metrics.NewReviewClock(cfg.Metrics.ExcludeWeekends, weekendLocation))
 
 	badgeService := badges.NewService(
 		badgeRepo,
 		metricsRepo,
 		reviewRepo,
 		userRepo,
+		mattermostClient,
 		log,
 	)
+	badgeService.SetConcurrency(cfg.Scheduler.BadgeEvaluationConcurrency)
+	badgeService.SetBatchSize(cfg.Scheduler.BadgeEvaluationBatchSize)
+	badgeService.SetCalendarPeriods(cfg.Metrics.CalendarPeriods)
+	badgeService.SetAnnounceNewBadges(cfg.Mattermost.AnnounceNewBadges)
+	if err := badgeService.SyncFromConfig(context.Background(), cfg.Badges); err != nil {
+		log.Warn().Err(err).Msg("Failed to sync badges from config")
+	}
 
 	leaderboardService := leaderboard.NewService(
+		cfg,
 		metricsRepo,
 		badgeRepo,
 		userRepo,
+		repository.NewPersonalBestRepository(db),
 		log,
 	)
+	leaderboardService.SetProjectRepository(projectRepo)
+	leaderboardService.SetOOORepository(oooRepo)
+	leaderboardService.SetTeamMemberRepository(userRepo)
+	leaderboardService.SetAssignmentRepository(reviewRepo)
+	leaderboardService.SetReviewClock(metrics.NewReviewClock(cfg.Metrics.ExcludeWeekends, weekendLocation))
 
 	schedulerService := scheduler.NewService(
 		cfg,
 		reviewRepo,
 		badgeService,
+		leaderboardService,
 		mattermostClient,
 		log,
 	)
+	schedulerService.SetGitLabCommenter(gitlabClient, translator)
+	schedulerService.SetInactivityCheck(userRepo, metricsRepo, mattermostClient)
+	schedulerService.SetRetention(reviewRepo, metricsRepo)
+	schedulerService.SetThreadStore(repository.NewConfigurationRepository(db))
 
 	// Initialize handlers
 	webhookHandler := webhook.NewHandler(
@@ -143,6 +191,7 @@ func main() {
 		metricsService,
 		userRepo,
 		reviewRepo,
+		projectRepo,
 		translator,
 		log,
 	)
@@ -150,6 +199,15 @@ func main() {
 	healthHandler := health.NewHandler(db, redisCache, log)
 
 	dashboardHandler := dashboard.NewHandler(badgeService, leaderboardService, log)
+	dashboardHandler.SetReviewRepository(reviewRepo)
+	dashboardHandler.SetUserRepository(userRepo)
+	dashboardHandler.SetEngagementCalculator(metrics.NewEngagementCalculator(cfg.Metrics.Engagement))
+	dashboardHandler.SetConfig(cfg)
+	dashboardHandler.SetRequestTimeout(time.Duration(cfg.Server.RequestTimeout) * time.Second)
+
+	adminHandler := admin.NewHandler(schedulerService, badgeService, log)
+	adminHandler.SetOOORepository(oooRepo)
+	adminHandler.SetGaugeService(gauges.NewService(cfg, badgeRepo, reviewRepo, userRepo, oooRepo, log))
 
 	// Setup Gin router
 	if cfg.Server.Environment == "production" {
@@ -157,35 +215,84 @@ func main() {
 	}
 
 	router := gin.Default()
+	// Rate limiting keys on c.ClientIP(), so only forwarding headers from known proxies are honored
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatal().Err(err).Msg("Invalid server.trusted_proxies")
+	}
+	router.TrustedPlatform = cfg.Server.TrustedPlatform
+	router.Use(middleware.RequestMetrics())
 
 	// Health endpoints
 	router.GET("/health", healthHandler.HandleHealth)
 	router.GET("/readiness", healthHandler.HandleReadiness)
 	router.GET("/liveness", healthHandler.HandleLiveness)
+	router.GET("/healthz", healthHandler.HandleLiveness)
+	router.GET("/readyz", healthHandler.HandleReadiness)
 
 	// Webhook endpoint
 	router.POST("/webhook/gitlab", webhookHandler.HandleGitLabWebhook)
 
+	// HTTP response cache for heavy read endpoints (stale-while-revalidate)
+	responseCache := middleware.NewResponseCache(&cfg.ResponseCache, router, log)
+	cacheWarmer := middleware.NewCacheWarmer(responseCache, cfg, log)
+	schedulerService.SetCacheWarmer(cacheWarmer, cfg.ResponseCache.Warm.Schedule)
+
+	// Pick up team and badge changes from config.yaml without a restart
+	cfg.OnChange(func(reloaded *config.Config) {
+		if err := badgeService.SyncFromConfig(context.Background(), reloaded.Badges); err != nil {
+			log.Warn().Err(err).Msg("Failed to sync badges from reloaded config")
+		}
+		if err := syncUsersFromConfig(reloaded, userRepo, log); err != nil {
+			log.Warn().Err(err).Msg("Failed to sync users from reloaded config")
+		}
+		rouletteService.SetTeams(reloaded.Teams)
+		schedulerService.SetTeams(reloaded.Teams)
+		leaderboardService.SetTeams(reloaded.Teams)
+		dashboardHandler.SetTeams(reloaded.Teams)
+		cacheWarmer.SetTeams(reloaded.Teams)
+	})
+
 	// API v1 routes
 	v1 := router.Group("/api/v1")
+	rateLimiter := middleware.NewRateLimiter(cfg.Server.RateLimitRPS, cfg.Server.RateLimitBurst)
+	v1.Use(middleware.AdminIdentity(cfg.Server.AdminToken), rateLimiter.Middleware())
+	if cfg.Server.GzipEnabled {
+		// Compress outside the response cache so cached bodies stay uncompressed
+		v1.Use(middleware.Gzip(middleware.DefaultGzipMinSize))
+	}
+	v1.Use(responseCache.Middleware())
 	{
 		// Dashboard endpoints (read-only, no authentication required)
 		// These endpoints are safe for public access and provide statistics/leaderboards
 		v1.GET("/leaderboard", dashboardHandler.GetGlobalLeaderboard)
 		v1.GET("/leaderboard/:team", dashboardHandler.GetTeamLeaderboard)
+		v1.GET("/teams/compare", dashboardHandler.CompareTeams)
+		v1.GET("/projects/:id/leaderboard", dashboardHandler.GetProjectLeaderboard)
+		v1.GET("/stats/summary", dashboardHandler.GetStatsSummary)
+		v1.GET("/stats/by-role", dashboardHandler.GetStatsByRole)
 		v1.GET("/users/:id/stats", dashboardHandler.GetUserStats)
+		v1.GET("/users/:id/personal-bests", dashboardHandler.GetPersonalBests)
+		v1.GET("/users/:id/active-reviews", dashboardHandler.GetUserActiveReviews)
+		v1.GET("/users/:id/metrics", dashboardHandler.GetUserMetrics)
 		v1.GET("/users/:id/badges", dashboardHandler.GetUserBadges)
+		v1.GET("/users/:id/badges/progress", dashboardHandler.GetUserBadgeProgress)
+		v1.GET("/reviews/:projectId/:mrIid/engagement", dashboardHandler.GetReviewEngagement)
 		v1.GET("/badges", dashboardHandler.GetBadgeCatalog)
+		v1.GET("/badges/recent", dashboardHandler.GetRecentlyAwardedBadges)
 		v1.GET("/badges/:id", dashboardHandler.GetBadgeByID)
 		v1.GET("/badges/:id/holders", dashboardHandler.GetBadgeHolders)
 
-		// Admin endpoints (Phase 6 - Not yet implemented)
-		// TODO: Add OIDC authentication middleware before enabling these endpoints
-		// - POST   /api/v1/ooo                 - Create OOO status
-		// - DELETE /api/v1/ooo/:id             - Delete OOO status
-		// - POST   /api/v1/badges/:id/award    - Manually award badge
-		// - DELETE /api/v1/users/:id/badges/:badge_id - Revoke badge
-		// - PUT    /api/v1/users/:id           - Update user info
+		// Admin endpoints (require the X-Admin-Token header)
+		adminGroup := v1.Group("/admin", middleware.RequireAdmin())
+		adminGroup.POST("/jobs/daily-notifications", adminHandler.RunDailyNotifications)
+		adminGroup.POST("/jobs/badge-evaluation", adminHandler.RunBadgeEvaluation)
+		adminGroup.POST("/jobs/stale-mr-comments", adminHandler.RunStaleMRComments)
+		adminGroup.POST("/badges/simulate", adminHandler.SimulateBadge)
+		adminGroup.POST("/metrics/rebuild-gauges", adminHandler.RebuildGauges)
+		adminGroup.POST("/users/:id/badges/:badgeId", adminHandler.AwardBadge)
+		adminGroup.DELETE("/users/:id/badges/:badgeId", adminHandler.RevokeBadge)
+		v1.POST("/users/:id/ooo", middleware.RequireAdmin(), adminHandler.CreateOOO)
+		v1.DELETE("/ooo/:id", middleware.RequireAdmin(), adminHandler.DeleteOOO)
 
 		// Health check endpoint
 		v1.GET("/ping", func(c *gin.Context) {
@@ -280,18 +387,34 @@ func syncUsersFromConfig(cfg *config.Config, userRepo *repository.UserRepository
 	for _, team := range cfg.Teams {
 		for _, member := range team.Members {
 			// Check if user exists by username
-			_, err := userRepo.GetByUsername(member.Username)
+			existing, err := userRepo.GetByUsername(member.Username)
 			if err == nil {
-				// User exists, skip
+				// User exists, keep the configured team, role and display name in sync
+				changed := existing.Team != team.Name || existing.Role != member.Role
+				existing.Team = team.Name
+				existing.Role = member.Role
+				if member.DisplayName != "" && existing.DisplayName != member.DisplayName {
+					existing.DisplayName = member.DisplayName
+					changed = true
+				}
+				if changed {
+					if err := userRepo.Update(existing); err != nil {
+						log.Warn().
+							Str("username", member.Username).
+							Err(err).
+							Msg("Failed to update user")
+					}
+				}
 				continue
 			}
 
 			// User doesn't exist, create placeholder (will be updated when they interact with GitLab)
 			user := &models.User{
-				GitLabID: 0, // Will be updated later
-				Username: member.Username,
-				Role:     member.Role,
-				Team:     team.Name,
+				GitLabID:    0, // Will be updated later
+				Username:    member.Username,
+				DisplayName: member.DisplayName,
+				Role:        member.Role,
+				Team:        team.Name,
 			}
 
 			if err := userRepo.Create(user); err != nil {
@@ -0,0 +1,100 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/badges"
+)
+
+// AwardBadge grants a badge to a user regardless of its criteria. Awarding a badge
+// the user already holds succeeds without changes and reports already_awarded.
+// POST /api/v1/admin/users/:id/badges/:badgeId.
+func (h *Handler) AwardBadge(c *gin.Context) {
+	userID, badge, ok := h.userBadgeParams(c)
+	if !ok {
+		return
+	}
+
+	err := h.badgeService.AwardBadge(c.Request.Context(), userID, badge)
+	switch {
+	case errors.Is(err, badges.ErrBadgeAlreadyAwarded):
+		c.JSON(http.StatusOK, gin.H{
+			"user_id":         userID,
+			"badge":           badge,
+			"already_awarded": true,
+		})
+		return
+	case errors.Is(err, repository.ErrNotFound):
+		h.errorResponse(c, http.StatusNotFound, "User not found")
+		return
+	case err != nil:
+		h.log.Error().Err(err).Uint("user_id", userID).Uint("badge_id", badge.ID).Msg("Manual badge award failed")
+		h.errorResponse(c, http.StatusInternalServerError, "Failed to award badge")
+		return
+	}
+
+	h.log.Info().Uint("user_id", userID).Str("badge", badge.Name).Msg("Badge awarded manually")
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":         userID,
+		"badge":           badge,
+		"already_awarded": false,
+		"awarded_at":      time.Now().UTC(),
+	})
+}
+
+// RevokeBadge removes a badge from a user. Revoking a badge the user does not hold succeeds.
+// DELETE /api/v1/admin/users/:id/badges/:badgeId.
+func (h *Handler) RevokeBadge(c *gin.Context) {
+	userID, badge, ok := h.userBadgeParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.badgeService.RevokeBadge(c.Request.Context(), userID, badge.ID); err != nil {
+		h.log.Error().Err(err).Uint("user_id", userID).Uint("badge_id", badge.ID).Msg("Manual badge revoke failed")
+		h.errorResponse(c, http.StatusInternalServerError, "Failed to revoke badge")
+		return
+	}
+
+	h.log.Info().Uint("user_id", userID).Str("badge", badge.Name).Msg("Badge revoked manually")
+
+	c.Status(http.StatusNoContent)
+}
+
+// userBadgeParams validates the user and badge IDs of a manual award or revoke and
+// looks up the badge. On failure it writes the error response and returns false.
+func (h *Handler) userBadgeParams(c *gin.Context) (uint, *models.Badge, bool) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || userID == 0 {
+		h.errorResponse(c, http.StatusBadRequest, fmt.Sprintf("invalid user ID: %s", c.Param("id")))
+		return 0, nil, false
+	}
+
+	badgeID, err := strconv.ParseUint(c.Param("badgeId"), 10, 32)
+	if err != nil || badgeID == 0 {
+		h.errorResponse(c, http.StatusBadRequest, fmt.Sprintf("invalid badge ID: %s", c.Param("badgeId")))
+		return 0, nil, false
+	}
+
+	badge, err := h.badgeService.GetBadgeByID(c.Request.Context(), uint(badgeID))
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && badge == nil) {
+		h.errorResponse(c, http.StatusNotFound, "Badge not found")
+		return 0, nil, false
+	}
+	if err != nil {
+		h.log.Error().Err(err).Uint64("badge_id", badgeID).Msg("Failed to get badge")
+		h.errorResponse(c, http.StatusInternalServerError, "Failed to get badge")
+		return 0, nil, false
+	}
+
+	return uint(userID), badge, true
+}
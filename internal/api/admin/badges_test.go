@@ -0,0 +1,167 @@
+//nolint:noctx // Test file uses http.NewRequest for simplicity
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/api/middleware"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/badges"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+)
+
+// Mock Badge Service
+type mockBadgeService struct {
+	badges     map[uint]*models.Badge
+	users      map[uint]bool
+	userBadges map[uint]map[uint]bool // userID -> badgeID -> held
+	awards     int                    // New awards recorded
+}
+
+func newMockBadgeService() *mockBadgeService {
+	return &mockBadgeService{
+		badges:     map[uint]*models.Badge{1: {ID: 1, Name: "speed_demon"}},
+		users:      map[uint]bool{7: true},
+		userBadges: make(map[uint]map[uint]bool),
+	}
+}
+
+func (m *mockBadgeService) SimulateCriteria(_ *models.BadgeCriteria, _ badges.SimulationInput) (bool, error) {
+	return false, nil
+}
+
+func (m *mockBadgeService) GetBadgeByID(_ context.Context, badgeID uint) (*models.Badge, error) {
+	badge, ok := m.badges[badgeID]
+	if !ok {
+		return nil, fmt.Errorf("badge %d: %w", badgeID, repository.ErrNotFound)
+	}
+	return badge, nil
+}
+
+func (m *mockBadgeService) AwardBadge(_ context.Context, userID uint, badge *models.Badge) error {
+	if !m.users[userID] {
+		return fmt.Errorf("user %d: %w", userID, repository.ErrNotFound)
+	}
+	if m.userBadges[userID][badge.ID] {
+		return badges.ErrBadgeAlreadyAwarded
+	}
+	if m.userBadges[userID] == nil {
+		m.userBadges[userID] = make(map[uint]bool)
+	}
+	m.userBadges[userID][badge.ID] = true
+	m.awards++
+	return nil
+}
+
+func (m *mockBadgeService) RevokeBadge(_ context.Context, userID, badgeID uint) error {
+	delete(m.userBadges[userID], badgeID)
+	return nil
+}
+
+func setupBadgeRouter(badgeService BadgeService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	handler := NewHandlerWithInterfaces(&mockSchedulerService{}, badgeService, logger.New("error", "text", "stdout"))
+
+	api := router.Group("/api/v1")
+	api.Use(middleware.AdminIdentity(testAdminToken))
+	admin := api.Group("/admin", middleware.RequireAdmin())
+	admin.POST("/users/:id/badges/:badgeId", handler.AwardBadge)
+	admin.DELETE("/users/:id/badges/:badgeId", handler.RevokeBadge)
+
+	return router
+}
+
+func badgeRequest(method, path string) *http.Request {
+	req, _ := http.NewRequest(method, path, http.NoBody)
+	req.Header.Set(middleware.AdminTokenHeader, testAdminToken)
+	return req
+}
+
+func TestAwardBadge_Success(t *testing.T) {
+	badgeService := newMockBadgeService()
+	router := setupBadgeRouter(badgeService)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, badgeRequest("POST", "/api/v1/admin/users/7/badges/1"))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, badgeService.userBadges[7][1])
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, float64(7), response["user_id"])
+	assert.Equal(t, "speed_demon", response["badge"].(map[string]interface{})["name"])
+}
+
+func TestAwardBadge_AlreadyAwarded(t *testing.T) {
+	badgeService := newMockBadgeService()
+	router := setupBadgeRouter(badgeService)
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, badgeRequest("POST", "/api/v1/admin/users/7/badges/1"))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, i == 1, response["already_awarded"])
+	}
+	assert.Equal(t, 1, badgeService.awards)
+}
+
+func TestAwardBadge_UnknownUser(t *testing.T) {
+	badgeService := newMockBadgeService()
+	router := setupBadgeRouter(badgeService)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, badgeRequest("POST", "/api/v1/admin/users/99/badges/1"))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "User not found")
+	assert.Empty(t, badgeService.userBadges[99])
+}
+
+func TestRevokeBadge_Success(t *testing.T) {
+	badgeService := newMockBadgeService()
+	badgeService.userBadges[7] = map[uint]bool{1: true}
+	router := setupBadgeRouter(badgeService)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, badgeRequest("DELETE", "/api/v1/admin/users/7/badges/1"))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.False(t, badgeService.userBadges[7][1])
+}
+
+func TestAwardBadge_UnknownBadge(t *testing.T) {
+	badgeService := newMockBadgeService()
+	router := setupBadgeRouter(badgeService)
+
+	for _, method := range []string{"POST", "DELETE"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, badgeRequest(method, "/api/v1/admin/users/7/badges/99"))
+		assert.Equal(t, http.StatusNotFound, w.Code, method)
+	}
+	assert.Empty(t, badgeService.userBadges[7])
+}
+
+func TestAwardBadge_InvalidIDs(t *testing.T) {
+	router := setupBadgeRouter(newMockBadgeService())
+
+	for _, path := range []string{"/api/v1/admin/users/abc/badges/1", "/api/v1/admin/users/7/badges/0"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, badgeRequest("POST", path))
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+	}
+}
@@ -0,0 +1,44 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/gauges"
+)
+
+// GaugeService interface for rebuilding Prometheus gauges.
+type GaugeService interface {
+	Rebuild(ctx context.Context) (*gauges.Result, error)
+}
+
+// SetGaugeService enables the gauge rebuild endpoint.
+func (h *Handler) SetGaugeService(service GaugeService) {
+	h.gaugeService = service
+}
+
+// RebuildGauges recomputes the active review, available reviewer and badge holder
+// gauges from the database, e.g. after a restart reset them to zero.
+// POST /api/v1/admin/metrics/rebuild-gauges.
+func (h *Handler) RebuildGauges(c *gin.Context) {
+	if h.gaugeService == nil {
+		h.errorResponse(c, http.StatusServiceUnavailable, "Gauge rebuild is not available")
+		return
+	}
+
+	result, err := h.gaugeService.Rebuild(c.Request.Context())
+	if err != nil {
+		h.log.Error().Err(err).Msg("Gauge rebuild failed")
+		h.errorResponse(c, http.StatusInternalServerError, "Failed to rebuild gauges")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":       "completed",
+		"gauges":       result,
+		"completed_at": time.Now().UTC(),
+	})
+}
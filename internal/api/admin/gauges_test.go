@@ -0,0 +1,94 @@
+//nolint:noctx // Test file uses http.NewRequest for simplicity
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/api/middleware"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/gauges"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+)
+
+// Mock Gauge Service
+type mockGaugeService struct {
+	result *gauges.Result
+	err    error
+	runs   int
+}
+
+func (m *mockGaugeService) Rebuild(_ context.Context) (*gauges.Result, error) {
+	m.runs++
+	return m.result, m.err
+}
+
+func setupGaugesRouter(gaugeService GaugeService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	handler := NewHandlerWithInterfaces(&mockSchedulerService{}, nil, logger.New("error", "text", "stdout"))
+	if gaugeService != nil {
+		handler.SetGaugeService(gaugeService)
+	}
+
+	api := router.Group("/api/v1")
+	api.Use(middleware.AdminIdentity(testAdminToken))
+	admin := api.Group("/admin", middleware.RequireAdmin())
+	admin.POST("/metrics/rebuild-gauges", handler.RebuildGauges)
+
+	return router
+}
+
+func TestRebuildGauges_RequiresAdmin(t *testing.T) {
+	gaugeService := &mockGaugeService{result: &gauges.Result{}}
+	router := setupGaugesRouter(gaugeService)
+
+	for _, token := range []string{"", "wrong-token"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, adminRequest("/api/v1/admin/metrics/rebuild-gauges", token))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	}
+	assert.Equal(t, 0, gaugeService.runs)
+}
+
+func TestRebuildGauges_Success(t *testing.T) {
+	gaugeService := &mockGaugeService{result: &gauges.Result{BadgeHolders: 5, ActiveReviews: 3, AvailableReviewers: 2}}
+	router := setupGaugesRouter(gaugeService)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, adminRequest("/api/v1/admin/metrics/rebuild-gauges", testAdminToken))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, gaugeService.runs)
+
+	var response struct {
+		Gauges gauges.Result `json:"gauges"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, *gaugeService.result, response.Gauges)
+}
+
+func TestRebuildGauges_Error(t *testing.T) {
+	router := setupGaugesRouter(&mockGaugeService{err: errors.New("database unavailable")})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, adminRequest("/api/v1/admin/metrics/rebuild-gauges", testAdminToken))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestRebuildGauges_NotConfigured(t *testing.T) {
+	router := setupGaugesRouter(nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, adminRequest("/api/v1/admin/metrics/rebuild-gauges", testAdminToken))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,148 @@
+// Package admin provides REST API handlers for operator actions.
+// All routes must be mounted behind middleware.RequireAdmin.
+package admin
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/badges"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/scheduler"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+)
+
+// SchedulerService interface for on-demand job runs.
+type SchedulerService interface {
+	RunDailyNotificationsNow(ctx context.Context) error
+	RunBadgeEvaluationNow(ctx context.Context) (int, error)
+	RunStaleMRCommentsNow(ctx context.Context) (int, error)
+}
+
+// BadgeService interface for badge criteria simulation and manual awards.
+type BadgeService interface {
+	SimulateCriteria(criteria *models.BadgeCriteria, input badges.SimulationInput) (bool, error)
+	GetBadgeByID(ctx context.Context, badgeID uint) (*models.Badge, error)
+	AwardBadge(ctx context.Context, userID uint, badge *models.Badge) error
+	RevokeBadge(ctx context.Context, userID, badgeID uint) error
+}
+
+// Handler handles admin API requests.
+type Handler struct {
+	scheduler    SchedulerService
+	badgeService BadgeService
+	oooRepo      OOORepository
+	gaugeService GaugeService
+	log          *logger.Logger
+}
+
+// NewHandler creates a new admin handler.
+func NewHandler(schedulerService *scheduler.Service, badgeService *badges.Service, log *logger.Logger) *Handler {
+	return &Handler{
+		scheduler:    schedulerService,
+		badgeService: badgeService,
+		log:          log,
+	}
+}
+
+// NewHandlerWithInterfaces creates a new admin handler with interface dependencies (useful for testing).
+func NewHandlerWithInterfaces(schedulerService SchedulerService, badgeService BadgeService, log *logger.Logger) *Handler {
+	return &Handler{
+		scheduler:    schedulerService,
+		badgeService: badgeService,
+		log:          log,
+	}
+}
+
+// simulateBadgeRequest is the body of a badge criteria simulation.
+type simulateBadgeRequest struct {
+	Criteria *models.BadgeCriteria `json:"criteria" binding:"required"`
+	badges.SimulationInput
+}
+
+// RunDailyNotifications sends the daily review reminders immediately.
+// POST /api/v1/admin/jobs/daily-notifications.
+func (h *Handler) RunDailyNotifications(c *gin.Context) {
+	if err := h.scheduler.RunDailyNotificationsNow(c.Request.Context()); err != nil {
+		h.log.Error().Err(err).Msg("Manual daily notification run failed")
+		h.errorResponse(c, http.StatusInternalServerError, "Failed to send daily notifications")
+		return
+	}
+
+	h.log.Info().Msg("Manual daily notification run completed")
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":       "completed",
+		"completed_at": time.Now().UTC(),
+	})
+}
+
+// RunBadgeEvaluation evaluates badges for all users immediately.
+// POST /api/v1/admin/jobs/badge-evaluation.
+func (h *Handler) RunBadgeEvaluation(c *gin.Context) {
+	awarded, err := h.scheduler.RunBadgeEvaluationNow(c.Request.Context())
+	if err != nil {
+		h.log.Error().Err(err).Msg("Manual badge evaluation failed")
+		h.errorResponse(c, http.StatusInternalServerError, "Failed to evaluate badges")
+		return
+	}
+
+	h.log.Info().Int("badges_awarded", awarded).Msg("Manual badge evaluation completed")
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":         "completed",
+		"badges_awarded": awarded,
+		"completed_at":   time.Now().UTC(),
+	})
+}
+
+// RunStaleMRComments comments on MRs awaiting review for too long immediately.
+// POST /api/v1/admin/jobs/stale-mr-comments.
+func (h *Handler) RunStaleMRComments(c *gin.Context) {
+	commented, err := h.scheduler.RunStaleMRCommentsNow(c.Request.Context())
+	if err != nil {
+		h.log.Error().Err(err).Msg("Manual stale MR comment run failed")
+		h.errorResponse(c, http.StatusInternalServerError, "Failed to comment on stale MRs")
+		return
+	}
+
+	h.log.Info().Int("commented", commented).Msg("Manual stale MR comment run completed")
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":       "completed",
+		"commented":    commented,
+		"completed_at": time.Now().UTC(),
+	})
+}
+
+// SimulateBadge evaluates badge criteria against hypothetical metric values.
+// POST /api/v1/admin/badges/simulate.
+func (h *Handler) SimulateBadge(c *gin.Context) {
+	var req simulateBadgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.errorResponse(c, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+
+	passed, err := h.badgeService.SimulateCriteria(req.Criteria, req.SimulationInput)
+	if err != nil {
+		h.errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"criteria": req.Criteria,
+		"passed":   passed,
+	})
+}
+
+// errorResponse sends an error response.
+func (h *Handler) errorResponse(c *gin.Context, statusCode int, message string) {
+	c.JSON(statusCode, gin.H{
+		"error":     message,
+		"timestamp": time.Now().UTC(),
+	})
+}
@@ -0,0 +1,193 @@
+//nolint:noctx // Test file uses http.NewRequest for simplicity
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/api/middleware"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/badges"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+)
+
+const testAdminToken = "secret-admin-token"
+
+// Mock Scheduler Service
+type mockSchedulerService struct {
+	notificationsErr error
+	notificationRuns int
+	badgesAwarded    int
+	badgesErr        error
+	staleCommented   int
+	staleErr         error
+}
+
+func (m *mockSchedulerService) RunDailyNotificationsNow(_ context.Context) error {
+	m.notificationRuns++
+	return m.notificationsErr
+}
+
+func (m *mockSchedulerService) RunBadgeEvaluationNow(_ context.Context) (int, error) {
+	return m.badgesAwarded, m.badgesErr
+}
+
+func (m *mockSchedulerService) RunStaleMRCommentsNow(_ context.Context) (int, error) {
+	return m.staleCommented, m.staleErr
+}
+
+func setupRouter(schedulerService SchedulerService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	log := logger.New("error", "text", "stdout")
+	badgeService := badges.NewServiceWithInterfaces(nil, nil, nil, nil, log)
+	handler := NewHandlerWithInterfaces(schedulerService, badgeService, log)
+
+	api := router.Group("/api/v1")
+	api.Use(middleware.AdminIdentity(testAdminToken))
+	admin := api.Group("/admin", middleware.RequireAdmin())
+	admin.POST("/jobs/daily-notifications", handler.RunDailyNotifications)
+	admin.POST("/jobs/badge-evaluation", handler.RunBadgeEvaluation)
+	admin.POST("/jobs/stale-mr-comments", handler.RunStaleMRComments)
+	admin.POST("/badges/simulate", handler.SimulateBadge)
+
+	return router
+}
+
+func adminRequest(path, token string) *http.Request {
+	req, _ := http.NewRequest("POST", path, http.NoBody)
+	if token != "" {
+		req.Header.Set(middleware.AdminTokenHeader, token)
+	}
+	return req
+}
+
+func TestRunDailyNotifications_RequiresAdmin(t *testing.T) {
+	schedulerService := &mockSchedulerService{}
+	router := setupRouter(schedulerService)
+
+	for _, token := range []string{"", "wrong-token"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, adminRequest("/api/v1/admin/jobs/daily-notifications", token))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	}
+	assert.Equal(t, 0, schedulerService.notificationRuns)
+}
+
+func TestRunDailyNotifications_Success(t *testing.T) {
+	schedulerService := &mockSchedulerService{}
+	router := setupRouter(schedulerService)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, adminRequest("/api/v1/admin/jobs/daily-notifications", testAdminToken))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, schedulerService.notificationRuns)
+}
+
+func TestRunDailyNotifications_Error(t *testing.T) {
+	router := setupRouter(&mockSchedulerService{notificationsErr: errors.New("mattermost unavailable")})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, adminRequest("/api/v1/admin/jobs/daily-notifications", testAdminToken))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestRunBadgeEvaluation(t *testing.T) {
+	router := setupRouter(&mockSchedulerService{badgesAwarded: 4})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, adminRequest("/api/v1/admin/jobs/badge-evaluation", testAdminToken))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, float64(4), response["badges_awarded"])
+
+	router = setupRouter(&mockSchedulerService{badgesErr: errors.New("evaluation failed")})
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, adminRequest("/api/v1/admin/jobs/badge-evaluation", testAdminToken))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestRunStaleMRComments(t *testing.T) {
+	router := setupRouter(&mockSchedulerService{staleCommented: 2})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, adminRequest("/api/v1/admin/jobs/stale-mr-comments", testAdminToken))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, float64(2), response["commented"])
+
+	router = setupRouter(&mockSchedulerService{staleErr: errors.New("gitlab unavailable")})
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, adminRequest("/api/v1/admin/jobs/stale-mr-comments", testAdminToken))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestSimulateBadge(t *testing.T) {
+	router := setupRouter(&mockSchedulerService{})
+
+	tests := []struct {
+		name       string
+		value      float64
+		wantPassed bool
+	}{
+		{"above threshold", 85, true},
+		{"at threshold", 80, true},
+		{"below threshold", 75, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(map[string]interface{}{
+				"criteria": map[string]interface{}{"metric": "engagement_score", "operator": ">=", "value": 80},
+				"metrics":  map[string]float64{"engagement_score": tt.value},
+			})
+			req, _ := http.NewRequest("POST", "/api/v1/admin/badges/simulate", strings.NewReader(string(body)))
+			req.Header.Set(middleware.AdminTokenHeader, testAdminToken)
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantPassed, response["passed"])
+		})
+	}
+}
+
+func TestSimulateBadge_InvalidRequest(t *testing.T) {
+	router := setupRouter(&mockSchedulerService{})
+
+	for _, body := range []string{
+		`{"metrics": {"engagement_score": 90}}`,
+		`{"criteria": {"metric": "engagement_score", "operator": "!=", "value": 80}, "metrics": {"engagement_score": 90}}`,
+	} {
+		req, _ := http.NewRequest("POST", "/api/v1/admin/badges/simulate", strings.NewReader(body))
+		req.Header.Set(middleware.AdminTokenHeader, testAdminToken)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	}
+}
@@ -0,0 +1,159 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
+)
+
+// dateLayout is the date-only format accepted for OOO start and end dates.
+const dateLayout = "2006-01-02"
+
+// OOORepository interface for out-of-office management.
+type OOORepository interface {
+	GetAllOOOForUser(userID uint) ([]models.OOOStatus, error)
+	CreateOOO(status *models.OOOStatus) error
+	DeleteOOO(id uint) error
+}
+
+// SetOOORepository enables the OOO management endpoints.
+func (h *Handler) SetOOORepository(repo OOORepository) {
+	h.oooRepo = repo
+}
+
+// createOOORequest is the body of an OOO creation. Dates are YYYY-MM-DD or RFC 3339;
+// a date-only end_date covers that whole day.
+type createOOORequest struct {
+	StartDate string `json:"start_date" binding:"required"`
+	EndDate   string `json:"end_date" binding:"required"`
+	Reason    string `json:"reason"`
+}
+
+// CreateOOO records an out-of-office period for a user.
+// POST /api/v1/users/:id/ooo.
+func (h *Handler) CreateOOO(c *gin.Context) {
+	if h.oooRepo == nil {
+		h.errorResponse(c, http.StatusServiceUnavailable, "OOO management is not available")
+		return
+	}
+
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || userID == 0 {
+		h.errorResponse(c, http.StatusBadRequest, fmt.Sprintf("invalid user ID: %s", c.Param("id")))
+		return
+	}
+
+	var req createOOORequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.errorResponse(c, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+
+	startDate, err := parseOOODate(req.StartDate, false)
+	if err != nil {
+		h.errorResponse(c, http.StatusBadRequest, "invalid start_date: "+err.Error())
+		return
+	}
+	endDate, err := parseOOODate(req.EndDate, true)
+	if err != nil {
+		h.errorResponse(c, http.StatusBadRequest, "invalid end_date: "+err.Error())
+		return
+	}
+	if !endDate.After(startDate) {
+		h.errorResponse(c, http.StatusBadRequest, "end_date must be after start_date")
+		return
+	}
+
+	existing, err := h.oooRepo.GetAllOOOForUser(uint(userID))
+	if err != nil {
+		h.log.Error().Err(err).Uint64("user_id", userID).Msg("Failed to get OOO entries")
+		h.errorResponse(c, http.StatusInternalServerError, "Failed to create OOO entry")
+		return
+	}
+	now := time.Now()
+	for _, entry := range existing {
+		// Past entries can't conflict; current and upcoming ones must not overlap
+		if entry.EndDate.Before(now) {
+			continue
+		}
+		if startDate.Before(entry.EndDate) && entry.StartDate.Before(endDate) {
+			h.errorResponse(c, http.StatusConflict, fmt.Sprintf("overlaps existing OOO entry %d", entry.ID))
+			return
+		}
+	}
+
+	status := &models.OOOStatus{
+		UserID:    uint(userID),
+		StartDate: startDate,
+		EndDate:   endDate,
+		Reason:    req.Reason,
+	}
+	if err := h.oooRepo.CreateOOO(status); err != nil {
+		h.log.Error().Err(err).Uint64("user_id", userID).Msg("Failed to create OOO entry")
+		h.errorResponse(c, http.StatusInternalServerError, "Failed to create OOO entry")
+		return
+	}
+
+	h.log.Info().
+		Uint("ooo_id", status.ID).
+		Uint64("user_id", userID).
+		Time("start_date", startDate).
+		Time("end_date", endDate).
+		Msg("Created OOO entry")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"ooo": status,
+	})
+}
+
+// DeleteOOO removes an out-of-office entry.
+// DELETE /api/v1/ooo/:id.
+func (h *Handler) DeleteOOO(c *gin.Context) {
+	if h.oooRepo == nil {
+		h.errorResponse(c, http.StatusServiceUnavailable, "OOO management is not available")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		h.errorResponse(c, http.StatusBadRequest, fmt.Sprintf("invalid OOO ID: %s", c.Param("id")))
+		return
+	}
+
+	if err := h.oooRepo.DeleteOOO(uint(id)); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			h.errorResponse(c, http.StatusNotFound, "OOO entry not found")
+			return
+		}
+		h.log.Error().Err(err).Uint64("ooo_id", id).Msg("Failed to delete OOO entry")
+		h.errorResponse(c, http.StatusInternalServerError, "Failed to delete OOO entry")
+		return
+	}
+
+	h.log.Info().Uint64("ooo_id", id).Msg("Deleted OOO entry")
+
+	c.Status(http.StatusNoContent)
+}
+
+// parseOOODate parses a YYYY-MM-DD or RFC 3339 date. A date-only end date
+// is moved to the end of that day so single-day entries are valid.
+func parseOOODate(value string, isEnd bool) (time.Time, error) {
+	if t, err := time.Parse(dateLayout, value); err == nil {
+		if isEnd {
+			return t.Add(24*time.Hour - time.Second), nil
+		}
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected YYYY-MM-DD or RFC 3339, got %q", value)
+	}
+	return t, nil
+}
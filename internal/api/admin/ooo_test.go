@@ -0,0 +1,144 @@
+//nolint:noctx // Test file uses http.NewRequest for simplicity
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/api/middleware"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+)
+
+// Mock OOO Repository
+type mockOOORepository struct {
+	entries map[uint]*models.OOOStatus
+	nextID  uint
+}
+
+func newMockOOORepository() *mockOOORepository {
+	return &mockOOORepository{entries: make(map[uint]*models.OOOStatus)}
+}
+
+func (m *mockOOORepository) GetAllOOOForUser(userID uint) ([]models.OOOStatus, error) {
+	var statuses []models.OOOStatus
+	for _, entry := range m.entries {
+		if entry.UserID == userID {
+			statuses = append(statuses, *entry)
+		}
+	}
+	return statuses, nil
+}
+
+func (m *mockOOORepository) CreateOOO(status *models.OOOStatus) error {
+	m.nextID++
+	status.ID = m.nextID
+	m.entries[status.ID] = status
+	return nil
+}
+
+func (m *mockOOORepository) DeleteOOO(id uint) error {
+	if _, ok := m.entries[id]; !ok {
+		return fmt.Errorf("OOO entry %d: %w", id, repository.ErrNotFound)
+	}
+	delete(m.entries, id)
+	return nil
+}
+
+func setupOOORouter(oooRepo OOORepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	handler := NewHandlerWithInterfaces(&mockSchedulerService{}, nil, logger.New("error", "text", "stdout"))
+	handler.SetOOORepository(oooRepo)
+
+	api := router.Group("/api/v1")
+	api.Use(middleware.AdminIdentity(testAdminToken))
+	api.POST("/users/:id/ooo", middleware.RequireAdmin(), handler.CreateOOO)
+	api.DELETE("/ooo/:id", middleware.RequireAdmin(), handler.DeleteOOO)
+
+	return router
+}
+
+func oooRequest(method, path, body string) *http.Request {
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middleware.AdminTokenHeader, testAdminToken)
+	return req
+}
+
+func TestCreateOOO_Success(t *testing.T) {
+	oooRepo := newMockOOORepository()
+	router := setupOOORouter(oooRepo)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, oooRequest("POST", "/api/v1/users/7/ooo",
+		`{"start_date": "2030-01-10", "end_date": "2030-01-12", "reason": "vacation"}`))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response struct {
+		OOO models.OOOStatus `json:"ooo"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, uint(1), response.OOO.ID)
+	assert.Equal(t, uint(7), response.OOO.UserID)
+	assert.Equal(t, "vacation", response.OOO.Reason)
+	assert.Equal(t, time.Date(2030, 1, 12, 23, 59, 59, 0, time.UTC), response.OOO.EndDate)
+	assert.Len(t, oooRepo.entries, 1)
+}
+
+func TestCreateOOO_EndBeforeStart(t *testing.T) {
+	oooRepo := newMockOOORepository()
+	router := setupOOORouter(oooRepo)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, oooRequest("POST", "/api/v1/users/7/ooo",
+		`{"start_date": "2030-01-12T09:00:00Z", "end_date": "2030-01-10T18:00:00Z"}`))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "end_date must be after start_date")
+	assert.Empty(t, oooRepo.entries)
+}
+
+func TestCreateOOO_RejectsOverlap(t *testing.T) {
+	oooRepo := newMockOOORepository()
+	router := setupOOORouter(oooRepo)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, oooRequest("POST", "/api/v1/users/7/ooo", `{"start_date": "2030-01-10", "end_date": "2030-01-12"}`))
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, oooRequest("POST", "/api/v1/users/7/ooo", `{"start_date": "2030-01-12", "end_date": "2030-01-15"}`))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// Another user's entries don't conflict
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, oooRequest("POST", "/api/v1/users/8/ooo", `{"start_date": "2030-01-12", "end_date": "2030-01-15"}`))
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestDeleteOOO(t *testing.T) {
+	oooRepo := newMockOOORepository()
+	router := setupOOORouter(oooRepo)
+	_ = oooRepo.CreateOOO(&models.OOOStatus{UserID: 7})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, oooRequest("DELETE", "/api/v1/ooo/1", ""))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, oooRepo.entries)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, oooRequest("DELETE", "/api/v1/ooo/99", ""))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,113 @@
+package dashboard
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
+)
+
+// ReviewRepository interface for review and reviewer assignment lookups.
+type ReviewRepository interface {
+	GetActiveAssignmentsByUserID(userID uint) ([]models.ReviewerAssignment, error)
+	GetMRReview(projectID, mrIID int) (*models.MRReview, error)
+}
+
+// UserRepository interface for checking that a user exists.
+type UserRepository interface {
+	GetByID(id uint) (*models.User, error)
+}
+
+// SetReviewRepository enables the active review queue and review engagement endpoints.
+func (h *Handler) SetReviewRepository(repo ReviewRepository) {
+	h.reviewRepo = repo
+}
+
+// SetUserRepository makes the active review queue answer 404 for unknown users
+// instead of an empty queue.
+func (h *Handler) SetUserRepository(repo UserRepository) {
+	h.userRepo = repo
+}
+
+// ActiveReview is an assignment in a reviewer's current queue.
+type ActiveReview struct {
+	MRReviewID uint      `json:"mr_review_id"`
+	Title      string    `json:"title"`
+	URL        string    `json:"url"`
+	Status     string    `json:"status"`
+	Role       string    `json:"role"`
+	AssignedAt time.Time `json:"assigned_at"`
+	AgeHours   float64   `json:"age_hours"` // Time since assignment
+}
+
+// GetUserActiveReviews returns the reviews currently assigned to a user, oldest first.
+// GET /api/v1/users/:id/active-reviews?sort=status&order=asc.
+func (h *Handler) GetUserActiveReviews(c *gin.Context) {
+	userID, err := h.parseUserID(c)
+	if err != nil {
+		h.badRequest(c, err)
+		return
+	}
+
+	sortBy, order, err := h.parseSort(c, validActiveReviewSorts, defaultActiveReviewSort)
+	if err != nil {
+		h.badRequest(c, err)
+		return
+	}
+
+	if h.reviewRepo == nil {
+		h.errorResponse(c, http.StatusServiceUnavailable, CodeUnavailable, "Active reviews are not available")
+		return
+	}
+
+	if h.userRepo != nil {
+		if _, err := h.userRepo.GetByID(userID); errors.Is(err, repository.ErrNotFound) {
+			h.errorResponse(c, http.StatusNotFound, CodeNotFound, "User not found")
+			return
+		} else if err != nil {
+			h.log.Error().Err(err).Uint("user_id", userID).Msg("Failed to get user")
+			h.errorResponse(c, http.StatusInternalServerError, CodeInternal, "Failed to retrieve active reviews")
+			return
+		}
+	}
+
+	assignments, err := h.reviewRepo.GetActiveAssignmentsByUserID(userID)
+	if err != nil {
+		h.log.Error().Err(err).Uint("user_id", userID).Msg("Failed to get active reviews")
+		h.errorResponse(c, http.StatusInternalServerError, CodeInternal, "Failed to retrieve active reviews")
+		return
+	}
+
+	now := time.Now()
+	reviews := make([]ActiveReview, 0, len(assignments))
+	for _, assignment := range assignments {
+		reviews = append(reviews, ActiveReview{
+			MRReviewID: assignment.MRReviewID,
+			Title:      assignment.MRReview.MRTitle,
+			URL:        assignment.MRReview.MRURL,
+			Status:     assignment.MRReview.Status,
+			Role:       assignment.Role,
+			AssignedAt: assignment.AssignedAt,
+			AgeHours:   now.Sub(assignment.AssignedAt).Hours(),
+		})
+	}
+	sortActiveReviews(reviews, sortBy, order)
+
+	h.log.Info().
+		Uint("user_id", userID).
+		Int("count", len(reviews)).
+		Str("sort", sortBy).
+		Str("order", order).
+		Msg("Retrieved active reviews")
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":        userID,
+		"active_reviews": reviews,
+		"total":          len(reviews),
+		"generated_at":   now.UTC(),
+	})
+}
@@ -0,0 +1,49 @@
+package dashboard
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Response formats accepted by the format query parameter.
+const (
+	formatJSON = "json"
+	formatCSV  = "csv"
+)
+
+// leaderboardCSVHeader is the header row of CSV leaderboard exports.
+var leaderboardCSVHeader = []string{"rank", "username", "team", "completed_reviews", "avg_ttfr", "engagement_score", "badge_count"}
+
+// writeLeaderboardCSV streams leaderboard entries as CSV, in the same order as the JSON response.
+func writeLeaderboardCSV(c *gin.Context, filename string, entries []LeaderboardEntryResponse) error {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	if err := w.Write(leaderboardCSVHeader); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		record := []string{
+			strconv.Itoa(e.Rank),
+			e.Username,
+			e.Team,
+			strconv.Itoa(e.CompletedReviews),
+			strconv.FormatFloat(e.AvgTTFR, 'f', 2, 64),
+			strconv.FormatFloat(e.EngagementScore, 'f', 2, 64),
+			strconv.Itoa(e.BadgeCount),
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
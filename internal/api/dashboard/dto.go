@@ -0,0 +1,188 @@
+package dashboard
+
+import (
+	"time"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/leaderboard"
+)
+
+// UserResponse is the public representation of a user.
+// Email is only populated for admin requests that ask for it.
+type UserResponse struct {
+	ID          uint      `json:"id"`
+	GitLabID    int       `json:"gitlab_id"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name"`
+	Email       string    `json:"email,omitempty"`
+	Role        string    `json:"role"`
+	Team        string    `json:"team"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// UserBadgeResponse is the public representation of an earned badge.
+type UserBadgeResponse struct {
+	ID       uint          `json:"id"`
+	UserID   uint          `json:"user_id"`
+	User     *UserResponse `json:"user,omitempty"`
+	BadgeID  uint          `json:"badge_id"`
+	Badge    models.Badge  `json:"badge"`
+	EarnedAt time.Time     `json:"earned_at"`
+}
+
+// LeaderboardEntryResponse is a leaderboard entry with durations in the requested unit.
+type LeaderboardEntryResponse struct {
+	leaderboard.Entry
+	AvgTTFRHuman           string `json:"avg_ttfr_human,omitempty"` // Only with duration_unit=human
+	AvgTimeToApprovalHuman string `json:"avg_time_to_approval_human,omitempty"`
+}
+
+// UserStatsResponse is a user's statistics with durations in the requested unit.
+type UserStatsResponse struct {
+	leaderboard.UserStats
+	AvgTTFRHuman           string `json:"avg_ttfr_human,omitempty"` // Only with duration_unit=human
+	AvgTimeToApprovalHuman string `json:"avg_time_to_approval_human,omitempty"`
+}
+
+// RoleStatsResponse is a reviewer role's aggregate stats with durations in the requested unit.
+type RoleStatsResponse struct {
+	leaderboard.RoleStats
+	AvgTTFRHuman           string `json:"avg_ttfr_human,omitempty"` // Only with duration_unit=human
+	AvgTimeToApprovalHuman string `json:"avg_time_to_approval_human,omitempty"`
+}
+
+// TeamEntryResponse is a team comparison entry with durations in the requested unit.
+type TeamEntryResponse struct {
+	leaderboard.TeamEntry
+	AvgTTFRHuman           string `json:"avg_ttfr_human,omitempty"` // Only with duration_unit=human
+	AvgTimeToApprovalHuman string `json:"avg_time_to_approval_human,omitempty"`
+}
+
+// SummaryResponse is the org-wide summary with durations in the requested unit.
+type SummaryResponse struct {
+	leaderboard.Summary
+	AvgTTFRHuman string `json:"avg_ttfr_human,omitempty"` // Only with duration_unit=human
+}
+
+// newLeaderboardEntryResponses converts leaderboard entries, whose durations are in minutes, to unit.
+func newLeaderboardEntryResponses(entries []leaderboard.Entry, unit string) []LeaderboardEntryResponse {
+	resp := make([]LeaderboardEntryResponse, 0, len(entries))
+	for _, e := range entries {
+		item := LeaderboardEntryResponse{Entry: e}
+		item.AvgTTFR = scaleMinutes(e.AvgTTFR, unit)
+		item.AvgTimeToApproval = scaleMinutes(e.AvgTimeToApproval, unit)
+		if unit == durationUnitHuman {
+			item.AvgTTFRHuman = humanDuration(e.AvgTTFR)
+			item.AvgTimeToApprovalHuman = humanDuration(e.AvgTimeToApproval)
+		}
+		resp = append(resp, item)
+	}
+	return resp
+}
+
+// newUserStatsResponse converts user statistics, whose durations are in minutes, to unit.
+func newUserStatsResponse(stats *leaderboard.UserStats, unit string) UserStatsResponse {
+	resp := UserStatsResponse{UserStats: *stats}
+	resp.AvgTTFR = scaleMinutes(stats.AvgTTFR, unit)
+	resp.AvgTimeToApproval = scaleMinutes(stats.AvgTimeToApproval, unit)
+	if stats.AvgTTFRDelta != nil {
+		delta := scaleMinutes(*stats.AvgTTFRDelta, unit)
+		resp.AvgTTFRDelta = &delta
+	}
+	if unit == durationUnitHuman {
+		resp.AvgTTFRHuman = humanDuration(stats.AvgTTFR)
+		resp.AvgTimeToApprovalHuman = humanDuration(stats.AvgTimeToApproval)
+	}
+	return resp
+}
+
+// newTeamEntryResponses converts team comparison entries, whose durations are in minutes, to unit.
+func newTeamEntryResponses(teams []leaderboard.TeamEntry, unit string) []TeamEntryResponse {
+	resp := make([]TeamEntryResponse, 0, len(teams))
+	for _, t := range teams {
+		item := TeamEntryResponse{TeamEntry: t}
+		item.AvgTTFR = scaleMinutes(t.AvgTTFR, unit)
+		item.AvgTimeToApproval = scaleMinutes(t.AvgTimeToApproval, unit)
+		if unit == durationUnitHuman {
+			item.AvgTTFRHuman = humanDuration(t.AvgTTFR)
+			item.AvgTimeToApprovalHuman = humanDuration(t.AvgTimeToApproval)
+		}
+		resp = append(resp, item)
+	}
+	return resp
+}
+
+// newSummaryResponse converts the org-wide summary, whose durations are in minutes, to unit.
+func newSummaryResponse(summary *leaderboard.Summary, unit string) SummaryResponse {
+	resp := SummaryResponse{Summary: *summary}
+	resp.AvgTTFR = scaleMinutes(summary.AvgTTFR, unit)
+	if unit == durationUnitHuman {
+		resp.AvgTTFRHuman = humanDuration(summary.AvgTTFR)
+	}
+	return resp
+}
+
+// newRoleStatsResponses converts role stats, whose durations are in minutes, to unit.
+func newRoleStatsResponses(stats []leaderboard.RoleStats, unit string) []RoleStatsResponse {
+	resp := make([]RoleStatsResponse, 0, len(stats))
+	for _, s := range stats {
+		item := RoleStatsResponse{RoleStats: s}
+		item.AvgTTFR = scaleMinutes(s.AvgTTFR, unit)
+		item.AvgTimeToApproval = scaleMinutes(s.AvgTimeToApproval, unit)
+		if unit == durationUnitHuman {
+			item.AvgTTFRHuman = humanDuration(s.AvgTTFR)
+			item.AvgTimeToApprovalHuman = humanDuration(s.AvgTimeToApproval)
+		}
+		resp = append(resp, item)
+	}
+	return resp
+}
+
+// newUserResponse converts a user, dropping the email unless includeEmail is set.
+func newUserResponse(u *models.User, includeEmail bool) UserResponse {
+	resp := UserResponse{
+		ID:          u.ID,
+		GitLabID:    u.GitLabID,
+		Username:    u.Username,
+		DisplayName: u.DisplayName,
+		Role:        u.Role,
+		Team:        u.Team,
+		CreatedAt:   u.CreatedAt,
+		UpdatedAt:   u.UpdatedAt,
+	}
+	if includeEmail {
+		resp.Email = u.Email
+	}
+	return resp
+}
+
+// newUserResponses converts a list of users.
+func newUserResponses(users []models.User, includeEmail bool) []UserResponse {
+	resp := make([]UserResponse, 0, len(users))
+	for i := range users {
+		resp = append(resp, newUserResponse(&users[i], includeEmail))
+	}
+	return resp
+}
+
+// newUserBadgeResponses converts earned badges; the user is omitted when it was not loaded.
+func newUserBadgeResponses(userBadges []models.UserBadge, includeEmail bool) []UserBadgeResponse {
+	resp := make([]UserBadgeResponse, 0, len(userBadges))
+	for i := range userBadges {
+		ub := &userBadges[i]
+		item := UserBadgeResponse{
+			ID:       ub.ID,
+			UserID:   ub.UserID,
+			BadgeID:  ub.BadgeID,
+			Badge:    ub.Badge,
+			EarnedAt: ub.EarnedAt,
+		}
+		if ub.User.ID != 0 {
+			user := newUserResponse(&ub.User, includeEmail)
+			item.User = &user
+		}
+		resp = append(resp, item)
+	}
+	return resp
+}
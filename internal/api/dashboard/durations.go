@@ -0,0 +1,63 @@
+package dashboard
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Units accepted by the duration_unit query parameter. Durations are computed and
+// stored in minutes; "human" keeps numeric minutes and adds strings such as "1h 30m".
+const (
+	durationUnitMinutes = "minutes"
+	durationUnitSeconds = "seconds"
+	durationUnitHuman   = "human"
+)
+
+// parseDurationUnit extracts and validates the duration_unit query parameter.
+func (h *Handler) parseDurationUnit(c *gin.Context) (string, error) {
+	unit := c.DefaultQuery("duration_unit", durationUnitMinutes)
+	if err := validateAllowed("duration_unit", unit, validDurationUnits); err != nil {
+		return "", err
+	}
+	return unit, nil
+}
+
+// numericDurationUnit returns the unit numeric durations are reported in for a requested unit.
+func numericDurationUnit(unit string) string {
+	if unit == durationUnitSeconds {
+		return durationUnitSeconds
+	}
+	return durationUnitMinutes
+}
+
+// scaleMinutes converts a duration in minutes to the numeric unit of a requested unit.
+func scaleMinutes(minutes float64, unit string) float64 {
+	if unit == durationUnitSeconds {
+		return minutes * 60
+	}
+	return minutes
+}
+
+// humanDuration formats minutes with at most two units, e.g. "2d 3h", "1h 30m" or "45m".
+func humanDuration(minutes float64) string {
+	total := int(math.Round(minutes))
+	if total <= 0 {
+		return "0m"
+	}
+
+	days, hours, mins := total/(24*60), total/60%24, total%60
+	switch {
+	case days > 0 && hours > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case days > 0:
+		return fmt.Sprintf("%dd", days)
+	case hours > 0 && mins > 0:
+		return fmt.Sprintf("%dh %dm", hours, mins)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dm", mins)
+	}
+}
@@ -0,0 +1,97 @@
+package dashboard
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/metrics"
+)
+
+// SetEngagementCalculator sets the formula used to break down review engagement.
+// Without it, metrics.DefaultEngagementConfig is used.
+func (h *Handler) SetEngagementCalculator(calculator *metrics.EngagementCalculator) {
+	h.engagement = calculator
+}
+
+// ReviewerEngagement is a reviewer's engagement on an MR with its score components.
+type ReviewerEngagement struct {
+	UserID         uint       `json:"user_id"`
+	Username       string     `json:"username"`
+	Role           string     `json:"role"`
+	CommentCount   int        `json:"comment_count"`
+	CommentLength  int        `json:"comment_length"`
+	AssignedAt     time.Time  `json:"assigned_at"`
+	FirstCommentAt *time.Time `json:"first_comment_at"`
+	metrics.EngagementBreakdown
+}
+
+// GetReviewEngagement returns each reviewer's engagement on an MR, split into comment
+// count points, comment length points and response bonus, to explain a score.
+// GET /api/v1/reviews/:projectId/:mrIid/engagement.
+func (h *Handler) GetReviewEngagement(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil || projectID <= 0 {
+		h.badRequest(c, newParamError("project_id", "invalid project ID: %s", c.Param("projectId")))
+		return
+	}
+	mrIID, err := strconv.Atoi(c.Param("mrIid"))
+	if err != nil || mrIID <= 0 {
+		h.badRequest(c, newParamError("mr_iid", "invalid MR IID: %s", c.Param("mrIid")))
+		return
+	}
+
+	if h.reviewRepo == nil {
+		h.errorResponse(c, http.StatusServiceUnavailable, CodeUnavailable, "Review engagement is not available")
+		return
+	}
+
+	review, err := h.reviewRepo.GetMRReview(projectID, mrIID)
+	if errors.Is(err, repository.ErrNotFound) {
+		h.errorResponse(c, http.StatusNotFound, CodeNotFound, "Review not found")
+		return
+	}
+	if err != nil {
+		h.log.Error().Err(err).Int("project_id", projectID).Int("mr_iid", mrIID).Msg("Failed to get review")
+		h.errorResponse(c, http.StatusInternalServerError, CodeInternal, "Failed to retrieve review")
+		return
+	}
+
+	calculator := h.engagement
+	if calculator == nil {
+		calculator = metrics.NewEngagementCalculator(metrics.DefaultEngagementConfig)
+	}
+
+	reviewers := make([]ReviewerEngagement, 0, len(review.Assignments))
+	for i := range review.Assignments {
+		assignment := &review.Assignments[i]
+		reviewers = append(reviewers, ReviewerEngagement{
+			UserID:              assignment.UserID,
+			Username:            assignment.User.Username,
+			Role:                assignment.Role,
+			CommentCount:        assignment.CommentCount,
+			CommentLength:       assignment.CommentLength,
+			AssignedAt:          assignment.AssignedAt,
+			FirstCommentAt:      assignment.FirstCommentAt,
+			EngagementBreakdown: calculator.Breakdown(assignment),
+		})
+	}
+
+	h.log.Info().
+		Int("project_id", projectID).
+		Int("mr_iid", mrIID).
+		Int("reviewers", len(reviewers)).
+		Msg("Retrieved review engagement")
+
+	c.JSON(http.StatusOK, gin.H{
+		"project_id":   projectID,
+		"mr_iid":       mrIID,
+		"mr_url":       review.MRURL,
+		"reviewers":    reviewers,
+		"generated_at": time.Now().UTC(),
+	})
+}
@@ -0,0 +1,87 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Error codes returned in the code field of error responses. Parameter validation
+// failures use "invalid_<param>" (e.g. "invalid_period", "invalid_metric") and
+// conflicting parameters use "conflicting_range".
+const (
+	CodeInvalidRequest = "invalid_request"
+	CodeNotFound       = "not_found"
+	CodeForbidden      = "forbidden"
+	CodeUnavailable    = "unavailable"
+	CodeTimeout        = "timeout"
+	CodeInternal       = "internal_error"
+)
+
+// statusClientClosedRequest is the non-standard status recorded when the client disconnects
+// before the response is ready; nothing is sent since no one is listening.
+const statusClientClosedRequest = 499
+
+// APIError is the body of every error response. Clients should branch on Code;
+// Message is meant for humans and may change.
+type APIError struct {
+	Code      string    `json:"code"`
+	Message   string    `json:"error"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// paramError reports a malformed parameter whose message does not fit invalidParamError.
+type paramError struct {
+	param   string
+	message string
+}
+
+func newParamError(param, format string, args ...interface{}) error {
+	return &paramError{param: param, message: fmt.Sprintf(format, args...)}
+}
+
+func (e *paramError) Error() string {
+	return e.message
+}
+
+// Code returns the machine-readable error code, e.g. "invalid_limit".
+func (e *paramError) Code() string {
+	return "invalid_" + e.param
+}
+
+// badRequest sends a 400 response, using the error's code when it carries one.
+func (h *Handler) badRequest(c *gin.Context, err error) {
+	code := CodeInvalidRequest
+	var coded codedError
+	if errors.As(err, &coded) {
+		code = coded.Code()
+	}
+	h.errorResponse(c, http.StatusBadRequest, code, err.Error())
+}
+
+// serviceError reports a failed service call: 504 when the request timeout expired,
+// an aborted request when the client disconnected, and a 500 with message otherwise.
+func (h *Handler) serviceError(ctx context.Context, c *gin.Context, message string) {
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		h.errorResponse(c, http.StatusGatewayTimeout, CodeTimeout, "Request timed out")
+	case errors.Is(ctx.Err(), context.Canceled):
+		h.log.Debug().Str("path", c.FullPath()).Msg("Client disconnected, request aborted")
+		c.AbortWithStatus(statusClientClosedRequest)
+	default:
+		h.errorResponse(c, http.StatusInternalServerError, CodeInternal, message)
+	}
+}
+
+// errorResponse sends a standardized error response.
+func (h *Handler) errorResponse(c *gin.Context, statusCode int, code, message string) {
+	c.JSON(statusCode, APIError{
+		Code:      code,
+		Message:   message,
+		Timestamp: time.Now().UTC(),
+	})
+}
@@ -4,38 +4,76 @@ package dashboard
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/api/middleware"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/badges"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/leaderboard"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/metrics"
 	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
 )
 
 // BadgeService interface for badge operations.
 type BadgeService interface {
 	GetUserBadges(ctx context.Context, userID uint) ([]models.UserBadge, error)
-	GetBadgeCatalog(ctx context.Context) ([]models.Badge, error)
+	GetBadgeCatalog(ctx context.Context) ([]badges.CatalogEntry, error)
+	GetPublicBadgeCatalog(ctx context.Context) ([]badges.CatalogEntry, error)
 	GetBadgeByID(ctx context.Context, badgeID uint) (*models.Badge, error)
-	GetBadgeHolders(ctx context.Context, badgeID uint) ([]models.User, error)
+	GetBadgeHolders(ctx context.Context, badgeID uint, offset, limit int) ([]models.User, int64, error)
+	GetRecentlyAwardedBadges(ctx context.Context, since time.Time) ([]models.UserBadge, error)
+	GetBadgeProgress(ctx context.Context, userID uint) ([]badges.BadgeProgress, error)
 }
 
+const (
+	// defaultRecentBadgesWindow is used when GET /badges/recent has no since parameter.
+	defaultRecentBadgesWindow = 24 * time.Hour
+	// maxRecentBadgesWindow caps how far back GET /badges/recent can look.
+	maxRecentBadgesWindow = 30 * 24 * time.Hour
+
+	// defaultUserMetricsDays is the range of GET /users/:id/metrics without a start date.
+	defaultUserMetricsDays = 30
+	// dateLayout is the format of start and end query parameters.
+	dateLayout = "2006-01-02"
+)
+
 // LeaderboardService interface for leaderboard operations.
 type LeaderboardService interface {
-	GetGlobalLeaderboard(ctx context.Context, period, metric string, limit int) ([]leaderboard.Entry, error)
-	GetTeamLeaderboard(ctx context.Context, team, period, metric string, limit int) ([]leaderboard.Entry, error)
+	GetGlobalLeaderboard(ctx context.Context, filters leaderboard.Filters, period, metric string, limit int) ([]leaderboard.Entry, error)
+	GetTeamLeaderboard(ctx context.Context, team string, filters leaderboard.Filters, period, metric string, limit int) ([]leaderboard.Entry, error)
+	GetProjectLeaderboard(ctx context.Context, projectID int, filters leaderboard.Filters, period, metric string, limit int) (*leaderboard.ProjectLeaderboard, error)
+	GetFocusTeamSummary(ctx context.Context, filters leaderboard.Filters, period, metric string) (*leaderboard.TeamSummary, error)
 	GetUserStats(ctx context.Context, userID uint, period string) (*leaderboard.UserStats, error)
+	GetPersonalBests(ctx context.Context, userID uint) ([]models.PersonalBest, error)
+	GetUserMetricsHistory(ctx context.Context, userID uint, startDate, endDate time.Time) ([]models.ReviewMetrics, error)
+	GetStatsByRole(ctx context.Context, period string) ([]leaderboard.RoleStats, error)
+	GetSummary(ctx context.Context, period string) (*leaderboard.Summary, error)
+	CompareTeams(ctx context.Context, period, metric string) ([]leaderboard.TeamEntry, error)
 }
 
 // Handler handles dashboard API requests.
 type Handler struct {
 	badgeService       BadgeService
 	leaderboardService LeaderboardService
+	reviewRepo         ReviewRepository
+	userRepo           UserRepository
+	engagement         *metrics.EngagementCalculator
+	cfg                *config.Config
+	teamsMu            sync.RWMutex
+	teams              []config.TeamConfig // Reloaded teams; nil means cfg.Teams
+	requestTimeout     time.Duration
 	log                *logger.Logger
 }
 
@@ -57,57 +95,154 @@ func NewHandlerWithInterfaces(badgeService BadgeService, leaderboardService Lead
 	}
 }
 
+// SetConfig enables validation of team names against the configured teams.
+// Without it, any team name is accepted.
+func (h *Handler) SetConfig(cfg *config.Config) {
+	h.cfg = cfg
+}
+
+// SetTeams replaces the teams team names are validated against, e.g. after a config reload.
+func (h *Handler) SetTeams(teams []config.TeamConfig) {
+	h.teamsMu.Lock()
+	defer h.teamsMu.Unlock()
+	h.teams = teams
+}
+
+// SetRequestTimeout bounds how long a request's service calls may run. Zero means no
+// deadline; requests are still cancelled when the client disconnects.
+func (h *Handler) SetRequestTimeout(timeout time.Duration) {
+	h.requestTimeout = timeout
+}
+
+// requestContext returns the context for a request's service calls: the request's own
+// context, bounded by the request timeout. The caller must call cancel when done.
+func (h *Handler) requestContext(c *gin.Context) (ctx context.Context, cancel context.CancelFunc) {
+	if h.requestTimeout > 0 {
+		return context.WithTimeout(c.Request.Context(), h.requestTimeout)
+	}
+	return context.WithCancel(c.Request.Context())
+}
+
+// isKnownTeam reports whether team is configured, or true when teams are not validated.
+func (h *Handler) isKnownTeam(team string) bool {
+	if h.cfg == nil {
+		return true
+	}
+	h.teamsMu.RLock()
+	defer h.teamsMu.RUnlock()
+	teams := h.teams
+	if teams == nil {
+		teams = h.cfg.Teams
+	}
+	for i := range teams {
+		if teams[i].Name == team {
+			return true
+		}
+	}
+	return false
+}
+
 // GetGlobalLeaderboard returns the global leaderboard.
-// GET /api/v1/leaderboard?period=month&metric=completed_reviews&limit=10.
+// GET /api/v1/leaderboard?period=month&metric=completed_reviews&role=dev&exclude_ooo=true&project_id=42&limit=10 (limit=0 or limit=all for no limit).
+// format=csv returns the leaderboard as a CSV file instead of JSON; as_of=2024-03-01 ranks the period ending then.
 func (h *Handler) GetGlobalLeaderboard(c *gin.Context) {
 	period := c.DefaultQuery("period", "all_time")
 	metric := c.DefaultQuery("metric", "completed_reviews")
 	limit, err := h.parseLimit(c, 10)
 	if err != nil {
-		h.errorResponse(c, http.StatusBadRequest, err.Error())
+		h.badRequest(c, err)
+		return
+	}
+	format, err := h.parseFormat(c)
+	if err != nil {
+		h.badRequest(c, err)
+		return
+	}
+	durationUnit, err := h.parseDurationUnit(c)
+	if err != nil {
+		h.badRequest(c, err)
 		return
 	}
 
 	// Validate parameters
 	if err := h.validatePeriod(period); err != nil {
-		h.errorResponse(c, http.StatusBadRequest, err.Error())
+		h.badRequest(c, err)
 		return
 	}
 	if err := h.validateMetric(metric); err != nil {
-		h.errorResponse(c, http.StatusBadRequest, err.Error())
+		h.badRequest(c, err)
+		return
+	}
+	filters, err := h.parseLeaderboardFilters(c)
+	if err != nil {
+		h.badRequest(c, err)
 		return
 	}
 
-	ctx := context.Background()
-	entries, err := h.leaderboardService.GetGlobalLeaderboard(ctx, period, metric, limit)
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
+	ctx, warnings := leaderboard.WithWarnings(ctx)
+	entries, err := h.leaderboardService.GetGlobalLeaderboard(ctx, filters, period, metric, limit)
 	if err != nil {
 		h.log.Error().Err(err).Msg("Failed to get global leaderboard")
-		h.errorResponse(c, http.StatusInternalServerError, "Failed to retrieve leaderboard")
+		h.serviceError(ctx, c, "Failed to retrieve leaderboard")
 		return
 	}
 
 	h.log.Info().
 		Str("period", period).
 		Str("metric", metric).
+		Str("role", filters.Role).
+		Bool("exclude_ooo", filters.ExcludeOOO).
+		Int("project_id", filters.ProjectID).
 		Int("limit", limit).
 		Int("entries", len(entries)).
+		Str("format", format).
 		Msg("Retrieved global leaderboard")
 
-	c.JSON(http.StatusOK, gin.H{
-		"leaderboard":   entries,
+	leaderboardEntries := newLeaderboardEntryResponses(entries, durationUnit)
+	if format == formatCSV {
+		if err := writeLeaderboardCSV(c, fmt.Sprintf("leaderboard-%s-%s.csv", period, metric), leaderboardEntries); err != nil {
+			h.log.Error().Err(err).Msg("Failed to write global leaderboard CSV")
+		}
+		return
+	}
+
+	body := gin.H{
+		"leaderboard":   leaderboardEntries,
 		"period":        period,
 		"metric":        metric,
+		"as_of":         asOfValue(filters),
+		"duration_unit": numericDurationUnit(durationUnit),
 		"total_entries": len(entries),
 		"generated_at":  time.Now().UTC(),
-	})
+	}
+
+	// The configured focus team is summarized alongside the board; global ranks are unchanged
+	focusTeam, err := h.leaderboardService.GetFocusTeamSummary(ctx, filters, period, metric)
+	if err != nil {
+		h.log.Error().Err(err).Msg("Failed to get focus team summary")
+		h.serviceError(ctx, c, "Failed to retrieve leaderboard")
+		return
+	}
+	if focusTeam != nil {
+		body["focus_team"] = focusTeam
+	}
+
+	h.leaderboardResponse(c, body, warnings)
 }
 
 // GetTeamLeaderboard returns the leaderboard for a specific team.
-// GET /api/v1/leaderboard/:team?period=month&metric=completed_reviews&limit=10.
+// GET /api/v1/leaderboard/:team?period=month&metric=completed_reviews&role=dev&exclude_ooo=true&include_inactive=true&project_id=42&limit=10&format=csv.
 func (h *Handler) GetTeamLeaderboard(c *gin.Context) {
 	team := c.Param("team")
 	if team == "" {
-		h.errorResponse(c, http.StatusBadRequest, "team parameter is required")
+		h.badRequest(c, newParamError("team", "team parameter is required"))
+		return
+	}
+	// Unknown teams are told apart from configured teams that have no data yet
+	if !h.isKnownTeam(team) {
+		h.errorResponse(c, http.StatusNotFound, CodeNotFound, "Team not found")
 		return
 	}
 
@@ -115,25 +250,42 @@ func (h *Handler) GetTeamLeaderboard(c *gin.Context) {
 	metric := c.DefaultQuery("metric", "completed_reviews")
 	limit, err := h.parseLimit(c, 10)
 	if err != nil {
-		h.errorResponse(c, http.StatusBadRequest, err.Error())
+		h.badRequest(c, err)
+		return
+	}
+	format, err := h.parseFormat(c)
+	if err != nil {
+		h.badRequest(c, err)
+		return
+	}
+	durationUnit, err := h.parseDurationUnit(c)
+	if err != nil {
+		h.badRequest(c, err)
 		return
 	}
 
 	// Validate parameters
 	if err := h.validatePeriod(period); err != nil {
-		h.errorResponse(c, http.StatusBadRequest, err.Error())
+		h.badRequest(c, err)
 		return
 	}
 	if err := h.validateMetric(metric); err != nil {
-		h.errorResponse(c, http.StatusBadRequest, err.Error())
+		h.badRequest(c, err)
+		return
+	}
+	filters, err := h.parseLeaderboardFilters(c)
+	if err != nil {
+		h.badRequest(c, err)
 		return
 	}
 
-	ctx := context.Background()
-	entries, err := h.leaderboardService.GetTeamLeaderboard(ctx, team, period, metric, limit)
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
+	ctx, warnings := leaderboard.WithWarnings(ctx)
+	entries, err := h.leaderboardService.GetTeamLeaderboard(ctx, team, filters, period, metric, limit)
 	if err != nil {
 		h.log.Error().Err(err).Str("team", team).Msg("Failed to get team leaderboard")
-		h.errorResponse(c, http.StatusInternalServerError, "Failed to retrieve team leaderboard")
+		h.serviceError(ctx, c, "Failed to retrieve team leaderboard")
 		return
 	}
 
@@ -141,18 +293,163 @@ func (h *Handler) GetTeamLeaderboard(c *gin.Context) {
 		Str("team", team).
 		Str("period", period).
 		Str("metric", metric).
+		Str("role", filters.Role).
+		Bool("exclude_ooo", filters.ExcludeOOO).
+		Bool("include_inactive", filters.IncludeInactive).
+		Int("project_id", filters.ProjectID).
 		Int("limit", limit).
 		Int("entries", len(entries)).
+		Str("format", format).
 		Msg("Retrieved team leaderboard")
 
-	c.JSON(http.StatusOK, gin.H{
+	leaderboardEntries := newLeaderboardEntryResponses(entries, durationUnit)
+	if format == formatCSV {
+		if err := writeLeaderboardCSV(c, fmt.Sprintf("leaderboard-%s-%s-%s.csv", team, period, metric), leaderboardEntries); err != nil {
+			h.log.Error().Err(err).Str("team", team).Msg("Failed to write team leaderboard CSV")
+		}
+		return
+	}
+
+	h.leaderboardResponse(c, gin.H{
 		"team":          team,
-		"leaderboard":   entries,
+		"leaderboard":   leaderboardEntries,
 		"period":        period,
 		"metric":        metric,
+		"as_of":         asOfValue(filters),
+		"duration_unit": numericDurationUnit(durationUnit),
 		"total_entries": len(entries),
 		"generated_at":  time.Now().UTC(),
-	})
+	}, warnings)
+}
+
+// CompareTeams ranks teams side by side by an aggregated metric.
+// GET /api/v1/teams/compare?period=month&metric=avg_ttfr.
+func (h *Handler) CompareTeams(c *gin.Context) {
+	period := c.DefaultQuery("period", "month")
+	metric := c.DefaultQuery("metric", "completed_reviews")
+	if err := h.validatePeriod(period); err != nil {
+		h.badRequest(c, err)
+		return
+	}
+	if err := h.validateMetric(metric); err != nil {
+		h.badRequest(c, err)
+		return
+	}
+	durationUnit, err := h.parseDurationUnit(c)
+	if err != nil {
+		h.badRequest(c, err)
+		return
+	}
+
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
+	ctx, warnings := leaderboard.WithWarnings(ctx)
+	teams, err := h.leaderboardService.CompareTeams(ctx, period, metric)
+	if err != nil {
+		h.log.Error().Err(err).Str("period", period).Str("metric", metric).Msg("Failed to compare teams")
+		h.serviceError(ctx, c, "Failed to compare teams")
+		return
+	}
+
+	h.log.Info().
+		Str("period", period).
+		Str("metric", metric).
+		Int("teams", len(teams)).
+		Msg("Compared teams")
+
+	h.partialResponse(c, gin.H{
+		"teams":         newTeamEntryResponses(teams, durationUnit),
+		"period":        period,
+		"metric":        metric,
+		"duration_unit": numericDurationUnit(durationUnit),
+		"generated_at":  time.Now().UTC(),
+	}, warnings)
+}
+
+// GetProjectLeaderboard returns the leaderboard for reviews on a GitLab project.
+// GET /api/v1/projects/:id/leaderboard?period=month&metric=completed_reviews&role=dev&exclude_ooo=true&limit=10&format=csv.
+func (h *Handler) GetProjectLeaderboard(c *gin.Context) {
+	idStr := c.Param("id")
+	projectID, err := strconv.Atoi(idStr)
+	if err != nil || projectID <= 0 {
+		h.badRequest(c, newParamError("project_id", "invalid project ID: %s", idStr))
+		return
+	}
+
+	period := c.DefaultQuery("period", "all_time")
+	metric := c.DefaultQuery("metric", "completed_reviews")
+	limit, err := h.parseLimit(c, 10)
+	if err != nil {
+		h.badRequest(c, err)
+		return
+	}
+	format, err := h.parseFormat(c)
+	if err != nil {
+		h.badRequest(c, err)
+		return
+	}
+	durationUnit, err := h.parseDurationUnit(c)
+	if err != nil {
+		h.badRequest(c, err)
+		return
+	}
+
+	// Validate parameters
+	if err := h.validatePeriod(period); err != nil {
+		h.badRequest(c, err)
+		return
+	}
+	if err := h.validateMetric(metric); err != nil {
+		h.badRequest(c, err)
+		return
+	}
+	filters, err := h.parseLeaderboardFilters(c)
+	if err != nil {
+		h.badRequest(c, err)
+		return
+	}
+
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
+	ctx, warnings := leaderboard.WithWarnings(ctx)
+	board, err := h.leaderboardService.GetProjectLeaderboard(ctx, projectID, filters, period, metric, limit)
+	if err != nil {
+		h.log.Error().Err(err).Int("project_id", projectID).Msg("Failed to get project leaderboard")
+		h.serviceError(ctx, c, "Failed to retrieve project leaderboard")
+		return
+	}
+
+	h.log.Info().
+		Int("project_id", projectID).
+		Str("period", period).
+		Str("metric", metric).
+		Str("role", filters.Role).
+		Bool("exclude_ooo", filters.ExcludeOOO).
+		Int("limit", limit).
+		Int("entries", len(board.Entries)).
+		Str("format", format).
+		Msg("Retrieved project leaderboard")
+
+	leaderboardEntries := newLeaderboardEntryResponses(board.Entries, durationUnit)
+	if format == formatCSV {
+		if err := writeLeaderboardCSV(c, fmt.Sprintf("leaderboard-project-%d-%s-%s.csv", projectID, period, metric), leaderboardEntries); err != nil {
+			h.log.Error().Err(err).Int("project_id", projectID).Msg("Failed to write project leaderboard CSV")
+		}
+		return
+	}
+
+	h.partialResponse(c, gin.H{
+		"project_id":    board.ProjectID,
+		"project_name":  board.ProjectName,
+		"project_path":  board.ProjectPath,
+		"leaderboard":   leaderboardEntries,
+		"period":        period,
+		"metric":        metric,
+		"as_of":         asOfValue(filters),
+		"duration_unit": numericDurationUnit(durationUnit),
+		"total_entries": len(board.Entries),
+		"generated_at":  time.Now().UTC(),
+	}, warnings)
 }
 
 // GetUserStats returns statistics for a specific user.
@@ -160,21 +457,32 @@ func (h *Handler) GetTeamLeaderboard(c *gin.Context) {
 func (h *Handler) GetUserStats(c *gin.Context) {
 	userID, err := h.parseUserID(c)
 	if err != nil {
-		h.errorResponse(c, http.StatusBadRequest, err.Error())
+		h.badRequest(c, err)
 		return
 	}
 
 	period := c.DefaultQuery("period", "all_time")
 	if err := h.validatePeriod(period); err != nil {
-		h.errorResponse(c, http.StatusBadRequest, err.Error())
+		h.badRequest(c, err)
+		return
+	}
+	durationUnit, err := h.parseDurationUnit(c)
+	if err != nil {
+		h.badRequest(c, err)
 		return
 	}
 
-	ctx := context.Background()
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
+	ctx, warnings := leaderboard.WithWarnings(ctx)
 	stats, err := h.leaderboardService.GetUserStats(ctx, userID, period)
+	if errors.Is(err, leaderboard.ErrUserNotFound) {
+		h.errorResponse(c, http.StatusNotFound, CodeNotFound, "User not found")
+		return
+	}
 	if err != nil {
 		h.log.Error().Err(err).Uint("user_id", userID).Msg("Failed to get user stats")
-		h.errorResponse(c, http.StatusInternalServerError, "Failed to retrieve user statistics")
+		h.serviceError(ctx, c, "Failed to retrieve user statistics")
 		return
 	}
 
@@ -183,8 +491,163 @@ func (h *Handler) GetUserStats(c *gin.Context) {
 		Str("period", period).
 		Msg("Retrieved user stats")
 
+	h.partialResponse(c, gin.H{
+		"stats":         newUserStatsResponse(stats, durationUnit),
+		"duration_unit": numericDurationUnit(durationUnit),
+		"generated_at":  time.Now().UTC(),
+	}, warnings)
+}
+
+// GetStatsSummary returns org-wide headline numbers for a period.
+// GET /api/v1/stats/summary?period=month.
+func (h *Handler) GetStatsSummary(c *gin.Context) {
+	period := c.DefaultQuery("period", "month")
+	if err := h.validatePeriod(period); err != nil {
+		h.badRequest(c, err)
+		return
+	}
+	durationUnit, err := h.parseDurationUnit(c)
+	if err != nil {
+		h.badRequest(c, err)
+		return
+	}
+
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
+	summary, err := h.leaderboardService.GetSummary(ctx, period)
+	if err != nil {
+		h.log.Error().Err(err).Str("period", period).Msg("Failed to get stats summary")
+		h.serviceError(ctx, c, "Failed to retrieve stats summary")
+		return
+	}
+
+	h.log.Info().
+		Str("period", period).
+		Int("total_reviews", summary.TotalReviews).
+		Msg("Retrieved stats summary")
+
+	c.JSON(http.StatusOK, gin.H{
+		"summary":       newSummaryResponse(summary, durationUnit),
+		"period":        period,
+		"duration_unit": numericDurationUnit(durationUnit),
+		"generated_at":  time.Now().UTC(),
+	})
+}
+
+// GetStatsByRole returns org-wide review stats grouped by reviewer role.
+// GET /api/v1/stats/by-role?period=month.
+func (h *Handler) GetStatsByRole(c *gin.Context) {
+	period := c.DefaultQuery("period", "month")
+	if err := h.validatePeriod(period); err != nil {
+		h.badRequest(c, err)
+		return
+	}
+	durationUnit, err := h.parseDurationUnit(c)
+	if err != nil {
+		h.badRequest(c, err)
+		return
+	}
+
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
+	stats, err := h.leaderboardService.GetStatsByRole(ctx, period)
+	if errors.Is(err, leaderboard.ErrRoleStatsUnavailable) {
+		h.errorResponse(c, http.StatusServiceUnavailable, CodeUnavailable, "Role stats are not available")
+		return
+	}
+	if err != nil {
+		h.log.Error().Err(err).Str("period", period).Msg("Failed to get stats by role")
+		h.serviceError(ctx, c, "Failed to retrieve stats by role")
+		return
+	}
+
+	h.log.Info().
+		Str("period", period).
+		Int("roles", len(stats)).
+		Msg("Retrieved stats by role")
+
+	c.JSON(http.StatusOK, gin.H{
+		"roles":         newRoleStatsResponses(stats, durationUnit),
+		"period":        period,
+		"duration_unit": numericDurationUnit(durationUnit),
+		"generated_at":  time.Now().UTC(),
+	})
+}
+
+// GetPersonalBests returns a user's best-ever period values.
+// GET /api/v1/users/:id/personal-bests.
+func (h *Handler) GetPersonalBests(c *gin.Context) {
+	userID, err := h.parseUserID(c)
+	if err != nil {
+		h.badRequest(c, err)
+		return
+	}
+
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
+	bests, err := h.leaderboardService.GetPersonalBests(ctx, userID)
+	if err != nil {
+		h.log.Error().Err(err).Uint("user_id", userID).Msg("Failed to get personal bests")
+		h.serviceError(ctx, c, "Failed to retrieve personal bests")
+		return
+	}
+
+	h.log.Info().
+		Uint("user_id", userID).
+		Int("count", len(bests)).
+		Msg("Retrieved personal bests")
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":        userID,
+		"personal_bests": bests,
+		"generated_at":   time.Now().UTC(),
+	})
+}
+
+// GetUserMetrics exports a user's raw per-day metrics for a date range.
+// GET /api/v1/users/:id/metrics?start=2025-01-01&end=2025-01-31 or ?period=month.
+func (h *Handler) GetUserMetrics(c *gin.Context) {
+	userID, err := h.parseUserID(c)
+	if err != nil {
+		h.badRequest(c, err)
+		return
+	}
+
+	startDate, endDate, err := h.parseDateRange(c)
+	if err != nil {
+		h.badRequest(c, err)
+		return
+	}
+
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
+	history, err := h.leaderboardService.GetUserMetricsHistory(ctx, userID, startDate, endDate)
+	if errors.Is(err, leaderboard.ErrUserNotFound) {
+		h.errorResponse(c, http.StatusNotFound, CodeNotFound, "User not found")
+		return
+	}
+	if errors.Is(err, metrics.ErrDateRangeTooLarge) {
+		h.badRequest(c, newParamError("date_range", "%s", err))
+		return
+	}
+	if err != nil {
+		h.log.Error().Err(err).Uint("user_id", userID).Msg("Failed to get user metrics history")
+		h.serviceError(ctx, c, "Failed to retrieve user metrics")
+		return
+	}
+
+	h.log.Info().
+		Uint("user_id", userID).
+		Time("start", startDate).
+		Time("end", endDate).
+		Int("count", len(history)).
+		Msg("Exported user metrics history")
+
 	c.JSON(http.StatusOK, gin.H{
-		"stats":        stats,
+		"user_id":      userID,
+		"start":        startDate.Format(dateLayout),
+		"end":          endDate.Format(dateLayout),
+		"metrics":      history,
 		"generated_at": time.Now().UTC(),
 	})
 }
@@ -194,15 +657,22 @@ func (h *Handler) GetUserStats(c *gin.Context) {
 func (h *Handler) GetUserBadges(c *gin.Context) {
 	userID, err := h.parseUserID(c)
 	if err != nil {
-		h.errorResponse(c, http.StatusBadRequest, err.Error())
+		h.badRequest(c, err)
+		return
+	}
+
+	includeEmail, err := h.parseIncludeEmail(c)
+	if err != nil {
+		h.errorResponse(c, http.StatusForbidden, CodeForbidden, err.Error())
 		return
 	}
 
-	ctx := context.Background()
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
 	userBadges, err := h.badgeService.GetUserBadges(ctx, userID)
 	if err != nil {
 		h.log.Error().Err(err).Uint("user_id", userID).Msg("Failed to get user badges")
-		h.errorResponse(c, http.StatusInternalServerError, "Failed to retrieve user badges")
+		h.serviceError(ctx, c, "Failed to retrieve user badges")
 		return
 	}
 
@@ -213,25 +683,88 @@ func (h *Handler) GetUserBadges(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"user_id":      userID,
-		"badges":       userBadges,
+		"badges":       newUserBadgeResponses(userBadges, includeEmail),
 		"total_badges": len(userBadges),
 		"generated_at": time.Now().UTC(),
 	})
 }
 
-// GetBadgeCatalog returns all available badges with holder counts.
-// GET /api/v1/badges.
+// GetUserBadgeProgress returns how close a user is to each badge they have not earned yet.
+// Badges missing from the public catalog are only listed for admin requests.
+// GET /api/v1/users/:id/badges/progress.
+func (h *Handler) GetUserBadgeProgress(c *gin.Context) {
+	userID, err := h.parseUserID(c)
+	if err != nil {
+		h.badRequest(c, err)
+		return
+	}
+
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
+	progress, err := h.badgeService.GetBadgeProgress(ctx, userID)
+	if err != nil {
+		h.log.Error().Err(err).Uint("user_id", userID).Msg("Failed to get badge progress")
+		h.serviceError(ctx, c, "Failed to retrieve badge progress")
+		return
+	}
+
+	visible, err := h.badgeVisibility(ctx, c)
+	if err != nil {
+		h.log.Error().Err(err).Msg("Failed to get public badge catalog")
+		h.serviceError(ctx, c, "Failed to retrieve badge progress")
+		return
+	}
+	listed := progress[:0]
+	for _, item := range progress {
+		if visible(item.Badge.ID) {
+			listed = append(listed, item)
+		}
+	}
+	progress = listed
+
+	h.log.Info().
+		Uint("user_id", userID).
+		Int("badge_count", len(progress)).
+		Msg("Retrieved badge progress")
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":      userID,
+		"progress":     progress,
+		"total_badges": len(progress),
+		"generated_at": time.Now().UTC(),
+	})
+}
+
+// GetBadgeCatalog returns all available badges with their holder count and rarity.
+// Hidden badges and badges below their minimum holders are only listed for admin requests.
+// GET /api/v1/badges?sort=name&order=asc (sort: id, name, created_at).
 func (h *Handler) GetBadgeCatalog(c *gin.Context) {
-	ctx := context.Background()
-	catalogBadges, err := h.badgeService.GetBadgeCatalog(ctx)
+	sortBy, order, err := h.parseSort(c, validBadgeSorts, defaultBadgeSort)
+	if err != nil {
+		h.badRequest(c, err)
+		return
+	}
+
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
+	var catalogBadges []badges.CatalogEntry
+	if middleware.IsAdmin(c) {
+		catalogBadges, err = h.badgeService.GetBadgeCatalog(ctx)
+	} else {
+		catalogBadges, err = h.badgeService.GetPublicBadgeCatalog(ctx)
+	}
 	if err != nil {
 		h.log.Error().Err(err).Msg("Failed to get badge catalog")
-		h.errorResponse(c, http.StatusInternalServerError, "Failed to retrieve badge catalog")
+		h.serviceError(ctx, c, "Failed to retrieve badge catalog")
 		return
 	}
 
+	sortBadges(catalogBadges, sortBy, order)
+
 	h.log.Info().
 		Int("badge_count", len(catalogBadges)).
+		Str("sort", sortBy).
+		Str("order", order).
 		Msg("Retrieved badge catalog")
 
 	c.JSON(http.StatusOK, gin.H{
@@ -241,20 +774,33 @@ func (h *Handler) GetBadgeCatalog(c *gin.Context) {
 	})
 }
 
-// GetBadgeByID returns details for a specific badge.
+// GetBadgeByID returns details for a specific badge. Badges missing from the public
+// catalog are not found unless the request is admin.
 // GET /api/v1/badges/:id.
 func (h *Handler) GetBadgeByID(c *gin.Context) {
 	badgeID, err := h.parseBadgeID(c)
 	if err != nil {
-		h.errorResponse(c, http.StatusBadRequest, err.Error())
+		h.badRequest(c, err)
 		return
 	}
 
-	ctx := context.Background()
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
 	badge, err := h.badgeService.GetBadgeByID(ctx, badgeID)
 	if err != nil {
 		h.log.Error().Err(err).Uint("badge_id", badgeID).Msg("Failed to get badge details")
-		h.errorResponse(c, http.StatusNotFound, "Badge not found")
+		h.errorResponse(c, http.StatusNotFound, CodeNotFound, "Badge not found")
+		return
+	}
+
+	visible, err := h.badgeVisibility(ctx, c)
+	if err != nil {
+		h.log.Error().Err(err).Msg("Failed to get public badge catalog")
+		h.serviceError(ctx, c, "Failed to retrieve badge details")
+		return
+	}
+	if !visible(badgeID) {
+		h.errorResponse(c, http.StatusNotFound, CodeNotFound, "Badge not found")
 		return
 	}
 
@@ -269,58 +815,152 @@ func (h *Handler) GetBadgeByID(c *gin.Context) {
 	})
 }
 
-// GetBadgeHolders returns users who have earned a specific badge.
-// GET /api/v1/badges/:id/holders?limit=50.
+// GetBadgeHolders returns users who have earned a specific badge. Badges missing from the
+// public catalog are not found unless the request is admin.
+// GET /api/v1/badges/:id/holders?limit=50&offset=0.
 func (h *Handler) GetBadgeHolders(c *gin.Context) {
 	badgeID, err := h.parseBadgeID(c)
 	if err != nil {
-		h.errorResponse(c, http.StatusBadRequest, err.Error())
+		h.badRequest(c, err)
 		return
 	}
 
 	limit, err := h.parseLimit(c, 50)
 	if err != nil {
-		h.errorResponse(c, http.StatusBadRequest, err.Error())
+		h.badRequest(c, err)
 		return
 	}
 
-	ctx := context.Background()
-	holders, err := h.badgeService.GetBadgeHolders(ctx, badgeID)
+	offset, err := h.parseOffset(c)
 	if err != nil {
-		h.log.Error().Err(err).Uint("badge_id", badgeID).Msg("Failed to get badge holders")
-		h.errorResponse(c, http.StatusInternalServerError, "Failed to retrieve badge holders")
+		h.badRequest(c, err)
+		return
+	}
+
+	includeEmail, err := h.parseIncludeEmail(c)
+	if err != nil {
+		h.errorResponse(c, http.StatusForbidden, CodeForbidden, err.Error())
+		return
+	}
+
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
+	visible, err := h.badgeVisibility(ctx, c)
+	if err != nil {
+		h.log.Error().Err(err).Msg("Failed to get public badge catalog")
+		h.serviceError(ctx, c, "Failed to retrieve badge holders")
+		return
+	}
+	if !visible(badgeID) {
+		h.errorResponse(c, http.StatusNotFound, CodeNotFound, "Badge not found")
 		return
 	}
 
-	// Apply limit
-	totalHolders := len(holders)
-	if limit > 0 && len(holders) > limit {
-		holders = holders[:limit]
+	holders, totalHolders, err := h.badgeService.GetBadgeHolders(ctx, badgeID, offset, limit)
+	if err != nil {
+		h.log.Error().Err(err).Uint("badge_id", badgeID).Msg("Failed to get badge holders")
+		h.serviceError(ctx, c, "Failed to retrieve badge holders")
+		return
 	}
 
 	h.log.Info().
 		Uint("badge_id", badgeID).
 		Int("holder_count", len(holders)).
+		Int64("total_holders", totalHolders).
+		Int("offset", offset).
 		Int("limit", limit).
 		Msg("Retrieved badge holders")
 
 	c.JSON(http.StatusOK, gin.H{
 		"badge_id":      badgeID,
-		"holders":       holders,
+		"holders":       newUserResponses(holders, includeEmail),
 		"total_holders": totalHolders,
 		"limited_to":    len(holders),
+		"offset":        offset,
+		"limit":         limit,
 		"generated_at":  time.Now().UTC(),
 	})
 }
 
+// GetRecentlyAwardedBadges returns badges awarded within a recent time window.
+// Awards of badges missing from the public catalog are only listed for admin requests.
+// GET /api/v1/badges/recent?since=24h.
+func (h *Handler) GetRecentlyAwardedBadges(c *gin.Context) {
+	window, err := h.parseSince(c)
+	if err != nil {
+		h.badRequest(c, err)
+		return
+	}
+
+	includeEmail, err := h.parseIncludeEmail(c)
+	if err != nil {
+		h.errorResponse(c, http.StatusForbidden, CodeForbidden, err.Error())
+		return
+	}
+
+	since := time.Now().UTC().Add(-window)
+
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
+	awards, err := h.badgeService.GetRecentlyAwardedBadges(ctx, since)
+	if err != nil {
+		h.log.Error().Err(err).Dur("since", window).Msg("Failed to get recently awarded badges")
+		h.serviceError(ctx, c, "Failed to retrieve recently awarded badges")
+		return
+	}
+
+	visible, err := h.badgeVisibility(ctx, c)
+	if err != nil {
+		h.log.Error().Err(err).Msg("Failed to get public badge catalog")
+		h.serviceError(ctx, c, "Failed to retrieve recently awarded badges")
+		return
+	}
+	listed := awards[:0]
+	for _, award := range awards {
+		if visible(award.BadgeID) {
+			listed = append(listed, award)
+		}
+	}
+	awards = listed
+
+	h.log.Info().
+		Dur("since", window).
+		Int("award_count", len(awards)).
+		Msg("Retrieved recently awarded badges")
+
+	c.JSON(http.StatusOK, gin.H{
+		"awards":       newUserBadgeResponses(awards, includeEmail),
+		"total_awards": len(awards),
+		"since":        since,
+		"generated_at": time.Now().UTC(),
+	})
+}
+
 // Helper functions
 
+// badgeVisibility reports which badges the request may see: every badge for admin requests,
+// otherwise only those of the public catalog (not hidden and with enough holders).
+func (h *Handler) badgeVisibility(ctx context.Context, c *gin.Context) (func(badgeID uint) bool, error) {
+	if middleware.IsAdmin(c) {
+		return func(uint) bool { return true }, nil
+	}
+	catalog, err := h.badgeService.GetPublicBadgeCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	listed := make(map[uint]bool, len(catalog))
+	for _, entry := range catalog {
+		listed[entry.ID] = true
+	}
+	return func(badgeID uint) bool { return listed[badgeID] }, nil
+}
+
 // parseUserID extracts and validates the user ID from the URL parameter.
 func (h *Handler) parseUserID(c *gin.Context) (uint, error) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		return 0, fmt.Errorf("invalid user ID: %s", idStr)
+		return 0, newParamError("user_id", "invalid user ID: %s", idStr)
 	}
 	return uint(id), nil
 }
@@ -330,69 +970,282 @@ func (h *Handler) parseBadgeID(c *gin.Context) (uint, error) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		return 0, fmt.Errorf("invalid badge ID: %s", idStr)
+		return 0, newParamError("badge_id", "invalid badge ID: %s", idStr)
 	}
 	return uint(id), nil
 }
 
 // parseLimit extracts and validates the limit query parameter.
+// An empty value yields defaultLimit; "0" or "all" yields 0, meaning unlimited.
 func (h *Handler) parseLimit(c *gin.Context, defaultLimit int) (int, error) {
 	limitStr := c.Query("limit")
 	if limitStr == "" {
 		return defaultLimit, nil
 	}
 
+	if limitStr == "all" {
+		return 0, nil
+	}
+
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil {
-		return 0, fmt.Errorf("invalid limit parameter: %s", limitStr)
+		return 0, newParamError("limit", "invalid limit parameter: %s", limitStr)
 	}
 
-	if limit < 1 {
-		return 0, fmt.Errorf("limit must be greater than 0")
+	if limit < 0 {
+		return 0, newParamError("limit", "limit cannot be negative (use 0 or 'all' for no limit)")
 	}
 
 	if limit > 1000 {
-		return 0, fmt.Errorf("limit cannot exceed 1000")
+		return 0, newParamError("limit", "limit cannot exceed 1000")
 	}
 
 	return limit, nil
 }
 
-// validatePeriod validates the period parameter.
-func (h *Handler) validatePeriod(period string) error {
-	validPeriods := map[string]bool{
-		"day":      true,
-		"week":     true,
-		"month":    true,
-		"year":     true,
-		"all_time": true,
+// parseFormat extracts and validates the format query parameter (json or csv).
+func (h *Handler) parseFormat(c *gin.Context) (string, error) {
+	format := c.DefaultQuery("format", formatJSON)
+	if err := validateAllowed("format", format, validFormats); err != nil {
+		return "", err
+	}
+	return format, nil
+}
+
+// parseLeaderboardFilters extracts and validates the role, exclude_ooo, include_inactive, project_id
+// and as_of query parameters.
+func (h *Handler) parseLeaderboardFilters(c *gin.Context) (leaderboard.Filters, error) {
+	role := c.Query("role")
+	if err := validateAllowed("role", role, validRoles); err != nil {
+		return leaderboard.Filters{}, err
+	}
+	excludeOOO := c.Query("exclude_ooo")
+	if err := validateAllowed("exclude_ooo", excludeOOO, validBooleans); err != nil {
+		return leaderboard.Filters{}, err
+	}
+	includeInactive := c.Query("include_inactive")
+	if err := validateAllowed("include_inactive", includeInactive, validBooleans); err != nil {
+		return leaderboard.Filters{}, err
+	}
+	projectID, err := parsePositiveInt("project_id", c.Query("project_id"))
+	if err != nil {
+		return leaderboard.Filters{}, err
+	}
+	asOf, err := parseAsOf(c.Query("as_of"))
+	if err != nil {
+		return leaderboard.Filters{}, err
+	}
+	return leaderboard.Filters{
+		Role:            role,
+		ExcludeOOO:      excludeOOO == "true",
+		IncludeInactive: includeInactive == "true",
+		ProjectID:       projectID,
+		AsOf:            asOf,
+	}, nil
+}
+
+// parseSort extracts and validates the sort and order query parameters against an allowlist of sort fields.
+// Unknown values are rejected rather than silently replaced by the defaults.
+func (h *Handler) parseSort(c *gin.Context, allowed []string, defaultSort string) (sortBy, order string, err error) {
+	sortBy = c.DefaultQuery("sort", defaultSort)
+	if err := validateAllowed("sort", sortBy, allowed); err != nil {
+		return "", "", err
+	}
+
+	order = c.DefaultQuery("order", orderAsc)
+	if err := validateAllowed("order", order, validOrders); err != nil {
+		return "", "", err
+	}
+
+	return sortBy, order, nil
+}
+
+// parseOffset extracts and validates the offset query parameter.
+func (h *Handler) parseOffset(c *gin.Context) (int, error) {
+	offsetStr := c.Query("offset")
+	if offsetStr == "" {
+		return 0, nil
+	}
+
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil {
+		return 0, newParamError("offset", "invalid offset parameter: %s", offsetStr)
+	}
+
+	if offset < 0 {
+		return 0, newParamError("offset", "offset cannot be negative")
+	}
+
+	return offset, nil
+}
+
+// parseIncludeEmail reports whether user emails should be included in the response.
+// Emails are redacted by default; include_email=true is only honored for admin requests.
+func (h *Handler) parseIncludeEmail(c *gin.Context) (bool, error) {
+	if c.Query("include_email") != "true" {
+		return false, nil
+	}
+
+	if !middleware.IsAdmin(c) {
+		return false, fmt.Errorf("include_email requires admin access")
+	}
+
+	return true, nil
+}
+
+// parseSince extracts and validates the since query parameter as a look-back duration.
+// Accepts Go durations (e.g. "90m", "24h") and whole days (e.g. "7d").
+func (h *Handler) parseSince(c *gin.Context) (time.Duration, error) {
+	sinceStr := c.Query("since")
+	if sinceStr == "" {
+		return defaultRecentBadgesWindow, nil
+	}
+
+	var window time.Duration
+	if days, ok := strings.CutSuffix(sinceStr, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, newParamError("since", "invalid since parameter: %s", sinceStr)
+		}
+		window = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err := time.ParseDuration(sinceStr)
+		if err != nil {
+			return 0, newParamError("since", "invalid since parameter: %s", sinceStr)
+		}
+		window = d
+	}
+
+	if window <= 0 {
+		return 0, newParamError("since", "since must be a positive duration")
+	}
+
+	if window > maxRecentBadgesWindow {
+		return 0, newParamError("since", "since cannot exceed 30 days")
+	}
+
+	return window, nil
+}
+
+// parseDateRange extracts and validates the requested date range. Either start and end
+// (YYYY-MM-DD) or a named period may be given, but not both: an explicit range combined
+// with a period is rejected rather than silently preferring one of them.
+// end defaults to today and start to defaultUserMetricsDays before end.
+func (h *Handler) parseDateRange(c *gin.Context) (startDate, endDate time.Time, err error) {
+	startStr, endStr := c.Query("start"), c.Query("end")
+	if period, ok := c.GetQuery("period"); ok {
+		if startStr != "" || endStr != "" {
+			return time.Time{}, time.Time{}, errConflictingRange
+		}
+		return h.periodDateRange(period)
+	}
+
+	endDate = time.Now().UTC().Truncate(24 * time.Hour)
+	if endStr != "" {
+		endDate, err = time.Parse(dateLayout, endStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, newParamError("end", "invalid end date: %s (expected YYYY-MM-DD)", endStr)
+		}
+	}
+
+	startDate = endDate.AddDate(0, 0, -defaultUserMetricsDays)
+	if startStr != "" {
+		startDate, err = time.Parse(dateLayout, startStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, newParamError("start", "invalid start date: %s (expected YYYY-MM-DD)", startStr)
+		}
 	}
 
-	if !validPeriods[period] {
-		return fmt.Errorf("invalid period: %s (valid: day, week, month, year, all_time)", period)
+	if startDate.After(endDate) {
+		return time.Time{}, time.Time{}, newParamError("date_range", "start date must not be after end date")
 	}
-	return nil
+
+	if maxDays := h.maxRangeDays(); maxDays > 0 && endDate.Sub(startDate) > time.Duration(maxDays)*24*time.Hour {
+		return time.Time{}, time.Time{}, newParamError("date_range", "date range cannot exceed %d days", maxDays)
+	}
+
+	return startDate, endDate, nil
+}
+
+// periodDateRange resolves a named period to a date range ending today.
+// all_time is capped to the widest range that may be exported (see maxRangeDays).
+func (h *Handler) periodDateRange(period string) (startDate, endDate time.Time, err error) {
+	if err := h.validatePeriod(period); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	endDate = time.Now().UTC().Truncate(24 * time.Hour)
+	days, ok := periodDays[period]
+	if !ok {
+		// all_time
+		days = h.maxRangeDays()
+		if days == 0 {
+			return time.Time{}, endDate, nil
+		}
+	}
+	return endDate.AddDate(0, 0, -days), endDate, nil
+}
+
+// maxRangeDays returns the widest date range, in days, a metrics query may span:
+// metrics.max_query_range_days when a config is set (0 = unlimited), else its default.
+func (h *Handler) maxRangeDays() int {
+	if h.cfg == nil {
+		return config.DefaultMaxQueryRangeDays
+	}
+	return h.cfg.Metrics.MaxQueryRangeDays
+}
+
+// validatePeriod validates the period parameter.
+func (h *Handler) validatePeriod(period string) error {
+	return validateAllowed("period", period, validPeriods)
 }
 
 // validateMetric validates the metric parameter.
 func (h *Handler) validateMetric(metric string) error {
-	validMetrics := map[string]bool{
-		"completed_reviews": true,
-		"engagement_score":  true,
-		"avg_ttfr":          true,
-		"avg_comment_count": true,
+	return validateAllowed("metric", metric, validMetrics)
+}
+
+// leaderboardResponse sends a leaderboard with an ETag computed from everything but
+// generated_at, answering 304 Not Modified when it matches If-None-Match.
+// Partial responses get no ETag, so clients fetch the full board once it is back.
+func (h *Handler) leaderboardResponse(c *gin.Context, body gin.H, warnings *leaderboard.Warnings) {
+	if len(warnings.List()) == 0 {
+		if etag, err := leaderboardETag(body); err != nil {
+			h.log.Warn().Err(err).Msg("Failed to compute leaderboard ETag")
+		} else {
+			c.Header("ETag", etag)
+			if middleware.ETagMatches(c.GetHeader("If-None-Match"), etag) {
+				c.Status(http.StatusNotModified)
+				return
+			}
+		}
 	}
+	h.partialResponse(c, body, warnings)
+}
 
-	if !validMetrics[metric] {
-		return fmt.Errorf("invalid metric: %s (valid: completed_reviews, engagement_score, avg_ttfr, avg_comment_count)", metric)
+// leaderboardETag hashes the serialized body without generated_at, which changes on every call.
+func leaderboardETag(body gin.H) (string, error) {
+	hashed := make(gin.H, len(body))
+	for key, value := range body {
+		if key != "generated_at" {
+			hashed[key] = value
+		}
+	}
+	data, err := json.Marshal(hashed)
+	if err != nil {
+		return "", err
 	}
-	return nil
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`, nil
 }
 
-// errorResponse sends a standardized error response.
-func (h *Handler) errorResponse(c *gin.Context, statusCode int, message string) {
-	c.JSON(statusCode, gin.H{
-		"error":     message,
-		"timestamp": time.Now().UTC(),
-	})
+// partialResponse sends a 200 response, listing under "warnings" the enrichments
+// that were unavailable when a backend is degraded. Partial responses are not cached.
+func (h *Handler) partialResponse(c *gin.Context, body gin.H, warnings *leaderboard.Warnings) {
+	if list := warnings.List(); len(list) > 0 {
+		body["warnings"] = list
+		c.Header("Cache-Control", "no-store")
+		h.log.Warn().Strs("warnings", list).Str("path", c.FullPath()).Msg("Serving partial result")
+	}
+	c.JSON(http.StatusOK, body)
 }
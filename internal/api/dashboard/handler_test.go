@@ -3,7 +3,9 @@ package dashboard
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -13,8 +15,13 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/api/middleware"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/badges"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/leaderboard"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/metrics"
 	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
 )
 
@@ -23,6 +30,9 @@ type mockBadgeService struct {
 	userBadges   map[uint][]models.UserBadge
 	badges       map[uint]*models.Badge
 	badgeHolders map[uint][]models.User
+	recentAwards []models.UserBadge
+	lastSince    time.Time
+	progress     map[uint][]badges.BadgeProgress
 }
 
 func newMockBadgeService() *mockBadgeService {
@@ -41,12 +51,12 @@ func (m *mockBadgeService) GetUserBadges(ctx context.Context, userID uint) ([]mo
 	return badges, nil
 }
 
-func (m *mockBadgeService) GetBadgeCatalog(ctx context.Context) ([]models.Badge, error) {
-	badges := make([]models.Badge, 0, len(m.badges))
+func (m *mockBadgeService) GetBadgeCatalog(ctx context.Context) ([]badges.CatalogEntry, error) {
+	entries := make([]badges.CatalogEntry, 0, len(m.badges))
 	for _, badge := range m.badges {
-		badges = append(badges, *badge)
+		entries = append(entries, badges.CatalogEntry{Badge: *badge})
 	}
-	return badges, nil
+	return entries, nil
 }
 
 func (m *mockBadgeService) GetBadgeByID(ctx context.Context, badgeID uint) (*models.Badge, error) {
@@ -57,12 +67,45 @@ func (m *mockBadgeService) GetBadgeByID(ctx context.Context, badgeID uint) (*mod
 	return badge, nil
 }
 
-func (m *mockBadgeService) GetBadgeHolders(ctx context.Context, badgeID uint) ([]models.User, error) {
+func (m *mockBadgeService) GetBadgeHolders(ctx context.Context, badgeID uint, offset, limit int) ([]models.User, int64, error) {
 	holders, exists := m.badgeHolders[badgeID]
 	if !exists {
-		return []models.User{}, nil
+		return []models.User{}, 0, nil
+	}
+	total := int64(len(holders))
+	if offset >= len(holders) {
+		return []models.User{}, total, nil
+	}
+	holders = holders[offset:]
+	if limit > 0 && len(holders) > limit {
+		holders = holders[:limit]
+	}
+	return holders, total, nil
+}
+
+func (m *mockBadgeService) GetRecentlyAwardedBadges(ctx context.Context, since time.Time) ([]models.UserBadge, error) {
+	m.lastSince = since
+	var awards []models.UserBadge
+	for _, award := range m.recentAwards {
+		if !award.EarnedAt.Before(since) {
+			awards = append(awards, award)
+		}
+	}
+	return awards, nil
+}
+
+func (m *mockBadgeService) GetBadgeProgress(ctx context.Context, userID uint) ([]badges.BadgeProgress, error) {
+	return m.progress[userID], nil
+}
+
+func (m *mockBadgeService) GetPublicBadgeCatalog(ctx context.Context) ([]badges.CatalogEntry, error) {
+	var listed []badges.CatalogEntry
+	for _, badge := range m.badges {
+		if !badge.Hidden && len(m.badgeHolders[badge.ID]) >= badge.MinHolders {
+			listed = append(listed, badges.CatalogEntry{Badge: *badge})
+		}
 	}
-	return holders, nil
+	return listed, nil
 }
 
 // Mock Leaderboard Service
@@ -70,6 +113,16 @@ type mockLeaderboardService struct {
 	globalLeaderboard map[string][]leaderboard.Entry
 	teamLeaderboard   map[string][]leaderboard.Entry
 	userStats         map[uint]*leaderboard.UserStats
+	personalBests     map[uint][]models.PersonalBest
+	userMetrics       map[uint][]models.ReviewMetrics
+	metricsRange      [2]time.Time
+	projectBoards     map[int]*leaderboard.ProjectLeaderboard
+	focusTeam         *leaderboard.TeamSummary
+	lastFilters       leaderboard.Filters
+	roleStats         map[string][]leaderboard.RoleStats
+	summaries         map[string]*leaderboard.Summary
+	teamComparisons   map[string][]leaderboard.TeamEntry
+	blockUntilDone    bool // Leaderboard calls wait for the request context to end
 }
 
 func newMockLeaderboardService() *mockLeaderboardService {
@@ -77,10 +130,21 @@ func newMockLeaderboardService() *mockLeaderboardService {
 		globalLeaderboard: make(map[string][]leaderboard.Entry),
 		teamLeaderboard:   make(map[string][]leaderboard.Entry),
 		userStats:         make(map[uint]*leaderboard.UserStats),
+		personalBests:     make(map[uint][]models.PersonalBest),
+		userMetrics:       make(map[uint][]models.ReviewMetrics),
+		projectBoards:     make(map[int]*leaderboard.ProjectLeaderboard),
+		roleStats:         make(map[string][]leaderboard.RoleStats),
+		summaries:         make(map[string]*leaderboard.Summary),
+		teamComparisons:   make(map[string][]leaderboard.TeamEntry),
 	}
 }
 
-func (m *mockLeaderboardService) GetGlobalLeaderboard(ctx context.Context, period, metric string, limit int) ([]leaderboard.Entry, error) {
+func (m *mockLeaderboardService) GetGlobalLeaderboard(ctx context.Context, filters leaderboard.Filters, period, metric string, limit int) ([]leaderboard.Entry, error) {
+	m.lastFilters = filters
+	if m.blockUntilDone {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
 	key := fmt.Sprintf("%s:%s", period, metric)
 	entries, exists := m.globalLeaderboard[key]
 	if !exists {
@@ -92,7 +156,8 @@ func (m *mockLeaderboardService) GetGlobalLeaderboard(ctx context.Context, perio
 	return entries, nil
 }
 
-func (m *mockLeaderboardService) GetTeamLeaderboard(ctx context.Context, team, period, metric string, limit int) ([]leaderboard.Entry, error) {
+func (m *mockLeaderboardService) GetTeamLeaderboard(ctx context.Context, team string, filters leaderboard.Filters, period, metric string, limit int) ([]leaderboard.Entry, error) {
+	m.lastFilters = filters
 	key := fmt.Sprintf("%s:%s:%s", team, period, metric)
 	entries, exists := m.teamLeaderboard[key]
 	if !exists {
@@ -104,14 +169,93 @@ func (m *mockLeaderboardService) GetTeamLeaderboard(ctx context.Context, team, p
 	return entries, nil
 }
 
+func (m *mockLeaderboardService) GetProjectLeaderboard(ctx context.Context, projectID int, filters leaderboard.Filters, period, metric string, limit int) (*leaderboard.ProjectLeaderboard, error) {
+	m.lastFilters = filters
+	board, exists := m.projectBoards[projectID]
+	if !exists {
+		return &leaderboard.ProjectLeaderboard{ProjectID: projectID, Entries: []leaderboard.Entry{}}, nil
+	}
+	return board, nil
+}
+
+func (m *mockLeaderboardService) GetFocusTeamSummary(ctx context.Context, filters leaderboard.Filters, period, metric string) (*leaderboard.TeamSummary, error) {
+	return m.focusTeam, nil
+}
+
 func (m *mockLeaderboardService) GetUserStats(ctx context.Context, userID uint, period string) (*leaderboard.UserStats, error) {
 	stats, exists := m.userStats[userID]
 	if !exists {
-		return nil, fmt.Errorf("user stats not found")
+		return nil, leaderboard.ErrUserNotFound
+	}
+	return stats, nil
+}
+
+func (m *mockLeaderboardService) GetPersonalBests(ctx context.Context, userID uint) ([]models.PersonalBest, error) {
+	bests, exists := m.personalBests[userID]
+	if !exists {
+		return nil, fmt.Errorf("user not found")
+	}
+	return bests, nil
+}
+
+func (m *mockLeaderboardService) GetUserMetricsHistory(ctx context.Context, userID uint, startDate, endDate time.Time) ([]models.ReviewMetrics, error) {
+	m.metricsRange = [2]time.Time{startDate, endDate}
+	metrics, exists := m.userMetrics[userID]
+	if !exists {
+		return nil, leaderboard.ErrUserNotFound
+	}
+	return metrics, nil
+}
+
+func (m *mockLeaderboardService) GetStatsByRole(ctx context.Context, period string) ([]leaderboard.RoleStats, error) {
+	stats, exists := m.roleStats[period]
+	if !exists {
+		return nil, leaderboard.ErrRoleStatsUnavailable
 	}
 	return stats, nil
 }
 
+func (m *mockLeaderboardService) GetSummary(ctx context.Context, period string) (*leaderboard.Summary, error) {
+	summary, exists := m.summaries[period]
+	if !exists {
+		return nil, fmt.Errorf("no metrics")
+	}
+	return summary, nil
+}
+
+func (m *mockLeaderboardService) CompareTeams(ctx context.Context, period, metric string) ([]leaderboard.TeamEntry, error) {
+	return m.teamComparisons[fmt.Sprintf("%s:%s", period, metric)], nil
+}
+
+// Mock Review Repository
+type mockReviewRepository struct {
+	assignments []models.ReviewerAssignment
+	reviews     []models.MRReview
+}
+
+func (m *mockReviewRepository) GetMRReview(projectID, mrIID int) (*models.MRReview, error) {
+	for i := range m.reviews {
+		if m.reviews[i].GitLabProjectID == projectID && m.reviews[i].GitLabMRIID == mrIID {
+			return &m.reviews[i], nil
+		}
+	}
+	return nil, fmt.Errorf("MR review for project %d, MR %d: %w", projectID, mrIID, repository.ErrNotFound)
+}
+
+// GetActiveAssignmentsByUserID mirrors the repository query: assignments on MRs still under review.
+func (m *mockReviewRepository) GetActiveAssignmentsByUserID(userID uint) ([]models.ReviewerAssignment, error) {
+	var active []models.ReviewerAssignment
+	for _, a := range m.assignments {
+		switch a.MRReview.Status {
+		case models.MRStatusPending, models.MRStatusInReview, models.MRStatusApproved:
+			if a.UserID == userID {
+				active = append(active, a)
+			}
+		}
+	}
+	return active, nil
+}
+
 // Test Setup
 func setupTestHandler() (*Handler, *mockBadgeService, *mockLeaderboardService) {
 	badgeService := newMockBadgeService()
@@ -130,9 +274,19 @@ func setupRouter(handler *Handler) *gin.Engine {
 	api := router.Group("/api/v1")
 	api.GET("/leaderboard", handler.GetGlobalLeaderboard)
 	api.GET("/leaderboard/:team", handler.GetTeamLeaderboard)
+	api.GET("/teams/compare", handler.CompareTeams)
+	api.GET("/projects/:id/leaderboard", handler.GetProjectLeaderboard)
+	api.GET("/stats/summary", handler.GetStatsSummary)
+	api.GET("/stats/by-role", handler.GetStatsByRole)
 	api.GET("/users/:id/stats", handler.GetUserStats)
+	api.GET("/users/:id/personal-bests", handler.GetPersonalBests)
+	api.GET("/users/:id/active-reviews", handler.GetUserActiveReviews)
+	api.GET("/reviews/:projectId/:mrIid/engagement", handler.GetReviewEngagement)
+	api.GET("/users/:id/metrics", handler.GetUserMetrics)
 	api.GET("/users/:id/badges", handler.GetUserBadges)
+	api.GET("/users/:id/badges/progress", handler.GetUserBadgeProgress)
 	api.GET("/badges", handler.GetBadgeCatalog)
+	api.GET("/badges/recent", handler.GetRecentlyAwardedBadges)
 	api.GET("/badges/:id", handler.GetBadgeByID)
 	api.GET("/badges/:id/holders", handler.GetBadgeHolders)
 
@@ -182,6 +336,7 @@ func TestGetGlobalLeaderboard_InvalidPeriod(t *testing.T) {
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
+	assert.Equal(t, "invalid_period", response["code"])
 	assert.Contains(t, response["error"], "invalid period")
 }
 
@@ -198,6 +353,7 @@ func TestGetGlobalLeaderboard_InvalidMetric(t *testing.T) {
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
+	assert.Equal(t, "invalid_metric", response["code"])
 	assert.Contains(t, response["error"], "invalid metric")
 }
 
@@ -214,43 +370,52 @@ func TestGetGlobalLeaderboard_InvalidLimit(t *testing.T) {
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
+	assert.Equal(t, "invalid_limit", response["code"])
 	assert.Contains(t, response["error"], "invalid limit")
 }
 
-func TestGetTeamLeaderboard_Success(t *testing.T) {
+func TestGetGlobalLeaderboard_Unlimited(t *testing.T) {
 	handler, _, leaderboardService := setupTestHandler()
 	router := setupRouter(handler)
 
-	// Setup mock data
-	entries := []leaderboard.Entry{
-		{Rank: 1, UserID: 1, Username: "alice", Team: "backend", CompletedReviews: 50, EngagementScore: 95.5},
-		{Rank: 2, UserID: 3, Username: "charlie", Team: "backend", CompletedReviews: 40, EngagementScore: 88.2},
+	// More entries than the default limit of 10
+	entries := make([]leaderboard.Entry, 25)
+	for i := range entries {
+		entries[i] = leaderboard.Entry{Rank: i + 1, UserID: uint(i + 1), Username: fmt.Sprintf("user%d", i+1)}
+	}
+	leaderboardService.globalLeaderboard["all_time:completed_reviews"] = entries
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{name: "default limit", query: "", want: 10},
+		{name: "limit all", query: "?limit=all", want: 25},
+		{name: "limit zero", query: "?limit=0", want: 25},
 	}
-	leaderboardService.teamLeaderboard["backend:month:completed_reviews"] = entries
-
-	// Make request
-	req, _ := http.NewRequest("GET", "/api/v1/leaderboard/backend?period=month&metric=completed_reviews&limit=10", http.NoBody)
-	w := httptest.NewRecorder()
-	router.ServeHTTP(w, req)
 
-	// Assert
-	assert.Equal(t, http.StatusOK, w.Code)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/api/v1/leaderboard"+tt.query, http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
 
-	var response map[string]interface{}
-	err := json.Unmarshal(w.Body.Bytes(), &response)
-	assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, w.Code)
 
-	assert.Equal(t, "backend", response["team"])
-	assert.Equal(t, "month", response["period"])
-	assert.Equal(t, "completed_reviews", response["metric"])
-	assert.Equal(t, float64(2), response["total_entries"])
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Equal(t, float64(tt.want), response["total_entries"])
+		})
+	}
 }
 
-func TestGetTeamLeaderboard_InvalidParameters(t *testing.T) {
+func TestGetGlobalLeaderboard_NegativeLimit(t *testing.T) {
 	handler, _, _ := setupTestHandler()
 	router := setupRouter(handler)
 
-	req, _ := http.NewRequest("GET", "/api/v1/leaderboard/backend?period=invalid&metric=completed_reviews", http.NoBody)
+	req, _ := http.NewRequest("GET", "/api/v1/leaderboard?limit=-1", http.NoBody)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
@@ -259,119 +424,173 @@ func TestGetTeamLeaderboard_InvalidParameters(t *testing.T) {
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Contains(t, response["error"], "invalid period")
+	assert.Equal(t, "invalid_limit", response["code"])
+	assert.Contains(t, response["error"], "limit cannot be negative")
 }
 
-func TestGetUserStats_Success(t *testing.T) {
+func TestGetGlobalLeaderboard_CSV(t *testing.T) {
 	handler, _, leaderboardService := setupTestHandler()
 	router := setupRouter(handler)
 
-	// Setup mock data
-	stats := &leaderboard.UserStats{
-		UserID:            1,
-		Username:          "alice",
-		Team:              "backend",
-		Period:            "month",
-		TotalReviews:      50,
-		CompletedReviews:  48,
-		AvgTTFR:           3600.0,
-		AvgTimeToApproval: 7200.0,
-		AvgCommentCount:   5.5,
-		EngagementScore:   95.5,
-		GlobalRank:        1,
-		TeamRank:          1,
+	entries := []leaderboard.Entry{
+		{Rank: 1, UserID: 1, Username: "alice", Team: "backend", CompletedReviews: 50, AvgTTFR: 30, EngagementScore: 95.5, BadgeCount: 3},
+		{Rank: 2, UserID: 2, Username: "bob", Team: "frontend", CompletedReviews: 45, AvgTTFR: 42.5, EngagementScore: 92.3, BadgeCount: 1},
 	}
-	leaderboardService.userStats[1] = stats
+	leaderboardService.globalLeaderboard["month:completed_reviews"] = entries
 
-	// Make request
-	req, _ := http.NewRequest("GET", "/api/v1/users/1/stats?period=month", http.NoBody)
+	req, _ := http.NewRequest("GET", "/api/v1/leaderboard?period=month&format=csv", http.NoBody)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
-	// Assert
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
 
-	var response map[string]interface{}
-	err := json.Unmarshal(w.Body.Bytes(), &response)
+	records, err := csv.NewReader(w.Body).ReadAll()
 	assert.NoError(t, err)
-	assert.NotNil(t, response["stats"])
+	assert.Equal(t, []string{"rank", "username", "team", "completed_reviews", "avg_ttfr", "engagement_score", "badge_count"}, records[0])
+	assert.Len(t, records, len(entries)+1)
+
+	// Rows follow the JSON ordering
+	for i, entry := range entries {
+		assert.Equal(t, entry.Username, records[i+1][1])
+	}
+	assert.Equal(t, []string{"2", "bob", "frontend", "45", "42.50", "92.30", "1"}, records[2])
 }
 
-func TestGetUserStats_InvalidUserID(t *testing.T) {
-	handler, _, _ := setupTestHandler()
+func TestGetTeamLeaderboard_CSV(t *testing.T) {
+	handler, _, leaderboardService := setupTestHandler()
 	router := setupRouter(handler)
 
-	req, _ := http.NewRequest("GET", "/api/v1/users/abc/stats?period=month", http.NoBody)
+	leaderboardService.teamLeaderboard["backend:all_time:completed_reviews"] = []leaderboard.Entry{
+		{Rank: 1, UserID: 1, Username: "alice", Team: "backend", CompletedReviews: 50},
+	}
+
+	req, _ := http.NewRequest("GET", "/api/v1/leaderboard/backend?format=csv", http.NoBody)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
 
-	var response map[string]interface{}
-	err := json.Unmarshal(w.Body.Bytes(), &response)
+	records, err := csv.NewReader(w.Body).ReadAll()
 	assert.NoError(t, err)
-	assert.Contains(t, response["error"], "invalid user ID")
+	assert.Len(t, records, 2)
+	assert.Equal(t, "alice", records[1][1])
 }
 
-func TestGetUserStats_InvalidPeriod(t *testing.T) {
-	handler, _, _ := setupTestHandler()
+func TestLeaderboard_ETag(t *testing.T) {
+	handler, _, leaderboardService := setupTestHandler()
 	router := setupRouter(handler)
 
-	req, _ := http.NewRequest("GET", "/api/v1/users/1/stats?period=invalid", http.NoBody)
+	leaderboardService.globalLeaderboard["month:completed_reviews"] = []leaderboard.Entry{
+		{Rank: 1, UserID: 1, Username: "alice", Team: "backend", CompletedReviews: 50},
+	}
+	leaderboardService.teamLeaderboard["backend:month:completed_reviews"] = []leaderboard.Entry{
+		{Rank: 1, UserID: 1, Username: "alice", Team: "backend", CompletedReviews: 50},
+	}
+
+	get := func(path, ifNoneMatch string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, http.NoBody)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, path := range []string{"/api/v1/leaderboard?period=month", "/api/v1/leaderboard/backend?period=month"} {
+		t.Run(path, func(t *testing.T) {
+			first := get(path, "")
+			assert.Equal(t, http.StatusOK, first.Code)
+			etag := first.Header().Get("ETag")
+			assert.NotEmpty(t, etag)
+
+			// generated_at differs between calls but does not change the ETag
+			time.Sleep(time.Millisecond)
+			assert.Equal(t, etag, get(path, "").Header().Get("ETag"))
+
+			notModified := get(path, etag)
+			assert.Equal(t, http.StatusNotModified, notModified.Code)
+			assert.Empty(t, notModified.Body.String())
+
+			stale := get(path, `"stale"`)
+			assert.Equal(t, http.StatusOK, stale.Code)
+			assert.Equal(t, etag, stale.Header().Get("ETag"))
+
+			// Another metric is another board
+			other := get(path+"&metric=points", etag)
+			assert.Equal(t, http.StatusOK, other.Code)
+			assert.NotEqual(t, etag, other.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestGetGlobalLeaderboard_ClientDisconnected(t *testing.T) {
+	handler, _, leaderboardService := setupTestHandler()
+	leaderboardService.blockUntilDone = true
+	router := setupRouter(handler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/api/v1/leaderboard", http.NoBody)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
-
-	var response map[string]interface{}
-	err := json.Unmarshal(w.Body.Bytes(), &response)
-	assert.NoError(t, err)
-	assert.Contains(t, response["error"], "invalid period")
+	assert.Equal(t, statusClientClosedRequest, w.Code)
+	assert.Empty(t, w.Body.String())
 }
 
-func TestGetUserBadges_Success(t *testing.T) {
-	handler, badgeService, _ := setupTestHandler()
+func TestGetGlobalLeaderboard_RequestTimeout(t *testing.T) {
+	handler, _, leaderboardService := setupTestHandler()
+	leaderboardService.blockUntilDone = true
+	handler.SetRequestTimeout(10 * time.Millisecond)
 	router := setupRouter(handler)
 
-	// Setup mock data
-	badge := models.Badge{
-		Name:        "Speed Demon",
-		Description: "Complete 10 reviews in under 1 hour",
-		Icon:        "speed",
-	}
-	badge.ID = 1
+	req, _ := http.NewRequest("GET", "/api/v1/leaderboard", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
 
-	userBadges := []models.UserBadge{
-		{
-			UserID:   1,
-			BadgeID:  1,
-			Badge:    badge,
-			EarnedAt: time.Now(),
-		},
-	}
-	badgeService.userBadges[1] = userBadges
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"timeout"`)
+}
 
-	// Make request
-	req, _ := http.NewRequest("GET", "/api/v1/users/1/badges", http.NoBody)
+func TestGetGlobalLeaderboard_InvalidFormat(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/leaderboard?format=xml", http.NoBody)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
-	// Assert
-	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-
-	assert.Equal(t, float64(1), response["user_id"])
-	assert.Equal(t, float64(1), response["total_badges"])
+	assert.Equal(t, "invalid_format", response["code"])
+	assert.Contains(t, response["error"], "invalid format")
 }
 
-func TestGetUserBadges_InvalidUserID(t *testing.T) {
-	handler, _, _ := setupTestHandler()
+func TestGetLeaderboard_Filters(t *testing.T) {
+	handler, _, leaderboardService := setupTestHandler()
 	router := setupRouter(handler)
 
-	req, _ := http.NewRequest("GET", "/api/v1/users/invalid/badges", http.NoBody)
+	for _, path := range []string{
+		"/api/v1/leaderboard?role=ops&exclude_ooo=true",
+		"/api/v1/leaderboard/team-backend?role=ops&exclude_ooo=true",
+		"/api/v1/projects/42/leaderboard?role=ops&exclude_ooo=true",
+	} {
+		leaderboardService.lastFilters = leaderboard.Filters{}
+		req, _ := http.NewRequest("GET", path, http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.Equal(t, leaderboard.Filters{Role: "ops", ExcludeOOO: true}, leaderboardService.lastFilters, path)
+	}
+
+	req, _ := http.NewRequest("GET", "/api/v1/leaderboard?role=manager", http.NoBody)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
@@ -380,33 +599,76 @@ func TestGetUserBadges_InvalidUserID(t *testing.T) {
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Contains(t, response["error"], "invalid user ID")
+	assert.Equal(t, "invalid_role", response["code"])
+
+	req, _ = http.NewRequest("GET", "/api/v1/leaderboard?exclude_ooo=yes", http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "invalid_exclude_ooo", response["code"])
+
+	leaderboardService.lastFilters = leaderboard.Filters{}
+	req, _ = http.NewRequest("GET", "/api/v1/leaderboard/team-backend?include_inactive=true", http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, leaderboard.Filters{IncludeInactive: true}, leaderboardService.lastFilters)
+
+	req, _ = http.NewRequest("GET", "/api/v1/leaderboard/team-backend?include_inactive=1", http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "invalid_include_inactive", response["code"])
 }
 
-func TestGetBadgeCatalog_Success(t *testing.T) {
-	handler, badgeService, _ := setupTestHandler()
+func TestGetLeaderboard_ProjectFilter(t *testing.T) {
+	handler, _, leaderboardService := setupTestHandler()
 	router := setupRouter(handler)
 
-	// Setup mock data
-	badge1 := &models.Badge{
-		Name:        "Speed Demon",
-		Description: "Complete 10 reviews in under 1 hour",
-		Icon:        "speed",
+	for _, path := range []string{
+		"/api/v1/leaderboard?project_id=42",
+		"/api/v1/leaderboard/team-backend?project_id=42",
+	} {
+		leaderboardService.lastFilters = leaderboard.Filters{}
+		req, _ := http.NewRequest("GET", path, http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.Equal(t, leaderboard.Filters{ProjectID: 42}, leaderboardService.lastFilters, path)
 	}
-	badge1.ID = 1
 
-	badge2 := &models.Badge{
-		Name:        "Team Player",
-		Description: "Review 50 MRs from team members",
-		Icon:        "team",
+	for _, projectID := range []string{"abc", "0", "-3", "1.5"} {
+		req, _ := http.NewRequest("GET", "/api/v1/leaderboard?project_id="+projectID, http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, projectID)
+		var response map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "invalid_project_id", response["code"], projectID)
+		assert.Contains(t, response["error"], "expected a positive integer", projectID)
 	}
-	badge2.ID = 2
+}
 
-	badgeService.badges[1] = badge1
-	badgeService.badges[2] = badge2
+func TestGetTeamLeaderboard_Success(t *testing.T) {
+	handler, _, leaderboardService := setupTestHandler()
+	router := setupRouter(handler)
+
+	// Setup mock data
+	entries := []leaderboard.Entry{
+		{Rank: 1, UserID: 1, Username: "alice", Team: "backend", CompletedReviews: 50, EngagementScore: 95.5},
+		{Rank: 2, UserID: 3, Username: "charlie", Team: "backend", CompletedReviews: 40, EngagementScore: 88.2},
+	}
+	leaderboardService.teamLeaderboard["backend:month:completed_reviews"] = entries
 
 	// Make request
-	req, _ := http.NewRequest("GET", "/api/v1/badges", http.NoBody)
+	req, _ := http.NewRequest("GET", "/api/v1/leaderboard/backend?period=month&metric=completed_reviews&limit=10", http.NoBody)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
@@ -417,83 +679,150 @@ func TestGetBadgeCatalog_Success(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 
-	assert.Equal(t, float64(2), response["total_badges"])
+	assert.Equal(t, "backend", response["team"])
+	assert.Equal(t, "month", response["period"])
+	assert.Equal(t, "completed_reviews", response["metric"])
+	assert.Equal(t, float64(2), response["total_entries"])
 }
 
-func TestGetBadgeByID_Success(t *testing.T) {
-	handler, badgeService, _ := setupTestHandler()
+func TestGetTeamLeaderboard_ConfiguredTeamWithoutMetrics(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	handler.SetConfig(&config.Config{Teams: []config.TeamConfig{{Name: "backend"}, {Name: "frontend"}}})
 	router := setupRouter(handler)
 
-	// Setup mock data
-	badge := &models.Badge{
-		Name:        "Speed Demon",
-		Description: "Complete 10 reviews in under 1 hour",
-		Icon:        "speed",
-	}
-	badge.ID = 1
-	badgeService.badges[1] = badge
-
-	// Make request
-	req, _ := http.NewRequest("GET", "/api/v1/badges/1", http.NoBody)
+	req, _ := http.NewRequest("GET", "/api/v1/leaderboard/frontend?period=month", http.NoBody)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
-	// Assert
 	assert.Equal(t, http.StatusOK, w.Code)
 
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.NotNil(t, response["badge"])
+	assert.Equal(t, "frontend", response["team"])
+	assert.Empty(t, response["leaderboard"])
+	assert.Equal(t, float64(0), response["total_entries"])
 }
 
-func TestGetBadgeByID_InvalidID(t *testing.T) {
+func TestGetTeamLeaderboard_UnknownTeam(t *testing.T) {
 	handler, _, _ := setupTestHandler()
+	handler.SetConfig(&config.Config{Teams: []config.TeamConfig{{Name: "backend"}, {Name: "frontend"}}})
 	router := setupRouter(handler)
 
-	req, _ := http.NewRequest("GET", "/api/v1/badges/abc", http.NoBody)
+	req, _ := http.NewRequest("GET", "/api/v1/leaderboard/bakend?period=month", http.NoBody)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusNotFound, w.Code)
 
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Contains(t, response["error"], "invalid badge ID")
+	assert.Equal(t, "not_found", response["code"])
+	assert.Equal(t, "Team not found", response["error"])
 }
 
-func TestGetBadgeByID_NotFound(t *testing.T) {
+func TestGetTeamLeaderboard_ReloadedTeams(t *testing.T) {
 	handler, _, _ := setupTestHandler()
+	handler.SetConfig(&config.Config{Teams: []config.TeamConfig{{Name: "backend"}}})
 	router := setupRouter(handler)
 
-	req, _ := http.NewRequest("GET", "/api/v1/badges/999", http.NoBody)
+	handler.SetTeams([]config.TeamConfig{{Name: "platform"}})
+
+	for path, want := range map[string]int{
+		"/api/v1/leaderboard/platform?period=month": http.StatusOK,
+		"/api/v1/leaderboard/backend?period=month":  http.StatusNotFound,
+	} {
+		req, _ := http.NewRequest("GET", path, http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, want, w.Code, path)
+	}
+}
+
+func TestGetTeamLeaderboard_InvalidParameters(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/leaderboard/backend?period=invalid&metric=completed_reviews", http.NoBody)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Contains(t, response["error"], "Badge not found")
+	assert.Equal(t, "invalid_period", response["code"])
+	assert.Contains(t, response["error"], "invalid period")
 }
 
-func TestGetBadgeHolders_Success(t *testing.T) {
-	handler, badgeService, _ := setupTestHandler()
+func TestGetProjectLeaderboard_IncludesProjectName(t *testing.T) {
+	handler, _, leaderboardService := setupTestHandler()
+	router := setupRouter(handler)
+
+	leaderboardService.projectBoards[42] = &leaderboard.ProjectLeaderboard{
+		ProjectID:   42,
+		ProjectName: "payments-api",
+		ProjectPath: "backend/payments-api",
+		Entries:     []leaderboard.Entry{{Rank: 1, UserID: 1, Username: "alice", CompletedReviews: 12}},
+	}
+
+	req, _ := http.NewRequest("GET", "/api/v1/projects/42/leaderboard", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(42), response["project_id"])
+	assert.Equal(t, "payments-api", response["project_name"])
+	assert.Equal(t, "backend/payments-api", response["project_path"])
+	assert.Equal(t, float64(1), response["total_entries"])
+}
+
+func TestGetProjectLeaderboard_InvalidProjectID(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/projects/abc/leaderboard", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "invalid_project_id", response["code"])
+	assert.Contains(t, response["error"], "invalid project ID")
+}
+
+func TestGetUserStats_Success(t *testing.T) {
+	handler, _, leaderboardService := setupTestHandler()
 	router := setupRouter(handler)
 
 	// Setup mock data
-	holders := []models.User{
-		{Username: "alice", Email: "alice@example.com", Team: "backend"},
-		{Username: "bob", Email: "bob@example.com", Team: "frontend"},
+	stats := &leaderboard.UserStats{
+		UserID:            1,
+		Username:          "alice",
+		Team:              "backend",
+		Period:            "month",
+		TotalReviews:      50,
+		CompletedReviews:  48,
+		AvgTTFR:           3600.0,
+		AvgTimeToApproval: 7200.0,
+		AvgCommentCount:   5.5,
+		EngagementScore:   95.5,
+		GlobalRank:        1,
+		TeamRank:          1,
 	}
-	holders[0].ID = 1
-	holders[1].ID = 2
-	badgeService.badgeHolders[1] = holders
+	leaderboardService.userStats[1] = stats
 
 	// Make request
-	req, _ := http.NewRequest("GET", "/api/v1/badges/1/holders?limit=10", http.NoBody)
+	req, _ := http.NewRequest("GET", "/api/v1/users/1/stats?period=month", http.NoBody)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
@@ -503,17 +832,31 @@ func TestGetBadgeHolders_Success(t *testing.T) {
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
+	assert.NotNil(t, response["stats"])
+}
 
-	assert.Equal(t, float64(1), response["badge_id"])
-	assert.Equal(t, float64(2), response["total_holders"])
-	assert.Equal(t, float64(2), response["limited_to"])
+func TestGetUserStats_InvalidUserID(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/users/abc/stats?period=month", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "invalid_user_id", response["code"])
+	assert.Contains(t, response["error"], "invalid user ID")
 }
 
-func TestGetBadgeHolders_InvalidBadgeID(t *testing.T) {
+func TestGetUserStats_InvalidPeriod(t *testing.T) {
 	handler, _, _ := setupTestHandler()
 	router := setupRouter(handler)
 
-	req, _ := http.NewRequest("GET", "/api/v1/badges/abc/holders", http.NoBody)
+	req, _ := http.NewRequest("GET", "/api/v1/users/1/stats?period=invalid", http.NoBody)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
@@ -522,21 +865,1682 @@ func TestGetBadgeHolders_InvalidBadgeID(t *testing.T) {
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Contains(t, response["error"], "invalid badge ID")
+	assert.Equal(t, "invalid_period", response["code"])
+	assert.Contains(t, response["error"], "invalid period")
 }
 
-func TestGetBadgeHolders_LimitTooHigh(t *testing.T) {
+func TestGetUserStats_UserNotFound(t *testing.T) {
 	handler, _, _ := setupTestHandler()
 	router := setupRouter(handler)
 
-	req, _ := http.NewRequest("GET", "/api/v1/badges/1/holders?limit=2000", http.NoBody)
+	req, _ := http.NewRequest("GET", "/api/v1/users/999/stats?period=month", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "not_found", response["code"])
+	assert.Contains(t, response["error"], "User not found")
+}
+
+func TestGetUserActiveReviews(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	now := time.Now()
+	handler.SetReviewRepository(&mockReviewRepository{
+		assignments: []models.ReviewerAssignment{
+			{MRReviewID: 1, UserID: 1, AssignedAt: now.Add(-2 * time.Hour), MRReview: models.MRReview{MRTitle: "Add login", MRURL: "https://gitlab.example.com/mr/1", Status: models.MRStatusPending}},
+			{MRReviewID: 2, UserID: 1, AssignedAt: now.Add(-26 * time.Hour), MRReview: models.MRReview{MRTitle: "Fix cache", MRURL: "https://gitlab.example.com/mr/2", Status: models.MRStatusInReview}},
+			{MRReviewID: 3, UserID: 1, AssignedAt: now.Add(-72 * time.Hour), MRReview: models.MRReview{MRTitle: "Old change", MRURL: "https://gitlab.example.com/mr/3", Status: models.MRStatusMerged}},
+		},
+	})
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/users/1/active-reviews", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		ActiveReviews []ActiveReview `json:"active_reviews"`
+		Total         int            `json:"total"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, response.Total)
+	if assert.Len(t, response.ActiveReviews, 2) {
+		// Oldest first by default
+		assert.Equal(t, "Fix cache", response.ActiveReviews[0].Title)
+		assert.Equal(t, "Add login", response.ActiveReviews[1].Title)
+		assert.Equal(t, "https://gitlab.example.com/mr/1", response.ActiveReviews[1].URL)
+		assert.InDelta(t, 2, response.ActiveReviews[1].AgeHours, 0.1)
+	}
+}
+
+func TestGetUserActiveReviews_UnknownUser(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	handler.SetReviewRepository(&mockReviewRepository{})
+	handler.SetUserRepository(stubUserRepository{1: {ID: 1, Username: "alice"}})
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/users/99/active-reviews", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// A known user without assignments has an empty queue
+	req, _ = http.NewRequest("GET", "/api/v1/users/1/active-reviews", http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"total":0`)
+}
+
+func TestGetUserActiveReviews_Sort(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	now := time.Now()
+	assignment := func(id uint, hoursAgo int, status string) models.ReviewerAssignment {
+		return models.ReviewerAssignment{
+			MRReviewID: id,
+			UserID:     1,
+			AssignedAt: now.Add(-time.Duration(hoursAgo) * time.Hour),
+			MRReview:   models.MRReview{MRTitle: fmt.Sprintf("MR %d", id), Status: status},
+		}
+	}
+	handler.SetReviewRepository(&mockReviewRepository{
+		assignments: []models.ReviewerAssignment{
+			assignment(1, 5, models.MRStatusApproved),
+			assignment(2, 30, models.MRStatusInReview),
+			assignment(3, 2, models.MRStatusPending),
+			assignment(4, 10, models.MRStatusPending),
+		},
+	})
+	router := setupRouter(handler)
+
+	tests := []struct {
+		query string
+		want  []uint
+	}{
+		{"", []uint{2, 4, 1, 3}},
+		{"?sort=assigned_at&order=desc", []uint{3, 1, 4, 2}},
+		{"?sort=status", []uint{4, 3, 2, 1}},
+	}
+
+	for _, tt := range tests {
+		req, _ := http.NewRequest("GET", "/api/v1/users/1/active-reviews"+tt.query, http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code, tt.query)
+
+		var response struct {
+			ActiveReviews []ActiveReview `json:"active_reviews"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		got := make([]uint, 0, len(response.ActiveReviews))
+		for _, r := range response.ActiveReviews {
+			got = append(got, r.MRReviewID)
+		}
+		assert.Equal(t, tt.want, got, tt.query)
+	}
+
+	req, _ := http.NewRequest("GET", "/api/v1/users/1/active-reviews?sort=priority", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_sort")
+}
+
+func TestGetPersonalBests_Success(t *testing.T) {
+	handler, _, leaderboardService := setupTestHandler()
+	router := setupRouter(handler)
+
+	leaderboardService.personalBests[1] = []models.PersonalBest{
+		{UserID: 1, Metric: models.PersonalBestMetricAvgTTFR, Period: "week", Value: 42},
+		{UserID: 1, Metric: models.PersonalBestMetricEngagementScore, Period: "week", Value: 88.5},
+	}
+
+	req, _ := http.NewRequest("GET", "/api/v1/users/1/personal-bests", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	bests, ok := response["personal_bests"].([]interface{})
+	assert.True(t, ok)
+	assert.Len(t, bests, 2)
+}
+
+func TestGetPersonalBests_InvalidUserID(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/users/abc/personal-bests", http.NoBody)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_user_id"`)
+}
+
+func TestGetUserMetrics_ValidRange(t *testing.T) {
+	handler, _, leaderboardService := setupTestHandler()
+	router := setupRouter(handler)
+
+	userID := uint(1)
+	leaderboardService.userMetrics[1] = []models.ReviewMetrics{
+		{Date: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), UserID: &userID, TotalReviews: 4, CompletedReviews: 3},
+		{Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), UserID: &userID, TotalReviews: 2, CompletedReviews: 2},
+	}
+
+	req, _ := http.NewRequest("GET", "/api/v1/users/1/metrics?start=2025-01-01&end=2025-01-31", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
 
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Contains(t, response["error"], "limit cannot exceed 1000")
+	assert.Equal(t, "2025-01-01", response["start"])
+	assert.Equal(t, "2025-01-31", response["end"])
+	metrics, ok := response["metrics"].([]interface{})
+	assert.True(t, ok)
+	assert.Len(t, metrics, 2)
+	assert.Contains(t, metrics[0], "date")
+}
+
+func TestGetUserMetrics_DefaultRange(t *testing.T) {
+	handler, _, leaderboardService := setupTestHandler()
+	router := setupRouter(handler)
+	leaderboardService.userMetrics[1] = []models.ReviewMetrics{}
+
+	req, _ := http.NewRequest("GET", "/api/v1/users/1/metrics", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	start, end := leaderboardService.metricsRange[0], leaderboardService.metricsRange[1]
+	assert.Equal(t, 30*24*time.Hour, end.Sub(start))
+}
+
+func TestGetUserMetrics_UnknownUser(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/users/999/metrics", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "not_found", response["code"])
+	assert.Contains(t, response["error"], "User not found")
+}
+
+func TestGetUserMetrics_InvalidUserID(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/users/abc/metrics", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "invalid_user_id", response["code"])
+	assert.Contains(t, response["error"], "invalid user ID")
+}
+
+func TestGetUserMetrics_InvalidRange(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	tests := []struct {
+		name      string
+		query     string
+		wantError string
+		wantCode  string
+	}{
+		{name: "start after end", query: "start=2025-02-01&end=2025-01-01", wantError: "start date must not be after end date", wantCode: "invalid_date_range"},
+		{name: "range too large", query: "start=2020-01-01&end=2025-01-01", wantError: "cannot exceed 366 days", wantCode: "invalid_date_range"},
+		{name: "malformed start", query: "start=01/01/2025", wantError: "invalid start date", wantCode: "invalid_start"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/api/v1/users/1/metrics?"+tt.query, http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCode, response["code"])
+			assert.Contains(t, response["error"], tt.wantError)
+		})
+	}
+}
+
+func TestGetUserMetrics_PeriodOrRange(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	tests := []struct {
+		name      string
+		query     string
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{name: "range only", query: "start=2025-01-01&end=2025-01-31", wantStart: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), wantEnd: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "period only", query: "period=week", wantStart: today.AddDate(0, 0, -7), wantEnd: today},
+		{name: "all_time is capped to the export limit", query: "period=all_time", wantStart: today.AddDate(0, 0, -config.DefaultMaxQueryRangeDays), wantEnd: today},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, _, leaderboardService := setupTestHandler()
+			router := setupRouter(handler)
+			leaderboardService.userMetrics[1] = []models.ReviewMetrics{}
+
+			req, _ := http.NewRequest("GET", "/api/v1/users/1/metrics?"+tt.query, http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.wantStart, leaderboardService.metricsRange[0])
+			assert.Equal(t, tt.wantEnd, leaderboardService.metricsRange[1])
+		})
+	}
+}
+
+func TestGetUserMetrics_ConfiguredMaxRange(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	handler, _, leaderboardService := setupTestHandler()
+	handler.SetConfig(&config.Config{Metrics: config.MetricsConfig{MaxQueryRangeDays: 90}})
+	router := setupRouter(handler)
+	leaderboardService.userMetrics[1] = []models.ReviewMetrics{}
+
+	req, _ := http.NewRequest("GET", "/api/v1/users/1/metrics?start=2025-01-01&end=2025-06-01", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "cannot exceed 90 days")
+
+	req, _ = http.NewRequest("GET", "/api/v1/users/1/metrics?period=all_time", http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, today.AddDate(0, 0, -90), leaderboardService.metricsRange[0])
+}
+
+func TestGetUserMetrics_ConflictingPeriodAndRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		wantCode string
+	}{
+		{name: "period with start and end", query: "period=month&start=2025-01-01&end=2025-01-31", wantCode: "conflicting_range"},
+		{name: "period with start", query: "period=month&start=2025-01-01", wantCode: "conflicting_range"},
+		{name: "period with end", query: "period=all_time&end=2025-01-31", wantCode: "conflicting_range"},
+		{name: "unknown period", query: "period=decade", wantCode: "invalid_period"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, _, leaderboardService := setupTestHandler()
+			router := setupRouter(handler)
+
+			req, _ := http.NewRequest("GET", "/api/v1/users/1/metrics?"+tt.query, http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.True(t, leaderboardService.metricsRange[0].IsZero(), "metrics should not be queried")
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCode, response["code"])
+		})
+	}
+}
+
+func TestGetUserBadges_Success(t *testing.T) {
+	handler, badgeService, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	// Setup mock data
+	badge := models.Badge{
+		Name:        "Speed Demon",
+		Description: "Complete 10 reviews in under 1 hour",
+		Icon:        "speed",
+	}
+	badge.ID = 1
+
+	userBadges := []models.UserBadge{
+		{
+			UserID:   1,
+			BadgeID:  1,
+			Badge:    badge,
+			EarnedAt: time.Now(),
+		},
+	}
+	badgeService.userBadges[1] = userBadges
+
+	// Make request
+	req, _ := http.NewRequest("GET", "/api/v1/users/1/badges", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	assert.Equal(t, float64(1), response["user_id"])
+	assert.Equal(t, float64(1), response["total_badges"])
+}
+
+func TestGetUserBadges_InvalidUserID(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/users/invalid/badges", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "invalid_user_id", response["code"])
+	assert.Contains(t, response["error"], "invalid user ID")
+}
+
+func TestGetBadgeCatalog_Success(t *testing.T) {
+	handler, badgeService, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	// Setup mock data
+	badge1 := &models.Badge{
+		Name:        "Speed Demon",
+		Description: "Complete 10 reviews in under 1 hour",
+		Icon:        "speed",
+	}
+	badge1.ID = 1
+
+	badge2 := &models.Badge{
+		Name:        "Team Player",
+		Description: "Review 50 MRs from team members",
+		Icon:        "team",
+	}
+	badge2.ID = 2
+
+	badgeService.badges[1] = badge1
+	badgeService.badges[2] = badge2
+
+	// Make request
+	req, _ := http.NewRequest("GET", "/api/v1/badges", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	assert.Equal(t, float64(2), response["total_badges"])
+}
+
+func TestGetBadgeCatalog_Sorted(t *testing.T) {
+	handler, badgeService, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	badgeService.badges[1] = &models.Badge{ID: 1, Name: "Speed Demon"}
+	badgeService.badges[2] = &models.Badge{ID: 2, Name: "Team Player"}
+	badgeService.badges[3] = &models.Badge{ID: 3, Name: "Bug Hunter"}
+
+	req, _ := http.NewRequest("GET", "/api/v1/badges?sort=name&order=desc", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Badges []models.Badge `json:"badges"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	if assert.Len(t, response.Badges, 3) {
+		assert.Equal(t, "Team Player", response.Badges[0].Name)
+		assert.Equal(t, "Speed Demon", response.Badges[1].Name)
+		assert.Equal(t, "Bug Hunter", response.Badges[2].Name)
+	}
+}
+
+func TestUnknownEnumParameters_Rejected(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	tests := []struct {
+		name     string
+		url      string
+		wantCode string
+	}{
+		{name: "unknown badge sort", url: "/api/v1/badges?sort=rarity", wantCode: "invalid_sort"},
+		{name: "unknown badge order", url: "/api/v1/badges?sort=name&order=random", wantCode: "invalid_order"},
+		{name: "unknown leaderboard metric", url: "/api/v1/leaderboard?metric=karma", wantCode: "invalid_metric"},
+		{name: "unknown leaderboard period", url: "/api/v1/leaderboard?period=decade", wantCode: "invalid_period"},
+		{name: "unknown leaderboard format", url: "/api/v1/leaderboard?format=xml", wantCode: "invalid_format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tt.url, http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCode, response["code"])
+			assert.Contains(t, response["error"], "valid:")
+		})
+	}
+}
+
+func TestGetBadgeByID_Success(t *testing.T) {
+	handler, badgeService, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	// Setup mock data
+	badge := &models.Badge{
+		Name:        "Speed Demon",
+		Description: "Complete 10 reviews in under 1 hour",
+		Icon:        "speed",
+	}
+	badge.ID = 1
+	badgeService.badges[1] = badge
+
+	// Make request
+	req, _ := http.NewRequest("GET", "/api/v1/badges/1", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.NotNil(t, response["badge"])
+}
+
+func TestGetBadgeByID_InvalidID(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/badges/abc", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "invalid_badge_id", response["code"])
+	assert.Contains(t, response["error"], "invalid badge ID")
+}
+
+func TestGetBadgeByID_NotFound(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/badges/999", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "not_found", response["code"])
+	assert.Contains(t, response["error"], "Badge not found")
+}
+
+func TestGetBadgeHolders_Success(t *testing.T) {
+	handler, badgeService, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	// Setup mock data
+	holders := []models.User{
+		{Username: "alice", Email: "alice@example.com", Team: "backend"},
+		{Username: "bob", Email: "bob@example.com", Team: "frontend"},
+	}
+	holders[0].ID = 1
+	holders[1].ID = 2
+	badgeService.badges[1] = &models.Badge{ID: 1, Name: "Speed Demon"}
+	badgeService.badgeHolders[1] = holders
+
+	// Make request
+	req, _ := http.NewRequest("GET", "/api/v1/badges/1/holders?limit=10", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	assert.Equal(t, float64(1), response["badge_id"])
+	assert.Equal(t, float64(2), response["total_holders"])
+	assert.Equal(t, float64(2), response["limited_to"])
+}
+
+func TestGetBadgeHolders_Pagination(t *testing.T) {
+	handler, badgeService, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	holders := make([]models.User, 5)
+	for i := range holders {
+		holders[i].ID = uint(i + 1)
+		holders[i].Username = fmt.Sprintf("user%d", i+1)
+	}
+	badgeService.badges[1] = &models.Badge{ID: 1, Name: "Speed Demon"}
+	badgeService.badgeHolders[1] = holders
+
+	req, _ := http.NewRequest("GET", "/api/v1/badges/1/holders?limit=2&offset=4", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	assert.Equal(t, float64(5), response["total_holders"])
+	assert.Equal(t, float64(1), response["limited_to"])
+	assert.Equal(t, float64(4), response["offset"])
+	assert.Equal(t, float64(2), response["limit"])
+}
+
+func TestGetBadgeHolders_InvalidOffset(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/badges/1/holders?offset=-1", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "invalid_offset", response["code"])
+	assert.Contains(t, response["error"], "offset cannot be negative")
+}
+
+func TestGetBadgeHolders_InvalidBadgeID(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/badges/abc/holders", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "invalid_badge_id", response["code"])
+	assert.Contains(t, response["error"], "invalid badge ID")
+}
+
+func TestGetBadgeHolders_LimitTooHigh(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/badges/1/holders?limit=2000", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "invalid_limit", response["code"])
+	assert.Contains(t, response["error"], "limit cannot exceed 1000")
+}
+
+func TestGetRecentlyAwardedBadges_ValidSince(t *testing.T) {
+	handler, badgeService, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	now := time.Now().UTC()
+	badgeService.badges[1] = &models.Badge{ID: 1, Name: "speed_demon", Icon: "⚡"}
+	badgeService.recentAwards = []models.UserBadge{
+		{
+			UserID:   1,
+			User:     models.User{ID: 1, Username: "alice"},
+			BadgeID:  1,
+			Badge:    models.Badge{ID: 1, Name: "speed_demon", Icon: "⚡"},
+			EarnedAt: now.Add(-2 * time.Hour),
+		},
+		{
+			UserID:   2,
+			User:     models.User{ID: 2, Username: "bob"},
+			BadgeID:  1,
+			Badge:    models.Badge{ID: 1, Name: "speed_demon", Icon: "⚡"},
+			EarnedAt: now.Add(-72 * time.Hour),
+		},
+	}
+
+	req, _ := http.NewRequest("GET", "/api/v1/badges/recent?since=48h", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), response["total_awards"])
+
+	awards := response["awards"].([]interface{})
+	award := awards[0].(map[string]interface{})
+	assert.Equal(t, "alice", award["user"].(map[string]interface{})["username"])
+	assert.Equal(t, "speed_demon", award["badge"].(map[string]interface{})["name"])
+	assert.NotEmpty(t, award["earned_at"])
+
+	// Days suffix is accepted too
+	req, _ = http.NewRequest("GET", "/api/v1/badges/recent?since=7d", http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.WithinDuration(t, now.Add(-7*24*time.Hour), badgeService.lastSince, time.Minute)
+}
+
+func TestGetRecentlyAwardedBadges_MissingSince(t *testing.T) {
+	handler, badgeService, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/badges/recent", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.WithinDuration(t, time.Now().UTC().Add(-24*time.Hour), badgeService.lastSince, time.Minute)
+}
+
+func TestGetRecentlyAwardedBadges_InvalidSince(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	tests := []struct {
+		name    string
+		since   string
+		wantErr string
+	}{
+		{name: "malformed", since: "yesterday", wantErr: "invalid since parameter"},
+		{name: "malformed days", since: "xd", wantErr: "invalid since parameter"},
+		{name: "negative", since: "-1h", wantErr: "since must be a positive duration"},
+		{name: "too long", since: "31d", wantErr: "since cannot exceed 30 days"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/api/v1/badges/recent?since="+tt.since, http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Equal(t, "invalid_since", response["code"])
+			assert.Contains(t, response["error"], tt.wantErr)
+		})
+	}
+}
+
+func TestGetBadgeHolders_EmailRedacted(t *testing.T) {
+	handler, badgeService, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	holders := []models.User{{ID: 1, Username: "alice", Email: "alice@example.com", Team: "backend"}}
+	badgeService.badges[1] = &models.Badge{ID: 1, Name: "Speed Demon"}
+	badgeService.badgeHolders[1] = holders
+
+	req, _ := http.NewRequest("GET", "/api/v1/badges/1/holders", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "alice@example.com")
+	assert.NotContains(t, w.Body.String(), `"email"`)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	holder := response["holders"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "alice", holder["username"])
+
+	// Non-admins cannot opt in to emails
+	req, _ = http.NewRequest("GET", "/api/v1/badges/1/holders?include_email=true", http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"forbidden"`)
+	assert.NotContains(t, w.Body.String(), "alice@example.com")
+}
+
+func TestGetBadgeHolders_AdminIncludeEmail(t *testing.T) {
+	handler, badgeService, _ := setupTestHandler()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/badges/:id/holders", middleware.AdminIdentity("secret"), handler.GetBadgeHolders)
+
+	badgeService.badgeHolders[1] = []models.User{{ID: 1, Username: "alice", Email: "alice@example.com"}}
+
+	req, _ := http.NewRequest("GET", "/api/v1/badges/1/holders?include_email=true", http.NoBody)
+	req.Header.Set(middleware.AdminTokenHeader, "wrong")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"forbidden"`)
+
+	req, _ = http.NewRequest("GET", "/api/v1/badges/1/holders?include_email=true", http.NoBody)
+	req.Header.Set(middleware.AdminTokenHeader, "secret")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "alice@example.com")
+}
+
+// Repositories backing a real leaderboard service, with the badge repository down
+
+type stubMetricsRepository struct {
+	metrics []models.ReviewMetrics
+}
+
+func (m *stubMetricsRepository) GetByDateRange(startDate, endDate time.Time, filters map[string]interface{}) ([]models.ReviewMetrics, error) {
+	return m.metrics, nil
+}
+
+func (m *stubMetricsRepository) GetMetricsByUser(userID uint, startDate, endDate time.Time) ([]models.ReviewMetrics, error) {
+	var result []models.ReviewMetrics
+	for _, metric := range m.metrics {
+		if metric.UserID != nil && *metric.UserID == userID {
+			result = append(result, metric)
+		}
+	}
+	return result, nil
+}
+
+type failingBadgeRepository struct{}
+
+func (failingBadgeRepository) GetUserBadgeCount(userID uint) (int64, error) {
+	return 0, errors.New("badge database unavailable")
+}
+
+func (failingBadgeRepository) GetUserBadges(userID uint) ([]models.UserBadge, error) {
+	return nil, errors.New("badge database unavailable")
+}
+
+type stubUserRepository map[uint]*models.User
+
+func (m stubUserRepository) GetByID(id uint) (*models.User, error) {
+	user, ok := m[id]
+	if !ok {
+		return nil, fmt.Errorf("user %d: %w", id, repository.ErrNotFound)
+	}
+	return user, nil
+}
+
+func setupDegradedBadgesRouter() *gin.Engine {
+	aliceID := uint(1)
+	bobID := uint(2)
+	aliceScore := 9.0
+	bobScore := 7.0
+	metricsRepo := &stubMetricsRepository{metrics: []models.ReviewMetrics{
+		{UserID: &aliceID, Team: "backend", CompletedReviews: 10, EngagementScore: &aliceScore},
+		{UserID: &bobID, Team: "backend", CompletedReviews: 5, EngagementScore: &bobScore},
+	}}
+	users := stubUserRepository{
+		aliceID: {ID: aliceID, Username: "alice", Team: "backend"},
+		bobID:   {ID: bobID, Username: "bob", Team: "backend"},
+	}
+	log := logger.New("error", "json", "stdout")
+
+	leaderboardService := leaderboard.NewServiceWithInterfaces(&config.Config{}, metricsRepo, failingBadgeRepository{}, users, nil, log)
+	return setupRouter(NewHandlerWithInterfaces(newMockBadgeService(), leaderboardService, log))
+}
+
+func TestGetGlobalLeaderboard_BadgesUnavailable(t *testing.T) {
+	router := setupDegradedBadgesRouter()
+
+	req, _ := http.NewRequest("GET", "/api/v1/leaderboard?period=month&metric=completed_reviews", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+	var response struct {
+		Leaderboard []leaderboard.Entry `json:"leaderboard"`
+		Warnings    []string            `json:"warnings"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []string{leaderboard.WarningBadges}, response.Warnings)
+	assert.Len(t, response.Leaderboard, 2)
+	for _, entry := range response.Leaderboard {
+		assert.Equal(t, 0, entry.BadgeCount, "badge count for %s", entry.Username)
+	}
+}
+
+func TestGetUserStats_BadgesUnavailable(t *testing.T) {
+	router := setupDegradedBadgesRouter()
+
+	req, _ := http.NewRequest("GET", "/api/v1/users/2/stats?period=month", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Stats    leaderboard.UserStats `json:"stats"`
+		Warnings []string              `json:"warnings"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []string{leaderboard.WarningBadges}, response.Warnings)
+	assert.Empty(t, response.Stats.Badges)
+	assert.Equal(t, 2, response.Stats.GlobalRank)
+}
+
+func TestGetGlobalLeaderboard_NoWarningsWhenComplete(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/leaderboard", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+	assert.NotContains(t, w.Body.String(), "warnings")
+}
+
+func TestGetGlobalLeaderboard_FocusTeam(t *testing.T) {
+	handler, _, leaderboardService := setupTestHandler()
+	router := setupRouter(handler)
+
+	leaderboardService.globalLeaderboard["month:completed_reviews"] = []leaderboard.Entry{
+		{Rank: 1, UserID: 1, Username: "alice", Team: "team-a", CompletedReviews: 15},
+	}
+	leaderboardService.focusTeam = &leaderboard.TeamSummary{
+		Team:             "team-b",
+		Members:          2,
+		CompletedReviews: 15,
+		Points:           180,
+		BestRank:         2,
+		TopMember:        "bob",
+	}
+
+	req, _ := http.NewRequest("GET", "/api/v1/leaderboard?period=month&metric=completed_reviews&limit=1", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Leaderboard []LeaderboardEntryResponse `json:"leaderboard"`
+		FocusTeam   *leaderboard.TeamSummary   `json:"focus_team"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	if assert.Len(t, response.Leaderboard, 1) {
+		assert.Equal(t, "alice", response.Leaderboard[0].Username)
+		assert.Equal(t, 1, response.Leaderboard[0].Rank)
+	}
+	assert.Equal(t, leaderboardService.focusTeam, response.FocusTeam)
+}
+
+func TestGetGlobalLeaderboard_NoFocusTeam(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/leaderboard", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "focus_team")
+}
+
+func TestGetGlobalLeaderboard_DurationUnit(t *testing.T) {
+	handler, _, leaderboardService := setupTestHandler()
+	router := setupRouter(handler)
+
+	// Leaderboard entries report AvgTTFR in minutes
+	leaderboardService.globalLeaderboard["month:avg_ttfr"] = []leaderboard.Entry{
+		{Rank: 1, UserID: 1, Username: "alice", AvgTTFR: 90},
+	}
+
+	tests := []struct {
+		unit         string
+		wantUnit     string
+		wantTTFR     float64
+		wantTTFRText string
+	}{
+		{unit: "", wantUnit: "minutes", wantTTFR: 90},
+		{unit: "minutes", wantUnit: "minutes", wantTTFR: 90},
+		{unit: "seconds", wantUnit: "seconds", wantTTFR: 5400},
+		{unit: "human", wantUnit: "minutes", wantTTFR: 90, wantTTFRText: "1h 30m"},
+	}
+	for _, tt := range tests {
+		t.Run("unit="+tt.unit, func(t *testing.T) {
+			path := "/api/v1/leaderboard?period=month&metric=avg_ttfr"
+			if tt.unit != "" {
+				path += "&duration_unit=" + tt.unit
+			}
+			req, _ := http.NewRequest("GET", path, http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response struct {
+				DurationUnit string                     `json:"duration_unit"`
+				Leaderboard  []LeaderboardEntryResponse `json:"leaderboard"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantUnit, response.DurationUnit)
+			if assert.Len(t, response.Leaderboard, 1) {
+				assert.Equal(t, tt.wantTTFR, response.Leaderboard[0].AvgTTFR)
+				assert.Equal(t, tt.wantTTFRText, response.Leaderboard[0].AvgTTFRHuman)
+			}
+		})
+	}
+}
+
+func TestGetUserStats_DurationUnit(t *testing.T) {
+	handler, _, leaderboardService := setupTestHandler()
+	router := setupRouter(handler)
+
+	ttfrDelta := -15.0
+	leaderboardService.userStats[1] = &leaderboard.UserStats{UserID: 1, Username: "alice", AvgTTFR: 45, AvgTimeToApproval: 1500, AvgTTFRDelta: &ttfrDelta}
+
+	req, _ := http.NewRequest("GET", "/api/v1/users/1/stats?duration_unit=seconds", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		DurationUnit string            `json:"duration_unit"`
+		Stats        UserStatsResponse `json:"stats"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "seconds", response.DurationUnit)
+	assert.Equal(t, 2700.0, response.Stats.AvgTTFR)
+	assert.Equal(t, 90000.0, response.Stats.AvgTimeToApproval)
+	if assert.NotNil(t, response.Stats.AvgTTFRDelta) {
+		assert.Equal(t, -900.0, *response.Stats.AvgTTFRDelta)
+	}
+	assert.Empty(t, response.Stats.AvgTTFRHuman)
+
+	req, _ = http.NewRequest("GET", "/api/v1/users/1/stats?duration_unit=human", http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	response.Stats = UserStatsResponse{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "minutes", response.DurationUnit)
+	assert.Equal(t, 45.0, response.Stats.AvgTTFR)
+	assert.Equal(t, "45m", response.Stats.AvgTTFRHuman)
+	assert.Equal(t, "1d 1h", response.Stats.AvgTimeToApprovalHuman)
+
+	// The stored stats are not modified by the conversion
+	assert.Equal(t, 45.0, leaderboardService.userStats[1].AvgTTFR)
+	assert.Equal(t, -15.0, *leaderboardService.userStats[1].AvgTTFRDelta)
+}
+
+func TestDurationUnit_Invalid(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/leaderboard?duration_unit=hours", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_duration_unit"`)
+}
+
+func TestHumanDuration(t *testing.T) {
+	tests := []struct {
+		minutes float64
+		want    string
+	}{
+		{0, "0m"},
+		{-5, "0m"},
+		{0.4, "0m"},
+		{45, "45m"},
+		{60, "1h"},
+		{90, "1h 30m"},
+		{1440, "1d"},
+		{1500, "1d 1h"},
+		{2 * 1440, "2d"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, humanDuration(tt.minutes), "humanDuration(%v)", tt.minutes)
+	}
+}
+
+// stubBadgeRepository serves a fixed badge catalog for the real badge service.
+type stubBadgeRepository struct {
+	badges  []models.Badge
+	earned  map[uint]bool  // badge ID -> earned by the requested user
+	holders map[uint]int64 // badge ID -> holder count
+}
+
+func (m *stubBadgeRepository) GetAll() ([]models.Badge, error) { return m.badges, nil }
+func (m *stubBadgeRepository) GetByID(id uint) (*models.Badge, error) {
+	return nil, errors.New("not implemented")
+}
+func (m *stubBadgeRepository) HasUserEarnedBadge(userID, badgeID uint) (bool, error) {
+	return m.earned[badgeID], nil
+}
+func (m *stubBadgeRepository) Create(badge *models.Badge) error           { return nil }
+func (m *stubBadgeRepository) Update(badge *models.Badge) error           { return nil }
+func (m *stubBadgeRepository) AwardBadge(userID, badgeID uint) error      { return nil }
+func (m *stubBadgeRepository) RevokeUserBadge(userID, badgeID uint) error { return nil }
+func (m *stubBadgeRepository) GetUserBadges(userID uint) ([]models.UserBadge, error) {
+	return nil, nil
+}
+func (m *stubBadgeRepository) GetUsersWithBadge(badgeID uint) ([]models.User, error) {
+	return nil, nil
+}
+func (m *stubBadgeRepository) GetUsersWithBadgePaged(badgeID uint, offset, limit int) ([]models.User, int64, error) {
+	return nil, 0, nil
+}
+func (m *stubBadgeRepository) GetBadgeHoldersCount(badgeID uint) (int64, error) {
+	return m.holders[badgeID], nil
+}
+func (m *stubBadgeRepository) GetHolderCounts() (map[uint]int64, error) { return m.holders, nil }
+func (m *stubBadgeRepository) GetRecentlyAwardedBadges(since time.Time) ([]models.UserBadge, error) {
+	return nil, nil
+}
+
+type stubBadgeUserRepository struct{ stubUserRepository }
+
+func (stubBadgeUserRepository) List(team, role string) ([]models.User, error) { return nil, nil }
+
+func (stubBadgeUserRepository) Count() (int64, error) { return 0, nil }
+
+func TestGetUserBadgeProgress_MetricThreshold(t *testing.T) {
+	aliceID := uint(1)
+	metricsRepo := &stubMetricsRepository{metrics: []models.ReviewMetrics{
+		{UserID: &aliceID, Team: "backend", CompletedReviews: 4},
+		{UserID: &aliceID, Team: "backend", CompletedReviews: 2},
+	}}
+	badgeRepo := &stubBadgeRepository{
+		badges: []models.Badge{
+			{ID: 1, Name: "Reviewer", Criteria: json.RawMessage(`{"metric":"completed_reviews","operator":">=","value":10}`)},
+			{ID: 2, Name: "Veteran", Criteria: json.RawMessage(`{"metric":"completed_reviews","operator":">=","value":100}`)},
+			{ID: 3, Name: "First Review", Criteria: json.RawMessage(`{"metric":"completed_reviews","operator":">=","value":1}`)},
+		},
+		earned: map[uint]bool{3: true},
+	}
+	log := logger.New("error", "json", "stdout")
+	badgeService := badges.NewServiceWithInterfaces(badgeRepo, metricsRepo, nil, stubBadgeUserRepository{}, log)
+	router := setupRouter(NewHandlerWithInterfaces(badgeService, newMockLeaderboardService(), log))
+
+	req, _ := http.NewRequest("GET", "/api/v1/users/1/badges/progress", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		UserID      uint `json:"user_id"`
+		TotalBadges int  `json:"total_badges"`
+		Progress    []struct {
+			Badge      models.Badge `json:"badge"`
+			Current    float64      `json:"current"`
+			Target     float64      `json:"target"`
+			Operator   string       `json:"operator"`
+			Percent    float64      `json:"percent"`
+			Applicable bool         `json:"applicable"`
+		} `json:"progress"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, uint(1), response.UserID)
+
+	// The earned badge is left out; the rest are sorted closest first
+	assert.Equal(t, 2, response.TotalBadges)
+	if assert.Len(t, response.Progress, 2) {
+		assert.Equal(t, "Reviewer", response.Progress[0].Badge.Name)
+		assert.Equal(t, 6.0, response.Progress[0].Current)
+		assert.Equal(t, 10.0, response.Progress[0].Target)
+		assert.Equal(t, ">=", response.Progress[0].Operator)
+		assert.Equal(t, 60.0, response.Progress[0].Percent)
+		assert.True(t, response.Progress[0].Applicable)
+
+		assert.Equal(t, "Veteran", response.Progress[1].Badge.Name)
+		assert.Equal(t, 6.0, response.Progress[1].Percent)
+	}
+}
+
+func TestGetUserBadgeProgress_InvalidUserID(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/users/invalid/badges/progress", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_user_id"`)
+}
+
+func TestGetBadgeCatalog_HiddenBadges(t *testing.T) {
+	badgeRepo := &stubBadgeRepository{
+		badges: []models.Badge{
+			{ID: 1, Name: "Speed Demon"},
+			{ID: 2, Name: "Secret Santa", Hidden: true},
+			{ID: 3, Name: "Legend", MinHolders: 5},
+		},
+		holders: map[uint]int64{3: 1},
+	}
+	log := logger.New("error", "json", "stdout")
+	badgeService := badges.NewServiceWithInterfaces(badgeRepo, &stubMetricsRepository{}, nil, stubBadgeUserRepository{}, log)
+	handler := NewHandlerWithInterfaces(badgeService, newMockLeaderboardService(), log)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/badges", middleware.AdminIdentity("secret"), handler.GetBadgeCatalog)
+
+	catalogNames := func(adminToken string) []string {
+		req, _ := http.NewRequest("GET", "/api/v1/badges?sort=id", http.NoBody)
+		if adminToken != "" {
+			req.Header.Set(middleware.AdminTokenHeader, adminToken)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Badges []models.Badge `json:"badges"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		names := make([]string, 0, len(response.Badges))
+		for _, badge := range response.Badges {
+			names = append(names, badge.Name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"Speed Demon"}, catalogNames(""))
+	assert.Equal(t, []string{"Speed Demon"}, catalogNames("wrong"))
+	assert.Equal(t, []string{"Speed Demon", "Secret Santa", "Legend"}, catalogNames("secret"))
+}
+
+// setupBadgeVisibilityRouter serves the badge endpoints behind AdminIdentity, with a listed
+// badge (1), a hidden badge (2) and a badge below its minimum holders (3).
+func setupBadgeVisibilityRouter() (*gin.Engine, *mockBadgeService) {
+	handler, badgeService, _ := setupTestHandler()
+	badgeService.badges[1] = &models.Badge{ID: 1, Name: "Speed Demon"}
+	badgeService.badges[2] = &models.Badge{ID: 2, Name: "Secret Santa", Hidden: true}
+	badgeService.badges[3] = &models.Badge{ID: 3, Name: "Legend", MinHolders: 5}
+	for id := uint(1); id <= 3; id++ {
+		badgeService.badgeHolders[id] = []models.User{{ID: 1, Username: "alice"}}
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	api := router.Group("/api/v1", middleware.AdminIdentity("secret"))
+	api.GET("/users/:id/badges/progress", handler.GetUserBadgeProgress)
+	api.GET("/badges/recent", handler.GetRecentlyAwardedBadges)
+	api.GET("/badges/:id", handler.GetBadgeByID)
+	api.GET("/badges/:id/holders", handler.GetBadgeHolders)
+	return router, badgeService
+}
+
+func serveAs(router *gin.Engine, url, adminToken string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", url, http.NoBody)
+	if adminToken != "" {
+		req.Header.Set(middleware.AdminTokenHeader, adminToken)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetBadgeByID_NotListedBadges(t *testing.T) {
+	router, _ := setupBadgeVisibilityRouter()
+
+	assert.Equal(t, http.StatusOK, serveAs(router, "/api/v1/badges/1", "").Code)
+	for _, id := range []string{"2", "3"} {
+		w := serveAs(router, "/api/v1/badges/"+id, "")
+		assert.Equal(t, http.StatusNotFound, w.Code, "badge %s", id)
+		assert.Contains(t, w.Body.String(), `"code":"not_found"`)
+
+		assert.Equal(t, http.StatusOK, serveAs(router, "/api/v1/badges/"+id, "secret").Code, "admin badge %s", id)
+	}
+}
+
+func TestGetBadgeHolders_NotListedBadges(t *testing.T) {
+	router, _ := setupBadgeVisibilityRouter()
+
+	assert.Equal(t, http.StatusOK, serveAs(router, "/api/v1/badges/1/holders", "").Code)
+	for _, id := range []string{"2", "3"} {
+		w := serveAs(router, "/api/v1/badges/"+id+"/holders", "")
+		assert.Equal(t, http.StatusNotFound, w.Code, "badge %s", id)
+		assert.NotContains(t, w.Body.String(), "alice")
+
+		assert.Equal(t, http.StatusOK, serveAs(router, "/api/v1/badges/"+id+"/holders", "secret").Code, "admin badge %s", id)
+	}
+}
+
+func TestGetRecentlyAwardedBadges_NotListedBadges(t *testing.T) {
+	router, badgeService := setupBadgeVisibilityRouter()
+
+	now := time.Now().UTC()
+	for id := uint(1); id <= 3; id++ {
+		badgeService.recentAwards = append(badgeService.recentAwards, models.UserBadge{
+			UserID:   1,
+			User:     models.User{ID: 1, Username: "alice"},
+			BadgeID:  id,
+			Badge:    *badgeService.badges[id],
+			EarnedAt: now.Add(-time.Hour),
+		})
+	}
+
+	awardedNames := func(adminToken string) []string {
+		w := serveAs(router, "/api/v1/badges/recent?since=24h", adminToken)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Awards []struct {
+				Badge models.Badge `json:"badge"`
+			} `json:"awards"`
+			TotalAwards int `json:"total_awards"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, len(response.Awards), response.TotalAwards)
+		names := make([]string, 0, len(response.Awards))
+		for _, award := range response.Awards {
+			names = append(names, award.Badge.Name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"Speed Demon"}, awardedNames(""))
+	assert.Equal(t, []string{"Speed Demon", "Secret Santa", "Legend"}, awardedNames("secret"))
+}
+
+func TestGetUserBadgeProgress_NotListedBadges(t *testing.T) {
+	router, badgeService := setupBadgeVisibilityRouter()
+
+	badgeService.progress = map[uint][]badges.BadgeProgress{1: {
+		{Badge: *badgeService.badges[1], Current: 1, Target: 10, Operator: ">=", Percent: 10, Applicable: true},
+		{Badge: *badgeService.badges[2], Current: 1, Target: 2, Operator: ">=", Percent: 50, Applicable: true},
+		{Badge: *badgeService.badges[3], Current: 1, Target: 4, Operator: ">=", Percent: 25, Applicable: true},
+	}}
+
+	progressNames := func(adminToken string) []string {
+		w := serveAs(router, "/api/v1/users/1/badges/progress", adminToken)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Progress []struct {
+				Badge models.Badge `json:"badge"`
+			} `json:"progress"`
+			TotalBadges int `json:"total_badges"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, len(response.Progress), response.TotalBadges)
+		names := make([]string, 0, len(response.Progress))
+		for _, item := range response.Progress {
+			names = append(names, item.Badge.Name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"Speed Demon"}, progressNames(""))
+	assert.Equal(t, []string{"Speed Demon", "Secret Santa", "Legend"}, progressNames("secret"))
+}
+
+func TestGetStatsByRole(t *testing.T) {
+	handler, _, leaderboardService := setupTestHandler()
+	router := setupRouter(handler)
+
+	leaderboardService.roleStats["week"] = []leaderboard.RoleStats{
+		{Role: models.ReviewerRoleCodeowner, Reviewers: 2, Assignments: 4, CompletedReviews: 3, CompletionRate: 0.75, AvgTTFR: 90},
+		{Role: models.ReviewerRoleTeamMember, Reviewers: 3, Assignments: 6, CompletedReviews: 6, CompletionRate: 1, AvgTTFR: 30},
+	}
+
+	req, _ := http.NewRequest("GET", "/api/v1/stats/by-role?period=week&duration_unit=human", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Period       string              `json:"period"`
+		DurationUnit string              `json:"duration_unit"`
+		Roles        []RoleStatsResponse `json:"roles"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "week", response.Period)
+	assert.Equal(t, "minutes", response.DurationUnit)
+	if assert.Len(t, response.Roles, 2) {
+		assert.Equal(t, models.ReviewerRoleCodeowner, response.Roles[0].Role)
+		assert.Equal(t, 0.75, response.Roles[0].CompletionRate)
+		assert.Equal(t, "1h 30m", response.Roles[0].AvgTTFRHuman)
+		assert.Equal(t, models.ReviewerRoleTeamMember, response.Roles[1].Role)
+		assert.Equal(t, 6, response.Roles[1].CompletedReviews)
+	}
+}
+
+func TestGetStatsByRole_InvalidPeriod(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/stats/by-role?period=decade", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_period"`)
+}
+
+func TestGetStatsByRole_Unavailable(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/stats/by-role", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"unavailable"`)
+}
+
+func TestGetGlobalLeaderboard_ApprovalAndCommentLengthMetrics(t *testing.T) {
+	handler, _, leaderboardService := setupTestHandler()
+	router := setupRouter(handler)
+
+	leaderboardService.globalLeaderboard["month:avg_time_to_approval"] = []leaderboard.Entry{
+		{Rank: 1, UserID: 1, Username: "alice", AvgTimeToApproval: 90},
+	}
+	leaderboardService.globalLeaderboard["month:avg_comment_length"] = []leaderboard.Entry{
+		{Rank: 1, UserID: 2, Username: "bob", AvgCommentLength: 120.5},
+	}
+
+	req, _ := http.NewRequest("GET", "/api/v1/leaderboard?period=month&metric=avg_time_to_approval&duration_unit=seconds", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Metric      string                     `json:"metric"`
+		Leaderboard []LeaderboardEntryResponse `json:"leaderboard"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "avg_time_to_approval", response.Metric)
+	if assert.Len(t, response.Leaderboard, 1) {
+		assert.Equal(t, 5400.0, response.Leaderboard[0].AvgTimeToApproval)
+	}
+
+	req, _ = http.NewRequest("GET", "/api/v1/leaderboard?period=month&metric=avg_comment_length", http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	response.Leaderboard = nil
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "avg_comment_length", response.Metric)
+	if assert.Len(t, response.Leaderboard, 1) {
+		assert.Equal(t, 120.5, response.Leaderboard[0].AvgCommentLength)
+	}
+}
+
+func TestGetStatsSummary(t *testing.T) {
+	handler, _, leaderboardService := setupTestHandler()
+	router := setupRouter(handler)
+
+	leaderboardService.summaries["month"] = &leaderboard.Summary{
+		TotalReviews:     40,
+		CompletedReviews: 30,
+		AvgTTFR:          75,
+		ActiveReviewers:  6,
+		TopTeams: []leaderboard.TeamTotal{
+			{Team: "team-backend", CompletedReviews: 18},
+			{Team: "team-frontend", CompletedReviews: 12},
+		},
+	}
+
+	req, _ := http.NewRequest("GET", "/api/v1/stats/summary?period=month&duration_unit=seconds", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Period       string          `json:"period"`
+		DurationUnit string          `json:"duration_unit"`
+		Summary      SummaryResponse `json:"summary"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "month", response.Period)
+	assert.Equal(t, "seconds", response.DurationUnit)
+	assert.Equal(t, 40, response.Summary.TotalReviews)
+	assert.Equal(t, 30, response.Summary.CompletedReviews)
+	assert.Equal(t, 4500.0, response.Summary.AvgTTFR)
+	assert.Equal(t, 6, response.Summary.ActiveReviewers)
+	if assert.Len(t, response.Summary.TopTeams, 2) {
+		assert.Equal(t, "team-backend", response.Summary.TopTeams[0].Team)
+	}
+}
+
+func TestGetStatsSummary_InvalidPeriod(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/stats/summary?period=fortnight", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_period")
+}
+
+func TestGetGlobalLeaderboard_AsOf(t *testing.T) {
+	handler, _, leaderboardService := setupTestHandler()
+	router := setupRouter(handler)
+	leaderboardService.globalLeaderboard["month:completed_reviews"] = []leaderboard.Entry{
+		{Rank: 1, UserID: 1, Username: "alice", CompletedReviews: 12},
+	}
+
+	tests := []struct {
+		asOf string
+		want time.Time
+	}{
+		{asOf: "2024-03-01", want: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{asOf: "2024-03-01T12:30:00Z", want: time.Date(2024, time.March, 1, 12, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.asOf, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/api/v1/leaderboard?period=month&as_of="+tt.asOf, http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.True(t, leaderboardService.lastFilters.AsOf.Equal(tt.want), "as_of passed as %v", leaderboardService.lastFilters.AsOf)
+
+			var response struct {
+				AsOf *time.Time `json:"as_of"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			if assert.NotNil(t, response.AsOf) {
+				assert.True(t, response.AsOf.Equal(tt.want))
+			}
+		})
+	}
+
+	// Without as_of the board is live
+	req, _ := http.NewRequest("GET", "/api/v1/leaderboard?period=month", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, leaderboardService.lastFilters.AsOf.IsZero())
+	assert.Contains(t, w.Body.String(), `"as_of":null`)
+}
+
+func TestGetGlobalLeaderboard_InvalidAsOf(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	future := time.Now().AddDate(0, 1, 0).Format("2006-01-02")
+	for _, asOf := range []string{"yesterday", "03/01/2024", future} {
+		t.Run(asOf, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/api/v1/leaderboard?as_of="+asOf, http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), "invalid_as_of")
+		})
+	}
+}
+
+func TestCompareTeams(t *testing.T) {
+	handler, _, leaderboardService := setupTestHandler()
+	router := setupRouter(handler)
+
+	leaderboardService.teamComparisons["month:avg_ttfr"] = []leaderboard.TeamEntry{
+		{Team: "team-platform", AvgTTFR: 30, Rank: 1},
+		{Team: "team-backend", AvgTTFR: 90, Rank: 2},
+	}
+
+	req, _ := http.NewRequest("GET", "/api/v1/teams/compare?period=month&metric=avg_ttfr&duration_unit=human", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Metric string              `json:"metric"`
+		Teams  []TeamEntryResponse `json:"teams"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "avg_ttfr", response.Metric)
+	if assert.Len(t, response.Teams, 2) {
+		assert.Equal(t, "team-platform", response.Teams[0].Team)
+		assert.Equal(t, "30m", response.Teams[0].AvgTTFRHuman)
+		assert.Equal(t, "1h 30m", response.Teams[1].AvgTTFRHuman)
+	}
+}
+
+func TestCompareTeams_InvalidMetric(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	req, _ := http.NewRequest("GET", "/api/v1/teams/compare?metric=karma", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_metric")
+}
+
+func TestGetReviewEngagement(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+
+	assignedAt := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	firstCommentAt := assignedAt.Add(30 * time.Minute) // Fast response
+	handler.SetReviewRepository(&mockReviewRepository{reviews: []models.MRReview{{
+		GitLabProjectID: 42,
+		GitLabMRIID:     7,
+		MRURL:           "https://gitlab.example.com/group/app/-/merge_requests/7",
+		Assignments: []models.ReviewerAssignment{{
+			UserID:         1,
+			User:           models.User{ID: 1, Username: "alice"},
+			Role:           models.ReviewerRoleCodeowner,
+			AssignedAt:     assignedAt,
+			FirstCommentAt: &firstCommentAt,
+			CommentCount:   4,
+			CommentLength:  350,
+		}},
+	}}})
+
+	req, _ := http.NewRequest("GET", "/api/v1/reviews/42/7/engagement", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Reviewers []ReviewerEngagement `json:"reviewers"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if !assert.Len(t, response.Reviewers, 1) {
+		return
+	}
+
+	alice := response.Reviewers[0]
+	assert.Equal(t, "alice", alice.Username)
+	assert.Equal(t, 40.0, alice.CommentPoints) // 4 comments * 10
+	assert.Equal(t, 3.5, alice.LengthPoints)   // 350 characters / 100
+	assert.Equal(t, 10.0, alice.ResponseBonus) // First comment within 1 hour
+	assert.Equal(t, alice.CommentPoints+alice.LengthPoints+alice.ResponseBonus, alice.Score)
+
+	// The breakdown adds up to the score used for metrics
+	assignment := models.ReviewerAssignment{AssignedAt: assignedAt, FirstCommentAt: &firstCommentAt, CommentCount: 4, CommentLength: 350}
+	assert.Equal(t, metrics.CalculateEngagementScore(&assignment, nil), alice.Score)
+}
+
+func TestGetReviewEngagement_NotFound(t *testing.T) {
+	handler, _, _ := setupTestHandler()
+	router := setupRouter(handler)
+	handler.SetReviewRepository(&mockReviewRepository{})
+
+	req, _ := http.NewRequest("GET", "/api/v1/reviews/42/7/engagement", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"not_found"`)
+
+	req, _ = http.NewRequest("GET", "/api/v1/reviews/abc/7/engagement", http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_project_id"`)
 }
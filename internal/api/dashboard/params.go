@@ -0,0 +1,186 @@
+package dashboard
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/badges"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/leaderboard"
+)
+
+// Sort orders accepted by the order query parameter.
+const (
+	orderAsc  = "asc"
+	orderDesc = "desc"
+)
+
+// Allowlists for enumerated query parameters. Every new sort, order or
+// filter parameter should be validated against one of these.
+var (
+	validPeriods     = []string{"day", "week", "month", "year", "all_time"}
+	validMetrics     = []string{"completed_reviews", "engagement_score", "avg_ttfr", "avg_time_to_approval", "avg_comment_count", "avg_comment_length", "points", "streak"}
+	validFormats     = []string{formatJSON, formatCSV}
+	validOrders      = []string{orderAsc, orderDesc}
+	validBadgeSorts  = []string{"id", "name", "created_at"}
+	defaultBadgeSort = "id"
+	// Active reviews sort oldest first by default; "status" puts MRs nobody has started on first
+	validActiveReviewSorts  = []string{"assigned_at", "status"}
+	defaultActiveReviewSort = "assigned_at"
+	validRoles              = []string{"", models.RoleDev, models.RoleOps} // Empty means all roles
+	validBooleans           = []string{"", "true", "false"}
+	validDurationUnits      = []string{durationUnitMinutes, durationUnitSeconds, durationUnitHuman}
+)
+
+// periodDays is the number of days each named period covers when resolved to a date range.
+// all_time is left out: it spans the widest range GET /users/:id/metrics can export.
+var periodDays = map[string]int{
+	"day":   1,
+	"week":  7,
+	"month": 30,
+	"year":  365,
+}
+
+// codedError is a parameter error carrying a machine-readable code.
+type codedError interface {
+	error
+	Code() string
+}
+
+// conflictingParamsError reports query parameters that cannot be combined.
+type conflictingParamsError struct {
+	code    string
+	message string
+}
+
+func (e *conflictingParamsError) Error() string {
+	return e.message
+}
+
+// Code returns the machine-readable error code, e.g. "conflicting_range".
+func (e *conflictingParamsError) Code() string {
+	return e.code
+}
+
+// errConflictingRange is returned when a named period is combined with an explicit start or end.
+var errConflictingRange = &conflictingParamsError{
+	code:    "conflicting_range",
+	message: "period cannot be combined with start or end; use either a named period or an explicit date range",
+}
+
+// invalidParamError reports a query parameter value outside its allowlist,
+// or that does not match the expected form when there is no allowlist.
+type invalidParamError struct {
+	param    string
+	value    string
+	allowed  []string
+	expected string // Describes valid values for parameters without an allowlist
+}
+
+func (e *invalidParamError) Error() string {
+	if e.expected != "" {
+		return fmt.Sprintf("invalid %s: %s (expected %s)", e.param, e.value, e.expected)
+	}
+	return fmt.Sprintf("invalid %s: %s (valid: %s)", e.param, e.value, strings.Join(e.allowed, ", "))
+}
+
+// Code returns the machine-readable error code, e.g. "invalid_sort".
+func (e *invalidParamError) Code() string {
+	return "invalid_" + e.param
+}
+
+// validateAllowed checks that value is one of the allowed values for param.
+func validateAllowed(param, value string, allowed []string) error {
+	if !slices.Contains(allowed, value) {
+		return &invalidParamError{param: param, value: value, allowed: allowed}
+	}
+	return nil
+}
+
+// parsePositiveInt parses an optional positive integer query parameter; empty values return 0.
+func parsePositiveInt(param, value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, &invalidParamError{param: param, value: value, expected: "a positive integer"}
+	}
+	return n, nil
+}
+
+// parseAsOf parses the optional as_of reference time, as RFC3339 or a YYYY-MM-DD date
+// (midnight UTC). Empty values return the zero time, meaning now.
+func parseAsOf(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	asOf, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		asOf, err = time.Parse(dateLayout, value)
+	}
+	if err != nil {
+		return time.Time{}, &invalidParamError{param: "as_of", value: value, expected: "an RFC3339 time or YYYY-MM-DD date"}
+	}
+	if asOf.After(time.Now()) {
+		return time.Time{}, &invalidParamError{param: "as_of", value: value, expected: "a time in the past"}
+	}
+	return asOf, nil
+}
+
+// asOfValue returns the reference time of a leaderboard response, or nil for a live board.
+func asOfValue(filters leaderboard.Filters) *time.Time {
+	if filters.AsOf.IsZero() {
+		return nil
+	}
+	asOf := filters.AsOf.UTC()
+	return &asOf
+}
+
+// sortBadges orders catalog badges by a validated sort field and order.
+func sortBadges(entries []badges.CatalogEntry, sortBy, order string) {
+	slices.SortStableFunc(entries, func(a, b badges.CatalogEntry) int {
+		var c int
+		switch sortBy {
+		case "name":
+			c = strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		case "created_at":
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		default:
+			c = cmp.Compare(a.ID, b.ID)
+		}
+		if order == orderDesc {
+			return -c
+		}
+		return c
+	})
+}
+
+// activeReviewStatusPriority ranks MR statuses by how much they need a reviewer's attention.
+var activeReviewStatusPriority = map[string]int{
+	models.MRStatusPending:  0,
+	models.MRStatusInReview: 1,
+	models.MRStatusApproved: 2,
+}
+
+// sortActiveReviews orders a review queue by a validated sort field and order.
+// Ties are broken oldest assignment first.
+func sortActiveReviews(reviews []ActiveReview, sortBy, order string) {
+	slices.SortStableFunc(reviews, func(a, b ActiveReview) int {
+		var c int
+		if sortBy == "status" {
+			c = cmp.Compare(activeReviewStatusPriority[a.Status], activeReviewStatusPriority[b.Status])
+		}
+		if c == 0 {
+			c = a.AssignedAt.Compare(b.AssignedAt)
+		}
+		if order == orderDesc {
+			return -c
+		}
+		return c
+	})
+}
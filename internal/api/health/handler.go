@@ -4,6 +4,7 @@ package health
 import (
 	"context"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -13,10 +14,20 @@ import (
 	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
 )
 
+// DatabaseChecker interface for database connectivity checks.
+type DatabaseChecker interface {
+	Health() error
+}
+
+// CacheChecker interface for cache connectivity checks.
+type CacheChecker interface {
+	Health(ctx context.Context) error
+}
+
 // Handler handles health check endpoints
 type Handler struct {
-	db    *repository.DB
-	cache *cache.Cache
+	db    DatabaseChecker
+	cache CacheChecker
 	log   *logger.Logger
 }
 
@@ -29,6 +40,15 @@ func NewHandler(db *repository.DB, cacheClient *cache.Cache, log *logger.Logger)
 	}
 }
 
+// NewHandlerWithInterfaces creates a new health check handler with interface dependencies (useful for testing).
+func NewHandlerWithInterfaces(db DatabaseChecker, cacheClient CacheChecker, log *logger.Logger) *Handler {
+	return &Handler{
+		db:    db,
+		cache: cacheClient,
+		log:   log,
+	}
+}
+
 // HealthResponse represents the health check response
 type HealthResponse struct {
 	Status  string            `json:"status"`
@@ -83,24 +103,29 @@ func (h *Handler) HandleHealth(c *gin.Context) {
 	c.JSON(statusCode, response)
 }
 
-// HandleReadiness checks if the service is ready to accept requests
+// HandleReadiness checks if the service is ready to accept requests.
+// Every dependency is checked so a 503 lists all that failed.
 func (h *Handler) HandleReadiness(c *gin.Context) {
 	// Check critical dependencies
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
+	failed := []string{}
 	if err := h.db.Health(); err != nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"ready": false,
-			"error": "database not ready",
-		})
-		return
+		h.log.Warn().Err(err).Msg("Database readiness check failed")
+		failed = append(failed, "database")
 	}
 
 	if err := h.cache.Health(ctx); err != nil {
+		h.log.Warn().Err(err).Msg("Cache readiness check failed")
+		failed = append(failed, "cache")
+	}
+
+	if len(failed) > 0 {
 		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"ready": false,
-			"error": "cache not ready",
+			"ready":  false,
+			"failed": failed,
+			"error":  strings.Join(failed, ", ") + " not ready",
 		})
 		return
 	}
@@ -0,0 +1,86 @@
+//nolint:noctx // Test file uses http.NewRequest for simplicity
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+	"github.com/aimd54/gitlab-reviewer-roulette/test/mocks"
+)
+
+type mockDatabase struct {
+	err error
+}
+
+func (m *mockDatabase) Health() error {
+	return m.err
+}
+
+type failingCache struct{}
+
+func (failingCache) Health(_ context.Context) error {
+	return errors.New("connection refused")
+}
+
+func setupRouter(db DatabaseChecker, cacheClient CacheChecker) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewHandlerWithInterfaces(db, cacheClient, logger.New("error", "json", "stdout"))
+
+	router := gin.New()
+	router.GET("/healthz", handler.HandleLiveness)
+	router.GET("/readyz", handler.HandleReadiness)
+	return router
+}
+
+func get(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", path, http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestHealthz_AlwaysOK(t *testing.T) {
+	router := setupRouter(&mockDatabase{err: errors.New("down")}, failingCache{})
+
+	w := get(router, "/healthz")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestReadyz(t *testing.T) {
+	tests := []struct {
+		name       string
+		db         DatabaseChecker
+		cache      CacheChecker
+		wantStatus int
+		wantFailed []interface{}
+	}{
+		{"all healthy", &mockDatabase{}, mocks.NewMockCache(), http.StatusOK, nil},
+		{"database down", &mockDatabase{err: errors.New("down")}, mocks.NewMockCache(), http.StatusServiceUnavailable, []interface{}{"database"}},
+		{"cache down", &mockDatabase{}, failingCache{}, http.StatusServiceUnavailable, []interface{}{"cache"}},
+		{"both down", &mockDatabase{err: errors.New("down")}, failingCache{}, http.StatusServiceUnavailable, []interface{}{"database", "cache"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(setupRouter(tt.db, tt.cache), "/readyz")
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			var response map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantFailed == nil, response["ready"])
+			if tt.wantFailed != nil {
+				assert.Equal(t, tt.wantFailed, response["failed"])
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminTokenHeader is the request header carrying the admin token.
+const AdminTokenHeader = "X-Admin-Token"
+
+// adminContextKey is the Gin context key set for authenticated admin requests.
+const adminContextKey = "is_admin"
+
+// AdminIdentity marks requests carrying a valid admin token as admin.
+// Requests without a token are passed through unchanged; an empty token disables admin access.
+func AdminIdentity(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token != "" && validAdminToken(c.GetHeader(AdminTokenHeader), token) {
+			c.Set(adminContextKey, true)
+		}
+		c.Next()
+	}
+}
+
+// IsAdmin reports whether the request was authenticated as admin by AdminIdentity.
+func IsAdmin(c *gin.Context) bool {
+	return c.GetBool(adminContextKey)
+}
+
+// RequireAdmin rejects requests not authenticated as admin by AdminIdentity.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !IsAdmin(c) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":     "admin access required",
+				"timestamp": time.Now().UTC(),
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// validAdminToken compares tokens in constant time.
+func validAdminToken(provided, expected string) bool {
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(expected)) == 1
+}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+)
+
+// DefaultWarmLeaderboards is warmed when no combinations are configured.
+var DefaultWarmLeaderboards = []config.LeaderboardWarmEntry{
+	{Period: "month", Metric: "engagement_score"},
+}
+
+// CacheWarmer pre-computes popular leaderboard responses into a ResponseCache,
+// so the first request after aggregation is served from memory.
+type CacheWarmer struct {
+	cache        *ResponseCache
+	combinations []config.LeaderboardWarmEntry
+	mu           sync.RWMutex
+	uris         []string
+	log          *logger.Logger
+}
+
+// NewCacheWarmer creates a warmer for the global leaderboard and every configured
+// team's leaderboard, for each configured period/metric combination.
+func NewCacheWarmer(cache *ResponseCache, cfg *config.Config, log *logger.Logger) *CacheWarmer {
+	combinations := cfg.ResponseCache.Warm.Leaderboards
+	if len(combinations) == 0 {
+		combinations = DefaultWarmLeaderboards
+	}
+
+	return &CacheWarmer{
+		cache:        cache,
+		combinations: combinations,
+		uris:         warmURIs(combinations, cfg.Teams),
+		log:          log,
+	}
+}
+
+// SetTeams replaces the teams whose leaderboards are warmed, e.g. after a config reload.
+func (w *CacheWarmer) SetTeams(teams []config.TeamConfig) {
+	uris := warmURIs(w.combinations, teams)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.uris = uris
+}
+
+// warmURIs lists the global and team leaderboard URIs to warm for each combination.
+func warmURIs(combinations []config.LeaderboardWarmEntry, teams []config.TeamConfig) []string {
+	var uris []string
+	for _, combination := range combinations {
+		query := url.Values{}
+		if combination.Period != "" {
+			query.Set("period", combination.Period)
+		}
+		if combination.Metric != "" {
+			query.Set("metric", combination.Metric)
+		}
+
+		uris = append(uris, leaderboardURI("/api/v1/leaderboard", query))
+		for _, team := range teams {
+			uris = append(uris, leaderboardURI("/api/v1/leaderboard/"+url.PathEscape(team.Name), query))
+		}
+	}
+	return uris
+}
+
+// leaderboardURI builds a request URI with query parameters in cache key order.
+func leaderboardURI(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
+
+// Warm recomputes every warmed leaderboard and stores it in the cache.
+// Returns the number of entries warmed; failures are logged and summarized in the error.
+func (w *CacheWarmer) Warm(ctx context.Context) (int, error) {
+	w.mu.RLock()
+	uris := w.uris
+	w.mu.RUnlock()
+
+	var errs []error
+	warmed := 0
+
+	for _, uri := range uris {
+		if err := ctx.Err(); err != nil {
+			return warmed, err
+		}
+
+		if err := w.cache.warm(ctx, uri); err != nil {
+			w.log.Warn().Err(err).Str("uri", uri).Msg("Failed to warm response cache entry")
+			errs = append(errs, err)
+			continue
+		}
+		warmed++
+	}
+
+	w.log.Info().
+		Int("warmed", warmed).
+		Int("failed", len(errs)).
+		Msg("Response cache warming completed")
+
+	if len(errs) > 0 {
+		return warmed, fmt.Errorf("failed to warm %d of %d entries: %w", len(errs), len(uris), errors.Join(errs...))
+	}
+	return warmed, nil
+}
+
+// warm replays a GET request for uri through the handler, forcing the
+// middleware to recompute and store the response.
+func (rc *ResponseCache) warm(ctx context.Context, uri string) error {
+	req, err := http.NewRequestWithContext(context.WithValue(ctx, refreshKey{}, true), http.MethodGet, uri, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("invalid warm URI %s: %w", uri, err)
+	}
+
+	w := &discardWriter{header: make(http.Header), status: http.StatusOK}
+	rc.handler.ServeHTTP(w, req)
+
+	if w.status != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", uri, w.status)
+	}
+	if !rc.has(cacheKey(req.URL)) {
+		return fmt.Errorf("%s is not a cached route", uri)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+//nolint:noctx // Test file uses http.NewRequest for simplicity
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+)
+
+func setupWarmedRouter(cfg *config.Config) (*gin.Engine, *ResponseCache, *atomic.Int32) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	rc := NewResponseCache(&cfg.ResponseCache, router, logger.New("error", "json", "stdout"))
+
+	calls := &atomic.Int32{}
+	handler := func(c *gin.Context) {
+		n := calls.Add(1)
+		c.String(http.StatusOK, fmt.Sprintf("%s %s %s version-%d", c.Param("team"), c.Query("period"), c.Query("metric"), n))
+	}
+	api := router.Group("/api/v1")
+	api.Use(rc.Middleware())
+	api.GET("/leaderboard", handler)
+	api.GET("/leaderboard/:team", handler)
+
+	return router, rc, calls
+}
+
+func TestCacheWarmer_Warm(t *testing.T) {
+	cfg := &config.Config{
+		Teams: []config.TeamConfig{{Name: "backend"}, {Name: "front end"}},
+		ResponseCache: config.ResponseCacheConfig{
+			Enabled: true,
+			Routes: []config.ResponseCacheRoute{
+				{Path: "/api/v1/leaderboard", TTL: 60},
+				{Path: "/api/v1/leaderboard/:team", TTL: 60},
+			},
+		},
+	}
+	router, rc, calls := setupWarmedRouter(cfg)
+
+	warmer := NewCacheWarmer(rc, cfg, logger.New("error", "json", "stdout"))
+	warmed, err := warmer.Warm(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, warmed)
+	assert.Equal(t, int32(3), calls.Load())
+
+	// The default combination is cached for the global and every team leaderboard
+	for _, key := range []string{
+		"/api/v1/leaderboard?metric=engagement_score&period=month",
+		"/api/v1/leaderboard/backend?metric=engagement_score&period=month",
+		"/api/v1/leaderboard/front%20end?metric=engagement_score&period=month",
+	} {
+		assert.True(t, rc.has(key), "expected cache entry for %s", key)
+	}
+
+	// Requests are served from the warmed entries, whatever the query parameter order
+	w := doGet(router, "/api/v1/leaderboard/backend?period=month&metric=engagement_score")
+	assert.Equal(t, "HIT", w.Header().Get(CacheStatusHeader))
+	assert.Equal(t, "backend month engagement_score version-2", w.Body.String())
+
+	w = doGet(router, "/api/v1/leaderboard?metric=engagement_score&period=month")
+	assert.Equal(t, "HIT", w.Header().Get(CacheStatusHeader))
+	assert.Equal(t, int32(3), calls.Load(), "warmed responses must not be recomputed")
+}
+
+func TestCacheWarmer_SetTeams(t *testing.T) {
+	cfg := &config.Config{
+		Teams: []config.TeamConfig{{Name: "backend"}},
+		ResponseCache: config.ResponseCacheConfig{
+			Enabled: true,
+			Routes: []config.ResponseCacheRoute{
+				{Path: "/api/v1/leaderboard", TTL: 60},
+				{Path: "/api/v1/leaderboard/:team", TTL: 60},
+			},
+		},
+	}
+	_, rc, _ := setupWarmedRouter(cfg)
+
+	warmer := NewCacheWarmer(rc, cfg, logger.New("error", "json", "stdout"))
+	warmer.SetTeams([]config.TeamConfig{{Name: "platform"}})
+
+	warmed, err := warmer.Warm(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, warmed)
+	assert.True(t, rc.has("/api/v1/leaderboard/platform?metric=engagement_score&period=month"))
+	assert.False(t, rc.has("/api/v1/leaderboard/backend?metric=engagement_score&period=month"))
+}
+
+func TestCacheWarmer_ConfiguredCombinations(t *testing.T) {
+	cfg := &config.Config{
+		Teams: []config.TeamConfig{{Name: "backend"}},
+		ResponseCache: config.ResponseCacheConfig{
+			Enabled: true,
+			Routes: []config.ResponseCacheRoute{
+				{Path: "/api/v1/leaderboard", TTL: 60},
+				{Path: "/api/v1/leaderboard/:team", TTL: 60},
+			},
+			Warm: config.CacheWarmConfig{
+				Leaderboards: []config.LeaderboardWarmEntry{
+					{Period: "week", Metric: "completed_reviews"},
+					{Period: "all_time", Metric: "points"},
+				},
+			},
+		},
+	}
+	_, rc, _ := setupWarmedRouter(cfg)
+
+	warmed, err := NewCacheWarmer(rc, cfg, logger.New("error", "json", "stdout")).Warm(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 4, warmed)
+	assert.True(t, rc.has("/api/v1/leaderboard/backend?metric=completed_reviews&period=week"))
+	assert.True(t, rc.has("/api/v1/leaderboard?metric=points&period=all_time"))
+	assert.False(t, rc.has("/api/v1/leaderboard?metric=engagement_score&period=month"))
+}
+
+func TestCacheWarmer_UncachedRoute(t *testing.T) {
+	cfg := &config.Config{
+		Teams: []config.TeamConfig{{Name: "backend"}},
+		ResponseCache: config.ResponseCacheConfig{
+			Enabled: true,
+			Routes:  []config.ResponseCacheRoute{{Path: "/api/v1/leaderboard", TTL: 60}},
+		},
+	}
+	_, rc, _ := setupWarmedRouter(cfg)
+
+	warmed, err := NewCacheWarmer(rc, cfg, logger.New("error", "json", "stdout")).Warm(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 1, warmed)
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import "strings"
+
+// ETagMatches reports whether an If-None-Match header value matches etag, using the
+// weak comparison of RFC 9110: a W/ prefix is ignored and "*" matches any ETag.
+func ETagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" || etag == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
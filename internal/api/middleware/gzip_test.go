@@ -0,0 +1,85 @@
+//nolint:noctx // Test file uses http.NewRequest for simplicity
+package middleware
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupGzipRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	api := router.Group("/api/v1")
+	api.Use(Gzip(DefaultGzipMinSize))
+	api.GET("/leaderboard", func(c *gin.Context) {
+		entries := make([]gin.H, 0, 100)
+		for i := 0; i < 100; i++ {
+			entries = append(entries, gin.H{"rank": i + 1, "username": "reviewer"})
+		}
+		c.JSON(http.StatusOK, gin.H{"leaderboard": entries})
+	})
+	api.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	return router
+}
+
+func gzipRequest(router *gin.Engine, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", path, http.NoBody)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGzip_CompressesLargeResponses(t *testing.T) {
+	router := setupGzipRouter()
+
+	w := gzipRequest(router, "/api/v1/leaderboard", "gzip, deflate")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	reader, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	var response struct {
+		Leaderboard []struct {
+			Rank     int    `json:"rank"`
+			Username string `json:"username"`
+		} `json:"leaderboard"`
+	}
+	require.NoError(t, json.Unmarshal(body, &response))
+	assert.Len(t, response.Leaderboard, 100)
+	assert.Equal(t, 100, response.Leaderboard[99].Rank)
+}
+
+func TestGzip_SkipsSmallResponsesAndOtherClients(t *testing.T) {
+	router := setupGzipRouter()
+
+	small := gzipRequest(router, "/api/v1/small", "gzip")
+	assert.Empty(t, small.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", small.Header().Get("Vary"))
+	assert.JSONEq(t, `{"status":"ok"}`, small.Body.String())
+
+	for _, acceptEncoding := range []string{"", "deflate", "gzip;q=0"} {
+		w := gzipRequest(router, "/api/v1/leaderboard", acceptEncoding)
+		assert.Empty(t, w.Header().Get("Content-Encoding"), acceptEncoding)
+		assert.True(t, strings.HasPrefix(w.Body.String(), `{"leaderboard":[`), acceptEncoding)
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/metrics"
+)
+
+// unmatchedRoute labels requests that matched no route, so unknown paths share one series.
+const unmatchedRoute = "unmatched"
+
+// RequestMetrics records the count and latency of every request in Prometheus,
+// labelled with the matched route template rather than the raw path.
+func RequestMetrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		metrics.RecordAPIRequest(route, c.Writer.Status(), time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,43 @@
+//nolint:noctx // Test file uses http.NewRequest for simplicity
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/metrics"
+)
+
+func TestRequestMetrics(t *testing.T) {
+	metrics.APIRequestsTotal.Reset()
+	metrics.APIRequestDurationSeconds.Reset()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestMetrics())
+	router.GET("/api/v1/leaderboard/:team", func(c *gin.Context) {
+		if c.Param("team") == "unknown" {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		c.String(http.StatusOK, "ok")
+	})
+
+	for _, path := range []string{"/api/v1/leaderboard/backend", "/api/v1/leaderboard/frontend", "/api/v1/leaderboard/unknown", "/nope"} {
+		req, _ := http.NewRequest("GET", path, http.NoBody)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// Requests are labelled with the route template, not the raw path
+	assert.Equal(t, float64(2), testutil.ToFloat64(metrics.APIRequestsTotal.WithLabelValues("/api/v1/leaderboard/:team", "200")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.APIRequestsTotal.WithLabelValues("/api/v1/leaderboard/:team", "404")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.APIRequestsTotal.WithLabelValues(unmatchedRoute, "404")))
+	assert.Equal(t, 3, testutil.CollectAndCount(metrics.APIRequestsTotal))
+
+	assert.Equal(t, 2, testutil.CollectAndCount(metrics.APIRequestDurationSeconds))
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"container/list"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+// maxRateLimitClients is the number of client IPs tracked at once; beyond it the least
+// recently seen client is forgotten.
+const maxRateLimitClients = 10000
+
+// RateLimiter throttles requests per client IP with a token bucket: each client
+// may burst up to burst requests, refilled at rps requests per second.
+type RateLimiter struct {
+	rps        rate.Limit
+	burst      int
+	maxClients int
+	clients    map[string]*list.Element // values are *rateLimitClient
+	recent     *list.List               // most recently seen client first
+	mu         sync.Mutex
+	now        func() time.Time
+}
+
+// rateLimitClient is the token bucket of one client IP.
+type rateLimitClient struct {
+	ip      string
+	limiter *rate.Limiter
+}
+
+// NewRateLimiter creates a per-IP rate limiter. A non-positive rps or burst disables limiting.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		rps:        rate.Limit(rps),
+		burst:      burst,
+		maxClients: maxRateLimitClients,
+		clients:    make(map[string]*list.Element),
+		recent:     list.New(),
+		now:        time.Now,
+	}
+}
+
+// Enabled reports whether requests are limited.
+func (rl *RateLimiter) Enabled() bool {
+	return rl.rps > 0 && rl.burst > 0
+}
+
+// Middleware returns a Gin middleware answering 429 Too Many Requests once a client
+// exceeds its rate. Admin requests and internal cache refreshes are not limited.
+func (rl *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !rl.Enabled() || IsAdmin(c) || c.Request.Context().Value(refreshKey{}) != nil {
+			c.Next()
+			return
+		}
+
+		if retryAfter, ok := rl.allow(c.ClientIP()); !ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"code":      "rate_limited",
+				"error":     "Too many requests, slow down",
+				"timestamp": time.Now().UTC(),
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// allow takes a token from the client's bucket. When none is left it returns
+// how long until the next one is available.
+func (rl *RateLimiter) allow(ip string) (time.Duration, bool) {
+	now := rl.now()
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	limiter := rl.limiterFor(ip)
+	reservation := limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return delay, false
+	}
+	return 0, true
+}
+
+// limiterFor returns the client's bucket, marking it most recently seen. A new client
+// evicts the least recently seen one once maxClients are tracked, so memory stays bounded
+// whatever the number of distinct IPs. Must be called with mu held.
+func (rl *RateLimiter) limiterFor(ip string) *rate.Limiter {
+	if elem, ok := rl.clients[ip]; ok {
+		rl.recent.MoveToFront(elem)
+		return elem.Value.(*rateLimitClient).limiter
+	}
+
+	if rl.recent.Len() >= rl.maxClients {
+		oldest := rl.recent.Back()
+		rl.recent.Remove(oldest)
+		delete(rl.clients, oldest.Value.(*rateLimitClient).ip)
+	}
+
+	client := &rateLimitClient{ip: ip, limiter: rate.NewLimiter(rl.rps, rl.burst)}
+	rl.clients[ip] = rl.recent.PushFront(client)
+	return client.limiter
+}
@@ -0,0 +1,137 @@
+//nolint:noctx // Test file uses http.NewRequest for simplicity
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRateLimitedRouter(rps float64, burst int) (*gin.Engine, *fakeClock) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	rl := NewRateLimiter(rps, burst)
+	rl.now = clock.Now
+
+	api := router.Group("/api/v1")
+	api.Use(AdminIdentity("admin-secret"), rl.Middleware())
+	api.GET("/leaderboard", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	return router, clock
+}
+
+func requestFrom(router *gin.Engine, ip string, header ...string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/api/v1/leaderboard", http.NoBody)
+	req.RemoteAddr = ip + ":12345"
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimiter_RejectsBeyondBurst(t *testing.T) {
+	router, clock := setupRateLimitedRouter(1, 3)
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, requestFrom(router, "10.0.0.1").Code, "request %d", i+1)
+	}
+
+	w := requestFrom(router, "10.0.0.1")
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "rate_limited", body["code"])
+	assert.NotEmpty(t, body["error"])
+
+	// Other clients have their own bucket
+	assert.Equal(t, http.StatusOK, requestFrom(router, "10.0.0.2").Code)
+
+	// A token is refilled after one second
+	clock.Advance(time.Second)
+	assert.Equal(t, http.StatusOK, requestFrom(router, "10.0.0.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, requestFrom(router, "10.0.0.1").Code)
+
+	// The whole burst is available again once the bucket refilled
+	clock.Advance(3 * time.Second)
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, requestFrom(router, "10.0.0.1").Code, "request %d after refill", i+1)
+	}
+}
+
+func TestRateLimiter_AdminNotLimited(t *testing.T) {
+	router, _ := setupRateLimitedRouter(1, 1)
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, requestFrom(router, "10.0.0.1", AdminTokenHeader, "admin-secret").Code)
+	}
+}
+
+func TestRateLimiter_Disabled(t *testing.T) {
+	router, _ := setupRateLimitedRouter(0, 0)
+
+	for i := 0; i < 50; i++ {
+		assert.Equal(t, http.StatusOK, requestFrom(router, "10.0.0.1").Code)
+	}
+}
+
+func TestRateLimiter_EvictsLeastRecentlySeenClient(t *testing.T) {
+	rl := NewRateLimiter(1, 1)
+	rl.maxClients = 2
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	rl.now = clock.Now
+
+	_, ok := rl.allow("10.0.0.1")
+	require.True(t, ok)
+	_, ok = rl.allow("10.0.0.2")
+	require.True(t, ok)
+
+	// 10.0.0.1 is seen again, so 10.0.0.2 is the least recently seen
+	_, ok = rl.allow("10.0.0.1")
+	assert.False(t, ok)
+
+	_, ok = rl.allow("10.0.0.3")
+	require.True(t, ok)
+	assert.Len(t, rl.clients, 2)
+	assert.Equal(t, rl.recent.Len(), len(rl.clients))
+	assert.NotContains(t, rl.clients, "10.0.0.2")
+
+	// The still tracked client keeps its exhausted bucket
+	_, ok = rl.allow("10.0.0.1")
+	assert.False(t, ok)
+}
+
+func TestRateLimiter_TrustedProxies(t *testing.T) {
+	newRouter := func(proxies []string) *gin.Engine {
+		router, _ := setupRateLimitedRouter(1, 1)
+		require.NoError(t, router.SetTrustedProxies(proxies))
+		return router
+	}
+
+	t.Run("forwarded header ignored from untrusted peers", func(t *testing.T) {
+		router := newRouter(nil)
+		assert.Equal(t, http.StatusOK, requestFrom(router, "10.0.0.1", "X-Forwarded-For", "203.0.113.1").Code)
+		// A spoofed header does not get a fresh bucket
+		assert.Equal(t, http.StatusTooManyRequests, requestFrom(router, "10.0.0.1", "X-Forwarded-For", "203.0.113.2").Code)
+	})
+
+	t.Run("forwarded header honored from trusted proxies", func(t *testing.T) {
+		router := newRouter([]string{"10.0.0.0/8"})
+		assert.Equal(t, http.StatusOK, requestFrom(router, "10.0.0.1", "X-Forwarded-For", "203.0.113.1").Code)
+		// Clients behind the same proxy have their own bucket
+		assert.Equal(t, http.StatusOK, requestFrom(router, "10.0.0.1", "X-Forwarded-For", "203.0.113.2").Code)
+		assert.Equal(t, http.StatusTooManyRequests, requestFrom(router, "10.0.0.1", "X-Forwarded-For", "203.0.113.1").Code)
+	})
+}
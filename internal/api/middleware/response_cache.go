@@ -0,0 +1,249 @@
+// Package middleware provides HTTP middleware shared by the API handlers.
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+)
+
+// CacheStatusHeader reports how a response was served: HIT, STALE or MISS.
+const CacheStatusHeader = "X-Cache"
+
+// maxCacheEntries bounds the number of cached responses kept in memory.
+const maxCacheEntries = 1000
+
+// refreshKey marks internal background refresh requests.
+type refreshKey struct{}
+
+// routePolicy holds the cache timings for a route.
+type routePolicy struct {
+	ttl   time.Duration
+	stale time.Duration
+}
+
+// cacheEntry is a stored response.
+type cacheEntry struct {
+	status      int
+	contentType string
+	etag        string
+	body        []byte
+	storedAt    time.Time
+}
+
+// ResponseCache is an in-memory HTTP response cache with stale-while-revalidate.
+// Fresh responses are served from memory; stale ones are served immediately
+// while a single background request refreshes the entry.
+type ResponseCache struct {
+	handler    http.Handler
+	policies   map[string]routePolicy
+	entries    map[string]*cacheEntry
+	refreshing map[string]bool
+	mu         sync.Mutex
+	now        func() time.Time
+	log        *logger.Logger
+}
+
+// NewResponseCache creates a response cache for the configured routes.
+// handler is used to replay requests when refreshing stale entries, usually the Gin engine.
+func NewResponseCache(cfg *config.ResponseCacheConfig, handler http.Handler, log *logger.Logger) *ResponseCache {
+	policies := make(map[string]routePolicy)
+	if cfg.Enabled {
+		for _, route := range cfg.Routes {
+			if route.Path == "" || route.TTL <= 0 {
+				continue
+			}
+			policies[route.Path] = routePolicy{
+				ttl:   time.Duration(route.TTL) * time.Second,
+				stale: time.Duration(route.StaleWindow) * time.Second,
+			}
+		}
+	}
+
+	return &ResponseCache{
+		handler:    handler,
+		policies:   policies,
+		entries:    make(map[string]*cacheEntry),
+		refreshing: make(map[string]bool),
+		now:        time.Now,
+		log:        log,
+	}
+}
+
+// Middleware returns a Gin middleware caching GET responses of configured routes.
+func (rc *ResponseCache) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Admin responses may include private fields and must never be shared
+		policy, ok := rc.policies[c.FullPath()]
+		if !ok || c.Request.Method != http.MethodGet || c.GetHeader(AdminTokenHeader) != "" {
+			c.Next()
+			return
+		}
+
+		key := cacheKey(c.Request.URL)
+
+		// Background refreshes always run the handler and store the result
+		if c.Request.Context().Value(refreshKey{}) == nil {
+			if entry, fresh := rc.lookup(key, policy); entry != nil {
+				status := "HIT"
+				if !fresh {
+					status = "STALE"
+					rc.refresh(key, c.Request)
+				}
+				c.Header(CacheStatusHeader, status)
+				if entry.etag != "" {
+					c.Header("ETag", entry.etag)
+					if ETagMatches(c.GetHeader("If-None-Match"), entry.etag) {
+						c.AbortWithStatus(http.StatusNotModified)
+						return
+					}
+				}
+				c.Data(entry.status, entry.contentType, entry.body)
+				c.Abort()
+				return
+			}
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Header(CacheStatusHeader, "MISS")
+
+		c.Next()
+
+		// Partial results from a degraded backend opt out so recovery shows up on the next request
+		if recorder.Status() == http.StatusOK && recorder.Header().Get("Cache-Control") != "no-store" {
+			rc.store(key, &cacheEntry{
+				status:      recorder.Status(),
+				contentType: recorder.Header().Get("Content-Type"),
+				etag:        recorder.Header().Get("ETag"),
+				body:        recorder.body.Bytes(),
+				storedAt:    rc.now(),
+			})
+		}
+	}
+}
+
+// cacheKey identifies a cached response by path and query, with query
+// parameters sorted so equivalent requests share an entry.
+func cacheKey(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.EscapedPath()
+	}
+	return u.EscapedPath() + "?" + u.Query().Encode()
+}
+
+// has reports whether an entry is stored for key, fresh or not.
+func (rc *ResponseCache) has(key string) bool {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	_, ok := rc.entries[key]
+	return ok
+}
+
+// lookup returns the usable entry for key and whether it is still fresh.
+// Entries past their stale window are dropped.
+func (rc *ResponseCache) lookup(key string, policy routePolicy) (*cacheEntry, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	entry, ok := rc.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	age := rc.now().Sub(entry.storedAt)
+	if age < policy.ttl {
+		return entry, true
+	}
+	if age < policy.ttl+policy.stale {
+		return entry, false
+	}
+
+	delete(rc.entries, key)
+	return nil, false
+}
+
+// store saves an entry, skipping it when the cache is full.
+func (rc *ResponseCache) store(key string, entry *cacheEntry) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if _, exists := rc.entries[key]; !exists && len(rc.entries) >= maxCacheEntries {
+		rc.log.Debug().Str("key", key).Msg("Response cache full, not storing entry")
+		return
+	}
+	rc.entries[key] = entry
+}
+
+// refresh replays the request in the background unless a refresh is already running.
+func (rc *ResponseCache) refresh(key string, original *http.Request) {
+	rc.mu.Lock()
+	if rc.refreshing[key] {
+		rc.mu.Unlock()
+		return
+	}
+	rc.refreshing[key] = true
+	rc.mu.Unlock()
+
+	// Detach from the client request so the refresh outlives it
+	ctx := context.WithValue(context.Background(), refreshKey{}, true)
+	req := original.Clone(ctx)
+	// A conditional request could be answered with 304 and no body to store
+	req.Header.Del("If-None-Match")
+
+	go func() {
+		defer func() {
+			rc.mu.Lock()
+			delete(rc.refreshing, key)
+			rc.mu.Unlock()
+		}()
+
+		w := &discardWriter{header: make(http.Header), status: http.StatusOK}
+		rc.handler.ServeHTTP(w, req)
+
+		if w.status != http.StatusOK {
+			rc.log.Warn().
+				Str("key", key).
+				Int("status", w.status).
+				Msg("Response cache refresh failed, keeping stale entry")
+			return
+		}
+
+		rc.log.Debug().Str("key", key).Msg("Response cache entry refreshed")
+	}()
+}
+
+// bodyRecorder copies the response body while writing it to the client.
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// discardWriter is the response writer for background refreshes; the
+// middleware captures the body itself, so only the status is kept.
+type discardWriter struct {
+	header http.Header
+	status int
+}
+
+func (w *discardWriter) Header() http.Header         { return w.header }
+func (w *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardWriter) WriteHeader(status int)      { w.status = status }
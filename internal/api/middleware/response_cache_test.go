@@ -0,0 +1,218 @@
+//nolint:noctx // Test file uses http.NewRequest for simplicity
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+)
+
+// fakeClock is a manually advanced clock shared with the cache.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+func setupCachedRouter(cfg *config.ResponseCacheConfig) (*gin.Engine, *fakeClock, *atomic.Int32) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	rc := NewResponseCache(cfg, router, logger.New("error", "json", "stdout"))
+	rc.now = clock.Now
+
+	calls := &atomic.Int32{}
+	api := router.Group("/api/v1")
+	api.Use(rc.Middleware())
+	api.GET("/leaderboard", func(c *gin.Context) {
+		n := calls.Add(1)
+		c.String(http.StatusOK, fmt.Sprintf("version-%d", n))
+	})
+	api.GET("/partial", func(c *gin.Context) {
+		n := calls.Add(1)
+		c.Header("Cache-Control", "no-store")
+		c.String(http.StatusOK, fmt.Sprintf("version-%d", n))
+	})
+	api.GET("/etag", func(c *gin.Context) {
+		n := calls.Add(1)
+		c.Header("ETag", fmt.Sprintf(`"v%d"`, n))
+		c.String(http.StatusOK, fmt.Sprintf("version-%d", n))
+	})
+	api.GET("/uncached", func(c *gin.Context) {
+		n := calls.Add(1)
+		c.String(http.StatusOK, fmt.Sprintf("version-%d", n))
+	})
+
+	return router, clock, calls
+}
+
+func doGet(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", path, http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestResponseCache_StaleWhileRevalidate(t *testing.T) {
+	router, clock, calls := setupCachedRouter(&config.ResponseCacheConfig{
+		Enabled: true,
+		Routes: []config.ResponseCacheRoute{
+			{Path: "/api/v1/leaderboard", TTL: 60, StaleWindow: 300},
+		},
+	})
+
+	// First request populates the cache
+	w := doGet(router, "/api/v1/leaderboard")
+	assert.Equal(t, "MISS", w.Header().Get(CacheStatusHeader))
+	assert.Equal(t, "version-1", w.Body.String())
+
+	// Within TTL the cached body is served without calling the handler
+	clock.Advance(30 * time.Second)
+	w = doGet(router, "/api/v1/leaderboard")
+	assert.Equal(t, "HIT", w.Header().Get(CacheStatusHeader))
+	assert.Equal(t, "version-1", w.Body.String())
+	assert.Equal(t, int32(1), calls.Load())
+
+	// After TTL the stale body is served immediately and refreshed in the background
+	clock.Advance(60 * time.Second)
+	w = doGet(router, "/api/v1/leaderboard")
+	assert.Equal(t, "STALE", w.Header().Get(CacheStatusHeader))
+	assert.Equal(t, "version-1", w.Body.String())
+
+	assert.Eventually(t, func() bool {
+		return calls.Load() == 2
+	}, time.Second, 5*time.Millisecond, "expected background refresh")
+
+	// The refreshed body is served as fresh
+	assert.Eventually(t, func() bool {
+		w = doGet(router, "/api/v1/leaderboard")
+		return w.Header().Get(CacheStatusHeader) == "HIT" && w.Body.String() == "version-2"
+	}, time.Second, 5*time.Millisecond, "expected refreshed entry")
+}
+
+func TestResponseCache_ConditionalGet(t *testing.T) {
+	router, _, calls := setupCachedRouter(&config.ResponseCacheConfig{
+		Enabled: true,
+		Routes: []config.ResponseCacheRoute{
+			{Path: "/api/v1/etag", TTL: 60},
+		},
+	})
+
+	w := doGet(router, "/api/v1/etag")
+	assert.Equal(t, `"v1"`, w.Header().Get("ETag"))
+
+	// Cached responses keep their ETag and answer matching conditional requests with 304
+	req, _ := http.NewRequest("GET", "/api/v1/etag", http.NoBody)
+	req.Header.Set("If-None-Match", `"v1"`)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, "HIT", w.Header().Get(CacheStatusHeader))
+	assert.Empty(t, w.Body.String())
+
+	req, _ = http.NewRequest("GET", "/api/v1/etag", http.NoBody)
+	req.Header.Set("If-None-Match", `"v0"`)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"v1"`, w.Header().Get("ETag"))
+	assert.Equal(t, "version-1", w.Body.String())
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestResponseCache_ExpiredAfterStaleWindow(t *testing.T) {
+	router, clock, calls := setupCachedRouter(&config.ResponseCacheConfig{
+		Enabled: true,
+		Routes: []config.ResponseCacheRoute{
+			{Path: "/api/v1/leaderboard", TTL: 60, StaleWindow: 60},
+		},
+	})
+
+	doGet(router, "/api/v1/leaderboard")
+
+	clock.Advance(2 * time.Minute)
+	w := doGet(router, "/api/v1/leaderboard")
+	assert.Equal(t, "MISS", w.Header().Get(CacheStatusHeader))
+	assert.Equal(t, "version-2", w.Body.String())
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestResponseCache_UnconfiguredRouteAndDisabled(t *testing.T) {
+	router, _, calls := setupCachedRouter(&config.ResponseCacheConfig{
+		Enabled: true,
+		Routes: []config.ResponseCacheRoute{
+			{Path: "/api/v1/leaderboard", TTL: 60},
+		},
+	})
+
+	doGet(router, "/api/v1/uncached")
+	w := doGet(router, "/api/v1/uncached")
+	assert.Empty(t, w.Header().Get(CacheStatusHeader))
+	assert.Equal(t, int32(2), calls.Load())
+
+	router, _, calls = setupCachedRouter(&config.ResponseCacheConfig{
+		Enabled: false,
+		Routes: []config.ResponseCacheRoute{
+			{Path: "/api/v1/leaderboard", TTL: 60},
+		},
+	})
+
+	doGet(router, "/api/v1/leaderboard")
+	doGet(router, "/api/v1/leaderboard")
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestResponseCache_QueryStringsCachedSeparately(t *testing.T) {
+	router, _, calls := setupCachedRouter(&config.ResponseCacheConfig{
+		Enabled: true,
+		Routes: []config.ResponseCacheRoute{
+			{Path: "/api/v1/leaderboard", TTL: 60},
+		},
+	})
+
+	doGet(router, "/api/v1/leaderboard?period=week")
+	doGet(router, "/api/v1/leaderboard?period=month")
+	w := doGet(router, "/api/v1/leaderboard?period=week")
+
+	assert.Equal(t, "HIT", w.Header().Get(CacheStatusHeader))
+	assert.Equal(t, "version-1", w.Body.String())
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestResponseCache_NoStoreNotCached(t *testing.T) {
+	router, _, calls := setupCachedRouter(&config.ResponseCacheConfig{
+		Enabled: true,
+		Routes: []config.ResponseCacheRoute{
+			{Path: "/api/v1/partial", TTL: 60},
+		},
+	})
+
+	doGet(router, "/api/v1/partial")
+	w := doGet(router, "/api/v1/partial")
+
+	assert.Equal(t, "MISS", w.Header().Get(CacheStatusHeader))
+	assert.Equal(t, "version-2", w.Body.String())
+	assert.Equal(t, int32(2), calls.Load())
+}
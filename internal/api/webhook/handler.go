@@ -34,6 +34,7 @@ type Handler struct {
 	metricsService   *metrics.Service
 	userRepo         *repository.UserRepository
 	reviewRepo       *repository.ReviewRepository
+	projectRepo      *repository.ProjectRepository
 	translator       *i18n.Translator
 	log              *logger.Logger
 }
@@ -47,6 +48,7 @@ func NewHandler(
 	metricsService *metrics.Service,
 	userRepo *repository.UserRepository,
 	reviewRepo *repository.ReviewRepository,
+	projectRepo *repository.ProjectRepository,
 	translator *i18n.Translator,
 	log *logger.Logger,
 ) *Handler {
@@ -58,6 +60,7 @@ func NewHandler(
 		metricsService:   metricsService,
 		userRepo:         userRepo,
 		reviewRepo:       reviewRepo,
+		projectRepo:      projectRepo,
 		translator:       translator,
 		log:              log,
 	}
@@ -98,6 +101,23 @@ func (h *Handler) HandleGitLabWebhook(c *gin.Context) {
 	}
 }
 
+// saveProject records the project's name and path so project-scoped responses can show them.
+// Failures are logged only: project metadata is not required to run the roulette.
+func (h *Handler) saveProject(event NoteEvent) {
+	if h.projectRepo == nil || event.Project.Name == "" {
+		return
+	}
+
+	project := &models.Project{
+		GitLabProjectID: event.ProjectID,
+		Name:            event.Project.Name,
+		Path:            event.Project.PathWithNamespace,
+	}
+	if err := h.projectRepo.Upsert(project); err != nil {
+		h.log.Warn().Err(err).Int("project_id", event.ProjectID).Msg("Failed to save project metadata")
+	}
+}
+
 // validateSignature validates the webhook signature
 func (h *Handler) validateSignature(c *gin.Context) bool {
 	signature := c.GetHeader("X-Gitlab-Token")
@@ -118,7 +138,9 @@ type NoteEvent struct {
 	} `json:"user"`
 	ProjectID int `json:"project_id"`
 	Project   struct {
-		ID int `json:"id"`
+		ID                int    `json:"id"`
+		Name              string `json:"name"`
+		PathWithNamespace string `json:"path_with_namespace"`
 	} `json:"project"`
 	ObjectAttributes struct {
 		ID           int    `json:"id"`
@@ -127,9 +149,10 @@ type NoteEvent struct {
 		NoteableID   int    `json:"noteable_id"`
 	} `json:"object_attributes"`
 	MergeRequest struct {
-		IID   int    `json:"iid"`
-		Title string `json:"title"`
-		URL   string `json:"url"`
+		IID      int    `json:"iid"`
+		Title    string `json:"title"`
+		URL      string `json:"url"`
+		AuthorID int    `json:"author_id"`
 	} `json:"merge_request"`
 }
 
@@ -231,7 +254,7 @@ func (h *Handler) processRouletteCommand(ctx context.Context, event NoteEvent, o
 	result, err := h.rouletteService.SelectReviewers(ctx, req)
 	if err != nil {
 		h.log.Error().Err(err).Msg("Failed to select reviewers")
-		h.postErrorComment(event.ProjectID, event.MergeRequest.IID, err)
+		h.postErrorComment(ctx, event.ProjectID, event.MergeRequest.IID, err)
 		return
 	}
 
@@ -253,7 +276,7 @@ func (h *Handler) processRouletteCommand(ctx context.Context, event NoteEvent, o
 	prommetrics.RecordRouletteTrigger(result.Team, "success")
 
 	// Post or update result to MR
-	if err := h.postRouletteResult(event, result, mrReview); err != nil {
+	if err := h.postRouletteResult(ctx, event, result, mrReview); err != nil {
 		h.log.Error().Err(err).Msg("Failed to post roulette result")
 	}
 }
@@ -274,9 +297,24 @@ func (h *Handler) saveRouletteResult(event NoteEvent, user *models.User, result
 		Status:              models.MRStatusPending,
 	}
 
+	// The author is needed to keep MRs of excluded users out of the metrics
+	var author *models.User
+	if event.MergeRequest.AuthorID != 0 {
+		var err error
+		author, err = h.getOrCreateUser(event.MergeRequest.AuthorID, "")
+		if err != nil {
+			h.log.Warn().Err(err).Int("author_id", event.MergeRequest.AuthorID).Msg("Failed to resolve MR author")
+		} else {
+			mrReview.MRAuthorID = &author.ID
+		}
+	}
+
 	if err := h.reviewRepo.CreateOrUpdateMRReview(mrReview); err != nil {
 		return nil, fmt.Errorf("failed to save MR review: %w", err)
 	}
+	mrReview.MRAuthor = author
+
+	h.saveProject(event)
 
 	// Delete old assignments
 	_ = h.reviewRepo.DeleteAssignmentsByMRReviewID(mrReview.ID)
@@ -321,12 +359,12 @@ func (h *Handler) saveRouletteResult(event NoteEvent, user *models.User, result
 }
 
 // postRouletteResult posts or updates the selection result as a comment
-func (h *Handler) postRouletteResult(event NoteEvent, result *roulette.SelectionResult, mrReview *models.MRReview) error {
+func (h *Handler) postRouletteResult(ctx context.Context, event NoteEvent, result *roulette.SelectionResult, mrReview *models.MRReview) error {
 	comment := h.formatRouletteResult(result)
 
 	// If we have an existing bot comment, update it; otherwise create new one
 	if mrReview.BotCommentID != nil && *mrReview.BotCommentID > 0 {
-		err := h.gitlabClient.UpdateComment(event.ProjectID, event.MergeRequest.IID, *mrReview.BotCommentID, comment)
+		err := h.gitlabClient.UpdateComment(ctx, event.ProjectID, event.MergeRequest.IID, *mrReview.BotCommentID, comment)
 		if err != nil {
 			// If update fails (e.g., comment was deleted), create a new one
 			h.log.Warn().
@@ -334,7 +372,7 @@ func (h *Handler) postRouletteResult(event NoteEvent, result *roulette.Selection
 				Int("note_id", *mrReview.BotCommentID).
 				Msg("Failed to update existing comment, creating new one")
 
-			noteID, err := h.gitlabClient.PostComment(event.ProjectID, event.MergeRequest.IID, comment)
+			noteID, err := h.gitlabClient.PostComment(ctx, event.ProjectID, event.MergeRequest.IID, comment)
 			if err != nil {
 				return err
 			}
@@ -347,7 +385,7 @@ func (h *Handler) postRouletteResult(event NoteEvent, result *roulette.Selection
 	}
 
 	// Create new comment
-	noteID, err := h.gitlabClient.PostComment(event.ProjectID, event.MergeRequest.IID, comment)
+	noteID, err := h.gitlabClient.PostComment(ctx, event.ProjectID, event.MergeRequest.IID, comment)
 	if err != nil {
 		return err
 	}
@@ -401,11 +439,11 @@ func (h *Handler) formatRouletteResult(result *roulette.SelectionResult) string
 }
 
 // postErrorComment posts an error comment
-func (h *Handler) postErrorComment(projectID, mrIID int, err error) {
+func (h *Handler) postErrorComment(ctx context.Context, projectID, mrIID int, err error) {
 	comment := h.translator.Get("errors.selection_failed", map[string]interface{}{
 		"Error": err.Error(),
 	})
-	_, postErr := h.gitlabClient.PostComment(projectID, mrIID, comment)
+	_, postErr := h.gitlabClient.PostComment(ctx, projectID, mrIID, comment)
 	if postErr != nil {
 		h.log.Error().Err(postErr).Msg("Failed to post error comment")
 	}
@@ -442,10 +480,12 @@ func (h *Handler) handleMergeRequestEvent(c *gin.Context, body []byte) {
 		Str("state", event.ObjectAttributes.State).
 		Msg("Processing MR event")
 
-	// Handle approval, merge, or close events
+	// Handle approval, merge, close, or reopen events
 	switch {
 	case event.ObjectAttributes.Action == "approved":
 		go h.handleMRApproved(context.Background(), event)
+	case event.ObjectAttributes.Action == "reopen":
+		go h.handleMRReopened(context.Background(), event)
 	case event.ObjectAttributes.Action == "merge" || event.ObjectAttributes.State == "merged":
 		go h.handleMRMerged(context.Background(), event)
 	case event.ObjectAttributes.State == "closed":
@@ -512,6 +552,50 @@ func (h *Handler) handleMRClosed(_ context.Context, event MergeRequestEvent) {
 	prommetrics.RecordReviewAbandoned(review.Team)
 }
 
+// handleMRReopened reopens the review when a merged or closed MR is reopened.
+// Clearing the merge and close times takes the review out of completed reviews, so the
+// next aggregation of the day it was completed no longer credits it.
+func (h *Handler) handleMRReopened(_ context.Context, event MergeRequestEvent) {
+	review, err := h.reviewRepo.GetMRReview(event.Project.ID, event.ObjectAttributes.IID)
+	if err != nil {
+		h.log.Debug().Err(err).Msg("MR review not found for reopen event")
+		return
+	}
+
+	if review.Status != models.MRStatusMerged && review.Status != models.MRStatusClosed {
+		return
+	}
+
+	review.MergedAt = nil
+	review.ClosedAt = nil
+	review.Status = reopenedStatus(review)
+
+	if err := h.reviewRepo.UpdateMRReview(review); err != nil {
+		h.log.Error().Err(err).Msg("Failed to update reopened MR review")
+		return
+	}
+
+	prommetrics.RecordReviewReopened(review.Team)
+
+	h.log.Info().
+		Int("project_id", event.Project.ID).
+		Int("mr_iid", event.ObjectAttributes.IID).
+		Str("status", review.Status).
+		Msg("MR review reopened")
+}
+
+// reopenedStatus returns the open status a reopened review resumes from.
+func reopenedStatus(review *models.MRReview) string {
+	switch {
+	case review.ApprovedAt != nil:
+		return models.MRStatusApproved
+	case review.FirstReviewAt != nil:
+		return models.MRStatusInReview
+	default:
+		return models.MRStatusPending
+	}
+}
+
 // handleMRApproved updates the review status when MR is approved
 func (h *Handler) handleMRApproved(_ context.Context, event MergeRequestEvent) {
 	review, err := h.reviewRepo.GetMRReview(event.Project.ID, event.ObjectAttributes.IID)
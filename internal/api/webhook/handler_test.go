@@ -1,11 +1,22 @@
 package webhook
 
 import (
+	"context"
 	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rs/zerolog"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
 
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/i18n"
+	prommetrics "github.com/aimd54/gitlab-reviewer-roulette/internal/metrics"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/aggregator"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/roulette"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
 )
 
 func TestParseRouletteCommand(t *testing.T) {
@@ -423,6 +434,107 @@ func TestTranslatorNilHandling(t *testing.T) {
 	}
 }
 
+func TestHandleMRReopened_RemovesCompletedCredit(t *testing.T) {
+	gormDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := gormDB.AutoMigrate(&models.User{}, &models.MRReview{}, &models.ReviewerAssignment{}, &models.ReviewMetrics{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	if err := repository.CreateMetricsIndexes(gormDB); err != nil {
+		t.Fatalf("Failed to create indexes: %v", err)
+	}
+
+	db := &repository.DB{DB: gormDB}
+	reviewRepo := repository.NewReviewRepository(db)
+	metricsRepo := repository.NewMetricsRepository(db)
+
+	user := models.User{GitLabID: 1, Username: "alice", Team: "team-frontend"}
+	if err := gormDB.Create(&user).Error; err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	mergedAt := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	triggeredAt := mergedAt.Add(-2 * time.Hour)
+	firstCommentAt := mergedAt.Add(-time.Hour)
+	review := models.MRReview{
+		GitLabMRIID:         7,
+		GitLabProjectID:     100,
+		MRURL:               "https://gitlab.example.com/project/mr/7",
+		Team:                "team-frontend",
+		RouletteTriggeredAt: &triggeredAt,
+		FirstReviewAt:       &firstCommentAt,
+		MergedAt:            &mergedAt,
+		Status:              models.MRStatusMerged,
+	}
+	if err := reviewRepo.CreateMRReview(&review); err != nil {
+		t.Fatalf("Failed to create review: %v", err)
+	}
+	assignment := models.ReviewerAssignment{
+		MRReviewID:     review.ID,
+		UserID:         user.ID,
+		AssignedAt:     triggeredAt,
+		FirstCommentAt: &firstCommentAt,
+		CommentCount:   3,
+		CommentLength:  300,
+	}
+	if err := gormDB.Create(&assignment).Error; err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+
+	log := zerolog.Nop()
+	agg := aggregator.NewService(reviewRepo, metricsRepo, &log)
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	completedReviews := func() int {
+		rows, err := metricsRepo.GetMetricsByUser(user.ID, day, day)
+		if err != nil {
+			t.Fatalf("Failed to get user metrics: %v", err)
+		}
+		total := 0
+		for _, row := range rows {
+			total += row.CompletedReviews
+		}
+		return total
+	}
+
+	if err := agg.AggregateDaily(context.Background(), day); err != nil {
+		t.Fatalf("AggregateDaily failed: %v", err)
+	}
+	if got := completedReviews(); got != 1 {
+		t.Fatalf("Expected 1 completed review before reopening, got %d", got)
+	}
+
+	prommetrics.ReviewsReopenedTotal.Reset()
+	h := &Handler{reviewRepo: reviewRepo, log: logger.New("error", "json", "stdout")}
+
+	var event MergeRequestEvent
+	event.Project.ID = 100
+	event.ObjectAttributes.IID = 7
+	event.ObjectAttributes.Action = "reopen"
+	event.ObjectAttributes.State = "opened"
+	h.handleMRReopened(context.Background(), event)
+
+	if count := testutil.ToFloat64(prommetrics.ReviewsReopenedTotal.WithLabelValues("team-frontend")); count != 1 {
+		t.Errorf("Expected reopened count = 1, got %f", count)
+	}
+
+	reopened, err := reviewRepo.GetMRReview(100, 7)
+	if err != nil {
+		t.Fatalf("Failed to get review: %v", err)
+	}
+	if reopened.Status != models.MRStatusInReview || reopened.MergedAt != nil {
+		t.Errorf("Expected in_review status without merge time, got %s (merged_at %v)", reopened.Status, reopened.MergedAt)
+	}
+
+	if err := agg.AggregateDaily(context.Background(), day); err != nil {
+		t.Fatalf("AggregateDaily failed: %v", err)
+	}
+	if got := completedReviews(); got != 0 {
+		t.Errorf("Expected no completed reviews after reopening, got %d", got)
+	}
+}
+
 // Helper function
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && findSubstring(s, substr))
@@ -3,31 +3,53 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/robfig/cron/v3"
 	"github.com/spf13/viper"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
 )
 
 // Config represents the application configuration.
 type Config struct {
-	Server       ServerConfig       `mapstructure:"server"`
-	GitLab       GitLabConfig       `mapstructure:"gitlab"`
-	Mattermost   MattermostConfig   `mapstructure:"mattermost"`
-	Database     DatabaseConfig     `mapstructure:"database"`
-	Teams        []TeamConfig       `mapstructure:"teams"`
-	Roulette     RouletteConfig     `mapstructure:"roulette"`
-	Scheduler    SchedulerConfig    `mapstructure:"scheduler"`
-	Metrics      MetricsConfig      `mapstructure:"metrics"`
-	Logging      LoggingConfig      `mapstructure:"logging"`
-	Badges       []BadgeConfig      `mapstructure:"badges"`
-	Availability AvailabilityConfig `mapstructure:"availability"`
+	Server        ServerConfig        `mapstructure:"server"`
+	GitLab        GitLabConfig        `mapstructure:"gitlab"`
+	Mattermost    MattermostConfig    `mapstructure:"mattermost"`
+	Database      DatabaseConfig      `mapstructure:"database"`
+	Teams         []TeamConfig        `mapstructure:"teams"`
+	Roulette      RouletteConfig      `mapstructure:"roulette"`
+	Scheduler     SchedulerConfig     `mapstructure:"scheduler"`
+	Metrics       MetricsConfig       `mapstructure:"metrics"`
+	Logging       LoggingConfig       `mapstructure:"logging"`
+	Badges        []BadgeConfig       `mapstructure:"badges"`
+	Availability  AvailabilityConfig  `mapstructure:"availability"`
+	ResponseCache ResponseCacheConfig `mapstructure:"response_cache"`
+	Leaderboard   LeaderboardConfig   `mapstructure:"leaderboard"`
+	ExcludedUsers ExcludedUsersConfig `mapstructure:"excluded_users"`
+
+	watcher *watcher // set by LoadWatched
 }
 
 // ServerConfig contains HTTP server configuration.
 type ServerConfig struct {
 	Port        int    `mapstructure:"port"`
 	Environment string `mapstructure:"environment"`
-	Language    string `mapstructure:"language"` // Language for bot responses (en, fr)
+	Language    string `mapstructure:"language"`    // Language for bot responses (en, fr)
+	AdminToken  string `mapstructure:"admin_token"` // Token granting admin access to the API (empty disables admin access)
+
+	RequestTimeout int `mapstructure:"request_timeout"` // Seconds an API request may run before its context is cancelled (0 disables)
+
+	RateLimitRPS   float64 `mapstructure:"rate_limit_rps"`   // API requests per second allowed per client IP (0 disables)
+	RateLimitBurst int     `mapstructure:"rate_limit_burst"` // API requests a client IP may send at once before being limited
+
+	TrustedProxies  []string `mapstructure:"trusted_proxies"`  // Proxy IPs or CIDRs whose X-Forwarded-For header is trusted for the client IP (empty trusts none)
+	TrustedPlatform string   `mapstructure:"trusted_platform"` // Header set by the hosting platform carrying the client IP, e.g. CF-Connecting-IP (empty disables)
+
+	GzipEnabled bool `mapstructure:"gzip_enabled"` // Compress larger API responses for clients accepting gzip
 }
 
 // GitLabConfig contains GitLab API connection and authentication settings.
@@ -40,9 +62,34 @@ type GitLabConfig struct {
 
 // MattermostConfig contains Mattermost webhook notification settings.
 type MattermostConfig struct {
-	WebhookURL string `mapstructure:"webhook_url"`
-	Channel    string `mapstructure:"channel"`
-	Enabled    bool   `mapstructure:"enabled"`
+	WebhookURL string           `mapstructure:"webhook_url"`
+	Channel    string           `mapstructure:"channel"`
+	Enabled    bool             `mapstructure:"enabled"`
+	RetryQueue RetryQueueConfig `mapstructure:"retry_queue"`
+	URL        string           `mapstructure:"url"`        // Mattermost server URL for the bot API (threaded reminders)
+	BotToken   string           `mapstructure:"bot_token"`  // Bot access token; enables threaded reminders when set with url and channel_id
+	ChannelID  string           `mapstructure:"channel_id"` // Channel ID that threaded reminders are posted to
+	// AchievementsChannel receives badge announcements (defaults to channel)
+	AchievementsChannel string `mapstructure:"achievements_channel"`
+	// AnnounceNewBadges posts badges added to the config to the achievements channel when they are synced
+	AnnounceNewBadges bool `mapstructure:"announce_new_badges"`
+	// Dedup suppresses identical consecutive messages, e.g. a manual run right after the cron
+	Dedup MessageDedupConfig `mapstructure:"dedup"`
+}
+
+// MessageDedupConfig contains settings for suppressing repeated Mattermost messages.
+type MessageDedupConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	Window  int  `mapstructure:"window"` // Seconds during which an identical message is not sent again
+}
+
+// RetryQueueConfig contains settings for the database-backed webhook retry queue.
+type RetryQueueConfig struct {
+	Enabled      bool `mapstructure:"enabled"`
+	MaxAttempts  int  `mapstructure:"max_attempts"`  // Attempts before a delivery is marked failed
+	BaseBackoff  int  `mapstructure:"base_backoff"`  // Seconds before the first retry, doubled per attempt
+	MaxBackoff   int  `mapstructure:"max_backoff"`   // Upper bound on the retry delay in seconds
+	PollInterval int  `mapstructure:"poll_interval"` // Seconds between queue scans
 }
 
 // DatabaseConfig contains database connection settings for PostgreSQL and Redis.
@@ -75,14 +122,20 @@ type RedisConfig struct {
 
 // TeamConfig represents a team with its members.
 type TeamConfig struct {
-	Name    string         `mapstructure:"name"`
-	Members []MemberConfig `mapstructure:"members"`
+	Name             string         `mapstructure:"name"`
+	Members          []MemberConfig `mapstructure:"members"`
+	FallbackReviewer string         `mapstructure:"fallback_reviewer"` // Username to escalate to when no member is available
+	Channel          string         `mapstructure:"channel"`           // Mattermost channel for this team's reminders (defaults to mattermost.channel)
+	ChannelID        string         `mapstructure:"channel_id"`        // Mattermost channel ID for this team's threaded reminders (defaults to mattermost.channel_id)
+	// PointsWeights overrides leaderboard.points for this team's members (optional)
+	PointsWeights *PointsWeightsConfig `mapstructure:"points_weights"`
 }
 
 // MemberConfig represents a team member with their role.
 type MemberConfig struct {
-	Username string `mapstructure:"username"`
-	Role     string `mapstructure:"role"`
+	Username    string `mapstructure:"username"`
+	Role        string `mapstructure:"role"`
+	DisplayName string `mapstructure:"display_name"` // Optional friendly name shown in the UI
 }
 
 // RouletteConfig contains reviewer selection algorithm configuration.
@@ -96,6 +149,8 @@ type WeightsConfig struct {
 	CurrentLoad    int `mapstructure:"current_load"`
 	RecentReview   int `mapstructure:"recent_review"`
 	ExpertiseBonus int `mapstructure:"expertise_bonus"`
+	// RecentReviewWindowHours is how long an assignment counts as recent; its penalty decays linearly to 0 over the window (0 disables)
+	RecentReviewWindowHours int `mapstructure:"recent_review_window_hours"`
 }
 
 // ExpertiseConfig defines file patterns for developer and operations expertise.
@@ -106,18 +161,56 @@ type ExpertiseConfig struct {
 
 // SchedulerConfig contains daily notification scheduler settings.
 type SchedulerConfig struct {
-	Enabled             bool   `mapstructure:"enabled"`
-	Time                string `mapstructure:"time"`
-	BadgeEvaluationTime string `mapstructure:"badge_evaluation_time"` // Cron expression for badge evaluation
-	Timezone            string `mapstructure:"timezone"`
-	SkipWeekends        bool   `mapstructure:"skip_weekends"`
-	SkipHolidays        bool   `mapstructure:"skip_holidays"`
+	Enabled                    bool     `mapstructure:"enabled"`
+	Time                       string   `mapstructure:"time"`
+	BadgeEvaluationTime        string   `mapstructure:"badge_evaluation_time"`        // Cron expression for badge evaluation
+	BadgeEvaluationConcurrency int      `mapstructure:"badge_evaluation_concurrency"` // Users evaluated in parallel (0 uses GOMAXPROCS)
+	BadgeEvaluationBatchSize   int      `mapstructure:"badge_evaluation_batch_size"`  // Users evaluated between progress reports (0 uses the default)
+	Timezone                   string   `mapstructure:"timezone"`
+	SkipWeekends               bool     `mapstructure:"skip_weekends"`
+	SkipHolidays               bool     `mapstructure:"skip_holidays"`
+	Holidays                   []string `mapstructure:"holidays"`               // Dates (YYYY-MM-DD) skipped when skip_holidays is enabled
+	MinMRAgeHours              int      `mapstructure:"min_mr_age_hours"`       // MRs younger than this are left out of daily reminders
+	OverdueMRAgeHours          int      `mapstructure:"overdue_mr_age_hours"`   // MRs older than this are listed as overdue (0 disables)
+	StaleMRCommentHours        int      `mapstructure:"stale_mr_comment_hours"` // MRs older than this get a reminder comment on GitLab (0 disables)
+
+	InactivityCheck InactivityCheckConfig `mapstructure:"inactivity_check"`
+}
+
+// InactivityCheckConfig contains settings of the job reporting reviewers without recent completed reviews.
+type InactivityCheckConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	Schedule string `mapstructure:"schedule"` // Cron expression, e.g. "0 9 * * 1" for Monday mornings
+	Days     int    `mapstructure:"days"`     // Reviewers without a completed review in this many days are reported
+	Channel  string `mapstructure:"channel"`  // Mattermost channel for the report, e.g. the managers' (defaults to mattermost.channel)
 }
 
+// DefaultMaxQueryRangeDays is the default of metrics.max_query_range_days.
+const DefaultMaxQueryRangeDays = 366
+
 // MetricsConfig contains metrics collection and retention settings.
 type MetricsConfig struct {
-	RetentionDays int              `mapstructure:"retention_days"`
-	Prometheus    PrometheusConfig `mapstructure:"prometheus"`
+	RetentionDays     int              `mapstructure:"retention_days"`
+	MaxQueryRangeDays int              `mapstructure:"max_query_range_days"` // Widest custom date range a metrics query may span (0 = unlimited)
+	MinReviewComments int              `mapstructure:"min_review_comments"`  // Engagement floor: reviews with fewer comments are not counted as completed (0 = count all)
+	CalendarPeriods   bool             `mapstructure:"calendar_periods"`     // Named periods start at calendar boundaries (Monday, the 1st, Jan 1) instead of rolling windows
+	ExcludeWeekends   bool             `mapstructure:"exclude_weekends"`     // Leave Saturdays and Sundays (in scheduler.timezone) out of TTFR and time to approval
+	HourlyAggregation bool             `mapstructure:"hourly_aggregation"`   // Also build hourly team rows when aggregating a day
+	Engagement        EngagementConfig `mapstructure:"engagement"`
+	Prometheus        PrometheusConfig `mapstructure:"prometheus"`
+}
+
+// EngagementConfig contains the coefficients of the reviewer engagement score:
+// comment_count * CommentWeight + comment_length / LengthDivisor, plus a bonus for a first
+// comment within FastResponseHours (or a smaller one within PromptResponseHours) of assignment.
+// All zero uses the defaults: 10 per comment, 1 per 100 characters, +10 within 1 hour, +5 within 4 hours.
+type EngagementConfig struct {
+	CommentWeight       float64 `mapstructure:"comment_weight"`
+	LengthDivisor       float64 `mapstructure:"length_divisor"` // 0 ignores comment length
+	FastResponseHours   float64 `mapstructure:"fast_response_hours"`
+	FastResponseBonus   float64 `mapstructure:"fast_response_bonus"`
+	PromptResponseHours float64 `mapstructure:"prompt_response_hours"`
+	PromptResponseBonus float64 `mapstructure:"prompt_response_bonus"`
 }
 
 // PrometheusConfig contains Prometheus metrics exporter settings.
@@ -148,8 +241,60 @@ type AvailabilityConfig struct {
 	OOOKeywords []string `mapstructure:"ooo_keywords"`
 }
 
+// LeaderboardConfig contains leaderboard ranking settings.
+type LeaderboardConfig struct {
+	Points    PointsWeightsConfig `mapstructure:"points"`
+	FocusTeam string              `mapstructure:"focus_team"` // Team summarized at the top of the global leaderboard (optional)
+}
+
+// PointsWeightsConfig contains the weights of the combined "points" leaderboard metric.
+// points = completed_reviews * CompletedReviews + engagement_score * Engagement + badge_count * Badges.
+type PointsWeightsConfig struct {
+	CompletedReviews float64 `mapstructure:"completed_reviews"`
+	Engagement       float64 `mapstructure:"engagement"`
+	Badges           float64 `mapstructure:"badges"`
+}
+
+// ExcludedUsersConfig lists users (bots, service accounts) kept out of metrics,
+// leaderboards, statistics and reviewer selection.
+type ExcludedUsersConfig struct {
+	Usernames []string `mapstructure:"usernames"`
+	GitLabIDs []int    `mapstructure:"gitlab_ids"`
+}
+
+// ResponseCacheConfig contains HTTP response cache settings for read-heavy endpoints.
+type ResponseCacheConfig struct {
+	Enabled bool                 `mapstructure:"enabled"`
+	Routes  []ResponseCacheRoute `mapstructure:"routes"`
+	Warm    CacheWarmConfig      `mapstructure:"warm"`
+}
+
+// CacheWarmConfig configures scheduled pre-computation of popular leaderboards into the response cache.
+type CacheWarmConfig struct {
+	Schedule     string                 `mapstructure:"schedule"`     // Cron expression, e.g. "30 2 * * *" (empty disables warming)
+	Leaderboards []LeaderboardWarmEntry `mapstructure:"leaderboards"` // Combinations warmed for the global and every team leaderboard
+}
+
+// LeaderboardWarmEntry is a period/metric combination to pre-compute.
+type LeaderboardWarmEntry struct {
+	Period string `mapstructure:"period"`
+	Metric string `mapstructure:"metric"`
+}
+
+// ResponseCacheRoute configures caching for a single route.
+type ResponseCacheRoute struct {
+	Path        string `mapstructure:"path"`         // Gin route path, e.g. /api/v1/leaderboard/:team
+	TTL         int    `mapstructure:"ttl"`          // seconds a response is served as fresh
+	StaleWindow int    `mapstructure:"stale_window"` // seconds a stale response is served while refreshing
+}
+
 // Load reads configuration from file and environment variables.
 func Load(configPath string) (*Config, error) {
+	return decode(newViper(configPath))
+}
+
+// newViper creates a Viper instance with the config file location, environment bindings and defaults.
+func newViper(configPath string) *viper.Viper {
 	v := viper.New()
 
 	// Set config file
@@ -168,6 +313,11 @@ func Load(configPath string) (*Config, error) {
 	_ = v.BindEnv("server.port", "SERVER_PORT")
 	_ = v.BindEnv("server.environment", "SERVER_ENVIRONMENT")
 	_ = v.BindEnv("server.language", "SERVER_LANGUAGE")
+	_ = v.BindEnv("server.admin_token", "SERVER_ADMIN_TOKEN")
+	_ = v.BindEnv("server.request_timeout", "SERVER_REQUEST_TIMEOUT")
+	_ = v.BindEnv("server.rate_limit_rps", "SERVER_RATE_LIMIT_RPS")
+	_ = v.BindEnv("server.rate_limit_burst", "SERVER_RATE_LIMIT_BURST")
+	_ = v.BindEnv("server.gzip_enabled", "SERVER_GZIP_ENABLED")
 
 	// GitLab configuration
 	_ = v.BindEnv("gitlab.url", "GITLAB_URL")
@@ -179,6 +329,10 @@ func Load(configPath string) (*Config, error) {
 	_ = v.BindEnv("mattermost.webhook_url", "MATTERMOST_WEBHOOK_URL")
 	_ = v.BindEnv("mattermost.channel", "MATTERMOST_CHANNEL")
 	_ = v.BindEnv("mattermost.enabled", "MATTERMOST_ENABLED")
+	_ = v.BindEnv("mattermost.url", "MATTERMOST_URL")
+	_ = v.BindEnv("mattermost.bot_token", "MATTERMOST_BOT_TOKEN")
+	_ = v.BindEnv("mattermost.channel_id", "MATTERMOST_CHANNEL_ID")
+	_ = v.BindEnv("mattermost.achievements_channel", "MATTERMOST_ACHIEVEMENTS_CHANNEL")
 
 	// PostgreSQL configuration
 	_ = v.BindEnv("database.postgres.host", "POSTGRES_HOST")
@@ -207,10 +361,33 @@ func Load(configPath string) (*Config, error) {
 	_ = v.BindEnv("scheduler.enabled", "SCHEDULER_ENABLED")
 	_ = v.BindEnv("scheduler.time", "SCHEDULER_TIME")
 	_ = v.BindEnv("scheduler.badge_evaluation_time", "SCHEDULER_BADGE_EVALUATION_TIME")
+	_ = v.BindEnv("scheduler.badge_evaluation_concurrency", "SCHEDULER_BADGE_EVALUATION_CONCURRENCY")
+	_ = v.BindEnv("scheduler.badge_evaluation_batch_size", "SCHEDULER_BADGE_EVALUATION_BATCH_SIZE")
 	_ = v.BindEnv("scheduler.timezone", "SCHEDULER_TIMEZONE")
 	_ = v.BindEnv("scheduler.skip_weekends", "SCHEDULER_SKIP_WEEKENDS")
 	_ = v.BindEnv("scheduler.skip_holidays", "SCHEDULER_SKIP_HOLIDAYS")
+	_ = v.BindEnv("scheduler.min_mr_age_hours", "SCHEDULER_MIN_MR_AGE_HOURS")
+	_ = v.BindEnv("scheduler.overdue_mr_age_hours", "SCHEDULER_OVERDUE_MR_AGE_HOURS")
+	_ = v.BindEnv("scheduler.stale_mr_comment_hours", "SCHEDULER_STALE_MR_COMMENT_HOURS")
+
+	// Defaults
+	v.SetDefault("server.request_timeout", 30)
+	v.SetDefault("server.rate_limit_rps", 10)
+	v.SetDefault("server.rate_limit_burst", 30)
+	v.SetDefault("server.gzip_enabled", true)
+	v.SetDefault("scheduler.min_mr_age_hours", 4)
+	v.SetDefault("scheduler.overdue_mr_age_hours", 48)
+	v.SetDefault("scheduler.inactivity_check.schedule", "0 9 * * 1")
+	v.SetDefault("scheduler.inactivity_check.days", 30)
+	v.SetDefault("metrics.max_query_range_days", DefaultMaxQueryRangeDays)
+	v.SetDefault("roulette.weights.recent_review_window_hours", 24)
+	v.SetDefault("mattermost.dedup.window", 600)
+
+	return v
+}
 
+// decode reads, unmarshals and validates the configuration.
+func decode(v *viper.Viper) (*Config, error) {
 	// Read config file
 	if err := v.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
@@ -255,10 +432,111 @@ func (c *Config) Validate() error {
 	if len(c.Teams) == 0 {
 		return fmt.Errorf("at least one team must be configured")
 	}
+	if c.Server.RateLimitRPS < 0 {
+		return fmt.Errorf("server.rate_limit_rps must be non-negative")
+	}
+	if c.Server.RateLimitBurst < 0 {
+		return fmt.Errorf("server.rate_limit_burst must be non-negative")
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				return fmt.Errorf("server.trusted_proxies: %q is not an IP or CIDR", proxy)
+			}
+		}
+	}
+	if c.Roulette.Weights.RecentReviewWindowHours < 0 {
+		return fmt.Errorf("roulette.weights.recent_review_window_hours must be non-negative")
+	}
+	if c.Scheduler.MinMRAgeHours < 0 {
+		return fmt.Errorf("scheduler.min_mr_age_hours must be non-negative")
+	}
+	if c.Scheduler.OverdueMRAgeHours < 0 {
+		return fmt.Errorf("scheduler.overdue_mr_age_hours must be non-negative")
+	}
+	if c.Scheduler.StaleMRCommentHours < 0 {
+		return fmt.Errorf("scheduler.stale_mr_comment_hours must be non-negative")
+	}
+	if c.Scheduler.BadgeEvaluationConcurrency < 0 {
+		return fmt.Errorf("scheduler.badge_evaluation_concurrency must be non-negative")
+	}
+	if c.Scheduler.BadgeEvaluationBatchSize < 0 {
+		return fmt.Errorf("scheduler.badge_evaluation_batch_size must be non-negative")
+	}
+	if c.Metrics.MinReviewComments < 0 {
+		return fmt.Errorf("metrics.min_review_comments must be non-negative")
+	}
+	if err := c.Metrics.Engagement.validate(); err != nil {
+		return err
+	}
+	if c.Scheduler.Enabled {
+		if err := c.Scheduler.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Leaderboard.FocusTeam != "" && c.GetTeamByName(c.Leaderboard.FocusTeam) == nil {
+		return fmt.Errorf("leaderboard.focus_team %q is not a configured team", c.Leaderboard.FocusTeam)
+	}
 
 	return nil
 }
 
+// validate checks that every engagement coefficient is non-negative.
+func (e *EngagementConfig) validate() error {
+	coefficients := []struct {
+		name  string
+		value float64
+	}{
+		{"comment_weight", e.CommentWeight},
+		{"length_divisor", e.LengthDivisor},
+		{"fast_response_hours", e.FastResponseHours},
+		{"fast_response_bonus", e.FastResponseBonus},
+		{"prompt_response_hours", e.PromptResponseHours},
+		{"prompt_response_bonus", e.PromptResponseBonus},
+	}
+	for _, coefficient := range coefficients {
+		if coefficient.value < 0 {
+			return fmt.Errorf("metrics.engagement.%s must be non-negative", coefficient.name)
+		}
+	}
+	return nil
+}
+
+// validate checks the daily notification time and the badge evaluation cron expression,
+// which are otherwise only parsed when the scheduler starts.
+func (c *SchedulerConfig) validate() error {
+	if _, _, err := c.ParseTime(); err != nil {
+		return fmt.Errorf("scheduler.time: %w", err)
+	}
+	if c.BadgeEvaluationTime != "" {
+		// Same parser as the scheduler's cron.New
+		if _, err := cron.ParseStandard(c.BadgeEvaluationTime); err != nil {
+			return fmt.Errorf("scheduler.badge_evaluation_time %q is not a valid cron expression: %w", c.BadgeEvaluationTime, err)
+		}
+	}
+	return nil
+}
+
+// ParseTime returns the hour and minute of the daily notification time (format: "HH:MM").
+func (c *SchedulerConfig) ParseTime() (hour, minute int, err error) {
+	parts := strings.Split(c.Time, ":")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid time format %q, expected HH:MM", c.Time)
+	}
+
+	hour, err = strconv.Atoi(parts[0])
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, 0, fmt.Errorf("invalid hour %q", parts[0])
+	}
+
+	minute, err = strconv.Atoi(parts[1])
+	if err != nil || minute < 0 || minute > 59 {
+		return 0, 0, fmt.Errorf("invalid minute %q", parts[1])
+	}
+
+	return hour, minute, nil
+}
+
 // GetLocation returns the timezone location.
 func (c *SchedulerConfig) GetLocation() (*time.Location, error) {
 	return time.LoadLocation(c.Timezone)
@@ -295,3 +573,30 @@ func (c *Config) GetUsersByRole(role string) []MemberConfig {
 	}
 	return users
 }
+
+// IsExcluded reports whether a user matches the excluded users list.
+// Usernames are compared case-insensitively; a zero GitLab ID never matches.
+func (c *ExcludedUsersConfig) IsExcluded(username string, gitlabID int) bool {
+	if c == nil {
+		return false
+	}
+	for _, excluded := range c.Usernames {
+		if username != "" && strings.EqualFold(excluded, username) {
+			return true
+		}
+	}
+	for _, excluded := range c.GitLabIDs {
+		if gitlabID != 0 && excluded == gitlabID {
+			return true
+		}
+	}
+	return false
+}
+
+// ExcludesUser reports whether a user matches the excluded users list. A nil user never matches.
+func (c *ExcludedUsersConfig) ExcludesUser(user *models.User) bool {
+	if user == nil {
+		return false
+	}
+	return c.IsExcluded(user.Username, user.GitLabID)
+}
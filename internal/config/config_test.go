@@ -0,0 +1,174 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const minimalConfig = `
+gitlab:
+  url: "https://gitlab.example.com"
+  token: "token"
+  webhook_secret: "secret"
+database:
+  postgres:
+    host: localhost
+    database: reviewer_roulette
+    user: postgres
+  redis:
+    host: localhost
+teams:
+  - name: backend
+`
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_MinMRAgeHours(t *testing.T) {
+	t.Run("defaults to 4 hours", func(t *testing.T) {
+		cfg, err := Load(writeConfig(t, minimalConfig))
+		require.NoError(t, err)
+		assert.Equal(t, 4, cfg.Scheduler.MinMRAgeHours)
+		assert.Equal(t, 48, cfg.Scheduler.OverdueMRAgeHours)
+	})
+
+	t.Run("read from config file", func(t *testing.T) {
+		cfg, err := Load(writeConfig(t, minimalConfig+"scheduler:\n  min_mr_age_hours: 1\n"))
+		require.NoError(t, err)
+		assert.Equal(t, 1, cfg.Scheduler.MinMRAgeHours)
+	})
+
+	t.Run("overridden by environment", func(t *testing.T) {
+		t.Setenv("SCHEDULER_MIN_MR_AGE_HOURS", "0")
+		cfg, err := Load(writeConfig(t, minimalConfig))
+		require.NoError(t, err)
+		assert.Equal(t, 0, cfg.Scheduler.MinMRAgeHours)
+	})
+
+	t.Run("negative is rejected", func(t *testing.T) {
+		_, err := Load(writeConfig(t, minimalConfig+"scheduler:\n  min_mr_age_hours: -1\n"))
+		assert.ErrorContains(t, err, "scheduler.min_mr_age_hours")
+	})
+}
+
+func TestLoad_MetricsEngagement(t *testing.T) {
+	t.Run("unset uses zero so the default formula applies", func(t *testing.T) {
+		cfg, err := Load(writeConfig(t, minimalConfig))
+		require.NoError(t, err)
+		assert.Equal(t, EngagementConfig{}, cfg.Metrics.Engagement)
+	})
+
+	t.Run("read from config file", func(t *testing.T) {
+		cfg, err := Load(writeConfig(t, minimalConfig+`metrics:
+  engagement:
+    comment_weight: 5
+    length_divisor: 0
+    fast_response_hours: 2
+    fast_response_bonus: 20
+    prompt_response_hours: 8
+    prompt_response_bonus: 1.5
+`))
+		require.NoError(t, err)
+		assert.Equal(t, EngagementConfig{
+			CommentWeight:       5,
+			FastResponseHours:   2,
+			FastResponseBonus:   20,
+			PromptResponseHours: 8,
+			PromptResponseBonus: 1.5,
+		}, cfg.Metrics.Engagement)
+	})
+
+	t.Run("negative coefficient is rejected", func(t *testing.T) {
+		_, err := Load(writeConfig(t, minimalConfig+"metrics:\n  engagement:\n    fast_response_bonus: -1\n"))
+		assert.ErrorContains(t, err, "metrics.engagement.fast_response_bonus")
+	})
+}
+
+func TestLoad_LeaderboardFocusTeam(t *testing.T) {
+	t.Run("read from config file", func(t *testing.T) {
+		cfg, err := Load(writeConfig(t, minimalConfig+"leaderboard:\n  focus_team: backend\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "backend", cfg.Leaderboard.FocusTeam)
+	})
+
+	t.Run("unknown team is rejected", func(t *testing.T) {
+		_, err := Load(writeConfig(t, minimalConfig+"leaderboard:\n  focus_team: frontend\n"))
+		assert.ErrorContains(t, err, "leaderboard.focus_team")
+	})
+}
+
+func TestLoad_SchedulerSchedule(t *testing.T) {
+	t.Run("valid time and cron", func(t *testing.T) {
+		_, err := Load(writeConfig(t, minimalConfig+"scheduler:\n  enabled: true\n  time: \"09:30\"\n  badge_evaluation_time: \"0 2 * * *\"\n"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("bad time is rejected", func(t *testing.T) {
+		_, err := Load(writeConfig(t, minimalConfig+"scheduler:\n  enabled: true\n  time: \"25:00\"\n"))
+		assert.ErrorContains(t, err, "scheduler.time")
+	})
+
+	t.Run("malformed cron is rejected", func(t *testing.T) {
+		_, err := Load(writeConfig(t, minimalConfig+"scheduler:\n  enabled: true\n  time: \"09:00\"\n  badge_evaluation_time: \"0 2 * *\"\n"))
+		assert.ErrorContains(t, err, "scheduler.badge_evaluation_time")
+	})
+
+	t.Run("not checked when disabled", func(t *testing.T) {
+		_, err := Load(writeConfig(t, minimalConfig+"scheduler:\n  time: \"9h\"\n"))
+		assert.NoError(t, err)
+	})
+}
+
+func TestLoadWatched_NotifiesOnTeamChange(t *testing.T) {
+	path := writeConfig(t, minimalConfig)
+	cfg, err := LoadWatched(path)
+	require.NoError(t, err)
+
+	changes := make(chan *Config, 10)
+	cfg.OnChange(func(reloaded *Config) {
+		changes <- reloaded
+	})
+
+	// An invalid reload is ignored, the next valid one is delivered
+	require.NoError(t, os.WriteFile(path, []byte(minimalConfig+"  - name: ops\nleaderboard:\n  focus_team: unknown\n"), 0o600))
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, os.WriteFile(path, []byte(minimalConfig+"  - name: frontend\n"), 0o600))
+
+	select {
+	case reloaded := <-changes:
+		require.Len(t, reloaded.Teams, 2)
+		assert.Equal(t, "frontend", reloaded.Teams[1].Name)
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnChange callback was not called")
+	}
+}
+
+func TestLoad_TrustedProxies(t *testing.T) {
+	t.Run("defaults to none", func(t *testing.T) {
+		cfg, err := Load(writeConfig(t, minimalConfig))
+		require.NoError(t, err)
+		assert.Empty(t, cfg.Server.TrustedProxies)
+		assert.Empty(t, cfg.Server.TrustedPlatform)
+	})
+
+	t.Run("read from config file", func(t *testing.T) {
+		cfg, err := Load(writeConfig(t, minimalConfig+"server:\n  trusted_proxies: [\"10.0.0.0/8\", \"192.168.1.10\"]\n  trusted_platform: CF-Connecting-IP\n"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.10"}, cfg.Server.TrustedProxies)
+		assert.Equal(t, "CF-Connecting-IP", cfg.Server.TrustedPlatform)
+	})
+
+	t.Run("malformed entry is rejected", func(t *testing.T) {
+		_, err := Load(writeConfig(t, minimalConfig+"server:\n  trusted_proxies: [\"proxy.local\"]\n"))
+		assert.ErrorContains(t, err, "server.trusted_proxies")
+	})
+}
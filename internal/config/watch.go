@@ -0,0 +1,83 @@
+package config
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+)
+
+// watcher tracks the last valid configuration of a watched file and its subscribers.
+type watcher struct {
+	mu          sync.Mutex
+	current     *Config
+	subscribers []func(*Config)
+}
+
+// LoadWatched reads the configuration like Load and keeps watching the file for changes.
+// Subscribers registered with OnChange are called when the teams or badges sections change.
+// Reloads that fail validation are logged and ignored, keeping the previous configuration.
+func LoadWatched(configPath string) (*Config, error) {
+	v := newViper(configPath)
+	config, err := decode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &watcher{current: config}
+	config.watcher = w
+
+	v.OnConfigChange(func(_ fsnotify.Event) {
+		w.reload(v)
+	})
+	v.WatchConfig()
+
+	return config, nil
+}
+
+// OnChange registers fn to be called with the reloaded configuration when the teams or
+// badges sections of a watched config file change. It does nothing for a config that
+// was not loaded with LoadWatched.
+func (c *Config) OnChange(fn func(*Config)) {
+	if c.watcher == nil {
+		return
+	}
+	c.watcher.mu.Lock()
+	defer c.watcher.mu.Unlock()
+	c.watcher.subscribers = append(c.watcher.subscribers, fn)
+}
+
+// reload decodes the changed file and notifies subscribers when teams or badges differ.
+func (w *watcher) reload(v *viper.Viper) {
+	log := logger.Get()
+
+	config, err := decode(v)
+	if err != nil {
+		log.Error().Err(err).Msg("Ignoring config reload, keeping previous configuration")
+		return
+	}
+	config.watcher = w
+
+	w.mu.Lock()
+	changed := !reflect.DeepEqual(w.current.Teams, config.Teams) ||
+		!reflect.DeepEqual(w.current.Badges, config.Badges)
+	if !changed {
+		w.mu.Unlock()
+		return
+	}
+	w.current = config
+	subscribers := append([]func(*Config){}, w.subscribers...)
+	w.mu.Unlock()
+
+	log.Info().
+		Int("teams", len(config.Teams)).
+		Int("badges", len(config.Badges)).
+		Msg("Configuration reloaded")
+
+	for _, fn := range subscribers {
+		fn(config)
+	}
+}
@@ -1,9 +1,11 @@
 package gitlab
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"strings"
+	"time"
 
 	gitlab "gitlab.com/gitlab-org/api/client-go"
 
@@ -80,10 +82,10 @@ func (c *Client) GetMergeRequestChanges(projectID, mrIID int) ([]*gitlab.MergeRe
 }
 
 // PostComment posts a comment on a merge request and returns the note ID.
-func (c *Client) PostComment(projectID, mrIID int, comment string) (int, error) {
+func (c *Client) PostComment(ctx context.Context, projectID, mrIID int, comment string) (int, error) {
 	note, _, err := c.client.Notes.CreateMergeRequestNote(projectID, mrIID, &gitlab.CreateMergeRequestNoteOptions{
 		Body: gitlab.Ptr(comment),
-	})
+	}, gitlab.WithContext(ctx))
 	if err != nil {
 		return 0, fmt.Errorf("failed to post comment on MR %d in project %d: %w", mrIID, projectID, err)
 	}
@@ -98,10 +100,10 @@ func (c *Client) PostComment(projectID, mrIID int, comment string) (int, error)
 }
 
 // UpdateComment updates an existing comment on a merge request.
-func (c *Client) UpdateComment(projectID, mrIID, noteID int, comment string) error {
+func (c *Client) UpdateComment(ctx context.Context, projectID, mrIID, noteID int, comment string) error {
 	_, _, err := c.client.Notes.UpdateMergeRequestNote(projectID, mrIID, noteID, &gitlab.UpdateMergeRequestNoteOptions{
 		Body: gitlab.Ptr(comment),
-	})
+	}, gitlab.WithContext(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to update comment %d on MR %d in project %d: %w", noteID, mrIID, projectID, err)
 	}
@@ -290,6 +292,26 @@ func (c *Client) ListOpenMergeRequests(projectID int) ([]*gitlab.BasicMergeReque
 	return allMRs, nil
 }
 
+// ListMergedMergeRequests retrieves one page of merged merge requests updated since the given time,
+// oldest first. It returns the next page number, or 0 when there are no more pages.
+func (c *Client) ListMergedMergeRequests(projectID int, since time.Time, page int) ([]*gitlab.BasicMergeRequest, int, error) {
+	mrs, resp, err := c.client.MergeRequests.ListProjectMergeRequests(projectID, &gitlab.ListProjectMergeRequestsOptions{
+		State:        gitlab.Ptr("merged"),
+		UpdatedAfter: gitlab.Ptr(since),
+		OrderBy:      gitlab.Ptr("created_at"),
+		Sort:         gitlab.Ptr("asc"),
+		ListOptions: gitlab.ListOptions{
+			Page:    page,
+			PerPage: 100,
+		},
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list merged MRs for project %d (page %d): %w", projectID, page, err)
+	}
+
+	return mrs, resp.NextPage, nil
+}
+
 // IsUserAvailable checks if a user is available based on their status.
 func IsUserAvailable(status *UserStatus, oooKeywords []string) bool {
 	if status == nil {
@@ -45,6 +45,15 @@ func New(lang string) (*Translator, error) {
 	}, nil
 }
 
+// MustNew is like New but panics if the embedded translations cannot be loaded.
+func MustNew(lang string) *Translator {
+	t, err := New(lang)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
 // Get retrieves a translated message by key with optional template data. Returns the key itself if translation not found.
 func (t *Translator) Get(key string, data ...map[string]interface{}) string {
 	message, ok := t.messages[key]
@@ -585,6 +585,14 @@ func TestAllEnglishTranslations(t *testing.T) {
 		"errors.selection_failed",
 		"errors.invalid_command",
 		"errors.webhook_processing",
+		"mattermost.daily_reminder.title",
+		"mattermost.daily_reminder.pending_count",
+		"mattermost.daily_reminder.overdue_section",
+		"mattermost.daily_reminder.pending_section",
+		"mattermost.daily_reminder.mr_line",
+		"mattermost.daily_reminder.age_hours",
+		"mattermost.daily_reminder.age_days",
+		"mattermost.daily_reminder.footer",
 	}
 
 	for _, key := range expectedKeys {
@@ -623,6 +631,14 @@ func TestAllFrenchTranslations(t *testing.T) {
 		"errors.selection_failed",
 		"errors.invalid_command",
 		"errors.webhook_processing",
+		"mattermost.daily_reminder.title",
+		"mattermost.daily_reminder.pending_count",
+		"mattermost.daily_reminder.overdue_section",
+		"mattermost.daily_reminder.pending_section",
+		"mattermost.daily_reminder.mr_line",
+		"mattermost.daily_reminder.age_hours",
+		"mattermost.daily_reminder.age_days",
+		"mattermost.daily_reminder.footer",
 	}
 
 	for _, key := range expectedKeys {
@@ -5,32 +5,82 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/i18n"
 	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
 )
 
+const (
+	// maxSendAttempts bounds immediate delivery attempts before a message is
+	// handed to the retry queue (or dropped).
+	maxSendAttempts = 3
+	// defaultRetryBackoff is the delay before the second attempt, doubled per attempt.
+	defaultRetryBackoff = 500 * time.Millisecond
+	// sendTimeout bounds a whole delivery, including retries.
+	sendTimeout = 30 * time.Second
+)
+
+// RetryQueue persists failed deliveries so they can be retried later.
+type RetryQueue interface {
+	Enqueue(url string, payload []byte, lastErr error) error
+}
+
 // Client handles Mattermost webhook notifications.
 type Client struct {
-	webhookURL string
-	channel    string
-	enabled    bool
-	log        *logger.Logger
+	webhookURL   string
+	channel      string
+	achievements string
+	enabled      bool
+	apiURL       string
+	botToken     string
+	channelID    string
+	httpClient   *http.Client
+	retryBackoff time.Duration
+	retryQueue   RetryQueue
+	dedupStore   FingerprintStore
+	dedupWindow  time.Duration
+	translator   *i18n.Translator
+	log          *logger.Logger
 }
 
-// NewClient creates a new Mattermost client.
-func NewClient(cfg *config.MattermostConfig, log *logger.Logger) *Client {
+// NewClient creates a new Mattermost client. A nil httpClient uses a client with a 10s timeout.
+// Messages are in English until SetTranslator is called.
+func NewClient(cfg *config.MattermostConfig, httpClient *http.Client, log *logger.Logger) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
 	return &Client{
-		webhookURL: cfg.WebhookURL,
-		channel:    cfg.Channel,
-		enabled:    cfg.Enabled,
-		log:        log,
+		webhookURL:   cfg.WebhookURL,
+		channel:      cfg.Channel,
+		achievements: cfg.AchievementsChannel,
+		enabled:      cfg.Enabled,
+		apiURL:       strings.TrimRight(cfg.URL, "/"),
+		botToken:     cfg.BotToken,
+		channelID:    cfg.ChannelID,
+		httpClient:   httpClient,
+		retryBackoff: defaultRetryBackoff,
+		translator:   i18n.MustNew("en"),
+		log:          log,
 	}
 }
 
+// SetTranslator sets the language used for message templates.
+func (c *Client) SetTranslator(translator *i18n.Translator) {
+	c.translator = translator
+}
+
+// SetRetryQueue enables queuing failed messages for retry instead of dropping them.
+func (c *Client) SetRetryQueue(queue RetryQueue) {
+	c.retryQueue = queue
+}
+
 // Message represents a Mattermost message payload.
 type Message struct {
 	Channel     string       `json:"channel,omitempty"`
@@ -81,29 +131,106 @@ func (c *Client) SendMessage(msg *Message) error {
 		return fmt.Errorf("failed to marshal message: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
 	defer cancel()
 
+	key, send := c.claim(ctx, c.webhookURL, payload)
+	if !send {
+		return nil
+	}
+
+	err = c.withRetry(ctx, func(ctx context.Context) error {
+		return c.post(ctx, payload)
+	})
+	if err != nil {
+		if c.retryQueue == nil {
+			c.release(key)
+			return err
+		}
+		if qErr := c.retryQueue.Enqueue(c.webhookURL, payload, err); qErr != nil {
+			c.release(key)
+			return fmt.Errorf("%w (retry queue unavailable: %v)", err, qErr)
+		}
+		c.log.Warn().
+			Err(err).
+			Str("channel", msg.Channel).
+			Msg("Failed to send message to Mattermost, queued for retry")
+		return nil
+	}
+
+	c.log.Debug().
+		Str("channel", msg.Channel).
+		Msg("Sent message to Mattermost")
+
+	return nil
+}
+
+// withRetry runs send, retrying network errors and 5xx responses with
+// exponential backoff. Client errors (4xx) are returned immediately.
+func (c *Client) withRetry(ctx context.Context, send func(context.Context) error) error {
+	backoff := c.retryBackoff
+
+	var err error
+	for attempt := 1; attempt <= maxSendAttempts; attempt++ {
+		err = send(ctx)
+		if err == nil || !isRetryable(err) || attempt == maxSendAttempts {
+			return err
+		}
+
+		c.log.Debug().
+			Err(err).
+			Int("attempt", attempt).
+			Dur("backoff", backoff).
+			Msg("Mattermost delivery failed, retrying")
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w (gave up after %d attempts: %v)", err, attempt, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	return err
+}
+
+// statusError reports a non-2xx response from Mattermost.
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("mattermost returned status %d", e.code)
+}
+
+// isRetryable reports whether a delivery error is worth retrying:
+// network failures and server errors are, client errors are not.
+func isRetryable(err error) bool {
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.code >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// post delivers a JSON payload to the webhook URL.
+func (c *Client) post(ctx context.Context, payload []byte) error {
 	req, err := http.NewRequestWithContext(ctx, "POST", c.webhookURL, bytes.NewBuffer(payload))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send message to Mattermost: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("mattermost returned status %d", resp.StatusCode)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &statusError{code: resp.StatusCode}
 	}
 
-	c.log.Debug().
-		Str("channel", msg.Channel).
-		Msg("Sent message to Mattermost")
-
 	return nil
 }
 
@@ -115,36 +242,83 @@ func (c *Client) SendSimpleMessage(text string) error {
 }
 
 // SendDailyReviewReminder sends a daily reminder about pending reviews.
-func (c *Client) SendDailyReviewReminder(pendingMRs []PendingMR) error {
+// An empty channel posts to the default configured channel.
+// MRs older than overdueAfter are listed first in an urgent section;
+// a non-positive overdueAfter disables that section.
+func (c *Client) SendDailyReviewReminder(channel string, pendingMRs []PendingMR, overdueAfter time.Duration) error {
 	if len(pendingMRs) == 0 {
 		c.log.Debug().Msg("No pending MRs, skipping daily reminder")
 		return nil
 	}
 
-	text := fmt.Sprintf("### 📋 Daily Review Reminder\n\nThere are **%d** merge requests pending review:\n\n", len(pendingMRs))
+	return c.SendMessage(&Message{
+		Channel:  channel,
+		Username: "Reviewer Roulette Bot",
+		Text:     buildDailyReminderText(c.translator, pendingMRs, overdueAfter),
+	})
+}
+
+// buildDailyReminderText formats the daily reminder, with overdue MRs
+// (oldest first) in their own section above the rest.
+func buildDailyReminderText(tr *i18n.Translator, pendingMRs []PendingMR, overdueAfter time.Duration) string {
+	type agedMR struct {
+		mr  PendingMR
+		age time.Duration
+	}
 
+	var overdue, normal []agedMR
 	for _, mr := range pendingMRs {
-		age := mr.Age()
-		ageStr := fmt.Sprintf("%.1f hours", age.Hours())
-		if age.Hours() > 24 {
-			ageStr = fmt.Sprintf("%.1f days", age.Hours()/24)
+		aged := agedMR{mr: mr, age: mr.Age()}
+		if overdueAfter > 0 && aged.age > overdueAfter {
+			overdue = append(overdue, aged)
+		} else {
+			normal = append(normal, aged)
 		}
+	}
+	sort.SliceStable(overdue, func(i, j int) bool {
+		return overdue[i].age > overdue[j].age
+	})
 
-		// Add warning icon for old MRs
-		icon := "•"
-		if age.Hours() > 48 {
-			icon = "⚠️"
+	formatLine := func(icon string, aged agedMR) string {
+		ageStr := tr.Get("mattermost.daily_reminder.age_hours", map[string]interface{}{
+			"Value": fmt.Sprintf("%.1f", aged.age.Hours()),
+		})
+		if aged.age.Hours() > 24 {
+			ageStr = tr.Get("mattermost.daily_reminder.age_days", map[string]interface{}{
+				"Value": fmt.Sprintf("%.1f", aged.age.Hours()/24),
+			})
 		}
+		return tr.Get("mattermost.daily_reminder.mr_line", map[string]interface{}{
+			"Icon":   icon,
+			"Title":  aged.mr.Title,
+			"URL":    aged.mr.URL,
+			"Author": aged.mr.Author,
+			"Age":    ageStr,
+		}) + "\n"
+	}
 
-		text += fmt.Sprintf("%s [%s](%s) by @%s (%s old)\n", icon, mr.Title, mr.URL, mr.Author, ageStr)
+	text := tr.Get("mattermost.daily_reminder.title") + "\n\n" +
+		tr.Get("mattermost.daily_reminder.pending_count", map[string]interface{}{"Count": len(pendingMRs)}) + "\n\n"
+
+	if len(overdue) > 0 {
+		text += "#### " + tr.Get("mattermost.daily_reminder.overdue_section", map[string]interface{}{
+			"Hours": fmt.Sprintf("%.0f", overdueAfter.Hours()),
+		}) + "\n\n"
+		for _, aged := range overdue {
+			text += formatLine("⚠️", aged)
+		}
+		if len(normal) > 0 {
+			text += "\n#### " + tr.Get("mattermost.daily_reminder.pending_section") + "\n\n"
+		}
 	}
 
-	text += "\n_Please review these merge requests when you have time!_ 🙏"
+	for _, aged := range normal {
+		text += formatLine("•", aged)
+	}
 
-	return c.SendMessage(&Message{
-		Username: "Reviewer Roulette Bot",
-		Text:     text,
-	})
+	text += "\n" + tr.Get("mattermost.daily_reminder.footer")
+
+	return text
 }
 
 // PendingMR represents a pending merge request for daily reminders.
@@ -193,6 +367,69 @@ func (c *Client) SendRouletteResult(_, _ int, mrURL string, selections []Reviewe
 	return c.SendSimpleMessage(text)
 }
 
+// SendBadgeAward announces a newly earned badge in the achievements channel,
+// or the default channel when none is configured.
+func (c *Client) SendBadgeAward(username, badgeName, badgeIcon string) error {
+	if !c.enabled {
+		return nil
+	}
+
+	return c.SendMessage(&Message{
+		Channel:  c.achievements,
+		Username: "Reviewer Roulette Bot",
+		Text:     buildBadgeAwardText(username, badgeName, badgeIcon),
+	})
+}
+
+// SendNewBadgeAnnouncement tells the achievements channel, or the default channel
+// when none is configured, that a badge was added to the catalog and how to earn it.
+func (c *Client) SendNewBadgeAnnouncement(badgeName, description, badgeIcon string) error {
+	if !c.enabled {
+		return nil
+	}
+
+	return c.SendMessage(&Message{
+		Channel:  c.achievements,
+		Username: "Reviewer Roulette Bot",
+		Text:     buildNewBadgeText(badgeName, description, badgeIcon),
+	})
+}
+
+// SendFallbackReviewerNotice reports that a team had no available reviewer
+// and the review was escalated to its fallback reviewer.
+func (c *Client) SendFallbackReviewerNotice(team, username, mrURL string) error {
+	if !c.enabled {
+		return nil
+	}
+
+	text := fmt.Sprintf("🚨 No available reviewer in **%s**, escalated to fallback reviewer @%s", team, username)
+	if mrURL != "" {
+		text += fmt.Sprintf("\n\n[View Merge Request](%s)", mrURL)
+	}
+
+	return c.SendSimpleMessage(text)
+}
+
+// buildBadgeAwardText formats the celebratory badge award message.
+func buildBadgeAwardText(username, badgeName, badgeIcon string) string {
+	if badgeIcon == "" {
+		return fmt.Sprintf("🏅 @%s earned **%s**!", username, badgeName)
+	}
+	return fmt.Sprintf("🏅 @%s earned %s **%s**!", username, badgeIcon, badgeName)
+}
+
+// buildNewBadgeText formats the announcement of a badge added to the catalog.
+func buildNewBadgeText(badgeName, description, badgeIcon string) string {
+	text := fmt.Sprintf("✨ New badge available: **%s**", badgeName)
+	if badgeIcon != "" {
+		text = fmt.Sprintf("✨ New badge available: %s **%s**", badgeIcon, badgeName)
+	}
+	if description != "" {
+		text += "\n\n" + description
+	}
+	return text
+}
+
 // ReviewerSelection represents a selected reviewer.
 type ReviewerSelection struct {
 	Username      string
@@ -0,0 +1,361 @@
+package mattermost
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/i18n"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+)
+
+func TestBuildBadgeAwardText(t *testing.T) {
+	tests := []struct {
+		name      string
+		username  string
+		badgeName string
+		badgeIcon string
+		want      string
+	}{
+		{
+			name:      "with icon",
+			username:  "alice",
+			badgeName: "Speed Demon",
+			badgeIcon: "⚡",
+			want:      "🏅 @alice earned ⚡ **Speed Demon**!",
+		},
+		{
+			name:      "without icon",
+			username:  "bob",
+			badgeName: "Thorough Reviewer",
+			want:      "🏅 @bob earned **Thorough Reviewer**!",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildBadgeAwardText(tt.username, tt.badgeName, tt.badgeIcon)
+			if got != tt.want {
+				t.Errorf("buildBadgeAwardText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildDailyReminderText_OverdueSection(t *testing.T) {
+	pendingMR := func(title string, age time.Duration) PendingMR {
+		return PendingMR{Title: title, URL: "https://gitlab.example.com/" + title, Author: "alice", Age: func() time.Duration { return age }}
+	}
+
+	text := buildDailyReminderText(i18n.MustNew("en"), []PendingMR{
+		pendingMR("fresh", 6*time.Hour),
+		pendingMR("old", 60*time.Hour),
+		pendingMR("oldest", 120*time.Hour),
+		pendingMR("recent", 30*time.Hour),
+	}, 48*time.Hour)
+
+	overdueIdx := strings.Index(text, "🚨 Overdue (>48h)")
+	pendingIdx := strings.Index(text, "#### Pending")
+	if overdueIdx < 0 || pendingIdx < overdueIdx {
+		t.Fatalf("expected overdue section before pending section, got:\n%s", text)
+	}
+
+	oldestIdx := strings.Index(text, "[oldest]")
+	oldIdx := strings.Index(text, "[old]")
+	if oldestIdx < overdueIdx || oldIdx < oldestIdx || oldIdx > pendingIdx {
+		t.Errorf("expected overdue MRs oldest-first in the urgent section, got:\n%s", text)
+	}
+	for _, title := range []string{"[fresh]", "[recent]"} {
+		if strings.Index(text, title) < pendingIdx {
+			t.Errorf("expected %s in the pending section, got:\n%s", title, text)
+		}
+	}
+}
+
+func TestBuildDailyReminderText_NoOverdue(t *testing.T) {
+	text := buildDailyReminderText(i18n.MustNew("en"), []PendingMR{
+		{Title: "fresh", Author: "alice", Age: func() time.Duration { return 6 * time.Hour }},
+	}, 48*time.Hour)
+
+	if strings.Contains(text, "Overdue") || strings.Contains(text, "#### Pending") {
+		t.Errorf("expected no sections without overdue MRs, got:\n%s", text)
+	}
+	if !strings.Contains(text, "• [fresh]") {
+		t.Errorf("expected MR to be listed, got:\n%s", text)
+	}
+}
+
+func TestBuildDailyReminderText_Localized(t *testing.T) {
+	pendingMRs := []PendingMR{
+		{Title: "old", Author: "alice", Age: func() time.Duration { return 72 * time.Hour }},
+		{Title: "fresh", Author: "bob", Age: func() time.Duration { return 6 * time.Hour }},
+	}
+
+	text := buildDailyReminderText(i18n.MustNew("fr"), pendingMRs, 48*time.Hour)
+
+	for _, want := range []string{
+		"### 📋 Rappel Quotidien des Reviews",
+		"Il y a **2** merge requests en attente de review :",
+		"#### 🚨 En retard (>48h)",
+		"par @alice (depuis 3.0 jours)",
+		"par @bob (depuis 6.0 heures)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in French reminder, got:\n%s", want, text)
+		}
+	}
+
+	// Unsupported languages fall back to English
+	text = buildDailyReminderText(i18n.MustNew("de"), pendingMRs, 48*time.Hour)
+	if !strings.HasPrefix(text, "### 📋 Daily Review Reminder") {
+		t.Errorf("expected English fallback header, got:\n%s", text)
+	}
+}
+
+func TestBuildNewBadgeText(t *testing.T) {
+	tests := []struct {
+		name        string
+		badgeName   string
+		description string
+		badgeIcon   string
+		want        string
+	}{
+		{
+			name:        "with icon and description",
+			badgeName:   "Night Owl",
+			description: "Complete 10 reviews after 8 PM",
+			badgeIcon:   "🦉",
+			want:        "✨ New badge available: 🦉 **Night Owl**\n\nComplete 10 reviews after 8 PM",
+		},
+		{
+			name:      "name only",
+			badgeName: "Night Owl",
+			want:      "✨ New badge available: **Night Owl**",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildNewBadgeText(tt.badgeName, tt.description, tt.badgeIcon)
+			if got != tt.want {
+				t.Errorf("buildNewBadgeText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendNewBadgeAnnouncement(t *testing.T) {
+	var received Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.MattermostConfig{
+		WebhookURL:          server.URL,
+		Channel:             "reviews",
+		AchievementsChannel: "achievements",
+		Enabled:             true,
+	}, nil, logger.New("debug", "text", "stdout"))
+
+	if err := client.SendNewBadgeAnnouncement("Night Owl", "Complete 10 reviews after 8 PM", "🦉"); err != nil {
+		t.Fatalf("SendNewBadgeAnnouncement failed: %v", err)
+	}
+
+	if received.Channel != "achievements" {
+		t.Errorf("Channel = %q, want %q", received.Channel, "achievements")
+	}
+	if !strings.Contains(received.Text, "Night Owl") || !strings.Contains(received.Text, "after 8 PM") {
+		t.Errorf("Text = %q, want badge name and description", received.Text)
+	}
+}
+
+func TestSendBadgeAward(t *testing.T) {
+	var received Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.MattermostConfig{
+		WebhookURL: server.URL,
+		Channel:    "reviews",
+		Enabled:    true,
+	}, nil, logger.New("debug", "text", "stdout"))
+
+	if err := client.SendBadgeAward("alice", "Speed Demon", "⚡"); err != nil {
+		t.Fatalf("SendBadgeAward failed: %v", err)
+	}
+
+	if received.Channel != "reviews" {
+		t.Errorf("Channel = %q, want %q", received.Channel, "reviews")
+	}
+	if !strings.Contains(received.Text, "Speed Demon") || !strings.Contains(received.Text, "⚡") {
+		t.Errorf("Text = %q, want badge name and icon", received.Text)
+	}
+}
+
+func TestSendBadgeAward_AchievementsChannel(t *testing.T) {
+	var received []Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg Message
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		received = append(received, msg)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.MattermostConfig{
+		WebhookURL:          server.URL,
+		Channel:             "reviews",
+		AchievementsChannel: "achievements",
+		Enabled:             true,
+	}, nil, logger.New("debug", "text", "stdout"))
+
+	if err := client.SendBadgeAward("alice", "Speed Demon", "⚡"); err != nil {
+		t.Fatalf("SendBadgeAward failed: %v", err)
+	}
+	pending := []PendingMR{{Title: "Fix bug", URL: "https://gitlab.example.com/mr/1", Age: func() time.Duration { return time.Hour }}}
+	if err := client.SendDailyReviewReminder("", pending, 0); err != nil {
+		t.Fatalf("SendDailyReviewReminder failed: %v", err)
+	}
+
+	if len(received) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(received))
+	}
+	if received[0].Channel != "achievements" {
+		t.Errorf("Badge announcement channel = %q, want %q", received[0].Channel, "achievements")
+	}
+	if received[1].Channel != "reviews" {
+		t.Errorf("Reminder channel = %q, want %q", received[1].Channel, "reviews")
+	}
+}
+
+func TestSendBadgeAward_Disabled(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.MattermostConfig{
+		WebhookURL: server.URL,
+		Enabled:    false,
+	}, nil, logger.New("debug", "text", "stdout"))
+
+	if err := client.SendBadgeAward("alice", "Speed Demon", "⚡"); err != nil {
+		t.Fatalf("SendBadgeAward failed: %v", err)
+	}
+	if called {
+		t.Error("Expected no request when Mattermost is disabled")
+	}
+}
+
+type fakeRetryQueue struct {
+	url     string
+	payload []byte
+	lastErr error
+}
+
+func (q *fakeRetryQueue) Enqueue(url string, payload []byte, lastErr error) error {
+	q.url, q.payload, q.lastErr = url, payload, lastErr
+	return nil
+}
+
+func TestSendMessage_QueuesFailedDelivery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.MattermostConfig{WebhookURL: server.URL, Enabled: true}, nil, logger.New("error", "json", "stdout"))
+	client.retryBackoff = time.Millisecond
+
+	if err := client.SendSimpleMessage("hello"); err == nil {
+		t.Fatal("Expected error without a retry queue")
+	}
+
+	queue := &fakeRetryQueue{}
+	client.SetRetryQueue(queue)
+
+	if err := client.SendSimpleMessage("hello"); err != nil {
+		t.Fatalf("Expected queued delivery to succeed, got %v", err)
+	}
+	if queue.url != server.URL || !strings.Contains(string(queue.payload), "hello") || queue.lastErr == nil {
+		t.Errorf("Unexpected queued delivery: url=%q payload=%q err=%v", queue.url, queue.payload, queue.lastErr)
+	}
+}
+
+func TestSendMessage_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) <= 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.MattermostConfig{WebhookURL: server.URL, Enabled: true}, server.Client(), logger.New("error", "json", "stdout"))
+	client.retryBackoff = time.Millisecond
+
+	if err := client.SendSimpleMessage("hello"); err != nil {
+		t.Fatalf("Expected delivery after retries, got %v", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("Expected 3 attempts, got %d", got)
+	}
+}
+
+func TestSendMessage_RetriesAreBounded(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.MattermostConfig{WebhookURL: server.URL, Enabled: true}, server.Client(), logger.New("error", "json", "stdout"))
+	client.retryBackoff = time.Millisecond
+
+	if err := client.SendSimpleMessage("hello"); err == nil {
+		t.Fatal("Expected error after exhausting retries")
+	}
+	if got := calls.Load(); got != maxSendAttempts {
+		t.Errorf("Expected %d attempts, got %d", maxSendAttempts, got)
+	}
+}
+
+func TestSendMessage_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.MattermostConfig{WebhookURL: server.URL, Enabled: true}, server.Client(), logger.New("error", "json", "stdout"))
+	client.retryBackoff = time.Millisecond
+
+	if err := client.SendSimpleMessage("hello"); err == nil {
+		t.Fatal("Expected error for 400 response")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Expected a single attempt for a client error, got %d", got)
+	}
+}
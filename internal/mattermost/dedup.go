@@ -0,0 +1,67 @@
+package mattermost
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// dedupKeyPrefix namespaces message fingerprints in the store.
+const dedupKeyPrefix = "mattermost:sent:"
+
+// FingerprintStore remembers fingerprints of recently sent messages.
+// *cache.Cache satisfies it.
+type FingerprintStore interface {
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+	Del(ctx context.Context, keys ...string) error
+}
+
+// SetDeduplication suppresses a message identical to one sent to the same destination
+// within window, e.g. when a manual run follows the scheduled one. A non-positive
+// window or a nil store disables de-duplication.
+func (c *Client) SetDeduplication(store FingerprintStore, window time.Duration) {
+	if store == nil || window <= 0 {
+		c.dedupStore = nil
+		return
+	}
+	c.dedupStore = store
+	c.dedupWindow = window
+}
+
+// claim records the fingerprint of content sent to destination and reports whether
+// it should be sent. It returns an empty key when the content is not tracked.
+// Store errors fail open, so a cache outage never silences notifications.
+func (c *Client) claim(ctx context.Context, destination string, content []byte) (key string, send bool) {
+	if c.dedupStore == nil {
+		return "", true
+	}
+
+	sum := sha256.Sum256(append([]byte(destination+"\n"), content...))
+	key = dedupKeyPrefix + hex.EncodeToString(sum[:])
+
+	first, err := c.dedupStore.SetNX(ctx, key, 1, c.dedupWindow)
+	if err != nil {
+		c.log.Warn().Err(err).Msg("Failed to check Mattermost message fingerprint, sending anyway")
+		return "", true
+	}
+	if !first {
+		c.log.Info().
+			Str("destination", destination).
+			Dur("window", c.dedupWindow).
+			Msg("Skipping Mattermost message identical to one sent recently")
+		return "", false
+	}
+	return key, true
+}
+
+// release forgets a fingerprint after a failed delivery so the message can be sent again.
+// It does not reuse the delivery context, which may have timed out.
+func (c *Client) release(key string) {
+	if key == "" {
+		return
+	}
+	if err := c.dedupStore.Del(context.Background(), key); err != nil {
+		c.log.Warn().Err(err).Msg("Failed to release Mattermost message fingerprint")
+	}
+}
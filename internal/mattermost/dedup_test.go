@@ -0,0 +1,177 @@
+package mattermost
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+)
+
+// fakeFingerprintStore is an in-memory FingerprintStore honoring expirations.
+type fakeFingerprintStore struct {
+	expires map[string]time.Time
+	err     error
+}
+
+func newFakeFingerprintStore() *fakeFingerprintStore {
+	return &fakeFingerprintStore{expires: make(map[string]time.Time)}
+}
+
+func (s *fakeFingerprintStore) SetNX(_ context.Context, key string, _ interface{}, expiration time.Duration) (bool, error) {
+	if s.err != nil {
+		return false, s.err
+	}
+	if exp, ok := s.expires[key]; ok && time.Now().Before(exp) {
+		return false, nil
+	}
+	s.expires[key] = time.Now().Add(expiration)
+	return true, nil
+}
+
+func (s *fakeFingerprintStore) Del(_ context.Context, keys ...string) error {
+	for _, key := range keys {
+		delete(s.expires, key)
+	}
+	return nil
+}
+
+// expireAll simulates the dedup window elapsing.
+func (s *fakeFingerprintStore) expireAll() {
+	for key := range s.expires {
+		s.expires[key] = time.Now().Add(-time.Second)
+	}
+}
+
+func TestSendDailyReviewReminder_Deduplicated(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.MattermostConfig{WebhookURL: server.URL, Channel: "reviews", Enabled: true}, server.Client(), logger.New("error", "json", "stdout"))
+	store := newFakeFingerprintStore()
+	client.SetDeduplication(store, 10*time.Minute)
+
+	pending := []PendingMR{{Title: "Fix login", URL: "https://gitlab.example.com/mr/1", Author: "alice", Age: func() time.Duration { return 6 * time.Hour }}}
+
+	for i := 0; i < 2; i++ {
+		if err := client.SendDailyReviewReminder("", pending, 0); err != nil {
+			t.Fatalf("SendDailyReviewReminder #%d failed: %v", i+1, err)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("Expected the identical reminder to be sent once within the window, got %d", got)
+	}
+
+	// Another channel is a different destination
+	if err := client.SendDailyReviewReminder("team-frontend", pending, 0); err != nil {
+		t.Fatalf("SendDailyReviewReminder failed: %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("Expected the reminder for another channel to be sent, got %d calls", got)
+	}
+
+	// Once the window has passed, the reminder is sent again
+	store.expireAll()
+	if err := client.SendDailyReviewReminder("", pending, 0); err != nil {
+		t.Fatalf("SendDailyReviewReminder failed: %v", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("Expected the reminder to be sent again after the window, got %d calls", got)
+	}
+}
+
+func TestSendMessage_DedupReleasedOnFailure(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.MattermostConfig{WebhookURL: server.URL, Enabled: true}, server.Client(), logger.New("error", "json", "stdout"))
+	client.SetDeduplication(newFakeFingerprintStore(), 10*time.Minute)
+
+	if err := client.SendSimpleMessage("hello"); err == nil {
+		t.Fatal("Expected the failed delivery to return an error")
+	}
+
+	failing.Store(false)
+	if err := client.SendSimpleMessage("hello"); err != nil {
+		t.Fatalf("Expected the retried message to be sent, got %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("Expected a failed delivery not to suppress the next attempt, got %d calls", got)
+	}
+}
+
+func TestSendMessage_DedupStoreErrorSendsAnyway(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	store := newFakeFingerprintStore()
+	store.err = errors.New("redis down")
+	client := NewClient(&config.MattermostConfig{WebhookURL: server.URL, Enabled: true}, server.Client(), logger.New("error", "json", "stdout"))
+	client.SetDeduplication(store, 10*time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if err := client.SendSimpleMessage("hello"); err != nil {
+			t.Fatalf("SendSimpleMessage failed: %v", err)
+		}
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("Expected both messages to be sent when the store is down, got %d", got)
+	}
+}
+
+func TestSendThreadedReminder_DeduplicatedAcrossRerun(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		id := calls.Add(1)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(apiPost{ID: fmt.Sprintf("post-%d", id)})
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.MattermostConfig{
+		WebhookURL: "http://webhook.invalid",
+		Enabled:    true,
+		URL:        server.URL + "/",
+		BotToken:   "bot-token",
+		ChannelID:  "channel-123",
+	}, server.Client(), logger.New("error", "json", "stdout"))
+	client.SetDeduplication(newFakeFingerprintStore(), time.Hour)
+
+	// The scheduled run starts the thread; a manual rerun replies to the saved root
+	rootID, err := client.SendThreadedReminder("", "", "", threadTestMRs(), 48*time.Hour)
+	if err != nil {
+		t.Fatalf("SendThreadedReminder (root) failed: %v", err)
+	}
+	if _, err := client.SendThreadedReminder("", "", rootID, threadTestMRs(), 48*time.Hour); err != nil {
+		t.Fatalf("SendThreadedReminder (rerun) failed: %v", err)
+	}
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Expected the rerun reminder to be suppressed, got %d posts", got)
+	}
+}
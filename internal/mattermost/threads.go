@@ -0,0 +1,114 @@
+package mattermost
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// apiPost is the subset of a Mattermost post used by the bot API.
+type apiPost struct {
+	ID        string `json:"id,omitempty"`
+	ChannelID string `json:"channel_id"`
+	RootID    string `json:"root_id,omitempty"`
+	Message   string `json:"message"`
+}
+
+// ThreadingEnabled reports whether reminders can be threaded, which requires
+// the bot API (server URL, bot token and channel ID) rather than a webhook.
+func (c *Client) ThreadingEnabled() bool {
+	return c.apiURL != "" && c.botToken != "" && c.channelID != ""
+}
+
+// SendThreadedReminder posts a daily reminder as a reply to rootID, or as a new
+// thread root when rootID is empty, and returns the root post ID to reply to next.
+// The post goes to channelID, or to the configured channel ID when empty.
+// Without bot API credentials it falls back to the webhook, which cannot thread,
+// posting to channel (a channel name) and returning rootID unchanged.
+func (c *Client) SendThreadedReminder(channel, channelID, rootID string, pendingMRs []PendingMR, overdueAfter time.Duration) (string, error) {
+	if !c.ThreadingEnabled() {
+		return rootID, c.SendDailyReviewReminder(channel, pendingMRs, overdueAfter)
+	}
+
+	if channelID == "" {
+		channelID = c.channelID
+	}
+
+	if !c.enabled {
+		c.log.Debug().Msg("Mattermost is disabled, skipping threaded reminder")
+		return rootID, nil
+	}
+
+	if len(pendingMRs) == 0 {
+		return rootID, nil
+	}
+
+	text := buildDailyReminderText(c.translator, pendingMRs, overdueAfter)
+	payload, err := json.Marshal(apiPost{
+		ChannelID: channelID,
+		RootID:    rootID,
+		Message:   text,
+	})
+	if err != nil {
+		return rootID, fmt.Errorf("failed to marshal post: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+
+	// The root is left out of the fingerprint, so a rerun replying to the thread
+	// is recognized as the reminder that started it
+	key, send := c.claim(ctx, c.apiURL+"/channels/"+channelID, []byte(text))
+	if !send {
+		return rootID, nil
+	}
+
+	var created apiPost
+	err = c.withRetry(ctx, func(ctx context.Context) error {
+		return c.createPost(ctx, payload, &created)
+	})
+	if err != nil {
+		c.release(key)
+		return rootID, err
+	}
+
+	c.log.Debug().
+		Str("post_id", created.ID).
+		Str("channel_id", channelID).
+		Str("root_id", rootID).
+		Msg("Sent threaded reminder to Mattermost")
+
+	if rootID == "" {
+		return created.ID, nil
+	}
+	return rootID, nil
+}
+
+// createPost creates a post through the bot API and decodes the created post into out.
+func (c *Client) createPost(ctx context.Context, payload []byte, out *apiPost) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.apiURL+"/api/v4/posts", bytes.NewBuffer(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.botToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send post to Mattermost: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &statusError{code: resp.StatusCode}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode Mattermost post: %w", err)
+	}
+
+	return nil
+}
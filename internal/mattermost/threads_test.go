@@ -0,0 +1,124 @@
+package mattermost
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+)
+
+func threadTestMRs() []PendingMR {
+	return []PendingMR{
+		{Title: "Fix login", URL: "https://gitlab.example.com/mr/1", Author: "alice", Age: func() time.Duration { return 6 * time.Hour }},
+	}
+}
+
+func TestSendThreadedReminder_WebhookFallback(t *testing.T) {
+	var received Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Error("Webhook fallback should not send an Authorization header")
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.MattermostConfig{
+		WebhookURL: server.URL,
+		Channel:    "reviews",
+		Enabled:    true,
+	}, server.Client(), logger.New("error", "json", "stdout"))
+
+	if client.ThreadingEnabled() {
+		t.Fatal("Expected threading to be disabled without a bot token")
+	}
+
+	rootID, err := client.SendThreadedReminder("#frontend-reviews", "", "", threadTestMRs(), 48*time.Hour)
+	if err != nil {
+		t.Fatalf("SendThreadedReminder failed: %v", err)
+	}
+	if rootID != "" {
+		t.Errorf("Expected no root ID from webhook fallback, got %q", rootID)
+	}
+	if received.Channel != "#frontend-reviews" || !strings.Contains(received.Text, "Fix login") {
+		t.Errorf("Unexpected webhook message: %+v", received)
+	}
+}
+
+func TestSendThreadedReminder_TokenMode(t *testing.T) {
+	var posts []apiPost
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v4/posts" {
+			t.Errorf("Unexpected path %q", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer bot-token" {
+			t.Errorf("Authorization = %q, want bearer token", got)
+		}
+
+		var post apiPost
+		if err := json.NewDecoder(r.Body).Decode(&post); err != nil {
+			t.Errorf("failed to decode post: %v", err)
+		}
+		posts = append(posts, post)
+
+		post.ID = fmt.Sprintf("post-%d", len(posts))
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(post)
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.MattermostConfig{
+		WebhookURL: "http://webhook.invalid",
+		Enabled:    true,
+		URL:        server.URL + "/",
+		BotToken:   "bot-token",
+		ChannelID:  "channel-123",
+	}, server.Client(), logger.New("error", "json", "stdout"))
+
+	if !client.ThreadingEnabled() {
+		t.Fatal("Expected threading to be enabled with a bot token")
+	}
+
+	rootID, err := client.SendThreadedReminder("", "", "", threadTestMRs(), 48*time.Hour)
+	if err != nil {
+		t.Fatalf("SendThreadedReminder (root) failed: %v", err)
+	}
+	if rootID != "post-1" {
+		t.Errorf("Expected root ID post-1, got %q", rootID)
+	}
+
+	replyRoot, err := client.SendThreadedReminder("", "", rootID, threadTestMRs(), 48*time.Hour)
+	if err != nil {
+		t.Fatalf("SendThreadedReminder (reply) failed: %v", err)
+	}
+	if replyRoot != rootID {
+		t.Errorf("Expected replies to keep root ID %q, got %q", rootID, replyRoot)
+	}
+
+	// A team channel ID overrides the configured one
+	if _, err := client.SendThreadedReminder("", "team-456", "", threadTestMRs(), 48*time.Hour); err != nil {
+		t.Fatalf("SendThreadedReminder (team channel) failed: %v", err)
+	}
+
+	if len(posts) != 3 {
+		t.Fatalf("Expected 3 posts, got %d", len(posts))
+	}
+	if posts[0].RootID != "" || posts[1].RootID != "post-1" {
+		t.Errorf("Expected root then reply, got root IDs %q and %q", posts[0].RootID, posts[1].RootID)
+	}
+	if posts[0].ChannelID != "channel-123" || !strings.Contains(posts[0].Message, "Fix login") {
+		t.Errorf("Unexpected root post: %+v", posts[0])
+	}
+	if posts[2].ChannelID != "team-456" || posts[2].RootID != "" {
+		t.Errorf("Expected a new thread in the team channel, got %+v", posts[2])
+	}
+}
@@ -2,6 +2,8 @@
 package metrics
 
 import (
+	"strconv"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -33,6 +35,14 @@ var (
 		[]string{"team"},
 	)
 
+	ReviewsReopenedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "reviews_reopened_total",
+			Help: "Total number of reviews reopened after being merged or closed",
+		},
+		[]string{"team"},
+	)
+
 	// Gauges.
 	ActiveReviews = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -170,6 +180,14 @@ var (
 		[]string{"status"},
 	)
 
+	BadgeEvaluationProgress = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "badge_evaluation_progress",
+			Help: "Users processed and total users of the current or last badge evaluation run",
+		},
+		[]string{"state"}, // "processed" or "total"
+	)
+
 	BadgeEvaluationDurationSeconds = promauto.NewHistogram(
 		prometheus.HistogramOpts{
 			Name:    "badge_evaluation_duration_seconds",
@@ -177,6 +195,24 @@ var (
 			Buckets: prometheus.ExponentialBuckets(1, 2, 10), // 1s to ~1024s
 		},
 	)
+
+	// HTTP API metrics.
+	APIRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "api_requests_total",
+			Help: "Total number of HTTP requests by route template and status code",
+		},
+		[]string{"route", "status"},
+	)
+
+	APIRequestDurationSeconds = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "api_request_duration_seconds",
+			Help:    "Time taken to serve HTTP requests by route template",
+			Buckets: prometheus.DefBuckets, // 5ms to 10s
+		},
+		[]string{"route"},
+	)
 )
 
 // RecordRouletteTrigger records a roulette command trigger.
@@ -194,6 +230,11 @@ func RecordReviewAbandoned(team string) {
 	ReviewsAbandonedTotal.WithLabelValues(team).Inc()
 }
 
+// RecordReviewReopened records a review reopened after being merged or closed.
+func RecordReviewReopened(team string) {
+	ReviewsReopenedTotal.WithLabelValues(team).Inc()
+}
+
 // SetActiveReviews sets the current number of active reviews for a user.
 func SetActiveReviews(team, user string, count int) {
 	ActiveReviews.WithLabelValues(team, user).Set(float64(count))
@@ -269,12 +310,34 @@ func SetActiveBadgeHolders(badgeName string, count int) {
 	ActiveBadgeHolders.WithLabelValues(badgeName).Set(float64(count))
 }
 
+// ResetDatabaseGauges clears the gauges derived from database state (active reviews,
+// available reviewers and badge holders) before they are rebuilt, so label sets
+// that no longer exist stop being exported.
+func ResetDatabaseGauges() {
+	ActiveReviews.Reset()
+	AvailableReviewers.Reset()
+	ActiveBadgeHolders.Reset()
+}
+
 // RecordBadgeEvaluationRun records a badge evaluation job execution.
 func RecordBadgeEvaluationRun(status string) {
 	BadgeEvaluationJobsRunTotal.WithLabelValues(status).Inc()
 }
 
+// SetBadgeEvaluationProgress sets how many users a badge evaluation run has processed out of total.
+func SetBadgeEvaluationProgress(processed, total int) {
+	BadgeEvaluationProgress.WithLabelValues("processed").Set(float64(processed))
+	BadgeEvaluationProgress.WithLabelValues("total").Set(float64(total))
+}
+
 // ObserveBadgeEvaluationDuration observes the duration of a badge evaluation job.
 func ObserveBadgeEvaluationDuration(seconds float64) {
 	BadgeEvaluationDurationSeconds.Observe(seconds)
 }
+
+// RecordAPIRequest records a served HTTP request. route is the matched route template,
+// e.g. /api/v1/leaderboard/:team, so that label values stay bounded.
+func RecordAPIRequest(route string, status int, seconds float64) {
+	APIRequestsTotal.WithLabelValues(route, strconv.Itoa(status)).Inc()
+	APIRequestDurationSeconds.WithLabelValues(route).Observe(seconds)
+}
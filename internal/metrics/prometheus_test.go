@@ -58,6 +58,17 @@ func TestRecordReviewAbandoned(t *testing.T) {
 	}
 }
 
+func TestRecordReviewReopened(t *testing.T) {
+	ReviewsReopenedTotal.Reset()
+
+	RecordReviewReopened("team-frontend")
+
+	count := testutil.ToFloat64(ReviewsReopenedTotal.WithLabelValues("team-frontend"))
+	if count != 1 {
+		t.Errorf("Expected reopened count = 1, got %f", count)
+	}
+}
+
 func TestSetActiveReviews(t *testing.T) {
 	// Set active reviews for users
 	SetActiveReviews("team-frontend", "alice", 3)
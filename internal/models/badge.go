@@ -12,7 +12,9 @@ type Badge struct {
 	Name        string          `gorm:"uniqueIndex;not null;size:100" json:"name"`
 	Description string          `gorm:"type:text" json:"description"`
 	Icon        string          `gorm:"size:50" json:"icon"`
-	Criteria    json.RawMessage `gorm:"type:jsonb" json:"criteria"` // JSON structure for criteria
+	Criteria    json.RawMessage `gorm:"type:jsonb" json:"criteria"`            // JSON structure for criteria
+	Hidden      bool            `gorm:"not null;default:false" json:"hidden"`  // Never listed in the public catalog
+	MinHolders  int             `gorm:"not null;default:0" json:"min_holders"` // Holders required before public listing
 	CreatedAt   time.Time       `json:"created_at"`
 	UpdatedAt   time.Time       `json:"updated_at"`
 }
@@ -24,12 +26,18 @@ func (Badge) TableName() string {
 
 // BadgeCriteria represents the criteria for earning a badge.
 type BadgeCriteria struct {
+	Type     string      `json:"type,omitempty"` // "" (metric comparison) or "streak"
 	Metric   string      `json:"metric"`
 	Operator string      `json:"operator"` // "<", ">", ">=", "<=", "==", "top"
 	Value    interface{} `json:"value"`
-	Period   string      `json:"period,omitempty"` // "day", "week", "month", "year"
+	Period   string      `json:"period,omitempty"` // "day", "week", "month", "quarter", "year", or "days"/"weeks"/"months" with N
+	N        int         `json:"n,omitempty"`      // Length of a "days", "weeks" or "months" period, e.g. 90 days
+	Days     int         `json:"days,omitempty"`   // Consecutive active days required for "streak"
 }
 
+// BadgeCriteriaTypeStreak is the criteria type for consecutive active days.
+const BadgeCriteriaTypeStreak = "streak"
+
 // UserBadge represents a badge earned by a user.
 type UserBadge struct {
 	ID       uint      `gorm:"primaryKey" json:"id"`
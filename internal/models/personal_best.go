@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// PersonalBest is a user's best-ever value for a metric over a period (e.g. fastest weekly avg TTFR).
+type PersonalBest struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UserID      uint      `gorm:"not null;uniqueIndex:idx_personal_bests_user_metric_period" json:"user_id"`
+	Metric      string    `gorm:"size:50;not null;uniqueIndex:idx_personal_bests_user_metric_period" json:"metric"` // 'avg_ttfr', 'engagement_score'
+	Period      string    `gorm:"size:20;not null;uniqueIndex:idx_personal_bests_user_metric_period" json:"period"` // 'week', 'month'
+	Value       float64   `gorm:"not null" json:"value"`
+	PeriodStart time.Time `gorm:"not null" json:"period_start"`
+	PeriodEnd   time.Time `gorm:"not null" json:"period_end"`
+	AchievedAt  time.Time `gorm:"not null" json:"achieved_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for PersonalBest model.
+func (PersonalBest) TableName() string {
+	return "personal_bests"
+}
+
+// Personal best metric constants.
+const (
+	PersonalBestMetricAvgTTFR         = "avg_ttfr"         // lower is better
+	PersonalBestMetricEngagementScore = "engagement_score" // higher is better
+)
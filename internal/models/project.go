@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// Project holds GitLab project metadata so project-scoped responses can show names instead of bare IDs.
+type Project struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	GitLabProjectID int       `gorm:"column:gitlab_project_id;not null;uniqueIndex" json:"gitlab_project_id"`
+	Name            string    `gorm:"size:255;not null" json:"name"`
+	Path            string    `gorm:"size:500" json:"path"` // path_with_namespace, e.g. "group/subgroup/project"
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Project model.
+func (Project) TableName() string {
+	return "projects"
+}
@@ -18,6 +18,7 @@ type MRReview struct {
 	RouletteTriggeredBy *uint      `json:"roulette_triggered_by"`
 	TriggeredBy         *User      `gorm:"foreignKey:RouletteTriggeredBy" json:"triggered_by,omitempty"`
 	BotCommentID        *int       `gorm:"index" json:"bot_comment_id"` // GitLab note ID for updating the bot's comment
+	ReminderCommentID   *int       `json:"reminder_comment_id"`         // GitLab note ID of the stale-review reminder comment
 	FirstReviewAt       *time.Time `json:"first_review_at"`
 	ApprovedAt          *time.Time `json:"approved_at"`
 	MergedAt            *time.Time `json:"merged_at"`
@@ -57,6 +58,9 @@ func (ReviewerAssignment) TableName() string {
 }
 
 // ReviewMetrics represents aggregated review metrics.
+// Rows are unique per (date, team, granularity, user_id, project_id, hour) through the
+// idx_review_metrics_dimensions expression index, which treats NULLs as equal; GORM tags
+// can't express it, so it is created by repository.CreateMetricsIndexes and a migration.
 type ReviewMetrics struct {
 	ID                uint      `gorm:"primaryKey" json:"id"`
 	Date              time.Time `gorm:"type:date;not null" json:"date"`
@@ -71,6 +75,12 @@ type ReviewMetrics struct {
 	AvgCommentCount   *float64  `gorm:"type:decimal(10,2)" json:"avg_comment_count"`
 	AvgCommentLength  *float64  `gorm:"type:decimal(10,2)" json:"avg_comment_length"`
 	EngagementScore   *float64  `gorm:"type:decimal(10,2)" json:"engagement_score"`
+	TTFRSamples       int       `gorm:"default:0" json:"ttfr_samples"`                  // Samples averaged into AvgTTFR
+	ApprovalSamples   int       `gorm:"default:0" json:"approval_samples"`              // Samples averaged into AvgTimeToApproval
+	CommentSamples    int       `gorm:"default:0" json:"comment_samples"`               // Samples averaged into AvgCommentCount/AvgCommentLength
+	EngagementSamples int       `gorm:"default:0" json:"engagement_samples"`            // Samples averaged into EngagementScore
+	Granularity       string    `gorm:"size:20;default:daily;index" json:"granularity"` // 'daily' or 'hourly'
+	Hour              *int      `json:"hour,omitempty"`                                 // Hour of day (0-23), only set for hourly rows
 	CreatedAt         time.Time `json:"created_at"`
 }
 
@@ -88,6 +98,12 @@ const (
 	MRStatusClosed   = "closed"
 )
 
+// MetricsGranularity constants.
+const (
+	MetricsGranularityDaily  = "daily"
+	MetricsGranularityHourly = "hourly"
+)
+
 // ReviewerRole constants.
 const (
 	ReviewerRoleCodeowner  = "codeowner"
@@ -6,21 +6,36 @@ import (
 
 // User represents a GitLab user in the system.
 type User struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	GitLabID  int       `gorm:"column:gitlab_id;uniqueIndex;not null" json:"gitlab_id"`
-	Username  string    `gorm:"uniqueIndex;not null;size:255" json:"username"`
-	Email     string    `gorm:"size:255" json:"email"`
-	Role      string    `gorm:"size:50" json:"role"` // 'dev' or 'ops'
-	Team      string    `gorm:"size:100" json:"team"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	GitLabID    int       `gorm:"column:gitlab_id;uniqueIndex;not null" json:"gitlab_id"`
+	Username    string    `gorm:"uniqueIndex;not null;size:255" json:"username"`
+	DisplayName string    `gorm:"size:255" json:"display_name"` // Friendly name shown in the UI (optional)
+	Email       string    `gorm:"size:255" json:"email"`
+	Role        string    `gorm:"size:50" json:"role"` // RoleDev or RoleOps
+	Team        string    `gorm:"size:100" json:"team"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+// Reviewer roles.
+const (
+	RoleDev = "dev"
+	RoleOps = "ops"
+)
+
 // TableName specifies the table name for User model.
 func (User) TableName() string {
 	return "users"
 }
 
+// PreferredName returns the display name, falling back to the username when empty.
+func (u *User) PreferredName() string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	return u.Username
+}
+
 // OOOStatus represents out-of-office status for a user.
 type OOOStatus struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
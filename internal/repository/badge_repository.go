@@ -2,8 +2,12 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
+	"gorm.io/gorm"
+
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
 )
 
@@ -26,6 +30,9 @@ func (r *BadgeRepository) Create(badge *models.Badge) error {
 func (r *BadgeRepository) GetByID(id uint) (*models.Badge, error) {
 	var badge models.Badge
 	err := r.db.First(&badge, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("badge %d: %w", id, ErrNotFound)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -115,6 +122,29 @@ func (r *BadgeRepository) GetUsersWithBadge(badgeID uint) ([]models.User, error)
 	return users, err
 }
 
+// GetUsersWithBadgePaged retrieves one page of users who have earned a specific badge,
+// most recent first, along with the total number of holders.
+func (r *BadgeRepository) GetUsersWithBadgePaged(badgeID uint, offset, limit int) ([]models.User, int64, error) {
+	total, err := r.GetBadgeHoldersCount(badgeID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var users []models.User
+	query := r.db.
+		Joins("JOIN user_badges ON user_badges.user_id = users.id").
+		Where("user_badges.badge_id = ?", badgeID).
+		Order("user_badges.earned_at DESC").
+		Order("users.id ASC").
+		Offset(offset)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	err = query.Find(&users).Error
+	return users, total, err
+}
+
 // GetBadgeHoldersCount returns the number of users who have earned a specific badge.
 func (r *BadgeRepository) GetBadgeHoldersCount(badgeID uint) (int64, error) {
 	var count int64
@@ -124,6 +154,27 @@ func (r *BadgeRepository) GetBadgeHoldersCount(badgeID uint) (int64, error) {
 	return count, err
 }
 
+// GetHolderCounts returns the number of holders of every badge that has at least one, keyed by badge ID.
+func (r *BadgeRepository) GetHolderCounts() (map[uint]int64, error) {
+	var results []struct {
+		BadgeID uint
+		Count   int64
+	}
+	err := r.db.Model(&models.UserBadge{}).
+		Select("badge_id, count(*) as count").
+		Group("badge_id").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(results))
+	for _, result := range results {
+		counts[result.BadgeID] = result.Count
+	}
+	return counts, nil
+}
+
 // RevokeUserBadge revokes a badge from a user.
 func (r *BadgeRepository) RevokeUserBadge(userID, badgeID uint) error {
 	return r.db.
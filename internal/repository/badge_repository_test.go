@@ -2,6 +2,7 @@ package repository
 
 import (
 	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
@@ -377,6 +378,70 @@ func TestBadgeRepository_GetUsersWithBadge(t *testing.T) {
 	}
 }
 
+func TestBadgeRepository_GetUsersWithBadgePaged(t *testing.T) {
+	db := setupBadgeTestDB(t)
+	repo := NewBadgeRepository(db)
+
+	badge := createTestBadge(t, repo, "popular_badge", "Popular", "🌟")
+	other := createTestBadge(t, repo, "other_badge", "Other", "🎯")
+
+	// Award the badge to more users than fit in a single page
+	for i := 0; i < 7; i++ {
+		user := createTestUser(t, db, fmt.Sprintf("holder%d", i), "team-frontend")
+		if err := repo.AwardBadge(user.ID, badge.ID); err != nil {
+			t.Fatalf("AwardBadge() failed: %v", err)
+		}
+	}
+	outsider := createTestUser(t, db, "outsider", "team-backend")
+	_ = repo.AwardBadge(outsider.ID, other.ID)
+
+	seen := make(map[uint]bool)
+	for _, tc := range []struct {
+		offset   int
+		wantPage int
+	}{
+		{offset: 0, wantPage: 3},
+		{offset: 3, wantPage: 3},
+		{offset: 6, wantPage: 1},
+		{offset: 9, wantPage: 0},
+	} {
+		users, total, err := repo.GetUsersWithBadgePaged(badge.ID, tc.offset, 3)
+		if err != nil {
+			t.Fatalf("GetUsersWithBadgePaged(offset=%d) failed: %v", tc.offset, err)
+		}
+
+		if total != 7 {
+			t.Errorf("offset=%d: expected total 7, got %d", tc.offset, total)
+		}
+		if len(users) != tc.wantPage {
+			t.Errorf("offset=%d: expected %d users, got %d", tc.offset, tc.wantPage, len(users))
+		}
+
+		for _, user := range users {
+			if user.ID == outsider.ID {
+				t.Error("Expected outsider to not be a holder")
+			}
+			if seen[user.ID] {
+				t.Errorf("User %d returned on more than one page", user.ID)
+			}
+			seen[user.ID] = true
+		}
+	}
+
+	if len(seen) != 7 {
+		t.Errorf("Expected 7 distinct holders across pages, got %d", len(seen))
+	}
+
+	// A limit of 0 returns every holder from the offset
+	users, total, err := repo.GetUsersWithBadgePaged(badge.ID, 2, 0)
+	if err != nil {
+		t.Fatalf("GetUsersWithBadgePaged() without limit failed: %v", err)
+	}
+	if total != 7 || len(users) != 5 {
+		t.Errorf("Expected 5 of 7 holders, got %d of %d", len(users), total)
+	}
+}
+
 func TestBadgeRepository_GetBadgeHoldersCount(t *testing.T) {
 	db := setupBadgeTestDB(t)
 	repo := NewBadgeRepository(db)
@@ -411,6 +476,33 @@ func TestBadgeRepository_GetBadgeHoldersCount(t *testing.T) {
 	}
 }
 
+func TestBadgeRepository_GetHolderCounts(t *testing.T) {
+	db := setupBadgeTestDB(t)
+	repo := NewBadgeRepository(db)
+
+	user1 := createTestUser(t, db, "alice", "team-frontend")
+	user2 := createTestUser(t, db, "bob", "team-backend")
+	badge1 := createTestBadge(t, repo, "badge_1", "Badge 1", "🥇")
+	badge2 := createTestBadge(t, repo, "badge_2", "Badge 2", "🥈")
+	badge3 := createTestBadge(t, repo, "badge_3", "Badge 3", "🥉")
+
+	_ = repo.AwardBadge(user1.ID, badge1.ID)
+	_ = repo.AwardBadge(user2.ID, badge1.ID)
+	_ = repo.AwardBadge(user2.ID, badge2.ID)
+
+	counts, err := repo.GetHolderCounts()
+	if err != nil {
+		t.Fatalf("GetHolderCounts() failed: %v", err)
+	}
+
+	if counts[badge1.ID] != 2 || counts[badge2.ID] != 1 {
+		t.Errorf("Expected 2 and 1 holders, got %v", counts)
+	}
+	if _, ok := counts[badge3.ID]; ok {
+		t.Errorf("Expected no count for a badge without holders, got %v", counts)
+	}
+}
+
 func TestBadgeRepository_RevokeUserBadge(t *testing.T) {
 	db := setupBadgeTestDB(t)
 	repo := NewBadgeRepository(db)
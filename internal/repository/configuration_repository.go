@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+)
+
+// ConfigurationRepository handles key-value configuration entries.
+type ConfigurationRepository struct {
+	db *gorm.DB
+}
+
+// NewConfigurationRepository creates a new configuration repository instance.
+func NewConfigurationRepository(db *DB) *ConfigurationRepository {
+	return &ConfigurationRepository{
+		db: db.DB,
+	}
+}
+
+// GetValue decodes the value stored under key into dest. It reports false when the key does not exist.
+func (r *ConfigurationRepository) GetValue(key string, dest interface{}) (bool, error) {
+	var entry models.Configuration
+	err := r.db.Where("key = ?", key).First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get configuration %q: %w", key, err)
+	}
+
+	if err := json.Unmarshal(entry.Value, dest); err != nil {
+		return false, fmt.Errorf("failed to decode configuration %q: %w", key, err)
+	}
+
+	return true, nil
+}
+
+// SetValue stores value under key, replacing any existing value.
+func (r *ConfigurationRepository) SetValue(key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode configuration %q: %w", key, err)
+	}
+
+	var entry models.Configuration
+	err = r.db.Where("key = ?", key).First(&entry).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to get configuration %q: %w", key, err)
+	}
+
+	entry.Key = key
+	entry.Value = data
+	if err := r.db.Save(&entry).Error; err != nil {
+		return fmt.Errorf("failed to save configuration %q: %w", key, err)
+	}
+
+	return nil
+}
+
+// DeleteValue removes the value stored under key.
+func (r *ConfigurationRepository) DeleteValue(key string) error {
+	if err := r.db.Where("key = ?", key).Delete(&models.Configuration{}).Error; err != nil {
+		return fmt.Errorf("failed to delete configuration %q: %w", key, err)
+	}
+	return nil
+}
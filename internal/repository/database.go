@@ -2,6 +2,8 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -14,11 +16,19 @@ import (
 	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
 )
 
+// ErrNotFound is returned (wrapped) when a requested record does not exist.
+var ErrNotFound = errors.New("record not found")
+
 // DB holds the database connection.
 type DB struct {
 	*gorm.DB
 }
 
+// WithContext returns a DB whose queries are bound to ctx, so they are aborted once ctx is cancelled.
+func (db *DB) WithContext(ctx context.Context) *DB {
+	return &DB{db.DB.WithContext(ctx)}
+}
+
 // NewDB creates a new database connection.
 func NewDB(cfg *config.PostgresConfig, log *logger.Logger) (*DB, error) {
 	dsn := fmt.Sprintf(
@@ -75,7 +85,7 @@ func NewDB(cfg *config.PostgresConfig, log *logger.Logger) (*DB, error) {
 
 // AutoMigrate runs database migrations for all models.
 func (db *DB) AutoMigrate() error {
-	return db.DB.AutoMigrate(
+	if err := db.DB.AutoMigrate(
 		&models.User{},
 		&models.OOOStatus{},
 		&models.MRReview{},
@@ -84,7 +94,13 @@ func (db *DB) AutoMigrate() error {
 		&models.Badge{},
 		&models.UserBadge{},
 		&models.Configuration{},
-	)
+		&models.WebhookDelivery{},
+		&models.PersonalBest{},
+		&models.Project{},
+	); err != nil {
+		return err
+	}
+	return CreateMetricsIndexes(db.DB)
 }
 
 // Close closes the database connection.
@@ -1,9 +1,12 @@
 package repository
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
 )
@@ -18,49 +21,141 @@ func NewMetricsRepository(db *DB) *MetricsRepository {
 	return &MetricsRepository{db: db}
 }
 
+// WithContext returns a copy of the repository whose queries are bound to ctx.
+func (r *MetricsRepository) WithContext(ctx context.Context) *MetricsRepository {
+	return &MetricsRepository{db: r.db.WithContext(ctx)}
+}
+
 // Create creates a new review metric record.
 func (r *MetricsRepository) Create(metric *models.ReviewMetrics) error {
 	return r.db.Create(metric).Error
 }
 
 // CreateOrUpdate creates or updates a review metrics record. This ensures idempotency for daily aggregations.
+// It is a single upsert on idx_review_metrics_dimensions, so concurrent aggregation runs
+// can't insert duplicate rows for the same date, team, user, project and hour.
 func (r *MetricsRepository) CreateOrUpdate(metric *models.ReviewMetrics) error {
-	// Try to find existing record
-	var existing models.ReviewMetrics
-	query := r.db.Where("date = ? AND team = ?", metric.Date, metric.Team)
+	if metric.Granularity == "" {
+		metric.Granularity = models.MetricsGranularityDaily
+	}
 
-	if metric.UserID != nil {
-		query = query.Where("user_id = ?", *metric.UserID)
-	} else {
-		query = query.Where("user_id IS NULL")
+	// Rows are identified by their dimensions; the ID of the stored row is read back from the upsert
+	metric.ID = 0
+
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   metricsConflictColumns,
+		DoUpdates: clause.AssignmentColumns(metricsUpsertColumns),
+	}).Create(metric).Error
+	if err != nil {
+		return fmt.Errorf("failed to upsert review metrics: %w", err)
 	}
 
-	if metric.ProjectID != nil {
-		query = query.Where("project_id = ?", *metric.ProjectID)
-	} else {
-		query = query.Where("project_id IS NULL")
+	return nil
+}
+
+// metricsDimensionsIndexSQL creates the unique index identifying a metrics row.
+// NULL dimensions are coalesced so team-level and daily rows are unique too.
+const metricsDimensionsIndexSQL = `CREATE UNIQUE INDEX IF NOT EXISTS idx_review_metrics_dimensions ON review_metrics
+	(date, team, granularity, COALESCE(user_id, 0), COALESCE(project_id, 0), COALESCE(hour, -1))`
+
+// metricsConflictColumns is the upsert conflict target matching idx_review_metrics_dimensions.
+var metricsConflictColumns = []clause.Column{
+	{Name: "date"},
+	{Name: "team"},
+	{Name: "granularity"},
+	{Name: "COALESCE(user_id, 0)", Raw: true},
+	{Name: "COALESCE(project_id, 0)", Raw: true},
+	{Name: "COALESCE(hour, -1)", Raw: true},
+}
+
+// metricsUpsertColumns are overwritten when an upserted row already exists.
+var metricsUpsertColumns = []string{
+	"total_reviews",
+	"completed_reviews",
+	"avg_ttfr",
+	"avg_time_to_approval",
+	"avg_comment_count",
+	"avg_comment_length",
+	"engagement_score",
+	"ttfr_samples",
+	"approval_samples",
+	"comment_samples",
+	"engagement_samples",
+}
+
+// CreateMetricsIndexes creates indexes GORM tags can't express. AutoMigrate calls it;
+// databases managed by SQL migrations get the same index from a migration.
+func CreateMetricsIndexes(db *gorm.DB) error {
+	if err := db.Exec(metricsDimensionsIndexSQL).Error; err != nil {
+		return fmt.Errorf("failed to create review metrics dimensions index: %w", err)
 	}
+	return nil
+}
+
+// CreateOrUpdateBatch creates or updates many review metrics records in a single upsert statement.
+// Rows sharing the same dimensions are collapsed, keeping the last one, as repeated CreateOrUpdate calls would.
+func (r *MetricsRepository) CreateOrUpdateBatch(metrics []*models.ReviewMetrics) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	type dimensions struct {
+		date        string
+		team        string
+		granularity string
+		userID      uint
+		projectID   int
+		hour        int
+	}
+
+	index := make(map[dimensions]int, len(metrics))
+	batch := make([]*models.ReviewMetrics, 0, len(metrics))
+	for _, metric := range metrics {
+		if metric.Granularity == "" {
+			metric.Granularity = models.MetricsGranularityDaily
+		}
 
-	err := query.First(&existing).Error
+		key := dimensions{
+			date:        metric.Date.Format("2006-01-02"),
+			team:        metric.Team,
+			granularity: metric.Granularity,
+			hour:        -1,
+		}
+		if metric.UserID != nil {
+			key.userID = *metric.UserID
+		}
+		if metric.ProjectID != nil {
+			key.projectID = *metric.ProjectID
+		}
+		if metric.Hour != nil {
+			key.hour = *metric.Hour
+		}
 
-	if err == gorm.ErrRecordNotFound {
-		// Record doesn't exist, create new one
-		return r.Create(metric)
+		if i, ok := index[key]; ok {
+			batch[i] = metric
+			continue
+		}
+		index[key] = len(batch)
+		batch = append(batch, metric)
 	}
 
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   metricsConflictColumns,
+		DoUpdates: clause.AssignmentColumns(metricsUpsertColumns),
+	}).Create(&batch).Error
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to upsert %d review metrics: %w", len(batch), err)
 	}
 
-	// Record exists, update it
-	metric.ID = existing.ID
-	return r.db.Save(metric).Error
+	return nil
 }
 
-// GetByDate retrieves metrics for a specific date with optional filters.
-func (r *MetricsRepository) GetByDate(date time.Time, team string, userID *uint) (*models.ReviewMetrics, error) {
+// GetByDate retrieves the daily metric row for a date and team.
+// A nil userID selects the team row and a nil projectID the row not scoped to a project,
+// matching the uniqueness used by CreateOrUpdate.
+func (r *MetricsRepository) GetByDate(date time.Time, team string, userID *uint, projectID *int) (*models.ReviewMetrics, error) {
 	var metric models.ReviewMetrics
-	query := r.db.Where("date = ? AND team = ?", date, team)
+	query := r.db.Where("date = ? AND team = ? AND granularity = ? AND hour IS NULL", date, team, models.MetricsGranularityDaily)
 
 	if userID != nil {
 		query = query.Where("user_id = ?", *userID)
@@ -68,6 +163,12 @@ func (r *MetricsRepository) GetByDate(date time.Time, team string, userID *uint)
 		query = query.Where("user_id IS NULL")
 	}
 
+	if projectID != nil {
+		query = query.Where("project_id = ?", *projectID)
+	} else {
+		query = query.Where("project_id IS NULL")
+	}
+
 	err := query.First(&metric).Error
 	if err != nil {
 		return nil, err
@@ -77,10 +178,17 @@ func (r *MetricsRepository) GetByDate(date time.Time, team string, userID *uint)
 }
 
 // GetByDateRange retrieves metrics within a date range with optional filters.
+// Only daily rows are returned unless a "granularity" filter is given.
 func (r *MetricsRepository) GetByDateRange(startDate, endDate time.Time, filters map[string]interface{}) ([]models.ReviewMetrics, error) {
 	var metrics []models.ReviewMetrics
 	query := r.db.Where("date BETWEEN ? AND ?", startDate, endDate)
 
+	granularity := models.MetricsGranularityDaily
+	if g, ok := filters["granularity"].(string); ok && g != "" {
+		granularity = g
+	}
+	query = query.Where("granularity = ?", granularity)
+
 	// Apply filters
 	if team, ok := filters["team"].(string); ok && team != "" {
 		query = query.Where("team = ?", team)
@@ -90,15 +198,15 @@ func (r *MetricsRepository) GetByDateRange(startDate, endDate time.Time, filters
 		query = query.Where("user_id = ?", *userID)
 	}
 
-	if projectID, ok := filters["project_id"].(*uint); ok && projectID != nil {
+	if projectID, ok := filters["project_id"].(*int); ok && projectID != nil {
 		query = query.Where("project_id = ?", *projectID)
 	}
 
-	err := query.Order("date DESC").Find(&metrics).Error
+	err := query.Order("date DESC").Order("hour ASC").Find(&metrics).Error
 	return metrics, err
 }
 
-// GetAverageTTFRByTeam calculates average TTFR (in seconds) by team for a date range.
+// GetAverageTTFRByTeam calculates average TTFR (in minutes, the unit metrics are stored in) by team for a date range.
 func (r *MetricsRepository) GetAverageTTFRByTeam(startDate, endDate time.Time) (map[string]float64, error) {
 	type Result struct {
 		Team    string
@@ -109,6 +217,7 @@ func (r *MetricsRepository) GetAverageTTFRByTeam(startDate, endDate time.Time) (
 	err := r.db.Model(&models.ReviewMetrics{}).
 		Select("team, AVG(avg_ttfr) as avg_ttfr").
 		Where("date BETWEEN ? AND ? AND avg_ttfr IS NOT NULL", startDate, endDate).
+		Where("granularity = ?", models.MetricsGranularityDaily).
 		Group("team").
 		Scan(&results).Error
 
@@ -136,6 +245,7 @@ func (r *MetricsRepository) GetTopReviewersByEngagement(startDate, endDate time.
 	err := r.db.Model(&models.ReviewMetrics{}).
 		Select("user_id, SUM(engagement_score) as total_engagement_score").
 		Where("date BETWEEN ? AND ? AND user_id IS NOT NULL", startDate, endDate).
+		Where("granularity = ?", models.MetricsGranularityDaily).
 		Group("user_id").
 		Order("total_engagement_score DESC").
 		Limit(limit).
@@ -161,6 +271,7 @@ func (r *MetricsRepository) GetTopReviewersByEngagement(startDate, endDate time.
 func (r *MetricsRepository) GetMetricsByTeam(team string, startDate, endDate time.Time) ([]models.ReviewMetrics, error) {
 	var metrics []models.ReviewMetrics
 	err := r.db.Where("team = ? AND date BETWEEN ? AND ?", team, startDate, endDate).
+		Where("granularity = ?", models.MetricsGranularityDaily).
 		Order("date DESC").
 		Find(&metrics).Error
 
@@ -171,12 +282,45 @@ func (r *MetricsRepository) GetMetricsByTeam(team string, startDate, endDate tim
 func (r *MetricsRepository) GetMetricsByUser(userID uint, startDate, endDate time.Time) ([]models.ReviewMetrics, error) {
 	var metrics []models.ReviewMetrics
 	err := r.db.Where("user_id = ? AND date BETWEEN ? AND ?", userID, startDate, endDate).
+		Where("granularity = ?", models.MetricsGranularityDaily).
 		Order("date DESC").
 		Find(&metrics).Error
 
 	return metrics, err
 }
 
+// Transaction runs fn with a repository bound to a single database transaction,
+// committed when fn returns nil and rolled back otherwise.
+func (r *MetricsRepository) Transaction(fn func(tx *MetricsRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&MetricsRepository{db: &DB{tx}})
+	})
+}
+
+// DeleteDailyAggregatedMetrics deletes the rows daily aggregation rebuilds for a day: the
+// team rows and the project-scoped user rows. Daily aggregation deletes them before
+// re-aggregating so that reviews no longer completed (e.g. reopened MRs) stop counting.
+func (r *MetricsRepository) DeleteDailyAggregatedMetrics(date time.Time) error {
+	err := r.db.Where("date = ? AND granularity = ?", date, models.MetricsGranularityDaily).
+		Where("(user_id IS NOT NULL AND project_id IS NOT NULL) OR (user_id IS NULL AND project_id IS NULL)").
+		Delete(&models.ReviewMetrics{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete daily metrics for %s: %w", date.Format("2006-01-02"), err)
+	}
+	return nil
+}
+
+// DeleteHourlyMetrics deletes the hourly rows of a day, which hourly aggregation deletes
+// before re-aggregating so that hours no longer holding completed reviews are dropped.
+func (r *MetricsRepository) DeleteHourlyMetrics(date time.Time) error {
+	err := r.db.Where("date = ? AND granularity = ?", date, models.MetricsGranularityHourly).
+		Delete(&models.ReviewMetrics{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete hourly metrics for %s: %w", date.Format("2006-01-02"), err)
+	}
+	return nil
+}
+
 // DeleteOldMetrics deletes metrics older than the specified retention period. Used for data cleanup if retention policy is configured.
 func (r *MetricsRepository) DeleteOldMetrics(retentionDays int) error {
 	cutoffDate := time.Now().AddDate(0, 0, -retentionDays)
@@ -190,7 +334,7 @@ func (r *MetricsRepository) GetDailyStats(date time.Time) (map[string]interface{
 	// Total reviews across all teams
 	var totalReviews int64
 	if err := r.db.Model(&models.ReviewMetrics{}).
-		Where("date = ?", date).
+		Where("date = ? AND granularity = ?", date, models.MetricsGranularityDaily).
 		Select("SUM(total_reviews)").
 		Scan(&totalReviews).Error; err != nil {
 		return nil, err
@@ -200,7 +344,7 @@ func (r *MetricsRepository) GetDailyStats(date time.Time) (map[string]interface{
 	// Average TTFR across all teams
 	var avgTTFR float64
 	if err := r.db.Model(&models.ReviewMetrics{}).
-		Where("date = ? AND avg_ttfr IS NOT NULL", date).
+		Where("date = ? AND granularity = ? AND avg_ttfr IS NOT NULL", date, models.MetricsGranularityDaily).
 		Select("AVG(avg_ttfr)").
 		Scan(&avgTTFR).Error; err != nil {
 		return nil, err
@@ -210,7 +354,7 @@ func (r *MetricsRepository) GetDailyStats(date time.Time) (map[string]interface{
 	// Total completed reviews
 	var totalCompleted int64
 	if err := r.db.Model(&models.ReviewMetrics{}).
-		Where("date = ?", date).
+		Where("date = ? AND granularity = ?", date, models.MetricsGranularityDaily).
 		Select("SUM(completed_reviews)").
 		Scan(&totalCompleted).Error; err != nil {
 		return nil, err
@@ -1,6 +1,11 @@
 package repository
 
 import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sync"
 	"testing"
 	"time"
 
@@ -29,6 +34,9 @@ func setupTestDB(t *testing.T) *DB {
 	); err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
 	}
+	if err := CreateMetricsIndexes(db); err != nil {
+		t.Fatalf("Failed to create metrics indexes: %v", err)
+	}
 
 	return &DB{db}
 }
@@ -118,7 +126,7 @@ func TestMetricsRepository_CreateOrUpdate(t *testing.T) {
 	}
 
 	// Fetch and verify values
-	fetched, err := repo.GetByDate(date, "team-frontend", nil)
+	fetched, err := repo.GetByDate(date, "team-frontend", nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to fetch metric: %v", err)
 	}
@@ -132,6 +140,232 @@ func TestMetricsRepository_CreateOrUpdate(t *testing.T) {
 	}
 }
 
+func TestMetricsRepository_CreateOrUpdate_Concurrent(t *testing.T) {
+	// In-memory SQLite gives every pooled connection its own database, so share a file instead
+	gormDB, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "metrics.db")+"?_busy_timeout=5000"), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := gormDB.AutoMigrate(&models.User{}, &models.ReviewMetrics{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	if err := CreateMetricsIndexes(gormDB); err != nil {
+		t.Fatalf("Failed to create metrics indexes: %v", err)
+	}
+	db := &DB{gormDB}
+	defer cleanupTestDB(t, db)
+
+	repo := NewMetricsRepository(db)
+	date := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	userID := uint(7)
+
+	// Hold every writer before its insert until all have arrived, so none can see another's row first
+	const writers = 20
+	var arrived sync.WaitGroup
+	arrived.Add(writers)
+	err = gormDB.Callback().Create().Before("gorm:begin_transaction").Register("test:barrier", func(*gorm.DB) {
+		arrived.Done()
+		arrived.Wait()
+	})
+	if err != nil {
+		t.Fatalf("Failed to register barrier: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 1; i <= writers; i++ {
+		wg.Add(1)
+		go func(totalReviews int) {
+			defer wg.Done()
+			errs <- repo.CreateOrUpdate(&models.ReviewMetrics{
+				Date:         date,
+				Team:         "team-frontend",
+				UserID:       &userID,
+				TotalReviews: totalReviews,
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("CreateOrUpdate failed: %v", err)
+		}
+	}
+
+	var count int64
+	if err := db.Model(&models.ReviewMetrics{}).Count(&count).Error; err != nil {
+		t.Fatalf("Failed to count metrics: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 row for the same dimensions, got %d", count)
+	}
+}
+
+// queryCounter is a GORM logger counting executed statements.
+type queryCounter struct {
+	gormlogger.Interface
+	count int
+}
+
+func (q *queryCounter) Trace(_ context.Context, _ time.Time, _ func() (string, int64), _ error) {
+	q.count++
+}
+
+func TestMetricsRepository_CreateOrUpdateBatch(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestDB(t, db)
+
+	repo := NewMetricsRepository(db)
+
+	date := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	userID := uint(1)
+	otherUserID := uint(2)
+	projectID := 100
+
+	// Existing team and user rows
+	existingTeam := &models.ReviewMetrics{Date: date, Team: "team-frontend", TotalReviews: 10}
+	existingUser := &models.ReviewMetrics{Date: date, Team: "team-frontend", UserID: &userID, ProjectID: &projectID, TotalReviews: 1}
+	for _, metric := range []*models.ReviewMetrics{existingTeam, existingUser} {
+		if err := repo.CreateOrUpdate(metric); err != nil {
+			t.Fatalf("Failed to create metric: %v", err)
+		}
+	}
+
+	batch := []*models.ReviewMetrics{
+		{Date: date, Team: "team-frontend", TotalReviews: 15, AvgTTFR: intPtr(30)},                         // updates team row
+		{Date: date, Team: "team-frontend", UserID: &userID, ProjectID: &projectID, TotalReviews: 2},       // updates user row
+		{Date: date, Team: "team-frontend", UserID: &otherUserID, ProjectID: &projectID, TotalReviews: 3},  // new user row
+		{Date: date, Team: "team-frontend", Granularity: models.MetricsGranularityHourly, Hour: intPtr(9)}, // new hourly row
+		{Date: date, Team: "team-backend", TotalReviews: 4},                                                // new team row
+		{Date: date, Team: "team-backend", TotalReviews: 5},                                                // duplicate in batch, last wins
+	}
+
+	counter := &queryCounter{Interface: gormlogger.Default.LogMode(gormlogger.Silent)}
+	batchRepo := NewMetricsRepository(&DB{db.Session(&gorm.Session{Logger: counter})})
+	if err := batchRepo.CreateOrUpdateBatch(batch); err != nil {
+		t.Fatalf("CreateOrUpdateBatch failed: %v", err)
+	}
+	if counter.count != 1 {
+		t.Errorf("Expected a single query, got %d", counter.count)
+	}
+
+	var total int64
+	db.Model(&models.ReviewMetrics{}).Count(&total)
+	if total != 5 {
+		t.Errorf("Expected 5 rows (2 updated, 3 created), got %d", total)
+	}
+
+	team, err := repo.GetByDate(date, "team-frontend", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to fetch team metric: %v", err)
+	}
+	if team.ID != existingTeam.ID || team.TotalReviews != 15 || team.AvgTTFR == nil || *team.AvgTTFR != 30 {
+		t.Errorf("Team row not updated in place: %+v", team)
+	}
+
+	user, err := repo.GetByDate(date, "team-frontend", &userID, &projectID)
+	if err != nil {
+		t.Fatalf("Failed to fetch user metric: %v", err)
+	}
+	if user.ID != existingUser.ID || user.TotalReviews != 2 {
+		t.Errorf("User row not updated in place: %+v", user)
+	}
+
+	backend, err := repo.GetByDate(date, "team-backend", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to fetch backend metric: %v", err)
+	}
+	if backend.TotalReviews != 5 {
+		t.Errorf("Expected last duplicate to win with TotalReviews = 5, got %d", backend.TotalReviews)
+	}
+
+	// Empty batches are a no-op
+	if err := repo.CreateOrUpdateBatch(nil); err != nil {
+		t.Errorf("CreateOrUpdateBatch(nil) failed: %v", err)
+	}
+}
+
+func TestMetricsRepository_CreateOrUpdateBatch_MatchesCreateOrUpdate(t *testing.T) {
+	date := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	userID := uint(1)
+	projectA, projectB := 100, 200
+	engagement := 7.5
+
+	// Pre-existing rows, then a day's worth of writes touching every dimension
+	existing := func() []*models.ReviewMetrics {
+		return []*models.ReviewMetrics{
+			{Date: date, Team: "team-frontend", TotalReviews: 1},
+			{Date: date, Team: "team-frontend", UserID: &userID, ProjectID: &projectA, TotalReviews: 1},
+		}
+	}
+	writes := func() []*models.ReviewMetrics {
+		return []*models.ReviewMetrics{
+			{Date: date, Team: "team-frontend", TotalReviews: 5, AvgTTFR: intPtr(30)},
+			{Date: date, Team: "team-frontend", UserID: &userID, ProjectID: &projectA, TotalReviews: 2, EngagementScore: &engagement},
+			{Date: date, Team: "team-frontend", UserID: &userID, ProjectID: &projectB, TotalReviews: 3},
+			{Date: date, Team: "team-frontend", UserID: &userID, TotalReviews: 4},
+			{Date: date, Team: "team-frontend", Granularity: models.MetricsGranularityHourly, Hour: intPtr(9), TotalReviews: 6},
+			{Date: date, Team: "team-frontend", Granularity: models.MetricsGranularityHourly, Hour: intPtr(10), TotalReviews: 7},
+			{Date: date, Team: "team-frontend", UserID: &userID, ProjectID: &projectB, TotalReviews: 8}, // repeated, last wins
+		}
+	}
+
+	// snapshot returns every row keyed by its dimensions, ignoring IDs and timestamps
+	snapshot := func(db *DB) map[string]models.ReviewMetrics {
+		var rows []models.ReviewMetrics
+		if err := db.Order("id").Find(&rows).Error; err != nil {
+			t.Fatalf("Failed to list metrics: %v", err)
+		}
+		result := make(map[string]models.ReviewMetrics, len(rows))
+		for _, row := range rows {
+			key := fmt.Sprintf("%s/%s/%s/%v/%v/%v", row.Date.Format("2006-01-02"), row.Team, row.Granularity,
+				derefOrNil(row.UserID), derefOrNil(row.ProjectID), derefOrNil(row.Hour))
+			row.ID, row.CreatedAt, row.Date = 0, time.Time{}, time.Time{}
+			result[key] = row
+		}
+		return result
+	}
+
+	oneByOneDB := setupTestDB(t)
+	defer cleanupTestDB(t, oneByOneDB)
+	oneByOne := NewMetricsRepository(oneByOneDB)
+	for _, metric := range append(existing(), writes()...) {
+		if err := oneByOne.CreateOrUpdate(metric); err != nil {
+			t.Fatalf("CreateOrUpdate failed: %v", err)
+		}
+	}
+
+	batchDB := setupTestDB(t)
+	defer cleanupTestDB(t, batchDB)
+	batched := NewMetricsRepository(batchDB)
+	if err := batched.CreateOrUpdateBatch(existing()); err != nil {
+		t.Fatalf("CreateOrUpdateBatch failed: %v", err)
+	}
+	if err := batched.CreateOrUpdateBatch(writes()); err != nil {
+		t.Fatalf("CreateOrUpdateBatch failed: %v", err)
+	}
+
+	want, got := snapshot(oneByOneDB), snapshot(batchDB)
+	if len(want) != 6 {
+		t.Fatalf("Expected 6 distinct rows from one-by-one writes, got %d", len(want))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Batch upsert diverges from one-by-one CreateOrUpdate:\n got: %+v\nwant: %+v", got, want)
+	}
+}
+
+// derefOrNil returns the pointed-to value, or nil for a nil pointer.
+func derefOrNil[T any](p *T) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
 func TestMetricsRepository_CreateOrUpdate_WithUserID(t *testing.T) {
 	db := setupTestDB(t)
 	defer cleanupTestDB(t, db)
@@ -201,7 +435,7 @@ func TestMetricsRepository_GetByDate(t *testing.T) {
 	_ = repo.Create(metric)
 
 	// Test retrieval
-	fetched, err := repo.GetByDate(date, "team-backend", nil)
+	fetched, err := repo.GetByDate(date, "team-backend", nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to get metric: %v", err)
 	}
@@ -211,12 +445,65 @@ func TestMetricsRepository_GetByDate(t *testing.T) {
 	}
 
 	// Test non-existent metric
-	_, err = repo.GetByDate(date, "team-nonexistent", nil)
+	_, err = repo.GetByDate(date, "team-nonexistent", nil, nil)
 	if err == nil {
 		t.Error("Expected error for non-existent metric")
 	}
 }
 
+func TestMetricsRepository_GetByDate_ProjectScoped(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestDB(t, db)
+
+	repo := NewMetricsRepository(db)
+
+	date := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	userID := uint(1)
+	projectA, projectB := 100, 200
+
+	// Same user, date and team: one row per project plus one not scoped to a project
+	rows := []*models.ReviewMetrics{
+		{Date: date, Team: "team-frontend", UserID: &userID, ProjectID: &projectA, TotalReviews: 1},
+		{Date: date, Team: "team-frontend", UserID: &userID, ProjectID: &projectB, TotalReviews: 2},
+		{Date: date, Team: "team-frontend", UserID: &userID, TotalReviews: 3},
+		{Date: date, Team: "team-frontend", ProjectID: &projectA, TotalReviews: 4},
+	}
+	for _, metric := range rows {
+		if err := repo.CreateOrUpdate(metric); err != nil {
+			t.Fatalf("Failed to create metric: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		userID    *uint
+		projectID *int
+		expected  int
+	}{
+		{"user in project A", &userID, &projectA, 1},
+		{"user in project B", &userID, &projectB, 2},
+		{"user without project", &userID, nil, 3},
+		{"team in project A", nil, &projectA, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetched, err := repo.GetByDate(date, "team-frontend", tt.userID, tt.projectID)
+			if err != nil {
+				t.Fatalf("GetByDate failed: %v", err)
+			}
+			if fetched.TotalReviews != tt.expected {
+				t.Errorf("Expected TotalReviews = %d, got %d", tt.expected, fetched.TotalReviews)
+			}
+		})
+	}
+
+	// No team row without a project exists, even though a project-scoped one does
+	if _, err := repo.GetByDate(date, "team-frontend", nil, nil); err == nil {
+		t.Error("Expected error for team row without a project")
+	}
+}
+
 func TestMetricsRepository_GetByDateRange(t *testing.T) {
 	db := setupTestDB(t)
 	defer cleanupTestDB(t, db)
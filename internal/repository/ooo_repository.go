@@ -68,7 +68,7 @@ func (r *OOORepository) DeleteOOO(id uint) error {
 		return fmt.Errorf("failed to delete OOO entry %d: %w", id, result.Error)
 	}
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("OOO entry %d not found", id)
+		return fmt.Errorf("OOO entry %d: %w", id, ErrNotFound)
 	}
 	return nil
 }
@@ -79,7 +79,7 @@ func (r *OOORepository) GetOOOByID(id uint) (*models.OOOStatus, error) {
 	err := r.db.First(&status, id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("OOO entry %d not found", id)
+			return nil, fmt.Errorf("OOO entry %d: %w", id, ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get OOO entry %d: %w", id, err)
 	}
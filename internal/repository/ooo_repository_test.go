@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+)
+
+func TestOOORepository_DeleteOOO(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestDB(t, db)
+
+	if err := db.DB.AutoMigrate(&models.OOOStatus{}); err != nil {
+		t.Fatalf("Failed to migrate ooo_status: %v", err)
+	}
+
+	repo := NewOOORepository(db)
+	status := &models.OOOStatus{UserID: 1, StartDate: time.Now(), EndDate: time.Now().Add(24 * time.Hour)}
+	if err := repo.CreateOOO(status); err != nil {
+		t.Fatalf("CreateOOO failed: %v", err)
+	}
+
+	if err := repo.DeleteOOO(status.ID); err != nil {
+		t.Fatalf("DeleteOOO failed: %v", err)
+	}
+	if err := repo.DeleteOOO(status.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound deleting a missing entry, got %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+)
+
+// PersonalBestRepository handles personal best database operations.
+type PersonalBestRepository struct {
+	db *gorm.DB
+}
+
+// NewPersonalBestRepository creates a new personal best repository instance.
+func NewPersonalBestRepository(db *DB) *PersonalBestRepository {
+	return &PersonalBestRepository{
+		db: db.DB,
+	}
+}
+
+// GetByUserID retrieves all personal bests of a user.
+func (r *PersonalBestRepository) GetByUserID(userID uint) ([]models.PersonalBest, error) {
+	var bests []models.PersonalBest
+	if err := r.db.Where("user_id = ?", userID).Order("period ASC, metric ASC").Find(&bests).Error; err != nil {
+		return nil, fmt.Errorf("failed to get personal bests for user %d: %w", userID, err)
+	}
+	return bests, nil
+}
+
+// GetByUserMetricPeriod retrieves a user's personal best for a metric and period.
+// Returns nil without error when the user has none yet.
+func (r *PersonalBestRepository) GetByUserMetricPeriod(userID uint, metric, period string) (*models.PersonalBest, error) {
+	var best models.PersonalBest
+	err := r.db.Where("user_id = ? AND metric = ? AND period = ?", userID, metric, period).First(&best).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get personal best: %w", err)
+	}
+	return &best, nil
+}
+
+// Save creates or updates a personal best.
+func (r *PersonalBestRepository) Save(best *models.PersonalBest) error {
+	if err := r.db.Save(best).Error; err != nil {
+		return fmt.Errorf("failed to save personal best: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+)
+
+// ProjectRepository handles project metadata database operations.
+type ProjectRepository struct {
+	db *gorm.DB
+}
+
+// NewProjectRepository creates a new project repository instance.
+func NewProjectRepository(db *DB) *ProjectRepository {
+	return &ProjectRepository{
+		db: db.DB,
+	}
+}
+
+// Upsert creates a project or updates its name and path, keyed by GitLab project ID.
+func (r *ProjectRepository) Upsert(project *models.Project) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "gitlab_project_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "path", "updated_at"}),
+	}).Create(project).Error
+	if err != nil {
+		return fmt.Errorf("failed to upsert project %d: %w", project.GitLabProjectID, err)
+	}
+	return nil
+}
+
+// GetByGitLabID retrieves a project by its GitLab project ID.
+// Returns nil without error when the project is unknown.
+func (r *ProjectRepository) GetByGitLabID(gitlabProjectID int) (*models.Project, error) {
+	var project models.Project
+	err := r.db.Where("gitlab_project_id = ?", gitlabProjectID).First(&project).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project %d: %w", gitlabProjectID, err)
+	}
+	return &project, nil
+}
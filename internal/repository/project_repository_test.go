@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+)
+
+func TestProjectRepository_UpsertAndGet(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestDB(t, db)
+
+	if err := db.DB.AutoMigrate(&models.Project{}); err != nil {
+		t.Fatalf("Failed to migrate projects: %v", err)
+	}
+
+	repo := NewProjectRepository(db)
+
+	if err := repo.Upsert(&models.Project{GitLabProjectID: 42, Name: "payments", Path: "backend/payments"}); err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+	// A renamed project updates the existing row
+	if err := repo.Upsert(&models.Project{GitLabProjectID: 42, Name: "payments-api", Path: "backend/payments-api"}); err != nil {
+		t.Fatalf("Upsert (rename) failed: %v", err)
+	}
+
+	project, err := repo.GetByGitLabID(42)
+	if err != nil {
+		t.Fatalf("GetByGitLabID failed: %v", err)
+	}
+	if project == nil || project.Name != "payments-api" || project.Path != "backend/payments-api" {
+		t.Errorf("Expected renamed project, got %+v", project)
+	}
+
+	var count int64
+	db.DB.Model(&models.Project{}).Count(&count)
+	if count != 1 {
+		t.Errorf("Expected 1 project row, got %d", count)
+	}
+
+	missing, err := repo.GetByGitLabID(7)
+	if err != nil || missing != nil {
+		t.Errorf("Expected nil for unknown project, got %+v, %v", missing, err)
+	}
+}
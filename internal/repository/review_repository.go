@@ -1,9 +1,13 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"gorm.io/gorm"
+
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
 )
 
@@ -17,6 +21,11 @@ func NewReviewRepository(db *DB) *ReviewRepository {
 	return &ReviewRepository{db: db}
 }
 
+// WithContext returns a copy of the repository whose queries are bound to ctx.
+func (r *ReviewRepository) WithContext(ctx context.Context) *ReviewRepository {
+	return &ReviewRepository{db: r.db.WithContext(ctx)}
+}
+
 // CreateMRReview creates a new MR review record.
 func (r *ReviewRepository) CreateMRReview(review *models.MRReview) error {
 	if err := r.db.Create(review).Error; err != nil {
@@ -33,6 +42,9 @@ func (r *ReviewRepository) GetMRReview(projectID, mrIID int) (*models.MRReview,
 		Preload("Assignments.User").
 		First(&review).Error
 
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("MR review for project %d, MR %d: %w", projectID, mrIID, ErrNotFound)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get MR review for project %d, MR %d: %w", projectID, mrIID, err)
 	}
@@ -57,6 +69,18 @@ func (r *ReviewRepository) UpdateMRReview(review *models.MRReview) error {
 	return nil
 }
 
+// SetReminderCommentID stores the GitLab note ID of an MR review's stale-review reminder.
+// Only that column is written, so concurrent updates to the review are not overwritten.
+func (r *ReviewRepository) SetReminderCommentID(reviewID uint, noteID int) error {
+	err := r.db.Model(&models.MRReview{}).
+		Where("id = ?", reviewID).
+		Updates(map[string]interface{}{"reminder_comment_id": noteID}).Error
+	if err != nil {
+		return fmt.Errorf("failed to set reminder comment ID: %w", err)
+	}
+	return nil
+}
+
 // CreateOrUpdateMRReview creates or updates an MR review.
 func (r *ReviewRepository) CreateOrUpdateMRReview(review *models.MRReview) error {
 	existing, err := r.GetMRReview(review.GitLabProjectID, review.GitLabMRIID)
@@ -72,6 +96,12 @@ func (r *ReviewRepository) CreateOrUpdateMRReview(review *models.MRReview) error
 	if review.BotCommentID == nil && existing.BotCommentID != nil {
 		review.BotCommentID = existing.BotCommentID
 	}
+	if review.ReminderCommentID == nil && existing.ReminderCommentID != nil {
+		review.ReminderCommentID = existing.ReminderCommentID
+	}
+	if review.MRAuthorID == nil && existing.MRAuthorID != nil {
+		review.MRAuthorID = existing.MRAuthorID
+	}
 	return r.UpdateMRReview(review)
 }
 
@@ -131,6 +161,28 @@ func (r *ReviewRepository) CountActiveReviewsByUserID(userID uint) (int64, error
 	return count, nil
 }
 
+// CountActiveReviewsByUser counts active reviews for every user with at least one, keyed by user ID.
+func (r *ReviewRepository) CountActiveReviewsByUser() (map[uint]int64, error) {
+	var results []struct {
+		UserID uint
+		Count  int64
+	}
+	if err := r.db.Model(&models.ReviewerAssignment{}).
+		Select("reviewer_assignments.user_id, count(*) as count").
+		Joins("JOIN mr_reviews ON mr_reviews.id = reviewer_assignments.mr_review_id").
+		Where("mr_reviews.status IN ?", []string{models.MRStatusPending, models.MRStatusInReview, models.MRStatusApproved}).
+		Group("reviewer_assignments.user_id").
+		Scan(&results).Error; err != nil {
+		return nil, fmt.Errorf("failed to count active reviews by user: %w", err)
+	}
+
+	counts := make(map[uint]int64, len(results))
+	for _, result := range results {
+		counts[result.UserID] = result.Count
+	}
+	return counts, nil
+}
+
 // GetRecentAssignmentsByUserID retrieves recent assignments for a user within a time window.
 func (r *ReviewRepository) GetRecentAssignmentsByUserID(userID uint, since time.Time) ([]models.ReviewerAssignment, error) {
 	var assignments []models.ReviewerAssignment
@@ -144,6 +196,21 @@ func (r *ReviewRepository) GetRecentAssignmentsByUserID(userID uint, since time.
 	return assignments, nil
 }
 
+// GetAssignmentsByUserIDInRange retrieves a user's assignments made within a time range
+// (bounds included), oldest first.
+func (r *ReviewRepository) GetAssignmentsByUserIDInRange(userID uint, start, end time.Time) ([]models.ReviewerAssignment, error) {
+	var assignments []models.ReviewerAssignment
+	err := r.db.Where("user_id = ? AND assigned_at BETWEEN ? AND ?", userID, start, end).
+		Preload("MRReview").
+		Order("assigned_at ASC").
+		Find(&assignments).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assignments for user %d in range: %w", userID, err)
+	}
+	return assignments, nil
+}
+
 // ListPendingMRReviews lists all MR reviews in pending or in_review status.
 func (r *ReviewRepository) ListPendingMRReviews() ([]models.MRReview, error) {
 	var reviews []models.MRReview
@@ -181,36 +248,108 @@ func (r *ReviewRepository) DeleteAssignmentsByMRReviewID(mrReviewID uint) error
 	return nil
 }
 
+// reviewDeleteBatchSize is the number of MR reviews DeleteReviewsBefore removes per
+// transaction, keeping its IN lists well under the databases' bind parameter limits.
+const reviewDeleteBatchSize = 1000
+
+// DeleteReviewsBefore deletes the merged and closed MR reviews that finished before cutoff,
+// with their assignments, and returns how many reviews were removed. Reviews without a
+// merge or close time are dated by their creation. Open reviews are kept whatever their age.
+// Reviews are deleted in batches, each in its own transaction, so a failure keeps the
+// batches already deleted and the count returned with the error includes them.
+func (r *ReviewRepository) DeleteReviewsBefore(cutoff time.Time) (int64, error) {
+	return r.deleteReviewsBefore(cutoff, reviewDeleteBatchSize)
+}
+
+func (r *ReviewRepository) deleteReviewsBefore(cutoff time.Time, batchSize int) (int64, error) {
+	var deleted int64
+	for {
+		var batchDeleted int64
+		full := false
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			var ids []uint
+			if err := tx.Model(&models.MRReview{}).
+				Where("status IN ?", []string{models.MRStatusMerged, models.MRStatusClosed}).
+				Where("COALESCE(merged_at, closed_at, created_at) < ?", cutoff).
+				Order("id").
+				Limit(batchSize).
+				Pluck("id", &ids).Error; err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				return nil
+			}
+			full = len(ids) == batchSize
+
+			// The foreign key cascades on PostgreSQL; deleting explicitly keeps other databases consistent
+			if err := tx.Where("mr_review_id IN ?", ids).Delete(&models.ReviewerAssignment{}).Error; err != nil {
+				return err
+			}
+			result := tx.Where("id IN ?", ids).Delete(&models.MRReview{})
+			if result.Error != nil {
+				return result.Error
+			}
+			batchDeleted = result.RowsAffected
+			return nil
+		})
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete MR reviews before %s: %w", cutoff.Format(time.RFC3339), err)
+		}
+		deleted += batchDeleted
+		if !full {
+			return deleted, nil
+		}
+	}
+}
+
+// StatusCount is the number of MR reviews in a given status.
+type StatusCount struct {
+	Status string
+	Count  int64
+}
+
 // GetMRReviewStats retrieves statistics for MR reviews.
+// The average TTFR is computed in Go rather than in SQL so the query stays portable across databases.
 func (r *ReviewRepository) GetMRReviewStats(startDate, endDate time.Time) (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
 
 	// Total reviews
 	var totalCount int64
-	r.db.Model(&models.MRReview{}).
+	if err := r.db.Model(&models.MRReview{}).
 		Where("created_at BETWEEN ? AND ?", startDate, endDate).
-		Count(&totalCount)
+		Count(&totalCount).Error; err != nil {
+		return nil, fmt.Errorf("failed to count MR reviews: %w", err)
+	}
 	stats["total_reviews"] = totalCount
 
 	// Reviews by status
-	var statusCounts []struct {
-		Status string
-		Count  int64
-	}
-	r.db.Model(&models.MRReview{}).
+	var statusCounts []StatusCount
+	if err := r.db.Model(&models.MRReview{}).
 		Select("status, count(*) as count").
 		Where("created_at BETWEEN ? AND ?", startDate, endDate).
 		Group("status").
-		Scan(&statusCounts)
+		Scan(&statusCounts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count MR reviews by status: %w", err)
+	}
 	stats["by_status"] = statusCounts
 
 	// Average TTFR
-	var avgTTFR float64
-	r.db.Model(&models.MRReview{}).
-		Select("AVG(EXTRACT(EPOCH FROM (first_review_at - roulette_triggered_at))/60) as avg_ttfr").
+	var reviews []models.MRReview
+	if err := r.db.Select("roulette_triggered_at", "first_review_at").
 		Where("first_review_at IS NOT NULL AND roulette_triggered_at IS NOT NULL").
 		Where("created_at BETWEEN ? AND ?", startDate, endDate).
-		Scan(&avgTTFR)
+		Find(&reviews).Error; err != nil {
+		return nil, fmt.Errorf("failed to get MR review timestamps: %w", err)
+	}
+
+	var avgTTFR float64
+	if len(reviews) > 0 {
+		var totalMinutes float64
+		for _, review := range reviews {
+			totalMinutes += review.FirstReviewAt.Sub(*review.RouletteTriggeredAt).Minutes()
+		}
+		avgTTFR = totalMinutes / float64(len(reviews))
+	}
 	stats["avg_ttfr_minutes"] = avgTTFR
 
 	return stats, nil
@@ -232,6 +371,7 @@ func (r *ReviewRepository) GetCompletedReviewsByDateRange(startDate, endDate tim
 	err := r.db.Where("(merged_at BETWEEN ? AND ?) OR (closed_at BETWEEN ? AND ?)",
 		startDate, endDate, startDate, endDate).
 		Where("status IN ?", []string{models.MRStatusMerged, models.MRStatusClosed}).
+		Preload("MRAuthor").
 		Find(&reviews).Error
 
 	if err != nil {
@@ -239,3 +379,21 @@ func (r *ReviewRepository) GetCompletedReviewsByDateRange(startDate, endDate tim
 	}
 	return reviews, nil
 }
+
+// GetCompletedAssignmentsByDateRange retrieves the assignments of reviews completed within a date range,
+// with their MR review and user.
+func (r *ReviewRepository) GetCompletedAssignmentsByDateRange(startDate, endDate time.Time) ([]models.ReviewerAssignment, error) {
+	var assignments []models.ReviewerAssignment
+	err := r.db.Joins("JOIN mr_reviews ON mr_reviews.id = reviewer_assignments.mr_review_id").
+		Where("(mr_reviews.merged_at BETWEEN ? AND ?) OR (mr_reviews.closed_at BETWEEN ? AND ?)",
+			startDate, endDate, startDate, endDate).
+		Where("mr_reviews.status IN ?", []string{models.MRStatusMerged, models.MRStatusClosed}).
+		Preload("MRReview").
+		Preload("User").
+		Find(&assignments).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get completed assignments: %w", err)
+	}
+	return assignments, nil
+}
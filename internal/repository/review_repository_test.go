@@ -0,0 +1,422 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+)
+
+func TestReviewRepository_GetMRReviewStats(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestDB(t, db)
+
+	if err := db.DB.AutoMigrate(&models.MRReview{}); err != nil {
+		t.Fatalf("Failed to migrate mr_reviews: %v", err)
+	}
+
+	repo := NewReviewRepository(db)
+	now := time.Now().UTC()
+
+	// TTFRs of 30 and 90 minutes, plus a pending review with no first review yet
+	reviews := []struct {
+		status string
+		ttfr   time.Duration
+	}{
+		{status: models.MRStatusMerged, ttfr: 30 * time.Minute},
+		{status: models.MRStatusInReview, ttfr: 90 * time.Minute},
+		{status: models.MRStatusPending},
+	}
+	for i, r := range reviews {
+		triggeredAt := now.Add(-3 * time.Hour)
+		review := &models.MRReview{
+			GitLabMRIID:         i + 1,
+			GitLabProjectID:     100,
+			MRURL:               fmt.Sprintf("https://gitlab.example.com/project/mr/%d", i+1),
+			Team:                "team-frontend",
+			Status:              r.status,
+			RouletteTriggeredAt: &triggeredAt,
+		}
+		if r.ttfr > 0 {
+			firstReviewAt := triggeredAt.Add(r.ttfr)
+			review.FirstReviewAt = &firstReviewAt
+		}
+		if err := repo.CreateMRReview(review); err != nil {
+			t.Fatalf("CreateMRReview failed: %v", err)
+		}
+	}
+
+	stats, err := repo.GetMRReviewStats(now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetMRReviewStats failed: %v", err)
+	}
+
+	if total := stats["total_reviews"]; total != int64(3) {
+		t.Errorf("total_reviews = %v, want 3", total)
+	}
+	if avg := stats["avg_ttfr_minutes"]; avg != 60.0 {
+		t.Errorf("avg_ttfr_minutes = %v, want 60", avg)
+	}
+
+	byStatus, ok := stats["by_status"].([]StatusCount)
+	if !ok {
+		t.Fatalf("by_status has type %T, want []StatusCount", stats["by_status"])
+	}
+	counts := make(map[string]int64)
+	for _, sc := range byStatus {
+		counts[sc.Status] = sc.Count
+	}
+	for _, status := range []string{models.MRStatusMerged, models.MRStatusInReview, models.MRStatusPending} {
+		if counts[status] != 1 {
+			t.Errorf("by_status[%s] = %d, want 1", status, counts[status])
+		}
+	}
+
+	// Reviews outside the range are not counted
+	stats, err = repo.GetMRReviewStats(now.Add(-48*time.Hour), now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("GetMRReviewStats failed: %v", err)
+	}
+	if total := stats["total_reviews"]; total != int64(0) {
+		t.Errorf("total_reviews outside range = %v, want 0", total)
+	}
+	if avg := stats["avg_ttfr_minutes"]; avg != 0.0 {
+		t.Errorf("avg_ttfr_minutes outside range = %v, want 0", avg)
+	}
+}
+
+func TestReviewRepository_GetCompletedAssignmentsByDateRange(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestDB(t, db)
+
+	if err := db.DB.AutoMigrate(&models.MRReview{}, &models.ReviewerAssignment{}); err != nil {
+		t.Fatalf("Failed to migrate reviews: %v", err)
+	}
+
+	repo := NewReviewRepository(db)
+	now := time.Now().UTC()
+	lastWeek := now.Add(-7 * 24 * time.Hour)
+
+	alice := &models.User{GitLabID: 1, Username: "alice", Role: "dev", Team: "team-frontend"}
+	if err := db.Create(alice).Error; err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	// Merged today, closed today, merged last week and still in review
+	reviews := []struct {
+		status   string
+		mergedAt *time.Time
+		closedAt *time.Time
+	}{
+		{status: models.MRStatusMerged, mergedAt: &now},
+		{status: models.MRStatusClosed, closedAt: &now},
+		{status: models.MRStatusMerged, mergedAt: &lastWeek},
+		{status: models.MRStatusInReview},
+	}
+	for i, r := range reviews {
+		review := &models.MRReview{
+			GitLabMRIID:     i + 1,
+			GitLabProjectID: 100,
+			MRURL:           fmt.Sprintf("https://gitlab.example.com/project/mr/%d", i+1),
+			Status:          r.status,
+			MergedAt:        r.mergedAt,
+			ClosedAt:        r.closedAt,
+		}
+		if err := repo.CreateMRReview(review); err != nil {
+			t.Fatalf("CreateMRReview failed: %v", err)
+		}
+		assignment := &models.ReviewerAssignment{MRReviewID: review.ID, UserID: alice.ID, Role: models.ReviewerRoleCodeowner}
+		if err := repo.CreateAssignment(assignment); err != nil {
+			t.Fatalf("CreateAssignment failed: %v", err)
+		}
+	}
+
+	assignments, err := repo.GetCompletedAssignmentsByDateRange(now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetCompletedAssignmentsByDateRange failed: %v", err)
+	}
+
+	if len(assignments) != 2 {
+		t.Fatalf("got %d assignments, want 2", len(assignments))
+	}
+	for _, assignment := range assignments {
+		if assignment.MRReview.GitLabMRIID != 1 && assignment.MRReview.GitLabMRIID != 2 {
+			t.Errorf("unexpected assignment for MR !%d", assignment.MRReview.GitLabMRIID)
+		}
+		if assignment.User.Username != "alice" {
+			t.Errorf("assignment user = %q, want alice", assignment.User.Username)
+		}
+	}
+}
+
+func TestReviewRepository_GetAssignmentsByUserIDInRange(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestDB(t, db)
+
+	if err := db.DB.AutoMigrate(&models.MRReview{}, &models.ReviewerAssignment{}); err != nil {
+		t.Fatalf("Failed to migrate reviews: %v", err)
+	}
+
+	repo := NewReviewRepository(db)
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)
+
+	alice := &models.User{GitLabID: 1, Username: "alice", Role: "dev", Team: "team-frontend"}
+	bob := &models.User{GitLabID: 2, Username: "bob", Role: "dev", Team: "team-frontend"}
+	for _, user := range []*models.User{alice, bob} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+
+	// Before, at the start of, within, at the end of and after the range, plus bob within it
+	assigned := []struct {
+		userID     uint
+		assignedAt time.Time
+	}{
+		{userID: alice.ID, assignedAt: start.Add(-time.Second)},
+		{userID: alice.ID, assignedAt: start},
+		{userID: alice.ID, assignedAt: start.Add(10 * 24 * time.Hour)},
+		{userID: alice.ID, assignedAt: end},
+		{userID: alice.ID, assignedAt: end.Add(time.Second)},
+		{userID: bob.ID, assignedAt: start.Add(24 * time.Hour)},
+	}
+	for i, a := range assigned {
+		review := &models.MRReview{
+			GitLabMRIID:     i + 1,
+			GitLabProjectID: 100,
+			MRURL:           fmt.Sprintf("https://gitlab.example.com/project/mr/%d", i+1),
+			Status:          models.MRStatusInReview,
+		}
+		if err := repo.CreateMRReview(review); err != nil {
+			t.Fatalf("CreateMRReview failed: %v", err)
+		}
+		assignment := &models.ReviewerAssignment{MRReviewID: review.ID, UserID: a.userID, Role: models.ReviewerRoleTeamMember, AssignedAt: a.assignedAt}
+		if err := repo.CreateAssignment(assignment); err != nil {
+			t.Fatalf("CreateAssignment failed: %v", err)
+		}
+	}
+
+	assignments, err := repo.GetAssignmentsByUserIDInRange(alice.ID, start, end)
+	if err != nil {
+		t.Fatalf("GetAssignmentsByUserIDInRange failed: %v", err)
+	}
+
+	if len(assignments) != 3 {
+		t.Fatalf("got %d assignments, want 3", len(assignments))
+	}
+	for i, want := range []int{2, 3, 4} {
+		if assignments[i].MRReview.GitLabMRIID != want {
+			t.Errorf("assignment %d is for MR !%d, want !%d", i, assignments[i].MRReview.GitLabMRIID, want)
+		}
+	}
+
+	assignments, err = repo.GetAssignmentsByUserIDInRange(alice.ID, end.Add(time.Hour), end.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("GetAssignmentsByUserIDInRange failed: %v", err)
+	}
+	if len(assignments) != 0 {
+		t.Errorf("got %d assignments in an empty range, want 0", len(assignments))
+	}
+}
+
+func TestReviewRepository_DeleteReviewsBefore(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestDB(t, db)
+
+	if err := db.DB.AutoMigrate(&models.MRReview{}, &models.ReviewerAssignment{}); err != nil {
+		t.Fatalf("Failed to migrate reviews: %v", err)
+	}
+
+	repo := NewReviewRepository(db)
+	now := time.Now().UTC()
+	cutoff := now.AddDate(0, 0, -90)
+	old := now.AddDate(0, 0, -200)
+	recent := now.AddDate(0, 0, -10)
+
+	alice := &models.User{GitLabID: 1, Username: "alice", Role: "dev", Team: "team-frontend"}
+	if err := db.Create(alice).Error; err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	reviews := []struct {
+		status   string
+		mergedAt *time.Time
+		closedAt *time.Time
+		keep     bool
+	}{
+		{status: models.MRStatusMerged, mergedAt: &old},                // Merged long ago
+		{status: models.MRStatusClosed, closedAt: &old},                // Closed long ago
+		{status: models.MRStatusMerged, mergedAt: &recent, keep: true}, // Merged recently
+		{status: models.MRStatusInReview, keep: true},                  // Still open, created long ago
+		{status: models.MRStatusClosed, closedAt: &recent, keep: true}, // Closed recently
+	}
+	for i, r := range reviews {
+		review := &models.MRReview{
+			GitLabMRIID:     i + 1,
+			GitLabProjectID: 100,
+			MRURL:           fmt.Sprintf("https://gitlab.example.com/project/mr/%d", i+1),
+			Status:          r.status,
+			MergedAt:        r.mergedAt,
+			ClosedAt:        r.closedAt,
+			CreatedAt:       old,
+		}
+		if err := repo.CreateMRReview(review); err != nil {
+			t.Fatalf("CreateMRReview failed: %v", err)
+		}
+		assignment := &models.ReviewerAssignment{MRReviewID: review.ID, UserID: alice.ID, Role: models.ReviewerRoleCodeowner}
+		if err := repo.CreateAssignment(assignment); err != nil {
+			t.Fatalf("CreateAssignment failed: %v", err)
+		}
+	}
+
+	// One review per batch exercises deleting across several transactions
+	deleted, err := repo.deleteReviewsBefore(cutoff, 1)
+	if err != nil {
+		t.Fatalf("deleteReviewsBefore failed: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("deleted %d reviews, want 2", deleted)
+	}
+
+	for i, r := range reviews {
+		review, err := repo.GetMRReview(100, i+1)
+		if r.keep && err != nil {
+			t.Errorf("MR !%d should have been kept: %v", i+1, err)
+		}
+		if !r.keep && err == nil {
+			t.Errorf("MR !%d should have been deleted", i+1)
+		}
+		if review != nil && len(review.Assignments) != 1 {
+			t.Errorf("MR !%d has %d assignments, want 1", i+1, len(review.Assignments))
+		}
+	}
+
+	var assignments int64
+	if err := db.Model(&models.ReviewerAssignment{}).Count(&assignments).Error; err != nil {
+		t.Fatalf("Failed to count assignments: %v", err)
+	}
+	if assignments != 3 {
+		t.Errorf("got %d assignments left, want 3", assignments)
+	}
+
+	// Nothing left to delete
+	deleted, err = repo.DeleteReviewsBefore(cutoff)
+	if err != nil {
+		t.Fatalf("DeleteReviewsBefore failed: %v", err)
+	}
+	if deleted != 0 {
+		t.Errorf("deleted %d reviews on the second run, want 0", deleted)
+	}
+}
+
+func TestReviewRepository_SetReminderCommentID(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestDB(t, db)
+
+	if err := db.DB.AutoMigrate(&models.MRReview{}, &models.ReviewerAssignment{}); err != nil {
+		t.Fatalf("Failed to migrate reviews: %v", err)
+	}
+
+	repo := NewReviewRepository(db)
+	review := &models.MRReview{GitLabMRIID: 1, GitLabProjectID: 100, Status: models.MRStatusPending}
+	if err := repo.CreateMRReview(review); err != nil {
+		t.Fatalf("CreateMRReview failed: %v", err)
+	}
+
+	// A stale snapshot must not revert changes made since it was loaded
+	stale := *review
+	if err := db.Model(&models.MRReview{}).Where("id = ?", review.ID).Update("status", models.MRStatusApproved).Error; err != nil {
+		t.Fatalf("Failed to update status: %v", err)
+	}
+
+	if err := repo.SetReminderCommentID(stale.ID, 42); err != nil {
+		t.Fatalf("SetReminderCommentID failed: %v", err)
+	}
+
+	got, err := repo.GetMRReview(100, 1)
+	if err != nil {
+		t.Fatalf("GetMRReview failed: %v", err)
+	}
+	if got.ReminderCommentID == nil || *got.ReminderCommentID != 42 {
+		t.Errorf("ReminderCommentID = %v, want 42", got.ReminderCommentID)
+	}
+	if got.Status != models.MRStatusApproved {
+		t.Errorf("Status = %q, want %q", got.Status, models.MRStatusApproved)
+	}
+}
+
+func TestReviewRepository_GetActiveAssignmentsByUserID(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestDB(t, db)
+
+	if err := db.DB.AutoMigrate(&models.MRReview{}, &models.ReviewerAssignment{}); err != nil {
+		t.Fatalf("Failed to migrate reviews: %v", err)
+	}
+
+	repo := NewReviewRepository(db)
+	alice := &models.User{GitLabID: 1, Username: "alice", Role: "dev", Team: "team-frontend"}
+	bob := &models.User{GitLabID: 2, Username: "bob", Role: "dev", Team: "team-frontend"}
+	for _, user := range []*models.User{alice, bob} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+
+	statuses := []string{
+		models.MRStatusPending,
+		models.MRStatusInReview,
+		models.MRStatusApproved,
+		models.MRStatusMerged,
+		models.MRStatusClosed,
+	}
+	for i, status := range statuses {
+		review := &models.MRReview{
+			GitLabMRIID:     i + 1,
+			GitLabProjectID: 100,
+			MRTitle:         status,
+			Status:          status,
+		}
+		if err := repo.CreateMRReview(review); err != nil {
+			t.Fatalf("CreateMRReview failed: %v", err)
+		}
+		for _, user := range []*models.User{alice, bob} {
+			assignment := &models.ReviewerAssignment{MRReviewID: review.ID, UserID: user.ID, Role: models.ReviewerRoleTeamMember}
+			if err := repo.CreateAssignment(assignment); err != nil {
+				t.Fatalf("CreateAssignment failed: %v", err)
+			}
+		}
+	}
+
+	assignments, err := repo.GetActiveAssignmentsByUserID(alice.ID)
+	if err != nil {
+		t.Fatalf("GetActiveAssignmentsByUserID failed: %v", err)
+	}
+
+	got := make(map[string]bool)
+	for _, assignment := range assignments {
+		if assignment.UserID != alice.ID {
+			t.Errorf("got assignment of user %d, want only user %d", assignment.UserID, alice.ID)
+		}
+		got[assignment.MRReview.Status] = true
+	}
+	want := map[string]bool{models.MRStatusPending: true, models.MRStatusInReview: true, models.MRStatusApproved: true}
+	if len(assignments) != len(want) {
+		t.Errorf("got %d active assignments, want %d", len(assignments), len(want))
+	}
+	for status := range want {
+		if !got[status] {
+			t.Errorf("missing active assignment on a %s MR", status)
+		}
+	}
+
+	// An unknown user has no active assignments rather than an error
+	assignments, err = repo.GetActiveAssignmentsByUserID(999)
+	if err != nil {
+		t.Fatalf("GetActiveAssignmentsByUserID (unknown user) failed: %v", err)
+	}
+	if len(assignments) != 0 {
+		t.Errorf("got %d assignments for an unknown user, want 0", len(assignments))
+	}
+}
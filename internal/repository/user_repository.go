@@ -1,9 +1,12 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
+	"gorm.io/gorm"
+
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
 )
 
@@ -47,6 +50,9 @@ func (r *UserRepository) GetByUsername(username string) (*models.User, error) {
 func (r *UserRepository) GetByID(id uint) (*models.User, error) {
 	var user models.User
 	if err := r.db.First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("user %d: %w", id, ErrNotFound)
+		}
 		return nil, fmt.Errorf("failed to get user by id %d: %w", id, err)
 	}
 	return &user, nil
@@ -78,6 +84,15 @@ func (r *UserRepository) List(team, role string) ([]models.User, error) {
 	return users, nil
 }
 
+// Count returns the number of users.
+func (r *UserRepository) Count() (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.User{}).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return count, nil
+}
+
 // GetByTeam retrieves all users in a team.
 func (r *UserRepository) GetByTeam(team string) ([]models.User, error) {
 	return r.List(team, "")
@@ -104,6 +119,9 @@ func (r *UserRepository) CreateOrUpdate(user *models.User) error {
 	if err == nil {
 		// User exists by GitLab ID, update it
 		existing.Username = user.Username
+		if user.DisplayName != "" {
+			existing.DisplayName = user.DisplayName
+		}
 		existing.Email = user.Email
 		existing.Role = user.Role
 		existing.Team = user.Team
@@ -116,6 +134,9 @@ func (r *UserRepository) CreateOrUpdate(user *models.User) error {
 	if err == nil {
 		// User exists by username, update including GitLab ID
 		existing.GitLabID = user.GitLabID
+		if user.DisplayName != "" {
+			existing.DisplayName = user.DisplayName
+		}
 		existing.Email = user.Email
 		existing.Role = user.Role
 		existing.Team = user.Team
@@ -0,0 +1,29 @@
+package repository
+
+import "testing"
+
+func TestUserRepository_Count(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestDB(t, db)
+
+	repo := NewUserRepository(db)
+
+	count, err := repo.Count()
+	if err != nil {
+		t.Fatalf("Count() failed: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected 0 users, got %d", count)
+	}
+
+	createTestUser(t, db, "alice", "team-a")
+	createTestUser(t, db, "bob", "team-b")
+
+	count, err = repo.Count()
+	if err != nil {
+		t.Fatalf("Count() failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 users, got %d", count)
+	}
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+)
+
+// WebhookDeliveryRepository handles the persisted outgoing webhook queue.
+type WebhookDeliveryRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookDeliveryRepository creates a new webhook delivery repository instance.
+func NewWebhookDeliveryRepository(db *DB) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{
+		db: db.DB,
+	}
+}
+
+// Create stores a new delivery.
+func (r *WebhookDeliveryRepository) Create(delivery *models.WebhookDelivery) error {
+	if err := r.db.Create(delivery).Error; err != nil {
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a delivery by ID.
+func (r *WebhookDeliveryRepository) GetByID(id uint) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	if err := r.db.First(&delivery, id).Error; err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery %d: %w", id, err)
+	}
+	return &delivery, nil
+}
+
+// GetDue retrieves pending deliveries whose next attempt is due, oldest first.
+func (r *WebhookDeliveryRepository) GetDue(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+
+	query := r.db.
+		Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryStatusPending, now).
+		Order("next_attempt_at ASC, id ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Find(&deliveries).Error; err != nil {
+		return nil, fmt.Errorf("failed to get due webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// Update saves the delivery state after an attempt.
+func (r *WebhookDeliveryRepository) Update(delivery *models.WebhookDelivery) error {
+	if err := r.db.Save(delivery).Error; err != nil {
+		return fmt.Errorf("failed to update webhook delivery %d: %w", delivery.ID, err)
+	}
+	return nil
+}
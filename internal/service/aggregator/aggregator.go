@@ -1,13 +1,15 @@
-// Package aggregator provides daily batch aggregation of review metrics.
+// Package aggregator provides daily and hourly batch aggregation of review metrics.
 package aggregator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/rs/zerolog"
 
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/metrics"
@@ -18,17 +20,66 @@ type Service struct {
 	reviewRepo  *repository.ReviewRepository
 	metricsRepo *repository.MetricsRepository
 	log         *zerolog.Logger
+
+	excludedUsers     *config.ExcludedUsersConfig
+	batchSize         int
+	minReviewComments int
+	hourly            bool
+	engagement        *metrics.EngagementCalculator
+	clock             *metrics.ReviewClock
 }
 
+// DefaultBatchSize is the number of metrics rows written per upsert statement.
+const DefaultBatchSize = 500
+
 // NewService creates a new aggregator service.
 func NewService(reviewRepo *repository.ReviewRepository, metricsRepo *repository.MetricsRepository, log *zerolog.Logger) *Service {
 	return &Service{
 		reviewRepo:  reviewRepo,
 		metricsRepo: metricsRepo,
 		log:         log,
+		engagement:  metrics.NewEngagementCalculator(metrics.DefaultEngagementConfig),
+		clock:       metrics.NewReviewClock(false, nil),
 	}
 }
 
+// SetExcludedUsers keeps the given users' MRs and reviews out of aggregated metrics.
+func (s *Service) SetExcludedUsers(excludedUsers *config.ExcludedUsersConfig) {
+	s.excludedUsers = excludedUsers
+}
+
+// SetBatchSize sets the number of metrics rows written per upsert statement.
+// Non-positive values use DefaultBatchSize.
+func (s *Service) SetBatchSize(batchSize int) {
+	s.batchSize = batchSize
+}
+
+// SetMinReviewComments sets the engagement floor: assignments with fewer comments
+// are not counted as completed reviews and get no engagement score. Zero disables it.
+func (s *Service) SetMinReviewComments(minComments int) {
+	s.minReviewComments = minComments
+}
+
+// SetHourly makes AggregateRange also build hourly team rows for every day.
+func (s *Service) SetHourly(enabled bool) {
+	s.hourly = enabled
+}
+
+// SetEngagementCalculator sets the formula used to score reviewer and team engagement.
+func (s *Service) SetEngagementCalculator(calculator *metrics.EngagementCalculator) {
+	s.engagement = calculator
+}
+
+// SetReviewClock sets how TTFR and time to approval are measured.
+func (s *Service) SetReviewClock(clock *metrics.ReviewClock) {
+	s.clock = clock
+}
+
+// meetsEngagementFloor reports whether an assignment has enough comments to count as a review.
+func (s *Service) meetsEngagementFloor(assignment *models.ReviewerAssignment) bool {
+	return assignment.CommentCount >= s.minReviewComments
+}
+
 // AggregateDaily aggregates metrics for a specific date.
 func (s *Service) AggregateDaily(ctx context.Context, date time.Time) error {
 	// Normalize to start of day
@@ -40,20 +91,16 @@ func (s *Service) AggregateDaily(ctx context.Context, date time.Time) error {
 		Msg("Starting daily metrics aggregation")
 
 	// Get all completed reviews for this day
-	reviews, err := s.reviewRepo.GetCompletedReviewsByDateRange(startOfDay, endOfDay)
+	reviews, err := s.reviewRepo.WithContext(ctx).GetCompletedReviewsByDateRange(startOfDay, endOfDay)
 	if err != nil {
 		return fmt.Errorf("failed to get completed reviews: %w", err)
 	}
+	reviews = s.withoutExcludedAuthors(reviews)
 
 	s.log.Debug().
 		Int("review_count", len(reviews)).
 		Msg("Found completed reviews")
 
-	if len(reviews) == 0 {
-		s.log.Info().Msg("No completed reviews found for date")
-		return nil
-	}
-
 	// Group reviews by team
 	teamReviews := make(map[string][]models.MRReview)
 	for _, review := range reviews {
@@ -61,25 +108,44 @@ func (s *Service) AggregateDaily(ctx context.Context, date time.Time) error {
 	}
 
 	// Aggregate metrics for each team
+	var rows []*models.ReviewMetrics
 	for team, reviews := range teamReviews {
-		if err := s.aggregateTeamMetrics(ctx, startOfDay, team, reviews); err != nil {
-			s.log.Error().
-				Err(err).
-				Str("team", team).
-				Msg("Failed to aggregate team metrics")
-			continue
+		if metric := s.aggregateTeamMetrics(ctx, startOfDay, nil, team, reviews); metric != nil {
+			rows = append(rows, metric)
 		}
 	}
 
 	// Aggregate user-level metrics
 	for _, review := range reviews {
-		if err := s.aggregateUserMetrics(ctx, startOfDay, review); err != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		userRows, err := s.aggregateUserMetrics(ctx, startOfDay, review)
+		if err != nil {
 			s.log.Error().
 				Err(err).
 				Uint("review_id", review.ID).
 				Msg("Failed to aggregate user metrics")
 			continue
 		}
+		rows = append(rows, userRows...)
+	}
+
+	// Rebuild the day's rows from scratch so reviews reopened since the last run lose their
+	// credit, atomically so a failed run leaves the previous rows in place
+	err = s.metricsRepo.WithContext(ctx).Transaction(func(tx *repository.MetricsRepository) error {
+		if err := tx.DeleteDailyAggregatedMetrics(startOfDay); err != nil {
+			return err
+		}
+		return s.saveMetrics(tx, rows)
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(reviews) == 0 {
+		s.log.Info().Msg("No completed reviews found for date")
+		return nil
 	}
 
 	s.log.Info().
@@ -91,51 +157,202 @@ func (s *Service) AggregateDaily(ctx context.Context, date time.Time) error {
 	return nil
 }
 
-// aggregateTeamMetrics calculates and stores team-level metrics.
-func (s *Service) aggregateTeamMetrics(_ context.Context, date time.Time, team string, reviews []models.MRReview) error {
-	// Calculate metrics
-	var totalTTFR, totalTimeToApproval float64
-	var ttfrCount, approvalCount int
-	var totalCommentCount, totalCommentLength int
-	var completedCount int
+// AggregateRange aggregates daily metrics, and hourly ones when enabled with SetHourly,
+// for every day from start to end (inclusive).
+// Failed days are logged and skipped; the returned error summarizes all failures.
+// Cancelling ctx stops the range before the next day and returns ctx.Err().
+// Re-running a range is safe since daily aggregation is idempotent.
+func (s *Service) AggregateRange(ctx context.Context, start, end time.Time) error {
+	startDay := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	endDay := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, start.Location())
+
+	if endDay.Before(startDay) {
+		return fmt.Errorf("invalid date range: end %s is before start %s", endDay.Format("2006-01-02"), startDay.Format("2006-01-02"))
+	}
 
-	for _, review := range reviews {
-		// Count completed reviews (merged)
-		if review.Status == models.MRStatusMerged {
-			completedCount++
+	s.log.Info().
+		Time("start", startDay).
+		Time("end", endDay).
+		Msg("Starting range metrics aggregation")
+
+	var errs []error
+	days := 0
+	for day := startDay; !day.After(endDay); day = day.AddDate(0, 0, 1) {
+		if err := ctx.Err(); err != nil {
+			s.log.Warn().
+				Err(err).
+				Time("date", day).
+				Int("days", days).
+				Msg("Range metrics aggregation cancelled")
+			return err
 		}
+		days++
 
-		// Calculate TTFR
-		if review.FirstReviewAt != nil && review.RouletteTriggeredAt != nil {
-			ttfr := review.FirstReviewAt.Sub(*review.RouletteTriggeredAt).Seconds()
-			if ttfr >= 0 {
-				totalTTFR += ttfr
-				ttfrCount++
+		if err := s.AggregateDaily(ctx, day); err != nil {
+			s.log.Error().
+				Err(err).
+				Time("date", day).
+				Msg("Failed to aggregate day, continuing")
+			errs = append(errs, fmt.Errorf("%s: %w", day.Format("2006-01-02"), err))
+			continue
+		}
+		if s.hourly {
+			if err := s.AggregateHourly(ctx, day); err != nil {
+				s.log.Error().
+					Err(err).
+					Time("date", day).
+					Msg("Failed to aggregate day hourly, continuing")
+				errs = append(errs, fmt.Errorf("%s hourly: %w", day.Format("2006-01-02"), err))
+				continue
 			}
 		}
 
-		// Calculate time to approval
-		if review.ApprovedAt != nil && review.RouletteTriggeredAt != nil {
-			approvalTime := review.ApprovedAt.Sub(*review.RouletteTriggeredAt).Seconds()
-			if approvalTime >= 0 {
-				totalTimeToApproval += approvalTime
-				approvalCount++
+		s.log.Debug().
+			Time("date", day).
+			Int("day", days).
+			Msg("Aggregated day")
+	}
+
+	s.log.Info().
+		Time("start", startDay).
+		Time("end", endDay).
+		Int("days", days).
+		Int("failed", len(errs)).
+		Msg("Range metrics aggregation completed")
+
+	if len(errs) > 0 {
+		return fmt.Errorf("aggregation failed for %d of %d days: %w", len(errs), days, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// AggregateHourly aggregates team-level metrics for a specific date into
+// one bucket per hour of the day, based on when each review was completed.
+// Daily aggregation is unaffected and remains the default granularity.
+func (s *Service) AggregateHourly(ctx context.Context, date time.Time) error {
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	s.log.Info().
+		Time("date", startOfDay).
+		Msg("Starting hourly metrics aggregation")
+
+	reviews, err := s.reviewRepo.WithContext(ctx).GetCompletedReviewsByDateRange(startOfDay, endOfDay)
+	if err != nil {
+		return fmt.Errorf("failed to get completed reviews: %w", err)
+	}
+	reviews = s.withoutExcludedAuthors(reviews)
+
+	// Group reviews by hour of completion, then by team
+	hourlyReviews := make(map[int]map[string][]models.MRReview)
+	for _, review := range reviews {
+		completedAt := review.MergedAt
+		if completedAt == nil {
+			completedAt = review.ClosedAt
+		}
+		if completedAt == nil {
+			continue
+		}
+
+		hour := completedAt.In(startOfDay.Location()).Hour()
+		if hourlyReviews[hour] == nil {
+			hourlyReviews[hour] = make(map[string][]models.MRReview)
+		}
+		hourlyReviews[hour][review.Team] = append(hourlyReviews[hour][review.Team], review)
+	}
+
+	var rows []*models.ReviewMetrics
+	for hour, teamReviews := range hourlyReviews {
+		for team, reviews := range teamReviews {
+			if metric := s.aggregateTeamMetrics(ctx, startOfDay, &hour, team, reviews); metric != nil {
+				rows = append(rows, metric)
 			}
 		}
+	}
 
+	// Rebuild the day's buckets from scratch so hours whose reviews were reopened or excluded
+	// since the last run are dropped, atomically so a failed run leaves the previous rows in place
+	err = s.metricsRepo.WithContext(ctx).Transaction(func(tx *repository.MetricsRepository) error {
+		if err := tx.DeleteHourlyMetrics(startOfDay); err != nil {
+			return err
+		}
+		return s.saveMetrics(tx, rows)
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(reviews) == 0 {
+		s.log.Info().Msg("No completed reviews found for date")
+		return nil
+	}
+
+	s.log.Info().
+		Time("date", startOfDay).
+		Int("buckets", len(hourlyReviews)).
+		Int("reviews", len(reviews)).
+		Msg("Hourly metrics aggregation completed")
+
+	return nil
+}
+
+// aggregateTeamMetrics calculates team-level metrics, or returns nil when no reviews count.
+// A nil hour builds a daily row; otherwise an hourly row for that hour.
+func (s *Service) aggregateTeamMetrics(ctx context.Context, date time.Time, hour *int, team string, reviews []models.MRReview) *models.ReviewMetrics {
+	// Calculate metrics
+	var totalTTFR, totalTimeToApproval float64
+	var ttfrCount, approvalCount int
+	var totalCommentCount, totalCommentLength int
+	var completedCount, reviewCount int
+
+	for _, review := range reviews {
 		// Get assignments for comment metrics
-		assignments, err := s.reviewRepo.GetAssignmentsByMRReviewID(review.ID)
+		assignments, err := s.reviewRepo.WithContext(ctx).GetAssignmentsByMRReviewID(review.ID)
 		if err != nil {
 			s.log.Warn().Err(err).Uint("review_id", review.ID).Msg("Failed to get assignments")
+		}
+
+		// Skip MRs reviewed only by excluded users
+		included := s.withoutExcludedReviewers(assignments)
+		if len(assignments) > 0 && len(included) == 0 {
 			continue
 		}
+		reviewCount++
 
-		for _, assignment := range assignments {
+		// MRs with reviewers but none above the engagement floor are not counted as completed
+		engaged := len(included) == 0
+		for _, assignment := range included {
 			totalCommentCount += assignment.CommentCount
 			totalCommentLength += assignment.CommentLength
+			if s.meetsEngagementFloor(&assignment) {
+				engaged = true
+			}
+		}
+
+		// Count completed reviews (merged)
+		if review.Status == models.MRStatusMerged && engaged {
+			completedCount++
+		}
+
+		// Calculate TTFR
+		if firstReviewAt := firstReviewAt(&review, included); firstReviewAt != nil && review.RouletteTriggeredAt != nil && !firstReviewAt.Before(*review.RouletteTriggeredAt) {
+			totalTTFR += float64(*s.clock.TTFR(*review.RouletteTriggeredAt, firstReviewAt))
+			ttfrCount++
+		}
+
+		// Calculate time to approval
+		if review.ApprovedAt != nil && review.RouletteTriggeredAt != nil && !review.ApprovedAt.Before(*review.RouletteTriggeredAt) {
+			totalTimeToApproval += float64(*s.clock.TimeToApproval(*review.RouletteTriggeredAt, review.ApprovedAt))
+			approvalCount++
 		}
 	}
 
+	if reviewCount == 0 {
+		s.log.Debug().Str("team", team).Msg("No reviews left after excluding users")
+		return nil
+	}
+
 	// Calculate averages
 	var avgTTFR, avgTimeToApproval float64
 	if ttfrCount > 0 {
@@ -145,19 +362,12 @@ func (s *Service) aggregateTeamMetrics(_ context.Context, date time.Time, team s
 		avgTimeToApproval = totalTimeToApproval / float64(approvalCount)
 	}
 
-	avgCommentCount := 0.0
-	avgCommentLength := 0.0
-	if len(reviews) > 0 {
-		avgCommentCount = float64(totalCommentCount) / float64(len(reviews))
-		avgCommentLength = float64(totalCommentLength) / float64(len(reviews))
-	}
+	avgCommentCount := float64(totalCommentCount) / float64(reviewCount)
+	avgCommentLength := float64(totalCommentLength) / float64(reviewCount)
 
 	// Calculate engagement score
-	// For aggregated data, we'll use a simple formula: (avgCommentCount * 10) + (avgCommentLength / 100)
-	var engagementScore float64
-	if len(reviews) > 0 {
-		engagementScore = (avgCommentCount * 10.0) + (avgCommentLength / 100.0)
-	}
+	// Aggregated data has no per-reviewer response times, so only the comment part of the formula applies
+	engagementScore := s.engagement.CommentScore(avgCommentCount, avgCommentLength)
 
 	// Convert seconds to minutes for storage
 	var avgTTFRMinutes, avgTimeToApprovalMinutes *int
@@ -170,74 +380,59 @@ func (s *Service) aggregateTeamMetrics(_ context.Context, date time.Time, team s
 		avgTimeToApprovalMinutes = &minutes
 	}
 
-	// Store metrics
+	granularity := models.MetricsGranularityDaily
+	if hour != nil {
+		granularity = models.MetricsGranularityHourly
+	}
+
 	metric := &models.ReviewMetrics{
 		Date:              date,
 		Team:              team,
-		TotalReviews:      len(reviews),
+		Granularity:       granularity,
+		Hour:              hour,
+		TotalReviews:      reviewCount,
 		CompletedReviews:  completedCount,
 		AvgTTFR:           avgTTFRMinutes,
 		AvgTimeToApproval: avgTimeToApprovalMinutes,
 		AvgCommentCount:   &avgCommentCount,
 		AvgCommentLength:  &avgCommentLength,
 		EngagementScore:   &engagementScore,
-	}
-
-	if err := s.metricsRepo.CreateOrUpdate(metric); err != nil {
-		return fmt.Errorf("failed to save team metrics: %w", err)
+		TTFRSamples:       ttfrCount,
+		ApprovalSamples:   approvalCount,
+		CommentSamples:    reviewCount,
+		EngagementSamples: reviewCount,
 	}
 
 	s.log.Debug().
 		Str("team", team).
-		Int("total_reviews", len(reviews)).
+		Int("total_reviews", reviewCount).
 		Int("completed", completedCount).
 		Float64("avg_ttfr", avgTTFR).
 		Float64("engagement", engagementScore).
 		Msg("Team metrics aggregated")
 
-	return nil
+	return metric
 }
 
-// aggregateUserMetrics calculates and stores user-level metrics.
-func (s *Service) aggregateUserMetrics(_ context.Context, date time.Time, review models.MRReview) error {
-	assignments, err := s.reviewRepo.GetAssignmentsByMRReviewID(review.ID)
+// aggregateUserMetrics calculates user-level metrics for each reviewer of an MR.
+func (s *Service) aggregateUserMetrics(ctx context.Context, date time.Time, review models.MRReview) ([]*models.ReviewMetrics, error) {
+	assignments, err := s.reviewRepo.WithContext(ctx).GetAssignmentsByMRReviewID(review.ID)
 	if err != nil {
-		return fmt.Errorf("failed to get assignments: %w", err)
+		return nil, fmt.Errorf("failed to get assignments: %w", err)
 	}
 
-	for _, assignment := range assignments {
-		// Calculate metrics for this user
-		var avgTTFR, avgTimeToApproval float64
-
-		// TTFR from user's first comment
-		if assignment.FirstCommentAt != nil && assignment.AssignedAt.Unix() > 0 {
-			ttfr := assignment.FirstCommentAt.Sub(assignment.AssignedAt).Seconds()
-			if ttfr >= 0 {
-				avgTTFR = ttfr
-			}
-		}
-
-		// Time to approval
-		if assignment.ApprovedAt != nil && assignment.AssignedAt.Unix() > 0 {
-			approvalTime := assignment.ApprovedAt.Sub(assignment.AssignedAt).Seconds()
-			if approvalTime >= 0 {
-				avgTimeToApproval = approvalTime
-			}
+	var rows []*models.ReviewMetrics
+	for _, assignment := range s.withoutExcludedReviewers(assignments) {
+		// TTFR and approval time are measured from the assignment, or from the
+		// roulette trigger for older assignments without AssignedAt
+		var avgTTFRMinutes, avgTimeToApprovalMinutes *int
+		if start, ok := assignmentStart(&assignment, &review); ok {
+			avgTTFRMinutes = metrics.SecondsToMinutes(s.clock.TTFR(start, assignment.FirstCommentAt))
+			avgTimeToApprovalMinutes = metrics.SecondsToMinutes(s.clock.TimeToApproval(start, assignment.ApprovedAt))
 		}
 
 		// Engagement score - use the actual assignment object
-		engagementScore := metrics.CalculateEngagementScore(&assignment, &review)
-
-		// Convert seconds to minutes for storage
-		var avgTTFRMinutes, avgTimeToApprovalMinutes *int
-		if avgTTFR > 0 {
-			minutes := int(avgTTFR / 60)
-			avgTTFRMinutes = &minutes
-		}
-		if avgTimeToApproval > 0 {
-			minutes := int(avgTimeToApproval / 60)
-			avgTimeToApprovalMinutes = &minutes
-		}
+		engagementScore := s.engagement.Score(&assignment)
 
 		commentCount := float64(assignment.CommentCount)
 		commentLength := float64(assignment.CommentLength)
@@ -246,10 +441,16 @@ func (s *Service) aggregateUserMetrics(_ context.Context, date time.Time, review
 			completedReviews = 1
 		}
 
-		// Store user-level metrics
+		// Reviews below the engagement floor (e.g. approvals without comments) do not count
+		engaged := s.meetsEngagementFloor(&assignment)
+		if !engaged {
+			completedReviews = 0
+		}
+
 		metric := &models.ReviewMetrics{
 			Date:              date,
 			Team:              review.Team,
+			Granularity:       models.MetricsGranularityDaily,
 			UserID:            &assignment.UserID,
 			ProjectID:         &review.GitLabProjectID,
 			TotalReviews:      1,
@@ -258,16 +459,20 @@ func (s *Service) aggregateUserMetrics(_ context.Context, date time.Time, review
 			AvgTimeToApproval: avgTimeToApprovalMinutes,
 			AvgCommentCount:   &commentCount,
 			AvgCommentLength:  &commentLength,
-			EngagementScore:   &engagementScore,
+			CommentSamples:    1,
 		}
-
-		if err := s.metricsRepo.CreateOrUpdate(metric); err != nil {
-			s.log.Warn().
-				Err(err).
-				Uint("user_id", assignment.UserID).
-				Msg("Failed to save user metrics")
-			continue
+		if engaged {
+			metric.EngagementScore = &engagementScore
+			metric.EngagementSamples = 1
+		}
+		if avgTTFRMinutes != nil {
+			metric.TTFRSamples = 1
 		}
+		if avgTimeToApprovalMinutes != nil {
+			metric.ApprovalSamples = 1
+		}
+
+		rows = append(rows, metric)
 
 		s.log.Debug().
 			Uint("user_id", assignment.UserID).
@@ -276,5 +481,81 @@ func (s *Service) aggregateUserMetrics(_ context.Context, date time.Time, review
 			Msg("User metrics aggregated")
 	}
 
+	return rows, nil
+}
+
+// saveMetrics upserts metrics rows in batches.
+func (s *Service) saveMetrics(metricsRepo *repository.MetricsRepository, rows []*models.ReviewMetrics) error {
+	batchSize := s.batchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	for start := 0; start < len(rows); start += batchSize {
+		end := min(start+batchSize, len(rows))
+		if err := metricsRepo.CreateOrUpdateBatch(rows[start:end]); err != nil {
+			return fmt.Errorf("failed to save metrics: %w", err)
+		}
+	}
+
 	return nil
 }
+
+// assignmentStart returns the time a reviewer's review clock starts.
+// Falls back to the MR's roulette trigger time when AssignedAt is unset.
+func assignmentStart(assignment *models.ReviewerAssignment, review *models.MRReview) (time.Time, bool) {
+	if assignment.AssignedAt.Unix() > 0 {
+		return assignment.AssignedAt, true
+	}
+	if review.RouletteTriggeredAt != nil {
+		return *review.RouletteTriggeredAt, true
+	}
+	return time.Time{}, false
+}
+
+// firstReviewAt returns when an MR was first reviewed: the earliest first comment among
+// its reviewers, or the MR's FirstReviewAt when no reviewer has commented. The MR field
+// is set from whichever comment event arrived first and may be stale with several reviewers.
+func firstReviewAt(review *models.MRReview, assignments []models.ReviewerAssignment) *time.Time {
+	var first *time.Time
+	for i := range assignments {
+		commentAt := assignments[i].FirstCommentAt
+		if commentAt != nil && (first == nil || commentAt.Before(*first)) {
+			first = commentAt
+		}
+	}
+	if first == nil {
+		return review.FirstReviewAt
+	}
+	return first
+}
+
+// withoutExcludedAuthors drops reviews of MRs authored by excluded users.
+func (s *Service) withoutExcludedAuthors(reviews []models.MRReview) []models.MRReview {
+	if s.excludedUsers == nil {
+		return reviews
+	}
+	filtered := make([]models.MRReview, 0, len(reviews))
+	for _, review := range reviews {
+		if s.excludedUsers.ExcludesUser(review.MRAuthor) {
+			continue
+		}
+		filtered = append(filtered, review)
+	}
+	return filtered
+}
+
+// withoutExcludedReviewers drops assignments of excluded users.
+func (s *Service) withoutExcludedReviewers(assignments []models.ReviewerAssignment) []models.ReviewerAssignment {
+	if s.excludedUsers == nil {
+		return assignments
+	}
+	filtered := make([]models.ReviewerAssignment, 0, len(assignments))
+	for _, assignment := range assignments {
+		if s.excludedUsers.ExcludesUser(&assignment.User) {
+			continue
+		}
+		filtered = append(filtered, assignment)
+	}
+	return filtered
+}
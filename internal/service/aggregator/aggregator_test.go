@@ -2,6 +2,7 @@ package aggregator
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -11,8 +12,10 @@ import (
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/metrics"
 )
 
 func setupTestDB(t *testing.T) (*gorm.DB, func()) {
@@ -27,6 +30,7 @@ func setupTestDB(t *testing.T) (*gorm.DB, func()) {
 		&models.ReviewMetrics{},
 	)
 	require.NoError(t, err)
+	require.NoError(t, repository.CreateMetricsIndexes(db))
 
 	cleanup := func() {
 		sqlDB, _ := db.DB()
@@ -134,7 +138,7 @@ func TestAggregateDaily_TeamMetrics(t *testing.T) {
 
 	// Verify team-level metrics (use start of day for query)
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	teamMetrics, err := metricsRepo.GetByDate(startOfDay, "team-frontend", nil)
+	teamMetrics, err := metricsRepo.GetByDate(startOfDay, "team-frontend", nil, nil)
 	require.NoError(t, err)
 	assert.NotNil(t, teamMetrics)
 
@@ -230,6 +234,62 @@ func TestAggregateDaily_UserMetrics(t *testing.T) {
 	assert.Equal(t, 500.0, *metric.AvgCommentLength)
 }
 
+func TestAggregateDaily_UserMetricsZeroAssignedAt(t *testing.T) {
+	gormDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	db := &repository.DB{DB: gormDB}
+	reviewRepo := repository.NewReviewRepository(db)
+	metricsRepo := repository.NewMetricsRepository(db)
+
+	user := models.User{GitLabID: 1, Username: "alice", Email: "alice@example.com", Role: "dev", Team: "team-frontend"}
+	require.NoError(t, gormDB.Create(&user).Error)
+
+	date := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	triggeredAt := date.Add(-2 * time.Hour)
+	mergedAt := date
+
+	review := models.MRReview{
+		GitLabMRIID:         1,
+		GitLabProjectID:     100,
+		MRURL:               "https://gitlab.example.com/project/mr/1",
+		MRTitle:             "Legacy MR",
+		Team:                "team-frontend",
+		RouletteTriggeredAt: &triggeredAt,
+		MergedAt:            &mergedAt,
+		Status:              models.MRStatusMerged,
+	}
+	require.NoError(t, reviewRepo.CreateMRReview(&review))
+
+	// Older records have no AssignedAt; TTFR falls back to the roulette trigger time
+	firstCommentAt := triggeredAt.Add(45 * time.Minute)
+	approvedAt := triggeredAt.Add(90 * time.Minute)
+	assignment := models.ReviewerAssignment{
+		MRReviewID:     review.ID,
+		UserID:         user.ID,
+		Role:           models.ReviewerRoleCodeowner,
+		FirstCommentAt: &firstCommentAt,
+		ApprovedAt:     &approvedAt,
+		CommentCount:   2,
+		CommentLength:  200,
+	}
+	require.NoError(t, gormDB.Create(&assignment).Error)
+
+	log := zerolog.Nop()
+	service := NewService(reviewRepo, metricsRepo, &log)
+	require.NoError(t, service.AggregateDaily(context.Background(), date))
+
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	userMetrics, err := metricsRepo.GetMetricsByUser(user.ID, startOfDay, startOfDay)
+	require.NoError(t, err)
+	require.Len(t, userMetrics, 1)
+
+	require.NotNil(t, userMetrics[0].AvgTTFR)
+	assert.Equal(t, 45, *userMetrics[0].AvgTTFR)
+	require.NotNil(t, userMetrics[0].AvgTimeToApproval)
+	assert.Equal(t, 90, *userMetrics[0].AvgTimeToApproval)
+}
+
 func TestAggregateDaily_MultipleTeams(t *testing.T) {
 	gormDB, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -311,12 +371,12 @@ func TestAggregateDaily_MultipleTeams(t *testing.T) {
 
 	// Verify both teams have metrics (use start of day)
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	frontendMetrics, err := metricsRepo.GetByDate(startOfDay, "team-frontend", nil)
+	frontendMetrics, err := metricsRepo.GetByDate(startOfDay, "team-frontend", nil, nil)
 	require.NoError(t, err)
 	assert.NotNil(t, frontendMetrics)
 	assert.Equal(t, 1, frontendMetrics.TotalReviews)
 
-	platformMetrics, err := metricsRepo.GetByDate(startOfDay, "team-platform", nil)
+	platformMetrics, err := metricsRepo.GetByDate(startOfDay, "team-platform", nil, nil)
 	require.NoError(t, err)
 	assert.NotNil(t, platformMetrics)
 	assert.Equal(t, 1, platformMetrics.TotalReviews)
@@ -393,6 +453,71 @@ func TestAggregateDaily_Idempotency(t *testing.T) {
 	assert.Len(t, userMetrics, 1, "Should have exactly one user-level metric")
 }
 
+func TestAggregateDaily_ReopenedOnlyReviewOfTeam(t *testing.T) {
+	gormDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	db := &repository.DB{DB: gormDB}
+	reviewRepo := repository.NewReviewRepository(db)
+	metricsRepo := repository.NewMetricsRepository(db)
+
+	alice := models.User{GitLabID: 1, Username: "alice", Email: "alice@example.com", Role: "dev", Team: "team-frontend"}
+	require.NoError(t, gormDB.Create(&alice).Error)
+	bob := models.User{GitLabID: 2, Username: "bob", Email: "bob@example.com", Role: "dev", Team: "team-backend"}
+	require.NoError(t, gormDB.Create(&bob).Error)
+
+	date := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	mergedAt := date
+	reviews := []models.MRReview{
+		{GitLabMRIID: 1, GitLabProjectID: 100, MRURL: "https://gitlab.example.com/project/mr/1", Team: "team-frontend", MergedAt: &mergedAt, Status: models.MRStatusMerged},
+		{GitLabMRIID: 2, GitLabProjectID: 100, MRURL: "https://gitlab.example.com/project/mr/2", Team: "team-backend", MergedAt: &mergedAt, Status: models.MRStatusMerged},
+	}
+	for i, reviewer := range []models.User{alice, bob} {
+		require.NoError(t, reviewRepo.CreateMRReview(&reviews[i]))
+		require.NoError(t, gormDB.Create(&models.ReviewerAssignment{
+			MRReviewID:   reviews[i].ID,
+			UserID:       reviewer.ID,
+			Role:         models.ReviewerRoleTeamMember,
+			CommentCount: 2,
+		}).Error)
+	}
+
+	log := zerolog.Nop()
+	service := NewService(reviewRepo, metricsRepo, &log)
+	require.NoError(t, service.AggregateDaily(context.Background(), date))
+
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	frontend, err := metricsRepo.GetByDate(startOfDay, "team-frontend", nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, frontend)
+	assert.Equal(t, 1, frontend.CompletedReviews)
+
+	// The frontend MR, its team's only completed review that day, is reopened
+	reviews[0].Status = models.MRStatusInReview
+	reviews[0].MergedAt = nil
+	require.NoError(t, gormDB.Save(&reviews[0]).Error)
+	require.NoError(t, service.AggregateDaily(context.Background(), date))
+
+	frontendRows, err := metricsRepo.GetMetricsByTeam("team-frontend", startOfDay, startOfDay)
+	require.NoError(t, err)
+	assert.Empty(t, frontendRows, "team and user rows of the reopened review should be gone")
+
+	backend, err := metricsRepo.GetByDate(startOfDay, "team-backend", nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, backend)
+	assert.Equal(t, 1, backend.CompletedReviews)
+
+	// Once no review of the day is completed, every aggregated row is gone
+	reviews[1].Status = models.MRStatusInReview
+	reviews[1].MergedAt = nil
+	require.NoError(t, gormDB.Save(&reviews[1]).Error)
+	require.NoError(t, service.AggregateDaily(context.Background(), date))
+
+	remaining, err := metricsRepo.GetByDateRange(startOfDay, startOfDay, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+}
+
 func TestAggregateDaily_ClosedButNotMerged(t *testing.T) {
 	gormDB, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -444,9 +569,546 @@ func TestAggregateDaily_ClosedButNotMerged(t *testing.T) {
 
 	// Verify metrics exist but completed count is 0 (query with start of day)
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	teamMetrics, err := metricsRepo.GetByDate(startOfDay, "team-frontend", nil)
+	teamMetrics, err := metricsRepo.GetByDate(startOfDay, "team-frontend", nil, nil)
 	require.NoError(t, err)
 	assert.NotNil(t, teamMetrics)
 	assert.Equal(t, 1, teamMetrics.TotalReviews)
 	assert.Equal(t, 0, teamMetrics.CompletedReviews) // Not merged, so not completed
 }
+
+func TestAggregateHourly_SpreadOutReviews(t *testing.T) {
+	gormDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	db := &repository.DB{DB: gormDB}
+	reviewRepo := repository.NewReviewRepository(db)
+	metricsRepo := repository.NewMetricsRepository(db)
+
+	// Create one merged review per hour of the day
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	for hour := 0; hour < 24; hour++ {
+		mergedAt := date.Add(time.Duration(hour)*time.Hour + 30*time.Minute)
+		triggeredAt := mergedAt.Add(-1 * time.Hour)
+		firstReviewAt := mergedAt.Add(-30 * time.Minute)
+
+		review := models.MRReview{
+			GitLabMRIID:         hour + 1,
+			GitLabProjectID:     100,
+			MRURL:               fmt.Sprintf("https://gitlab.example.com/project/mr/%d", hour+1),
+			MRTitle:             "Hourly MR",
+			Team:                "team-frontend",
+			RouletteTriggeredAt: &triggeredAt,
+			FirstReviewAt:       &firstReviewAt,
+			MergedAt:            &mergedAt,
+			Status:              models.MRStatusMerged,
+		}
+		require.NoError(t, reviewRepo.CreateMRReview(&review))
+	}
+
+	log := zerolog.Nop()
+	service := NewService(reviewRepo, metricsRepo, &log)
+
+	err := service.AggregateHourly(context.Background(), date)
+	require.NoError(t, err)
+
+	hourly, err := metricsRepo.GetByDateRange(date, date, map[string]interface{}{
+		"granularity": models.MetricsGranularityHourly,
+	})
+	require.NoError(t, err)
+	require.Len(t, hourly, 24)
+
+	seen := make(map[int]bool)
+	for _, m := range hourly {
+		require.NotNil(t, m.Hour)
+		assert.Equal(t, models.MetricsGranularityHourly, m.Granularity)
+		assert.Nil(t, m.UserID)
+		assert.Equal(t, 1, m.TotalReviews)
+		assert.Equal(t, 1, m.CompletedReviews)
+		require.NotNil(t, m.AvgTTFR)
+		assert.Equal(t, 30, *m.AvgTTFR)
+		seen[*m.Hour] = true
+	}
+	assert.Len(t, seen, 24)
+
+	// Daily queries must not pick up hourly rows
+	daily, err := metricsRepo.GetByDateRange(date, date, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Empty(t, daily)
+
+	// Running daily aggregation alongside keeps both granularities separate
+	require.NoError(t, service.AggregateDaily(context.Background(), date))
+
+	teamDaily, err := metricsRepo.GetByDate(date, "team-frontend", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, models.MetricsGranularityDaily, teamDaily.Granularity)
+	assert.Nil(t, teamDaily.Hour)
+	assert.Equal(t, 24, teamDaily.TotalReviews)
+
+	hourly, err = metricsRepo.GetByDateRange(date, date, map[string]interface{}{
+		"granularity": models.MetricsGranularityHourly,
+	})
+	require.NoError(t, err)
+	assert.Len(t, hourly, 24)
+}
+
+func TestAggregateHourly_DropsStaleBuckets(t *testing.T) {
+	gormDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	db := &repository.DB{DB: gormDB}
+	reviewRepo := repository.NewReviewRepository(db)
+	metricsRepo := repository.NewMetricsRepository(db)
+
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	reviews := make([]models.MRReview, 2)
+	for i, hour := range []int{9, 14} {
+		mergedAt := date.Add(time.Duration(hour) * time.Hour)
+		reviews[i] = models.MRReview{
+			GitLabMRIID:     i + 1,
+			GitLabProjectID: 100,
+			MRURL:           fmt.Sprintf("https://gitlab.example.com/project/mr/%d", i+1),
+			Team:            "team-frontend",
+			MergedAt:        &mergedAt,
+			Status:          models.MRStatusMerged,
+		}
+		require.NoError(t, reviewRepo.CreateMRReview(&reviews[i]))
+	}
+
+	log := zerolog.Nop()
+	service := NewService(reviewRepo, metricsRepo, &log)
+	require.NoError(t, service.AggregateHourly(context.Background(), date))
+
+	hourlyFilter := map[string]interface{}{"granularity": models.MetricsGranularityHourly}
+	hourly, err := metricsRepo.GetByDateRange(date, date, hourlyFilter)
+	require.NoError(t, err)
+	require.Len(t, hourly, 2)
+
+	// The 9:00 review is reopened: its bucket goes away on the next run
+	reviews[0].Status = models.MRStatusInReview
+	reviews[0].MergedAt = nil
+	require.NoError(t, gormDB.Save(&reviews[0]).Error)
+	require.NoError(t, service.AggregateHourly(context.Background(), date))
+
+	hourly, err = metricsRepo.GetByDateRange(date, date, hourlyFilter)
+	require.NoError(t, err)
+	require.Len(t, hourly, 1)
+	require.NotNil(t, hourly[0].Hour)
+	assert.Equal(t, 14, *hourly[0].Hour)
+
+	// Once no review is left, no bucket is
+	reviews[1].Status = models.MRStatusInReview
+	reviews[1].MergedAt = nil
+	require.NoError(t, gormDB.Save(&reviews[1]).Error)
+	require.NoError(t, service.AggregateHourly(context.Background(), date))
+
+	hourly, err = metricsRepo.GetByDateRange(date, date, hourlyFilter)
+	require.NoError(t, err)
+	assert.Empty(t, hourly)
+}
+
+func TestAggregateRange_OnlyDaysWithReviews(t *testing.T) {
+	gormDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	db := &repository.DB{DB: gormDB}
+	reviewRepo := repository.NewReviewRepository(db)
+	metricsRepo := repository.NewMetricsRepository(db)
+
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 2)
+
+	// Reviews on the first and last day, none on the middle day
+	for i, day := range []time.Time{start, end} {
+		mergedAt := day.Add(12 * time.Hour)
+		triggeredAt := mergedAt.Add(-2 * time.Hour)
+		firstReviewAt := mergedAt.Add(-1 * time.Hour)
+
+		review := models.MRReview{
+			GitLabMRIID:         i + 1,
+			GitLabProjectID:     100,
+			MRURL:               fmt.Sprintf("https://gitlab.example.com/project/mr/%d", i+1),
+			MRTitle:             "Range MR",
+			Team:                "team-frontend",
+			RouletteTriggeredAt: &triggeredAt,
+			FirstReviewAt:       &firstReviewAt,
+			MergedAt:            &mergedAt,
+			Status:              models.MRStatusMerged,
+		}
+		require.NoError(t, reviewRepo.CreateMRReview(&review))
+	}
+
+	log := zerolog.Nop()
+	service := NewService(reviewRepo, metricsRepo, &log)
+
+	err := service.AggregateRange(context.Background(), start, end)
+	require.NoError(t, err)
+
+	metrics, err := metricsRepo.GetByDateRange(start, end, map[string]interface{}{})
+	require.NoError(t, err)
+	require.Len(t, metrics, 2)
+
+	days := make(map[string]bool)
+	for _, m := range metrics {
+		days[m.Date.Format("2006-01-02")] = true
+		assert.Equal(t, 1, m.TotalReviews)
+	}
+	assert.True(t, days["2024-01-15"])
+	assert.False(t, days["2024-01-16"])
+	assert.True(t, days["2024-01-17"])
+}
+
+func TestAggregateRange_Hourly(t *testing.T) {
+	gormDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	db := &repository.DB{DB: gormDB}
+	reviewRepo := repository.NewReviewRepository(db)
+	metricsRepo := repository.NewMetricsRepository(db)
+
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+	for i, mergedAt := range []time.Time{start.Add(9 * time.Hour), start.Add(14 * time.Hour), end.Add(14 * time.Hour)} {
+		review := models.MRReview{
+			GitLabMRIID:     i + 1,
+			GitLabProjectID: 100,
+			MRURL:           fmt.Sprintf("https://gitlab.example.com/project/mr/%d", i+1),
+			Team:            "team-frontend",
+			MergedAt:        &mergedAt,
+			Status:          models.MRStatusMerged,
+		}
+		require.NoError(t, reviewRepo.CreateMRReview(&review))
+	}
+
+	log := zerolog.Nop()
+	service := NewService(reviewRepo, metricsRepo, &log)
+	hourlyFilter := map[string]interface{}{"granularity": models.MetricsGranularityHourly}
+
+	// Hourly rows are only built when enabled
+	require.NoError(t, service.AggregateRange(context.Background(), start, end))
+	hourly, err := metricsRepo.GetByDateRange(start, end, hourlyFilter)
+	require.NoError(t, err)
+	assert.Empty(t, hourly)
+
+	service.SetHourly(true)
+	require.NoError(t, service.AggregateRange(context.Background(), start, end))
+	hourly, err = metricsRepo.GetByDateRange(start, end, hourlyFilter)
+	require.NoError(t, err)
+	assert.Len(t, hourly, 3)
+
+	daily, err := metricsRepo.GetByDateRange(start, end, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Len(t, daily, 2)
+}
+
+func TestAggregateRange_InvalidRange(t *testing.T) {
+	gormDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	db := &repository.DB{DB: gormDB}
+	log := zerolog.Nop()
+	service := NewService(repository.NewReviewRepository(db), repository.NewMetricsRepository(db), &log)
+
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	err := service.AggregateRange(context.Background(), start, start.AddDate(0, 0, -1))
+	assert.Error(t, err)
+}
+
+func TestAggregateRange_Cancelled(t *testing.T) {
+	gormDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	db := &repository.DB{DB: gormDB}
+	reviewRepo := repository.NewReviewRepository(db)
+	metricsRepo := repository.NewMetricsRepository(db)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 29)
+
+	// One review per day across the whole range
+	for i := 0; i < 30; i++ {
+		mergedAt := start.AddDate(0, 0, i).Add(12 * time.Hour)
+		triggeredAt := mergedAt.Add(-2 * time.Hour)
+		review := models.MRReview{
+			GitLabMRIID:         i + 1,
+			GitLabProjectID:     100,
+			MRURL:               fmt.Sprintf("https://gitlab.example.com/project/mr/%d", i+1),
+			MRTitle:             "Range MR",
+			Team:                "team-frontend",
+			RouletteTriggeredAt: &triggeredAt,
+			MergedAt:            &mergedAt,
+			Status:              models.MRStatusMerged,
+		}
+		require.NoError(t, reviewRepo.CreateMRReview(&review))
+	}
+
+	log := zerolog.Nop()
+	service := NewService(reviewRepo, metricsRepo, &log)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := service.AggregateRange(ctx, start, end)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	metrics, err := metricsRepo.GetByDateRange(start, end, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Empty(t, metrics, "no day should be aggregated after cancellation")
+}
+
+func TestAggregateDaily_ExcludedUsers(t *testing.T) {
+	gormDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	db := &repository.DB{DB: gormDB}
+	reviewRepo := repository.NewReviewRepository(db)
+	metricsRepo := repository.NewMetricsRepository(db)
+
+	alice := models.User{GitLabID: 1, Username: "alice", Role: "dev", Team: "team-frontend"}
+	require.NoError(t, gormDB.Create(&alice).Error)
+	bot := models.User{GitLabID: 99, Username: "renovate-bot", Role: "dev", Team: "team-frontend"}
+	require.NoError(t, gormDB.Create(&bot).Error)
+
+	date := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	triggeredAt := date.Add(-2 * time.Hour)
+	mergedAt := date
+
+	newReview := func(iid int, author *models.User) models.MRReview {
+		review := models.MRReview{
+			GitLabMRIID:         iid,
+			GitLabProjectID:     100,
+			MRURL:               fmt.Sprintf("https://gitlab.example.com/project/mr/%d", iid),
+			Team:                "team-frontend",
+			RouletteTriggeredAt: &triggeredAt,
+			MergedAt:            &mergedAt,
+			Status:              models.MRStatusMerged,
+		}
+		if author != nil {
+			review.MRAuthorID = &author.ID
+		}
+		require.NoError(t, reviewRepo.CreateMRReview(&review))
+		return review
+	}
+	assign := func(review models.MRReview, user models.User, comments int) {
+		assignment := models.ReviewerAssignment{
+			MRReviewID:    review.ID,
+			UserID:        user.ID,
+			Role:          models.ReviewerRoleTeamMember,
+			CommentCount:  comments,
+			CommentLength: comments * 100,
+		}
+		require.NoError(t, gormDB.Create(&assignment).Error)
+	}
+
+	// Reviewed by alice and the bot: only alice's comments count
+	mixed := newReview(1, nil)
+	assign(mixed, alice, 2)
+	assign(mixed, bot, 10)
+
+	// Reviewed only by the bot: ignored
+	botOnly := newReview(2, nil)
+	assign(botOnly, bot, 7)
+
+	// Authored by the bot: ignored
+	botAuthored := newReview(3, &bot)
+	assign(botAuthored, alice, 4)
+
+	log := zerolog.Nop()
+	service := NewService(reviewRepo, metricsRepo, &log)
+	service.SetExcludedUsers(&config.ExcludedUsersConfig{Usernames: []string{"Renovate-Bot"}})
+
+	require.NoError(t, service.AggregateDaily(context.Background(), date))
+
+	startOfDay := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	teamMetrics, err := metricsRepo.GetByDate(startOfDay, "team-frontend", nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, teamMetrics)
+	assert.Equal(t, 1, teamMetrics.TotalReviews)
+	assert.Equal(t, 1, teamMetrics.CompletedReviews)
+	require.NotNil(t, teamMetrics.AvgCommentCount)
+	assert.Equal(t, 2.0, *teamMetrics.AvgCommentCount)
+
+	botMetrics, err := metricsRepo.GetMetricsByUser(bot.ID, startOfDay, startOfDay)
+	require.NoError(t, err)
+	assert.Empty(t, botMetrics)
+
+	aliceMetrics, err := metricsRepo.GetMetricsByUser(alice.ID, startOfDay, startOfDay)
+	require.NoError(t, err)
+	require.Len(t, aliceMetrics, 1)
+	assert.Equal(t, 1, aliceMetrics[0].TotalReviews)
+}
+
+func TestAggregateDaily_EngagementFloor(t *testing.T) {
+	tests := []struct {
+		name          string
+		minComments   int
+		wantCompleted int
+		wantEngaged   bool
+	}{
+		{name: "floor disabled counts zero-comment approvals", minComments: 0, wantCompleted: 1, wantEngaged: true},
+		{name: "floor enabled ignores zero-comment approvals", minComments: 1, wantCompleted: 0, wantEngaged: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gormDB, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			db := &repository.DB{DB: gormDB}
+			reviewRepo := repository.NewReviewRepository(db)
+			metricsRepo := repository.NewMetricsRepository(db)
+
+			alice := models.User{GitLabID: 1, Username: "alice", Role: "dev", Team: "team-frontend"}
+			require.NoError(t, gormDB.Create(&alice).Error)
+
+			date := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+			triggeredAt := date.Add(-2 * time.Hour)
+			approvedAt := date.Add(-1 * time.Hour)
+			mergedAt := date
+			review := models.MRReview{
+				GitLabMRIID:         1,
+				GitLabProjectID:     100,
+				MRURL:               "https://gitlab.example.com/project/mr/1",
+				Team:                "team-frontend",
+				RouletteTriggeredAt: &triggeredAt,
+				ApprovedAt:          &approvedAt,
+				MergedAt:            &mergedAt,
+				Status:              models.MRStatusMerged,
+			}
+			require.NoError(t, reviewRepo.CreateMRReview(&review))
+
+			// Approved without leaving a single comment
+			assignment := models.ReviewerAssignment{
+				MRReviewID: review.ID,
+				UserID:     alice.ID,
+				Role:       models.ReviewerRoleTeamMember,
+				AssignedAt: triggeredAt,
+				ApprovedAt: &approvedAt,
+			}
+			require.NoError(t, gormDB.Create(&assignment).Error)
+
+			log := zerolog.Nop()
+			service := NewService(reviewRepo, metricsRepo, &log)
+			service.SetMinReviewComments(tt.minComments)
+
+			require.NoError(t, service.AggregateDaily(context.Background(), date))
+
+			startOfDay := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+			aliceMetrics, err := metricsRepo.GetMetricsByUser(alice.ID, startOfDay, startOfDay)
+			require.NoError(t, err)
+			require.Len(t, aliceMetrics, 1)
+			assert.Equal(t, 1, aliceMetrics[0].TotalReviews)
+			assert.Equal(t, tt.wantCompleted, aliceMetrics[0].CompletedReviews)
+			assert.Equal(t, tt.wantEngaged, aliceMetrics[0].EngagementScore != nil)
+
+			teamMetrics, err := metricsRepo.GetByDate(startOfDay, "team-frontend", nil, nil)
+			require.NoError(t, err)
+			require.NotNil(t, teamMetrics)
+			assert.Equal(t, tt.wantCompleted, teamMetrics.CompletedReviews)
+		})
+	}
+}
+
+func TestAggregateDaily_SameUnitsAsRealTimeRecording(t *testing.T) {
+	date := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	triggeredAt := date.Add(-2 * time.Hour)
+	firstReviewAt := date.Add(-1 * time.Hour) // TTFR: 60 minutes
+	approvedAt := date.Add(-30 * time.Minute) // Time to approval: 90 minutes
+	newReview := func() *models.MRReview {
+		return &models.MRReview{
+			GitLabMRIID:         1,
+			GitLabProjectID:     100,
+			MRURL:               "https://gitlab.example.com/project/mr/1",
+			Team:                "team-frontend",
+			RouletteTriggeredAt: &triggeredAt,
+			FirstReviewAt:       &firstReviewAt,
+			ApprovedAt:          &approvedAt,
+			MergedAt:            &date,
+			Status:              models.MRStatusMerged,
+		}
+	}
+	startOfDay := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	// Real-time path: webhook events recorded by the metrics service
+	realTimeDB, cleanupRealTime := setupTestDB(t)
+	defer cleanupRealTime()
+	realTimeRepo := repository.NewMetricsRepository(&repository.DB{DB: realTimeDB})
+	recorder := metrics.NewService(realTimeRepo)
+	require.NoError(t, recorder.RecordReviewStarted(context.Background(), newReview(), nil))
+	require.NoError(t, recorder.RecordReviewCompleted(context.Background(), newReview(), nil))
+
+	realTime, err := realTimeRepo.GetByDate(startOfDay, "team-frontend", nil, nil)
+	require.NoError(t, err)
+
+	// Batch path: the daily aggregation of the same review
+	batchDB, cleanupBatch := setupTestDB(t)
+	defer cleanupBatch()
+	db := &repository.DB{DB: batchDB}
+	reviewRepo := repository.NewReviewRepository(db)
+	batchRepo := repository.NewMetricsRepository(db)
+	require.NoError(t, reviewRepo.CreateMRReview(newReview()))
+
+	log := zerolog.Nop()
+	require.NoError(t, NewService(reviewRepo, batchRepo, &log).AggregateDaily(context.Background(), date))
+
+	batch, err := batchRepo.GetByDate(startOfDay, "team-frontend", nil, nil)
+	require.NoError(t, err)
+
+	// Both paths store minutes
+	require.NotNil(t, realTime.AvgTTFR)
+	require.NotNil(t, batch.AvgTTFR)
+	assert.Equal(t, 60, *realTime.AvgTTFR)
+	assert.Equal(t, *batch.AvgTTFR, *realTime.AvgTTFR)
+
+	require.NotNil(t, realTime.AvgTimeToApproval)
+	require.NotNil(t, batch.AvgTimeToApproval)
+	assert.Equal(t, 90, *realTime.AvgTimeToApproval)
+	assert.Equal(t, *batch.AvgTimeToApproval, *realTime.AvgTimeToApproval)
+}
+
+func TestAggregateDaily_FirstReviewFromEarliestReviewer(t *testing.T) {
+	gormDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	db := &repository.DB{DB: gormDB}
+	reviewRepo := repository.NewReviewRepository(db)
+	metricsRepo := repository.NewMetricsRepository(db)
+
+	alice := models.User{GitLabID: 1, Username: "alice", Team: "team-frontend"}
+	require.NoError(t, gormDB.Create(&alice).Error)
+	bob := models.User{GitLabID: 2, Username: "bob", Team: "team-frontend"}
+	require.NoError(t, gormDB.Create(&bob).Error)
+
+	date := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	triggeredAt := date.Add(-4 * time.Hour)
+	staleFirstReviewAt := date.Add(-1 * time.Hour) // Set by the later reviewer's comment
+	aliceCommentAt := date.Add(-1 * time.Hour)
+	bobCommentAt := date.Add(-3 * time.Hour) // TTFR: 60 minutes
+
+	review := models.MRReview{
+		GitLabMRIID:         1,
+		GitLabProjectID:     100,
+		MRURL:               "https://gitlab.example.com/project/mr/1",
+		Team:                "team-frontend",
+		RouletteTriggeredAt: &triggeredAt,
+		FirstReviewAt:       &staleFirstReviewAt,
+		MergedAt:            &date,
+		Status:              models.MRStatusMerged,
+	}
+	require.NoError(t, reviewRepo.CreateMRReview(&review))
+
+	for _, assignment := range []models.ReviewerAssignment{
+		{MRReviewID: review.ID, UserID: alice.ID, Role: models.ReviewerRoleCodeowner, AssignedAt: triggeredAt, FirstCommentAt: &aliceCommentAt, CommentCount: 2},
+		{MRReviewID: review.ID, UserID: bob.ID, Role: models.ReviewerRoleTeamMember, AssignedAt: triggeredAt, FirstCommentAt: &bobCommentAt, CommentCount: 1},
+	} {
+		require.NoError(t, gormDB.Create(&assignment).Error)
+	}
+
+	log := zerolog.Nop()
+	require.NoError(t, NewService(reviewRepo, metricsRepo, &log).AggregateDaily(context.Background(), date))
+
+	startOfDay := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	teamMetrics, err := metricsRepo.GetByDate(startOfDay, "team-frontend", nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, teamMetrics.AvgTTFR)
+	assert.Equal(t, 60, *teamMetrics.AvgTTFR) // Bob's earlier comment, not the MR field
+}
@@ -0,0 +1,98 @@
+package badges
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+)
+
+// Badge rarities, from the share of users holding the badge.
+const (
+	RarityCommon    = "common"
+	RarityRare      = "rare"
+	RarityLegendary = "legendary"
+)
+
+// Holder shares at or below which a badge is rare or legendary.
+const (
+	rareHolderShare      = 0.25
+	legendaryHolderShare = 0.05
+)
+
+// CatalogEntry is a badge of the catalog with how many users hold it.
+// Rarity is derived from the holder count and not persisted.
+type CatalogEntry struct {
+	models.Badge
+	HolderCount int64  `json:"holder_count"`
+	Rarity      string `json:"rarity"`
+}
+
+// rarity buckets a badge by the share of users holding it. Badges nobody holds
+// are legendary.
+func rarity(holders, totalUsers int64) string {
+	if holders == 0 || totalUsers == 0 {
+		return RarityLegendary
+	}
+	share := float64(holders) / float64(totalUsers)
+	switch {
+	case share <= legendaryHolderShare:
+		return RarityLegendary
+	case share <= rareHolderShare:
+		return RarityRare
+	default:
+		return RarityCommon
+	}
+}
+
+// GetBadgeCatalog retrieves all available badges with their holder count and rarity.
+//
+//nolint:revive // ctx reserved for future context-aware operations (tracing, cancellation)
+func (s *Service) GetBadgeCatalog(ctx context.Context) ([]CatalogEntry, error) {
+	badges, err := s.badgeRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return s.catalogEntries(badges, func(models.Badge, int64) bool { return true })
+}
+
+// GetPublicBadgeCatalog retrieves the badges listed publicly: hidden badges and badges
+// with fewer holders than their MinHolders are left out.
+//
+//nolint:revive // ctx reserved for future context-aware operations (tracing, cancellation)
+func (s *Service) GetPublicBadgeCatalog(ctx context.Context) ([]CatalogEntry, error) {
+	badges, err := s.badgeRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return s.catalogEntries(badges, func(badge models.Badge, holders int64) bool {
+		return !badge.Hidden && holders >= int64(badge.MinHolders)
+	})
+}
+
+// catalogEntries attaches holder counts and rarities to the badges kept by listed.
+// Holder counts of all badges and the user count are loaded once, whatever the catalog size.
+func (s *Service) catalogEntries(badges []models.Badge, listed func(models.Badge, int64) bool) ([]CatalogEntry, error) {
+	holderCounts, err := s.badgeRepo.GetHolderCounts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get badge holder counts: %w", err)
+	}
+	totalUsers, err := s.userRepo.Count()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	entries := make([]CatalogEntry, 0, len(badges))
+	for _, badge := range badges {
+		holders := holderCounts[badge.ID]
+		if !listed(badge, holders) {
+			continue
+		}
+		entries = append(entries, CatalogEntry{
+			Badge:       badge,
+			HolderCount: holders,
+			Rarity:      rarity(holders, totalUsers),
+		})
+	}
+	return entries, nil
+}
@@ -7,12 +7,22 @@ import (
 	"time"
 
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/metrics"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/period"
 )
 
 // checkCriteria evaluates badge criteria against user metrics.
 func (s *Service) checkCriteria(ctx context.Context, criteria *models.BadgeCriteria, userID uint) (bool, error) {
 	// Calculate date range based on period
-	startDate, endDate := s.calculatePeriodRange(criteria.Period)
+	startDate, endDate, err := s.criteriaPeriodRange(criteria)
+	if err != nil {
+		return false, err
+	}
+
+	// Streak criteria work on daily activity rather than aggregated values
+	if criteria.Type == models.BadgeCriteriaTypeStreak {
+		return s.evaluateStreak(ctx, userID, criteria.Days, startDate, endDate)
+	}
 
 	// Get user metrics for the period
 	userMetrics, err := s.aggregateUserMetrics(userID, startDate, endDate)
@@ -34,7 +44,7 @@ func (s *Service) checkCriteria(ctx context.Context, criteria *models.BadgeCrite
 		if !ok {
 			return false, fmt.Errorf("invalid value type for 'top' operator: %T", criteria.Value)
 		}
-		return s.evaluateTopRanking(ctx, criteria.Metric, int(topN), criteria.Period, userID)
+		return s.evaluateTopRanking(ctx, criteria.Metric, int(topN), startDate, endDate, userID)
 	}
 
 	// Convert threshold value to float64 for comparison
@@ -47,6 +57,44 @@ func (s *Service) checkCriteria(ctx context.Context, criteria *models.BadgeCrite
 	return s.evaluateMetricCriteria(criteria.Operator, threshold, metricValue)
 }
 
+// SimulationInput holds hypothetical values to evaluate badge criteria against.
+type SimulationInput struct {
+	Metrics    map[string]float64 `json:"metrics"`
+	Rank       int                `json:"rank,omitempty"`        // 1-based rank for "top" criteria
+	StreakDays int                `json:"streak_days,omitempty"` // Longest active streak for "streak" criteria
+}
+
+// SimulateCriteria reports whether badge criteria would pass for hypothetical values,
+// without reading any stored metrics.
+func (s *Service) SimulateCriteria(criteria *models.BadgeCriteria, input SimulationInput) (bool, error) {
+	if criteria.Type == models.BadgeCriteriaTypeStreak {
+		if criteria.Days <= 0 {
+			return false, fmt.Errorf("invalid days for streak criteria: %d", criteria.Days)
+		}
+		return input.StreakDays >= criteria.Days, nil
+	}
+
+	if criteria.Operator == "top" {
+		topN, ok := criteria.Value.(float64) // JSON numbers are float64
+		if !ok {
+			return false, fmt.Errorf("invalid value type for 'top' operator: %T", criteria.Value)
+		}
+		return input.Rank > 0 && input.Rank <= int(topN), nil
+	}
+
+	threshold, ok := criteria.Value.(float64)
+	if !ok {
+		return false, fmt.Errorf("invalid value type: expected float64, got %T", criteria.Value)
+	}
+
+	metricValue, exists := input.Metrics[criteria.Metric]
+	if !exists {
+		return false, nil
+	}
+
+	return s.evaluateMetricCriteria(criteria.Operator, threshold, metricValue)
+}
+
 // evaluateMetricCriteria compares a metric value against criteria using the specified operator.
 func (s *Service) evaluateMetricCriteria(operator string, threshold, actualValue float64) (bool, error) {
 	switch operator {
@@ -68,33 +116,76 @@ func (s *Service) evaluateMetricCriteria(operator string, threshold, actualValue
 // evaluateTopRanking checks if a user is in the top N for a metric.
 //
 //nolint:revive,unparam // ctx reserved for future context-aware operations
-func (s *Service) evaluateTopRanking(ctx context.Context, metric string, topN int, period string, userID uint) (bool, error) {
-	// Calculate date range
-	startDate, endDate := s.calculatePeriodRange(period)
+func (s *Service) evaluateTopRanking(ctx context.Context, metric string, topN int, startDate, endDate time.Time, userID uint) (bool, error) {
+	rank, err := s.userRank(metric, startDate, endDate, userID)
+	if err != nil {
+		return false, err
+	}
 
+	// Check if userID is in top N
+	return rank > 0 && rank <= topN, nil
+}
+
+// userRank returns the 1-based rank of a user for a metric between startDate and endDate,
+// or 0 when the user has no metrics in the period.
+func (s *Service) userRank(metric string, startDate, endDate time.Time, userID uint) (int, error) {
 	// Get all metrics for the period
 	allMetrics, err := s.metricsRepo.GetByDateRange(startDate, endDate, nil)
 	if err != nil {
-		return false, fmt.Errorf("failed to get metrics: %w", err)
+		return 0, fmt.Errorf("failed to get metrics: %w", err)
 	}
 
 	// Aggregate metrics by user
 	userAggregates, err := s.aggregateMetricsByUser(allMetrics, metric)
 	if err != nil {
-		return false, err
+		return 0, err
 	}
 
 	// Create and sort rankings
 	rankings := s.sortUserRankings(userAggregates)
 
-	// Check if userID is in top N
-	for i := 0; i < topN && i < len(rankings); i++ {
-		if rankings[i].userID == userID {
-			return true, nil
+	for i, ranking := range rankings {
+		if ranking.userID == userID {
+			return i + 1, nil
 		}
 	}
 
-	return false, nil
+	return 0, nil
+}
+
+// evaluateStreak checks if a user was active (at least one completed review)
+// on at least the given number of consecutive calendar days between startDate and endDate.
+//
+//nolint:revive // ctx reserved for future context-aware operations
+func (s *Service) evaluateStreak(ctx context.Context, userID uint, days int, startDate, endDate time.Time) (bool, error) {
+	if days <= 0 {
+		return false, fmt.Errorf("invalid days for streak criteria: %d", days)
+	}
+
+	streak, err := s.userStreak(userID, startDate, endDate)
+	if err != nil {
+		return false, err
+	}
+
+	return streak >= days, nil
+}
+
+// userStreak returns the longest run of consecutive days a user completed at least
+// one review between startDate and endDate.
+func (s *Service) userStreak(userID uint, startDate, endDate time.Time) (int, error) {
+	userMetrics, err := s.metricsRepo.GetMetricsByUser(userID, startDate, endDate)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get user metrics: %w", err)
+	}
+
+	activeDays := make([]time.Time, 0, len(userMetrics))
+	for _, m := range userMetrics {
+		if m.CompletedReviews > 0 {
+			activeDays = append(activeDays, m.Date)
+		}
+	}
+
+	return period.LongestStreak(activeDays), nil
 }
 
 // getMetricValue extracts the value for a specific metric from a review metric.
@@ -124,6 +215,10 @@ func getMetricValue(m *models.ReviewMetrics, metric string) (float64, error) {
 
 // aggregateMetricsByUser aggregates metric values by user ID.
 func (s *Service) aggregateMetricsByUser(allMetrics []models.ReviewMetrics, metric string) (map[uint]float64, error) {
+	if metric == "external_reviews" {
+		return s.externalReviewsByUser(allMetrics), nil
+	}
+
 	userAggregates := make(map[uint]float64)
 
 	for _, m := range allMetrics {
@@ -142,6 +237,37 @@ func (s *Service) aggregateMetricsByUser(allMetrics []models.ReviewMetrics, metr
 	return userAggregates, nil
 }
 
+// externalReviewsByUser counts completed reviews on other teams' MRs by user ID.
+// Users whose team cannot be looked up are left out.
+func (s *Service) externalReviewsByUser(allMetrics []models.ReviewMetrics) map[uint]float64 {
+	userAggregates := make(map[uint]float64)
+	teams := make(map[uint]string)
+
+	for i := range allMetrics {
+		m := &allMetrics[i]
+		if m.UserID == nil {
+			continue
+		}
+
+		team, seen := teams[*m.UserID]
+		if !seen {
+			user, err := s.userRepo.GetByID(*m.UserID)
+			if err != nil {
+				s.log.Warn().Err(err).Uint("user_id", *m.UserID).Msg("Failed to get user team, skipping external reviews")
+			} else {
+				team = user.Team
+			}
+			teams[*m.UserID] = team
+		}
+
+		if isExternalReview(m, team) {
+			userAggregates[*m.UserID] += float64(m.CompletedReviews)
+		}
+	}
+
+	return userAggregates
+}
+
 // sortUserRankings creates a sorted list of user rankings.
 func (s *Service) sortUserRankings(userAggregates map[uint]float64) []userRank {
 	rankings := make([]userRank, 0, len(userAggregates))
@@ -163,34 +289,43 @@ type userRank struct {
 	value  float64
 }
 
-// calculatePeriodRange calculates the start and end dates for a period.
-func (s *Service) calculatePeriodRange(period string) (startDate, endDate time.Time) {
-	now := time.Now()
-	endDate = now
-
-	switch period {
-	case "day":
-		startDate = now.Add(-24 * time.Hour)
-	case "week":
-		startDate = now.Add(-7 * 24 * time.Hour)
-	case "month":
-		startDate = now.Add(-30 * 24 * time.Hour)
-	case "year":
-		startDate = now.Add(-365 * 24 * time.Hour)
-	case "all_time", "":
-		// All time: use a very old date
-		startDate = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
-	default:
-		// Default to all time
-		startDate = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+// Criteria periods counted in units, e.g. {"period": "days", "n": 90} for the last 90 days.
+// Months are 30 days, like the named "month" period.
+var periodUnitDays = map[string]int{
+	"days":   1,
+	"weeks":  7,
+	"months": 30,
+}
+
+// criteriaPeriodRange resolves the period of badge criteria to a date range: the last N
+// units for a unit period, otherwise the named period.
+func (s *Service) criteriaPeriodRange(criteria *models.BadgeCriteria) (startDate, endDate time.Time, err error) {
+	unitDays, isUnit := periodUnitDays[criteria.Period]
+	if !isUnit {
+		startDate, endDate = s.calculatePeriodRange(criteria.Period)
+		return startDate, endDate, nil
+	}
+	if criteria.N <= 0 {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid n for %q period: %d", criteria.Period, criteria.N)
 	}
 
-	return startDate, endDate
+	endDate = time.Now()
+	startDate = endDate.Add(-time.Duration(criteria.N*unitDays) * 24 * time.Hour)
+	return startDate, endDate, nil
+}
+
+// calculatePeriodRange calculates the start and end dates for a named period ending now,
+// snapped to calendar boundaries when calendar periods are enabled.
+func (s *Service) calculatePeriodRange(name string) (startDate, endDate time.Time) {
+	if s.calendarPeriods {
+		return period.Calendar(name, time.Now())
+	}
+	return period.Range(name)
 }
 
 // aggregateUserMetrics calculates aggregated metrics for a user in a time period.
 func (s *Service) aggregateUserMetrics(userID uint, startDate, endDate time.Time) (map[string]float64, error) {
-	metrics := make(map[string]float64)
+	values := make(map[string]float64)
 
 	// Get metrics from database
 	userMetrics, err := s.metricsRepo.GetMetricsByUser(userID, startDate, endDate)
@@ -200,51 +335,66 @@ func (s *Service) aggregateUserMetrics(userID uint, startDate, endDate time.Time
 
 	if len(userMetrics) == 0 {
 		// No metrics for this user in the period
-		return metrics, nil
+		return values, nil
 	}
 
-	// Aggregate metrics across the period
+	// Aggregate metrics across the period, weighting each row's averages by the samples
+	// behind them like the leaderboard does; rows without a value are skipped
 	var (
-		totalTTFR             float64
-		totalCommentCount     float64
-		totalCommentLength    float64
-		totalEngagementScore  float64
-		totalCompletedReviews int
-		metricsCount          int
+		ttfr, commentCount, commentLength, engagement metrics.SampleMean
+		totalCompletedReviews                         int
 	)
 
 	for _, m := range userMetrics {
 		if m.AvgTTFR != nil {
-			totalTTFR += float64(*m.AvgTTFR)
+			ttfr.Add(float64(*m.AvgTTFR), m.TTFRSamples)
 		}
 		if m.AvgCommentCount != nil {
-			totalCommentCount += *m.AvgCommentCount
+			commentCount.Add(*m.AvgCommentCount, m.CommentSamples)
 		}
 		if m.AvgCommentLength != nil {
-			totalCommentLength += *m.AvgCommentLength
+			commentLength.Add(*m.AvgCommentLength, m.CommentSamples)
 		}
 		if m.EngagementScore != nil {
-			totalEngagementScore += *m.EngagementScore
+			engagement.Add(*m.EngagementScore, m.EngagementSamples)
 		}
 		totalCompletedReviews += m.CompletedReviews
-		metricsCount++
 	}
 
-	// Calculate averages
-	if metricsCount > 0 {
-		metrics["avg_ttfr"] = totalTTFR / float64(metricsCount)
-		metrics["avg_comment_count"] = totalCommentCount / float64(metricsCount)
-		metrics["avg_comment_length"] = totalCommentLength / float64(metricsCount)
-		metrics["engagement_score"] = totalEngagementScore / float64(metricsCount)
+	// Averages are only reported when backed by data, so "<" criteria can't pass on no reviews
+	for name, mean := range map[string]metrics.SampleMean{
+		"avg_ttfr":           ttfr,
+		"avg_comment_count":  commentCount,
+		"avg_comment_length": commentLength,
+		"engagement_score":   engagement,
+	} {
+		if mean.Samples > 0 {
+			values[name] = mean.Mean()
+		}
 	}
 
 	// Totals
-	metrics["completed_reviews"] = float64(totalCompletedReviews)
+	values["completed_reviews"] = float64(totalCompletedReviews)
+
+	// External reviews are completed reviews on MRs of another team than the user's own
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		s.log.Warn().Err(err).Uint("user_id", userID).Msg("Failed to get user team, skipping external reviews")
+		return values, nil
+	}
+	externalReviews := 0
+	for i := range userMetrics {
+		if isExternalReview(&userMetrics[i], user.Team) {
+			externalReviews += userMetrics[i].CompletedReviews
+		}
+	}
+	values["external_reviews"] = float64(externalReviews)
 
-	// Calculate external reviews (reviews for other teams)
-	// This would require additional data from review_metrics table
-	// For now, use completed_reviews as a placeholder
-	metrics["external_reviews"] = float64(totalCompletedReviews) // TODO: Implement actual external reviews logic
+	return values, nil
+}
 
-	return metrics, nil
+// isExternalReview reports whether a metrics row covers reviews for another team than
+// userTeam. Users without a team have no external reviews.
+func isExternalReview(m *models.ReviewMetrics, userTeam string) bool {
+	return userTeam != "" && m.Team != "" && m.Team != userTeam
 }
@@ -0,0 +1,174 @@
+package badges
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+)
+
+// BadgeProgress reports how close a user is to earning a badge.
+// For "top" criteria, Current is the user's rank (0 when unranked) and Applicable is false,
+// since a rank cannot be expressed as a percentage of the way there.
+type BadgeProgress struct {
+	Badge      models.Badge `json:"badge"`
+	Current    float64      `json:"current"`
+	Target     float64      `json:"target"`
+	Operator   string       `json:"operator"`
+	Percent    float64      `json:"percent"`
+	Applicable bool         `json:"applicable"`
+}
+
+// GetBadgeProgress returns the progress of a user towards every visible badge they have not earned yet,
+// closest first. Hidden badges are left out, and badges whose criteria cannot be evaluated are logged and skipped.
+func (s *Service) GetBadgeProgress(ctx context.Context, userID uint) ([]BadgeProgress, error) {
+	badges, err := s.badgeRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get badges: %w", err)
+	}
+
+	progress := make([]BadgeProgress, 0, len(badges))
+	for _, badge := range badges {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if badge.Hidden {
+			continue
+		}
+
+		hasEarned, err := s.badgeRepo.HasUserEarnedBadge(userID, badge.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check badge %s: %w", badge.Name, err)
+		}
+		if hasEarned {
+			continue
+		}
+
+		item, err := s.badgeProgress(&badge, userID)
+		if err != nil {
+			s.log.Warn().
+				Err(err).
+				Uint("user_id", userID).
+				Str("badge", badge.Name).
+				Msg("Failed to compute badge progress")
+			continue
+		}
+		progress = append(progress, *item)
+	}
+
+	sort.SliceStable(progress, func(i, j int) bool {
+		if progress[i].Applicable != progress[j].Applicable {
+			return progress[i].Applicable
+		}
+		return progress[i].Percent > progress[j].Percent
+	})
+
+	return progress, nil
+}
+
+// badgeProgress computes a user's progress towards a single badge.
+func (s *Service) badgeProgress(badge *models.Badge, userID uint) (*BadgeProgress, error) {
+	var criteria models.BadgeCriteria
+	if err := json.Unmarshal(badge.Criteria, &criteria); err != nil {
+		return nil, fmt.Errorf("failed to parse badge criteria: %w", err)
+	}
+
+	startDate, endDate, err := s.criteriaPeriodRange(&criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &BadgeProgress{Badge: *badge, Operator: criteria.Operator, Applicable: true}
+
+	// Streaks progress towards the required number of consecutive days
+	if criteria.Type == models.BadgeCriteriaTypeStreak {
+		if criteria.Days <= 0 {
+			return nil, fmt.Errorf("invalid days for streak criteria: %d", criteria.Days)
+		}
+		streak, err := s.userStreak(userID, startDate, endDate)
+		if err != nil {
+			return nil, err
+		}
+		progress.Operator = models.BadgeCriteriaTypeStreak
+		progress.Current = float64(streak)
+		progress.Target = float64(criteria.Days)
+		progress.Percent = progressPercent(">=", progress.Current, progress.Target)
+		return progress, nil
+	}
+
+	target, ok := criteria.Value.(float64) // JSON numbers are float64
+	if !ok {
+		return nil, fmt.Errorf("invalid value type: expected float64, got %T", criteria.Value)
+	}
+	progress.Target = target
+
+	if criteria.Operator == "top" {
+		rank, err := s.userRank(criteria.Metric, startDate, endDate, userID)
+		if err != nil {
+			return nil, err
+		}
+		progress.Current = float64(rank)
+		progress.Applicable = false
+		return progress, nil
+	}
+
+	userMetrics, err := s.aggregateUserMetrics(userID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate user metrics: %w", err)
+	}
+
+	// Without data in the period there is no progress, whatever the operator
+	current, exists := userMetrics[criteria.Metric]
+	if !exists {
+		return progress, nil
+	}
+	if _, err := s.evaluateMetricCriteria(criteria.Operator, target, current); err != nil {
+		return nil, err
+	}
+
+	progress.Current = current
+	progress.Percent = progressPercent(criteria.Operator, current, target)
+	return progress, nil
+}
+
+// progressPercent returns how far current is towards target, from 0 to 100, rounded to one decimal.
+// For ">" and ">=" it is current/target; for "<" and "<=", where lower is better, see lowerIsBetterRatio;
+// for "==" the smaller of the two values over the larger.
+func progressPercent(operator string, current, target float64) float64 {
+	var ratio float64
+	switch operator {
+	case ">", ">=":
+		ratio = safeRatio(current, target)
+	case "<", "<=":
+		ratio = lowerIsBetterRatio(operator, current, target)
+	case "==":
+		ratio = safeRatio(math.Min(current, target), math.Max(current, target))
+	}
+
+	return math.Round(math.Min(math.Max(ratio, 0), 1)*1000) / 10
+}
+
+// lowerIsBetterRatio returns the progress towards a "<" or "<=" target. A current value of zero or less
+// is an average without samples (e.g. no first response yet), so it counts as no progress rather than
+// as already reached. Otherwise a value meeting the comparison is complete, and one above target is
+// target/current.
+func lowerIsBetterRatio(operator string, current, target float64) float64 {
+	if current <= 0 {
+		return 0
+	}
+	if current < target || (operator == "<=" && current == target) {
+		return 1
+	}
+	return target / current
+}
+
+// safeRatio divides a by b, treating a zero or negative b as already reached.
+func safeRatio(a, b float64) float64 {
+	if b <= 0 {
+		return 1
+	}
+	return a / b
+}
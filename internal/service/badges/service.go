@@ -4,9 +4,13 @@ package badges
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"runtime"
+	"sync"
 	"time"
 
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/mattermost"
 	prommetrics "github.com/aimd54/gitlab-reviewer-roulette/internal/metrics"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
@@ -17,11 +21,17 @@ import (
 type BadgeRepository interface {
 	GetAll() ([]models.Badge, error)
 	GetByID(id uint) (*models.Badge, error)
+	Create(badge *models.Badge) error
+	Update(badge *models.Badge) error
 	HasUserEarnedBadge(userID, badgeID uint) (bool, error)
 	AwardBadge(userID, badgeID uint) error
+	RevokeUserBadge(userID, badgeID uint) error
 	GetUserBadges(userID uint) ([]models.UserBadge, error)
 	GetUsersWithBadge(badgeID uint) ([]models.User, error)
+	GetUsersWithBadgePaged(badgeID uint, offset, limit int) ([]models.User, int64, error)
 	GetBadgeHoldersCount(badgeID uint) (int64, error)
+	GetHolderCounts() (map[uint]int64, error)
+	GetRecentlyAwardedBadges(since time.Time) ([]models.UserBadge, error)
 }
 
 // MetricsRepository interface for metrics operations.
@@ -39,23 +49,37 @@ type ReviewRepository interface {
 type UserRepository interface {
 	List(team, role string) ([]models.User, error)
 	GetByID(id uint) (*models.User, error)
+	Count() (int64, error)
 }
 
 // Service handles badge evaluation and awarding.
 type Service struct {
-	badgeRepo   BadgeRepository
-	metricsRepo MetricsRepository
-	reviewRepo  ReviewRepository
-	userRepo    UserRepository
-	log         *logger.Logger
+	badgeRepo       BadgeRepository
+	metricsRepo     MetricsRepository
+	reviewRepo      ReviewRepository
+	userRepo        UserRepository
+	mattermost      *mattermost.Client // optional, nil disables award notifications
+	concurrency     int                // workers used by EvaluateAllBadges, <= 0 means GOMAXPROCS
+	batchSize       int                // users evaluated between progress reports, <= 0 means DefaultEvaluationBatchSize
+	calendarPeriods bool               // named criteria periods start at calendar boundaries
+	announceNew     bool               // SyncFromConfig announces created badges on Mattermost
+	onProgress      func(processed, total int)
+	log             *logger.Logger
 }
 
+// ErrBadgeAlreadyAwarded is returned by AwardBadge when the user already holds the badge.
+var ErrBadgeAlreadyAwarded = errors.New("badge already awarded")
+
+// DefaultEvaluationBatchSize is the number of users EvaluateAllBadges evaluates between progress reports.
+const DefaultEvaluationBatchSize = 100
+
 // NewService creates a new badge service.
 func NewService(
 	badgeRepo *repository.BadgeRepository,
 	metricsRepo *repository.MetricsRepository,
 	reviewRepo *repository.ReviewRepository,
 	userRepo *repository.UserRepository,
+	mattermostClient *mattermost.Client,
 	log *logger.Logger,
 ) *Service {
 	return &Service{
@@ -63,6 +87,7 @@ func NewService(
 		metricsRepo: metricsRepo,
 		reviewRepo:  reviewRepo,
 		userRepo:    userRepo,
+		mattermost:  mattermostClient,
 		log:         log,
 	}
 }
@@ -84,10 +109,49 @@ func NewServiceWithInterfaces(
 	}
 }
 
+// SetConcurrency sets how many users are evaluated in parallel by EvaluateAllBadges.
+// Zero or a negative value falls back to GOMAXPROCS.
+func (s *Service) SetConcurrency(concurrency int) {
+	s.concurrency = concurrency
+}
+
+// SetBatchSize sets how many users EvaluateAllBadges evaluates between progress reports.
+// Zero or a negative value falls back to DefaultEvaluationBatchSize.
+func (s *Service) SetBatchSize(batchSize int) {
+	s.batchSize = batchSize
+}
+
+// SetCalendarPeriods makes named criteria periods ("month", "year", ...) start at calendar
+// boundaries instead of covering a rolling window ending now.
+func (s *Service) SetCalendarPeriods(enabled bool) {
+	s.calendarPeriods = enabled
+}
+
+// SetAnnounceNewBadges makes SyncFromConfig post an announcement for each badge it creates.
+func (s *Service) SetAnnounceNewBadges(enabled bool) {
+	s.announceNew = enabled
+}
+
+// workerCount returns the number of evaluation workers to start for the given number of users.
+func (s *Service) workerCount(users int) int {
+	workers := s.concurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > users {
+		workers = users
+	}
+	return workers
+}
+
 // EvaluateAllBadges evaluates all badges for all users.
 // This is typically run as a scheduled job.
-// Returns the number of badges awarded.
-func (s *Service) EvaluateAllBadges(ctx context.Context) (int, error) {
+// Users are evaluated in batches (see SetBatchSize), each in parallel by a bounded pool of workers
+// (see SetConcurrency); each user is handled by a single worker, so award writes never race for
+// the same user and badge. Progress is logged and exported after every batch.
+// Returns the number of badges awarded along with a breakdown by badge name.
+// When ctx is cancelled, evaluation stops early and ctx.Err() is returned with the awards made so far.
+func (s *Service) EvaluateAllBadges(ctx context.Context) (int, map[string]int, error) {
 	s.log.Info().Msg("Starting badge evaluation for all users")
 	start := time.Now()
 
@@ -95,79 +159,183 @@ func (s *Service) EvaluateAllBadges(ctx context.Context) (int, error) {
 	badges, err := s.badgeRepo.GetAll()
 	if err != nil {
 		s.log.Error().Err(err).Msg("Failed to get badges")
-		return 0, fmt.Errorf("failed to get badges: %w", err)
+		return 0, nil, fmt.Errorf("failed to get badges: %w", err)
 	}
 
 	// Get all users
 	users, err := s.userRepo.List("", "") // Get all users (empty filters)
 	if err != nil {
 		s.log.Error().Err(err).Msg("Failed to get users")
-		return 0, fmt.Errorf("failed to get users: %w", err)
+		return 0, nil, fmt.Errorf("failed to get users: %w", err)
 	}
 
-	awardsCount := 0
-
-	// Evaluate each badge for each user
-	for _, badge := range badges {
-		for _, user := range users {
-			// Check if user already has this badge
-			hasEarned, err := s.badgeRepo.HasUserEarnedBadge(user.ID, badge.ID)
-			if err != nil {
-				s.log.Error().
-					Err(err).
-					Uint("user_id", user.ID).
-					Uint("badge_id", badge.ID).
-					Msg("Failed to check if user has badge")
-				continue
-			}
-
-			if hasEarned {
-				// User already has this badge, skip
-				continue
-			}
+	var (
+		mu            sync.Mutex
+		awardsCount   int
+		awardsByBadge = make(map[string]int)
+		awardedBadges = make(map[uint]*models.Badge)
+	)
+	record := func(badge *models.Badge) {
+		mu.Lock()
+		defer mu.Unlock()
+		awardsCount++
+		awardsByBadge[badge.Name]++
+		awardedBadges[badge.ID] = badge
+	}
 
-			// Evaluate badge criteria
-			qualifies, err := s.EvaluateBadge(ctx, &badge, user.ID)
-			if err != nil {
-				s.log.Error().
-					Err(err).
-					Uint("user_id", user.ID).
-					Str("badge", badge.Name).
-					Msg("Failed to evaluate badge")
-				continue
-			}
+	// Users are evaluated in batches so long runs report progress as they go
+	batchSize := s.batchSize
+	if batchSize <= 0 {
+		batchSize = DefaultEvaluationBatchSize
+	}
+	processed := 0
+	s.reportProgress(processed, len(users))
+	for batchStart := 0; batchStart < len(users) && ctx.Err() == nil; batchStart += batchSize {
+		batch := users[batchStart:min(batchStart+batchSize, len(users))]
+		processed += s.evaluateBatch(ctx, badges, batch, record)
+		s.reportProgress(processed, len(users))
+	}
 
-			if qualifies {
-				// Award badge
-				err = s.AwardBadge(ctx, user.ID, &badge)
-				if err != nil {
-					s.log.Error().
-						Err(err).
-						Uint("user_id", user.ID).
-						Str("badge", badge.Name).
-						Msg("Failed to award badge")
-					continue
-				}
+	// Holder gauges are refreshed once all workers are done, so concurrent awards
+	// cannot leave a stale count behind.
+	for _, badge := range awardedBadges {
+		s.refreshBadgeHolders(badge)
+	}
 
-				awardsCount++
-				s.log.Info().
-					Uint("user_id", user.ID).
-					Str("username", user.Username).
-					Str("badge", badge.Name).
-					Msg("Badge awarded")
-			}
-		}
+	if err := ctx.Err(); err != nil {
+		s.log.Warn().
+			Err(err).
+			Int("badges_awarded", awardsCount).
+			Dur("duration", time.Since(start)).
+			Msg("Badge evaluation cancelled")
+		return awardsCount, awardsByBadge, err
 	}
 
 	duration := time.Since(start)
 	s.log.Info().
 		Int("badges_evaluated", len(badges)).
 		Int("users_evaluated", len(users)).
+		Int("workers", s.workerCount(len(users))).
 		Int("badges_awarded", awardsCount).
 		Dur("duration", duration).
 		Msg("Badge evaluation complete")
 
-	return awardsCount, nil
+	return awardsCount, awardsByBadge, nil
+}
+
+// evaluateBatch evaluates a batch of users with a bounded pool of workers and passes
+// each awarded badge to record. Returns the number of users handed to the workers,
+// which is less than the batch size when ctx is cancelled.
+func (s *Service) evaluateBatch(ctx context.Context, badges []models.Badge, users []models.User, record func(*models.Badge)) int {
+	var wg sync.WaitGroup
+	jobs := make(chan models.User)
+	for i := 0; i < s.workerCount(len(users)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for user := range jobs {
+				for _, badge := range s.evaluateUserAwards(ctx, badges, user) {
+					record(badge)
+				}
+			}
+		}()
+	}
+
+	// Stop handing out users once the context is cancelled
+	sent := 0
+feed:
+	for _, user := range users {
+		select {
+		case jobs <- user:
+			sent++
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return sent
+}
+
+// reportProgress logs and exports how many users an evaluation run has processed.
+func (s *Service) reportProgress(processed, total int) {
+	prommetrics.SetBadgeEvaluationProgress(processed, total)
+	if processed > 0 {
+		s.log.Info().
+			Int("processed", processed).
+			Int("total", total).
+			Msg("Badge evaluation progress")
+	}
+	if s.onProgress != nil {
+		s.onProgress(processed, total)
+	}
+}
+
+// evaluateUserAwards evaluates every badge for a single user and awards the ones they qualify for.
+// Returns the newly awarded badges. Per-badge failures are logged and skipped, and
+// evaluation stops at the next badge once ctx is cancelled.
+func (s *Service) evaluateUserAwards(ctx context.Context, badges []models.Badge, user models.User) []*models.Badge {
+	var awarded []*models.Badge
+
+	for i := range badges {
+		if ctx.Err() != nil {
+			return awarded
+		}
+		badge := &badges[i]
+
+		// Check if user already has this badge
+		hasEarned, err := s.badgeRepo.HasUserEarnedBadge(user.ID, badge.ID)
+		if err != nil {
+			s.log.Error().
+				Err(err).
+				Uint("user_id", user.ID).
+				Uint("badge_id", badge.ID).
+				Msg("Failed to check if user has badge")
+			continue
+		}
+
+		if hasEarned {
+			// User already has this badge, skip
+			continue
+		}
+
+		// Evaluate badge criteria
+		qualifies, err := s.EvaluateBadge(ctx, badge, user.ID)
+		if err != nil {
+			s.log.Error().
+				Err(err).
+				Uint("user_id", user.ID).
+				Str("badge", badge.Name).
+				Msg("Failed to evaluate badge")
+			continue
+		}
+
+		if !qualifies {
+			continue
+		}
+
+		// Award badge
+		if err := s.AwardBadge(ctx, user.ID, badge); err != nil {
+			s.log.Error().
+				Err(err).
+				Uint("user_id", user.ID).
+				Str("badge", badge.Name).
+				Msg("Failed to award badge")
+			continue
+		}
+
+		awarded = append(awarded, badge)
+		s.log.Info().
+			Uint("user_id", user.ID).
+			Str("username", user.Username).
+			Str("badge", badge.Name).
+			Msg("Badge awarded")
+
+		s.notifyBadgeAward(user.Username, badge)
+	}
+
+	return awarded
 }
 
 // EvaluateUserBadges evaluates all badges for a specific user and returns newly earned badges.
@@ -225,9 +393,36 @@ func (s *Service) EvaluateUserBadges(ctx context.Context, userID uint) ([]models
 		}
 	}
 
+	if len(newlyEarned) > 0 && s.mattermost != nil {
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil {
+			s.log.Warn().Err(err).Uint("user_id", userID).Msg("Failed to get user for badge notification")
+		} else {
+			for i := range newlyEarned {
+				s.notifyBadgeAward(user.Username, &newlyEarned[i])
+			}
+		}
+	}
+
 	return newlyEarned, nil
 }
 
+// notifyBadgeAward posts a Mattermost announcement for a newly earned badge.
+// Failures are logged and never affect the award itself.
+func (s *Service) notifyBadgeAward(username string, badge *models.Badge) {
+	if s.mattermost == nil {
+		return
+	}
+
+	if err := s.mattermost.SendBadgeAward(username, badge.Name, badge.Icon); err != nil {
+		s.log.Warn().
+			Err(err).
+			Str("username", username).
+			Str("badge", badge.Name).
+			Msg("Failed to send badge award notification")
+	}
+}
+
 // EvaluateBadge evaluates if a user qualifies for a specific badge.
 func (s *Service) EvaluateBadge(ctx context.Context, badge *models.Badge, userID uint) (bool, error) {
 	// Parse badge criteria
@@ -241,17 +436,30 @@ func (s *Service) EvaluateBadge(ctx context.Context, badge *models.Badge, userID
 	return s.checkCriteria(ctx, &criteria, userID)
 }
 
-// AwardBadge awards a badge to a user.
+// AwardBadge awards a badge to a user. It returns ErrBadgeAlreadyAwarded, without
+// recording another award, when the user already holds the badge, and an error
+// wrapping repository.ErrNotFound when the user does not exist.
 //
 //nolint:revive // ctx reserved for future context-aware operations (tracing, cancellation)
 func (s *Service) AwardBadge(ctx context.Context, userID uint, badge *models.Badge) error {
-	err := s.badgeRepo.AwardBadge(userID, badge.ID)
+	// Get user to record team name in metrics
+	user, userErr := s.userRepo.GetByID(userID)
+	if errors.Is(userErr, repository.ErrNotFound) {
+		return fmt.Errorf("failed to award badge %s: %w", badge.Name, userErr)
+	}
+
+	hasEarned, err := s.badgeRepo.HasUserEarnedBadge(userID, badge.ID)
 	if err != nil {
+		return fmt.Errorf("failed to check badge %s: %w", badge.Name, err)
+	}
+	if hasEarned {
+		return ErrBadgeAlreadyAwarded
+	}
+
+	if err := s.badgeRepo.AwardBadge(userID, badge.ID); err != nil {
 		return err
 	}
 
-	// Get user to record team name in metrics
-	user, userErr := s.userRepo.GetByID(userID)
 	team := "unknown"
 	if userErr == nil && user != nil {
 		team = user.Team
@@ -261,24 +469,44 @@ func (s *Service) AwardBadge(ctx context.Context, userID uint, badge *models.Bad
 	prommetrics.RecordBadgeAwarded(badge.Name, team)
 
 	// Update active holders count
-	count, _ := s.badgeRepo.GetBadgeHoldersCount(badge.ID)
-	prommetrics.SetActiveBadgeHolders(badge.Name, int(count))
+	s.refreshBadgeHolders(badge)
 
 	return nil
 }
 
-// GetUserBadges retrieves all badges earned by a user.
+// RevokeBadge removes a badge from a user and updates the badge's holders gauge.
+// Revoking a badge the user does not hold is a no-op.
 //
 //nolint:revive // ctx reserved for future context-aware operations (tracing, cancellation)
-func (s *Service) GetUserBadges(ctx context.Context, userID uint) ([]models.UserBadge, error) {
-	return s.badgeRepo.GetUserBadges(userID)
+func (s *Service) RevokeBadge(ctx context.Context, userID, badgeID uint) error {
+	badge, err := s.badgeRepo.GetByID(badgeID)
+	if err != nil {
+		return fmt.Errorf("failed to get badge %d: %w", badgeID, err)
+	}
+	if badge == nil {
+		return fmt.Errorf("badge %d: %w", badgeID, repository.ErrNotFound)
+	}
+
+	if err := s.badgeRepo.RevokeUserBadge(userID, badgeID); err != nil {
+		return fmt.Errorf("failed to revoke badge %d from user %d: %w", badgeID, userID, err)
+	}
+
+	s.refreshBadgeHolders(badge)
+
+	return nil
+}
+
+// refreshBadgeHolders updates the active holders gauge for a badge.
+func (s *Service) refreshBadgeHolders(badge *models.Badge) {
+	count, _ := s.badgeRepo.GetBadgeHoldersCount(badge.ID)
+	prommetrics.SetActiveBadgeHolders(badge.Name, int(count))
 }
 
-// GetBadgeCatalog retrieves all available badges.
+// GetUserBadges retrieves all badges earned by a user.
 //
 //nolint:revive // ctx reserved for future context-aware operations (tracing, cancellation)
-func (s *Service) GetBadgeCatalog(ctx context.Context) ([]models.Badge, error) {
-	return s.badgeRepo.GetAll()
+func (s *Service) GetUserBadges(ctx context.Context, userID uint) ([]models.UserBadge, error) {
+	return s.badgeRepo.GetUserBadges(userID)
 }
 
 // GetBadgeByID retrieves a badge by its ID.
@@ -288,11 +516,19 @@ func (s *Service) GetBadgeByID(ctx context.Context, badgeID uint) (*models.Badge
 	return s.badgeRepo.GetByID(badgeID)
 }
 
-// GetBadgeHolders retrieves users who have earned a specific badge.
+// GetBadgeHolders retrieves one page of users who have earned a specific badge
+// and the total number of holders. A limit of 0 returns all holders from offset.
+//
+//nolint:revive // ctx reserved for future context-aware operations (tracing, cancellation)
+func (s *Service) GetBadgeHolders(ctx context.Context, badgeID uint, offset, limit int) ([]models.User, int64, error) {
+	return s.badgeRepo.GetUsersWithBadgePaged(badgeID, offset, limit)
+}
+
+// GetRecentlyAwardedBadges retrieves badges awarded since the given time, most recent first.
 //
 //nolint:revive // ctx reserved for future context-aware operations (tracing, cancellation)
-func (s *Service) GetBadgeHolders(ctx context.Context, badgeID uint) ([]models.User, error) {
-	return s.badgeRepo.GetUsersWithBadge(badgeID)
+func (s *Service) GetRecentlyAwardedBadges(ctx context.Context, since time.Time) ([]models.UserBadge, error) {
+	return s.badgeRepo.GetRecentlyAwardedBadges(since)
 }
 
 // GetBadgeHoldersCount retrieves the count of users who have earned a badge.
@@ -3,16 +3,31 @@ package badges
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/mattermost"
+	prommetrics "github.com/aimd54/gitlab-reviewer-roulette/internal/metrics"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/period"
 	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
 )
 
 // Mock repositories for testing
 type mockBadgeRepository struct {
+	mu          sync.Mutex // EvaluateAllBadges calls the repository from several workers
 	badges      map[uint]*models.Badge
 	userBadges  map[uint]map[uint]bool // userID -> badgeID -> exists
 	nextBadgeID uint
@@ -27,6 +42,9 @@ func newMockBadgeRepository() *mockBadgeRepository {
 }
 
 func (m *mockBadgeRepository) GetAll() ([]models.Badge, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	badges := make([]models.Badge, 0, len(m.badges))
 	for _, b := range m.badges {
 		badges = append(badges, *b)
@@ -35,13 +53,37 @@ func (m *mockBadgeRepository) GetAll() ([]models.Badge, error) {
 }
 
 func (m *mockBadgeRepository) GetByID(id uint) (*models.Badge, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	if badge, ok := m.badges[id]; ok {
 		return badge, nil
 	}
 	return nil, nil
 }
 
+func (m *mockBadgeRepository) Create(badge *models.Badge) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	badge.ID = m.nextBadgeID
+	m.nextBadgeID++
+	m.badges[badge.ID] = badge
+	return nil
+}
+
+func (m *mockBadgeRepository) Update(badge *models.Badge) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.badges[badge.ID] = badge
+	return nil
+}
+
 func (m *mockBadgeRepository) HasUserEarnedBadge(userID, badgeID uint) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	if userBadges, ok := m.userBadges[userID]; ok {
 		return userBadges[badgeID], nil
 	}
@@ -49,6 +91,9 @@ func (m *mockBadgeRepository) HasUserEarnedBadge(userID, badgeID uint) (bool, er
 }
 
 func (m *mockBadgeRepository) AwardBadge(userID, badgeID uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	if m.userBadges[userID] == nil {
 		m.userBadges[userID] = make(map[uint]bool)
 	}
@@ -56,7 +101,18 @@ func (m *mockBadgeRepository) AwardBadge(userID, badgeID uint) error {
 	return nil
 }
 
+func (m *mockBadgeRepository) RevokeUserBadge(userID, badgeID uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.userBadges[userID], badgeID)
+	return nil
+}
+
 func (m *mockBadgeRepository) GetUserBadges(userID uint) ([]models.UserBadge, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	var result []models.UserBadge
 	if userBadges, ok := m.userBadges[userID]; ok {
 		for badgeID := range userBadges {
@@ -71,6 +127,9 @@ func (m *mockBadgeRepository) GetUserBadges(userID uint) ([]models.UserBadge, er
 }
 
 func (m *mockBadgeRepository) GetUsersWithBadge(badgeID uint) ([]models.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	var users []models.User
 	for userID, badges := range m.userBadges {
 		if badges[badgeID] {
@@ -80,7 +139,39 @@ func (m *mockBadgeRepository) GetUsersWithBadge(badgeID uint) ([]models.User, er
 	return users, nil
 }
 
+func (m *mockBadgeRepository) GetUsersWithBadgePaged(badgeID uint, offset, limit int) ([]models.User, int64, error) {
+	users, err := m.GetUsersWithBadge(badgeID)
+	if err != nil {
+		return nil, 0, err
+	}
+	total := int64(len(users))
+	if offset >= len(users) {
+		return []models.User{}, total, nil
+	}
+	users = users[offset:]
+	if limit > 0 && len(users) > limit {
+		users = users[:limit]
+	}
+	return users, total, nil
+}
+
+func (m *mockBadgeRepository) GetRecentlyAwardedBadges(since time.Time) ([]models.UserBadge, error) {
+	var result []models.UserBadge
+	for userID := range m.userBadges {
+		userBadges, _ := m.GetUserBadges(userID)
+		for _, ub := range userBadges {
+			if !ub.EarnedAt.Before(since) {
+				result = append(result, ub)
+			}
+		}
+	}
+	return result, nil
+}
+
 func (m *mockBadgeRepository) GetBadgeHoldersCount(badgeID uint) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	count := int64(0)
 	for _, badges := range m.userBadges {
 		if badges[badgeID] {
@@ -90,6 +181,21 @@ func (m *mockBadgeRepository) GetBadgeHoldersCount(badgeID uint) (int64, error)
 	return count, nil
 }
 
+func (m *mockBadgeRepository) GetHolderCounts() (map[uint]int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counts := make(map[uint]int64)
+	for _, badges := range m.userBadges {
+		for badgeID, earned := range badges {
+			if earned {
+				counts[badgeID]++
+			}
+		}
+	}
+	return counts, nil
+}
+
 type mockMetricsRepository struct {
 	metrics []models.ReviewMetrics
 }
@@ -101,19 +207,30 @@ func newMockMetricsRepository() *mockMetricsRepository {
 }
 
 func (m *mockMetricsRepository) GetByDateRange(startDate, endDate time.Time, filters map[string]interface{}) ([]models.ReviewMetrics, error) {
-	return m.metrics, nil
+	var result []models.ReviewMetrics
+	for _, metric := range m.metrics {
+		if inRange(metric.Date, startDate, endDate) {
+			result = append(result, metric)
+		}
+	}
+	return result, nil
 }
 
 func (m *mockMetricsRepository) GetMetricsByUser(userID uint, startDate, endDate time.Time) ([]models.ReviewMetrics, error) {
 	var result []models.ReviewMetrics
 	for _, metric := range m.metrics {
-		if metric.UserID != nil && *metric.UserID == userID {
+		if metric.UserID != nil && *metric.UserID == userID && inRange(metric.Date, startDate, endDate) {
 			result = append(result, metric)
 		}
 	}
 	return result, nil
 }
 
+// inRange reports whether a fixture date falls within a range. Undated fixtures match any range.
+func inRange(date, startDate, endDate time.Time) bool {
+	return date.IsZero() || (!date.Before(startDate) && !date.After(endDate))
+}
+
 type mockReviewRepository struct{}
 
 func newMockReviewRepository() *mockReviewRepository {
@@ -134,13 +251,17 @@ func (m *mockUserRepository) List(team, role string) ([]models.User, error) {
 	return m.users, nil
 }
 
+func (m *mockUserRepository) Count() (int64, error) {
+	return int64(len(m.users)), nil
+}
+
 func (m *mockUserRepository) GetByID(id uint) (*models.User, error) {
 	for _, user := range m.users {
 		if user.ID == id {
 			return &user, nil
 		}
 	}
-	return nil, fmt.Errorf("user not found")
+	return nil, fmt.Errorf("user %d: %w", id, repository.ErrNotFound)
 }
 
 // Test setup helper
@@ -208,55 +329,141 @@ func TestEvaluateMetricCriteria(t *testing.T) {
 func TestCalculatePeriodRange(t *testing.T) {
 	service, _, _, _ := setupTestService()
 
-	now := time.Now()
+	// Named criteria periods resolve through the shared period package
+	for _, name := range []string{"day", "week", "month", "quarter", "year"} {
+		t.Run(name, func(t *testing.T) {
+			startDate, endDate := service.calculatePeriodRange(name)
+			wantStart, wantEnd := period.Range(name)
+
+			if diff := wantEnd.Sub(endDate); diff < 0 || diff > time.Second {
+				t.Errorf("End date not close to now: %v", endDate)
+			}
+			if got, want := endDate.Sub(startDate), wantEnd.Sub(wantStart); got != want {
+				t.Errorf("Period '%s': expected a %v window, got %v", name, want, got)
+			}
+		})
+	}
+
+	// all_time and empty periods start at the epoch
+	for _, name := range []string{"all_time", ""} {
+		t.Run("epoch "+name, func(t *testing.T) {
+			startDate, _ := service.calculatePeriodRange(name)
+			expected := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+			if !startDate.Equal(expected) {
+				t.Errorf("Expected start date %v, got %v", expected, startDate)
+			}
+		})
+	}
+}
+
+func TestCriteriaPeriodRange(t *testing.T) {
+	service, _, _, _ := setupTestService()
 
 	tests := []struct {
-		period         string
-		expectedDelta  time.Duration
-		toleranceDelta time.Duration
+		name          string
+		criteria      models.BadgeCriteria
+		expectedDelta time.Duration
 	}{
-		{"day", 24 * time.Hour, 1 * time.Minute},
-		{"week", 7 * 24 * time.Hour, 1 * time.Minute},
-		{"month", 30 * 24 * time.Hour, 1 * time.Minute},
-		{"year", 365 * 24 * time.Hour, 1 * time.Minute},
+		{"last 90 days", models.BadgeCriteria{Period: "days", N: 90}, 90 * 24 * time.Hour},
+		{"last 2 weeks", models.BadgeCriteria{Period: "weeks", N: 2}, 14 * 24 * time.Hour},
+		{"last 3 months", models.BadgeCriteria{Period: "months", N: 3}, 90 * 24 * time.Hour},
+		{"named period", models.BadgeCriteria{Period: "quarter"}, 90 * 24 * time.Hour},
+		{"n is ignored for named periods", models.BadgeCriteria{Period: "week", N: 5}, 7 * 24 * time.Hour},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.period, func(t *testing.T) {
-			startDate, endDate := service.calculatePeriodRange(tt.period)
-
-			// End date should be approximately now
-			if endDate.Sub(now) > 1*time.Second {
-				t.Errorf("End date not close to now: %v", endDate.Sub(now))
+		t.Run(tt.name, func(t *testing.T) {
+			startDate, endDate, err := service.criteriaPeriodRange(&tt.criteria)
+			if err != nil {
+				t.Fatalf("criteriaPeriodRange failed: %v", err)
 			}
-
-			// Start date should be approximately expectedDelta ago
-			actualDelta := now.Sub(startDate)
-			deltaError := actualDelta - tt.expectedDelta
-
-			if deltaError < -tt.toleranceDelta || deltaError > tt.toleranceDelta {
-				t.Errorf("Period '%s': expected delta ~%v, got %v (error: %v)", tt.period, tt.expectedDelta, actualDelta, deltaError)
+			if delta := endDate.Sub(startDate); delta != tt.expectedDelta {
+				t.Errorf("Expected range of %v, got %v", tt.expectedDelta, delta)
 			}
 		})
 	}
 
-	// Test all_time
-	t.Run("all_time", func(t *testing.T) {
-		startDate, _ := service.calculatePeriodRange("all_time")
-		expected := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
-		if !startDate.Equal(expected) {
-			t.Errorf("Expected start date %v, got %v", expected, startDate)
+	t.Run("unit period without n", func(t *testing.T) {
+		if _, _, err := service.criteriaPeriodRange(&models.BadgeCriteria{Period: "days"}); err == nil {
+			t.Error("Expected error for a days period without n")
 		}
 	})
+}
+
+func TestCheckCriteria_NinetyDayPeriod(t *testing.T) {
+	service, _, metricsRepo, _ := setupTestService()
+
+	userID := uint(1)
+	now := time.Now()
+	metricsRepo.metrics = []models.ReviewMetrics{
+		{UserID: &userID, Date: now.AddDate(0, 0, -10), CompletedReviews: 4},
+		{UserID: &userID, Date: now.AddDate(0, 0, -60), CompletedReviews: 4},
+		{UserID: &userID, Date: now.AddDate(0, 0, -120), CompletedReviews: 4},
+	}
+
+	// 8 reviews in the last 90 days: more than a month's worth, less than all time
+	criteria := &models.BadgeCriteria{Metric: "completed_reviews", Operator: ">=", Value: 8.0, Period: "days", N: 90}
+	result, err := service.checkCriteria(context.Background(), criteria, userID)
+	if err != nil {
+		t.Fatalf("checkCriteria failed: %v", err)
+	}
+	if !result {
+		t.Error("Expected user to qualify with 8 reviews in the last 90 days")
+	}
+
+	criteria.Value = 12.0
+	result, err = service.checkCriteria(context.Background(), criteria, userID)
+	if err != nil {
+		t.Fatalf("checkCriteria failed: %v", err)
+	}
+	if result {
+		t.Error("Expected reviews older than 90 days not to count")
+	}
+}
+
+func TestCheckCriteria_QuarterTopRanking(t *testing.T) {
+	service, _, metricsRepo, _ := setupTestService()
+
+	alice := uint(1)
+	bob := uint(2)
+	now := time.Now()
+	// Bob leads over the last month, alice over the last quarter
+	metricsRepo.metrics = []models.ReviewMetrics{
+		{UserID: &alice, Date: now.AddDate(0, 0, -70), CompletedReviews: 20},
+		{UserID: &alice, Date: now.AddDate(0, 0, -5), CompletedReviews: 2},
+		{UserID: &bob, Date: now.AddDate(0, 0, -5), CompletedReviews: 10},
+	}
 
-	// Test empty string (should default to all_time)
-	t.Run("empty", func(t *testing.T) {
-		startDate, _ := service.calculatePeriodRange("")
-		expected := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
-		if !startDate.Equal(expected) {
-			t.Errorf("Expected start date %v, got %v", expected, startDate)
+	for _, criteria := range []*models.BadgeCriteria{
+		{Metric: "completed_reviews", Operator: "top", Value: 1.0, Period: "quarter"},
+		{Metric: "completed_reviews", Operator: "top", Value: 1.0, Period: "months", N: 3},
+	} {
+		result, err := service.checkCriteria(context.Background(), criteria, alice)
+		if err != nil {
+			t.Fatalf("checkCriteria failed: %v", err)
 		}
-	})
+		if !result {
+			t.Errorf("Expected alice to be top 1 for period %q (n=%d)", criteria.Period, criteria.N)
+		}
+	}
+
+	monthly := &models.BadgeCriteria{Metric: "completed_reviews", Operator: "top", Value: 1.0, Period: "month"}
+	result, err := service.checkCriteria(context.Background(), monthly, alice)
+	if err != nil {
+		t.Fatalf("checkCriteria failed: %v", err)
+	}
+	if result {
+		t.Error("Expected bob, not alice, to be top 1 for the last month")
+	}
+}
+
+func TestCheckCriteria_InvalidPeriodLength(t *testing.T) {
+	service, _, _, _ := setupTestService()
+
+	criteria := &models.BadgeCriteria{Metric: "completed_reviews", Operator: ">=", Value: 1.0, Period: "days"}
+	if _, err := service.checkCriteria(context.Background(), criteria, 1); err == nil {
+		t.Error("Expected error for a days period without n")
+	}
 }
 
 func TestAggregateUserMetrics(t *testing.T) {
@@ -321,6 +528,47 @@ func TestAggregateUserMetrics(t *testing.T) {
 	}
 }
 
+func TestAggregateUserMetrics_WeightsSamplesAndSkipsDaysWithoutData(t *testing.T) {
+	service, _, metricsRepo, _ := setupTestService()
+
+	aliceID, bobID := uint(1), uint(2)
+	ttfrBusy, ttfrQuiet := 60, 120
+	comments := 4.0
+	metricsRepo.metrics = []models.ReviewMetrics{
+		// alice: a busy day, a day without any TTFR and a quiet day
+		{UserID: &aliceID, CompletedReviews: 3, AvgTTFR: &ttfrBusy, TTFRSamples: 3},
+		{UserID: &aliceID, AvgCommentCount: &comments, CommentSamples: 2},
+		{UserID: &aliceID, CompletedReviews: 1, AvgTTFR: &ttfrQuiet, TTFRSamples: 1},
+		// bob: only days without reviews
+		{UserID: &bobID},
+		{UserID: &bobID},
+	}
+
+	metrics, err := service.aggregateUserMetrics(aliceID, time.Time{}, time.Now())
+	if err != nil {
+		t.Fatalf("aggregateUserMetrics failed: %v", err)
+	}
+	// (60*3 + 120*1) / 4, not (60 + 0 + 120) / 3
+	if metrics["avg_ttfr"] != 75 {
+		t.Errorf("Expected avg_ttfr 75, got %v", metrics["avg_ttfr"])
+	}
+	if metrics["avg_comment_count"] != 4 {
+		t.Errorf("Expected avg_comment_count 4, got %v", metrics["avg_comment_count"])
+	}
+	if _, ok := metrics["engagement_score"]; ok {
+		t.Errorf("Expected no engagement_score without data, got %v", metrics["engagement_score"])
+	}
+
+	// A "lower is better" badge is not earned without any data
+	criteria := &models.BadgeCriteria{Metric: "avg_ttfr", Operator: "<", Value: 70.0, Period: "all_time"}
+	if ok, err := service.checkCriteria(context.Background(), criteria, bobID); err != nil || ok {
+		t.Errorf("Expected bob not to qualify without reviews, got %v (err: %v)", ok, err)
+	}
+	if ok, err := service.checkCriteria(context.Background(), criteria, aliceID); err != nil || ok {
+		t.Errorf("Expected alice not to qualify with avg_ttfr 75, got %v (err: %v)", ok, err)
+	}
+}
+
 func TestCheckCriteria_LessThan(t *testing.T) {
 	service, _, metricsRepo, _ := setupTestService()
 
@@ -352,6 +600,53 @@ func TestCheckCriteria_LessThan(t *testing.T) {
 	}
 }
 
+func TestCheckCriteria_ExternalReviews(t *testing.T) {
+	service, _, metricsRepo, userRepo := setupTestService()
+
+	aliceID, bobID := uint(1), uint(2)
+	userRepo.users = []models.User{
+		{ID: aliceID, Username: "alice", Team: "backend"},
+		{ID: bobID, Username: "bob", Team: "frontend"},
+	}
+	metricsRepo.metrics = []models.ReviewMetrics{
+		// alice: 2 reviews for her own team, 4 for other teams
+		{UserID: &aliceID, Team: "backend", CompletedReviews: 2},
+		{UserID: &aliceID, Team: "frontend", CompletedReviews: 3},
+		{UserID: &aliceID, Team: "platform", CompletedReviews: 1},
+		// bob: 10 reviews, all for his own team, and 1 for backend
+		{UserID: &bobID, Team: "frontend", CompletedReviews: 10},
+		{UserID: &bobID, Team: "backend", CompletedReviews: 1},
+	}
+
+	metrics, err := service.aggregateUserMetrics(aliceID, time.Time{}, time.Now())
+	if err != nil {
+		t.Fatalf("aggregateUserMetrics failed: %v", err)
+	}
+	if metrics["external_reviews"] != 4 {
+		t.Errorf("Expected 4 external reviews for alice, got %v", metrics["external_reviews"])
+	}
+	if metrics["completed_reviews"] != 6 {
+		t.Errorf("Expected 6 completed reviews for alice, got %v", metrics["completed_reviews"])
+	}
+
+	threshold := &models.BadgeCriteria{Metric: "external_reviews", Operator: ">=", Value: 4.0, Period: "all_time"}
+	if ok, err := service.checkCriteria(context.Background(), threshold, aliceID); err != nil || !ok {
+		t.Errorf("Expected alice to qualify with 4 external reviews, got %v (err: %v)", ok, err)
+	}
+	if ok, err := service.checkCriteria(context.Background(), threshold, bobID); err != nil || ok {
+		t.Errorf("Expected bob not to qualify with 1 external review, got %v (err: %v)", ok, err)
+	}
+
+	// Bob has more completed reviews, but alice helped other teams the most
+	top := &models.BadgeCriteria{Metric: "external_reviews", Operator: "top", Value: 1.0, Period: "all_time"}
+	if ok, err := service.checkCriteria(context.Background(), top, aliceID); err != nil || !ok {
+		t.Errorf("Expected alice to be top 1 for external reviews, got %v (err: %v)", ok, err)
+	}
+	if ok, err := service.checkCriteria(context.Background(), top, bobID); err != nil || ok {
+		t.Errorf("Expected bob not to be top 1 for external reviews, got %v (err: %v)", ok, err)
+	}
+}
+
 func TestCheckCriteria_GreaterThanOrEqual(t *testing.T) {
 	service, _, metricsRepo, _ := setupTestService()
 
@@ -441,9 +736,10 @@ func TestEvaluateBadge(t *testing.T) {
 }
 
 func TestAwardBadge(t *testing.T) {
-	service, badgeRepo, _, _ := setupTestService()
+	service, badgeRepo, _, userRepo := setupTestService()
 
 	userID := uint(1)
+	userRepo.users = []models.User{{ID: userID, Username: "alice", Team: "team-a"}}
 	badge := &models.Badge{
 		ID:   1,
 		Name: "test_badge",
@@ -466,6 +762,212 @@ func TestAwardBadge(t *testing.T) {
 	}
 }
 
+func TestAwardBadge_AlreadyAwardedOrUnknownUser(t *testing.T) {
+	service, badgeRepo, _, userRepo := setupTestService()
+
+	userID := uint(1)
+	userRepo.users = []models.User{{ID: userID, Username: "alice", Team: "team-award"}}
+	badge := &models.Badge{ID: 1, Name: "award_once_badge"}
+	badgeRepo.badges[badge.ID] = badge
+	awarded := prommetrics.BadgesAwardedTotal.WithLabelValues(badge.Name, "team-award")
+
+	if err := service.AwardBadge(context.Background(), userID, badge); err != nil {
+		t.Fatalf("AwardBadge failed: %v", err)
+	}
+	if err := service.AwardBadge(context.Background(), userID, badge); !errors.Is(err, ErrBadgeAlreadyAwarded) {
+		t.Errorf("Expected ErrBadgeAlreadyAwarded on re-award, got %v", err)
+	}
+	if count := testutil.ToFloat64(awarded); count != 1 {
+		t.Errorf("Expected one recorded award, got %v", count)
+	}
+
+	err := service.AwardBadge(context.Background(), 99, badge)
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an unknown user, got %v", err)
+	}
+	if hasEarned, _ := badgeRepo.HasUserEarnedBadge(99, badge.ID); hasEarned {
+		t.Error("Expected no badge for an unknown user")
+	}
+}
+
+func TestRevokeBadge(t *testing.T) {
+	service, badgeRepo, _, _ := setupTestService()
+
+	badge := &models.Badge{ID: 1, Name: "revoke_badge"}
+	badgeRepo.badges[badge.ID] = badge
+	_ = badgeRepo.AwardBadge(1, badge.ID)
+	_ = badgeRepo.AwardBadge(2, badge.ID)
+
+	if err := service.RevokeBadge(context.Background(), 1, badge.ID); err != nil {
+		t.Fatalf("RevokeBadge failed: %v", err)
+	}
+
+	if hasEarned, _ := badgeRepo.HasUserEarnedBadge(1, badge.ID); hasEarned {
+		t.Error("Expected badge to be revoked")
+	}
+	if holders := testutil.ToFloat64(prommetrics.ActiveBadgeHolders.WithLabelValues(badge.Name)); holders != 1 {
+		t.Errorf("Expected 1 holder after revoking, got %f", holders)
+	}
+
+	if err := service.RevokeBadge(context.Background(), 1, 99); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an unknown badge, got %v", err)
+	}
+}
+
+func TestSyncFromConfig(t *testing.T) {
+	service, badgeRepo, _, _ := setupTestService()
+
+	badgeRepo.badges[10] = &models.Badge{
+		ID:          10,
+		Name:        "speed_demon",
+		Description: "Old description",
+		Icon:        "🐢",
+		Criteria:    json.RawMessage(`{"metric": "avg_ttfr", "operator": "<", "value": 240}`),
+	}
+	badgeRepo.badges[11] = &models.Badge{ID: 11, Name: "retired", Description: "No longer configured"}
+	badgeRepo.nextBadgeID = 12
+
+	configured := []config.BadgeConfig{
+		{
+			Name:        "speed_demon",
+			Description: "Average TTFR under 2 hours",
+			Icon:        "⚡",
+			Criteria:    map[string]interface{}{"metric": "avg_ttfr", "operator": "<", "value": 120},
+		},
+		{
+			Name:        "thorough_reviewer",
+			Description: "Averages 5+ comments per review",
+			Icon:        "🔍",
+			Criteria:    map[string]interface{}{"metric": "avg_comment_count", "operator": ">=", "value": 5},
+		},
+	}
+
+	if err := service.SyncFromConfig(context.Background(), configured); err != nil {
+		t.Fatalf("SyncFromConfig failed: %v", err)
+	}
+
+	if len(badgeRepo.badges) != 3 {
+		t.Fatalf("Expected 3 badges, got %d", len(badgeRepo.badges))
+	}
+
+	t.Run("new badge is created", func(t *testing.T) {
+		created := badgeRepo.badges[12]
+		if created == nil || created.Name != "thorough_reviewer" || created.Icon != "🔍" {
+			t.Fatalf("Expected thorough_reviewer to be created, got %+v", created)
+		}
+		var criteria models.BadgeCriteria
+		if err := json.Unmarshal(created.Criteria, &criteria); err != nil {
+			t.Fatalf("Invalid criteria JSON: %v", err)
+		}
+		if criteria.Metric != "avg_comment_count" || criteria.Operator != ">=" {
+			t.Errorf("Unexpected criteria: %+v", criteria)
+		}
+	})
+
+	t.Run("existing badge is updated", func(t *testing.T) {
+		updated := badgeRepo.badges[10]
+		if updated.Description != "Average TTFR under 2 hours" || updated.Icon != "⚡" {
+			t.Errorf("Expected description and icon to be updated, got %q %q", updated.Description, updated.Icon)
+		}
+		if !sameJSON(updated.Criteria, json.RawMessage(`{"metric":"avg_ttfr","operator":"<","value":120}`)) {
+			t.Errorf("Expected criteria to be updated, got %s", updated.Criteria)
+		}
+	})
+
+	t.Run("badge removed from config is kept", func(t *testing.T) {
+		if badgeRepo.badges[11] == nil || badgeRepo.badges[11].Description != "No longer configured" {
+			t.Error("Expected retired badge to be left intact")
+		}
+	})
+}
+
+func TestSyncFromConfig_AnnouncesNewBadges(t *testing.T) {
+	service, badgeRepo, _, _ := setupTestService()
+
+	var messages []mattermost.Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg mattermost.Message
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		messages = append(messages, msg)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	service.mattermost = mattermost.NewClient(&config.MattermostConfig{
+		WebhookURL: server.URL,
+		Enabled:    true,
+	}, nil, service.log)
+
+	configured := []config.BadgeConfig{
+		{Name: "speed_demon", Description: "Average TTFR under 2 hours", Icon: "⚡",
+			Criteria: map[string]interface{}{"metric": "avg_ttfr", "operator": "<", "value": 120}},
+	}
+
+	// Announcements are off by default, and never sent when seeding an empty catalog
+	service.SetAnnounceNewBadges(true)
+	if err := service.SyncFromConfig(context.Background(), configured); err != nil {
+		t.Fatalf("SyncFromConfig failed: %v", err)
+	}
+	if len(messages) != 0 {
+		t.Fatalf("Expected no announcement when seeding the catalog, got %d", len(messages))
+	}
+
+	// One badge added next to an existing one, whose description changed
+	configured[0].Description = "Average TTFR under 120 minutes"
+	configured = append(configured, config.BadgeConfig{
+		Name: "thorough_reviewer", Description: "Averages 5+ comments per review", Icon: "🔍",
+		Criteria: map[string]interface{}{"metric": "avg_comment_count", "operator": ">=", "value": 5},
+	})
+	if err := service.SyncFromConfig(context.Background(), configured); err != nil {
+		t.Fatalf("SyncFromConfig failed: %v", err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("Expected exactly one announcement, got %d", len(messages))
+	}
+	if !strings.Contains(messages[0].Text, "thorough_reviewer") || strings.Contains(messages[0].Text, "speed_demon") {
+		t.Errorf("Expected the announcement of thorough_reviewer only, got %q", messages[0].Text)
+	}
+
+	// A sync with nothing new announces nothing
+	if err := service.SyncFromConfig(context.Background(), configured); err != nil {
+		t.Fatalf("SyncFromConfig failed: %v", err)
+	}
+	if len(badgeRepo.badges) != 2 || len(messages) != 1 {
+		t.Errorf("Expected 2 badges and still one announcement, got %d badges and %d messages", len(badgeRepo.badges), len(messages))
+	}
+}
+
+func TestSyncFromConfig_AnnouncementsDisabled(t *testing.T) {
+	service, badgeRepo, _, _ := setupTestService()
+
+	sent := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	service.mattermost = mattermost.NewClient(&config.MattermostConfig{
+		WebhookURL: server.URL,
+		Enabled:    true,
+	}, nil, service.log)
+
+	badgeRepo.badges[1] = &models.Badge{ID: 1, Name: "speed_demon"}
+	badgeRepo.nextBadgeID = 2
+	configured := []config.BadgeConfig{
+		{Name: "speed_demon"},
+		{Name: "thorough_reviewer", Criteria: map[string]interface{}{"metric": "avg_comment_count", "operator": ">=", "value": 5}},
+	}
+	if err := service.SyncFromConfig(context.Background(), configured); err != nil {
+		t.Fatalf("SyncFromConfig failed: %v", err)
+	}
+	if sent != 0 {
+		t.Errorf("Expected no announcement without announce_new_badges, got %d", sent)
+	}
+}
+
 func TestGetUserBadges(t *testing.T) {
 	service, badgeRepo, _, _ := setupTestService()
 
@@ -511,6 +1013,80 @@ func TestGetBadgeCatalog(t *testing.T) {
 	}
 }
 
+func TestGetBadgeCatalog_Rarity(t *testing.T) {
+	service, badgeRepo, _, userRepo := setupTestService()
+
+	userRepo.users = nil
+	for id := uint(1); id <= 20; id++ {
+		userRepo.users = append(userRepo.users, models.User{ID: id, Username: fmt.Sprintf("user%d", id)})
+	}
+
+	// Holders out of 20 users: 10 (50%), 5 (25%), 1 (5%) and none
+	holders := map[uint]uint{1: 10, 2: 5, 3: 1, 4: 0}
+	for badgeID, count := range holders {
+		badgeRepo.badges[badgeID] = &models.Badge{ID: badgeID, Name: fmt.Sprintf("badge%d", badgeID)}
+		for userID := uint(1); userID <= count; userID++ {
+			if err := badgeRepo.AwardBadge(userID, badgeID); err != nil {
+				t.Fatalf("AwardBadge failed: %v", err)
+			}
+		}
+	}
+
+	catalog, err := service.GetBadgeCatalog(context.Background())
+	if err != nil {
+		t.Fatalf("GetBadgeCatalog failed: %v", err)
+	}
+
+	expected := map[uint]string{1: RarityCommon, 2: RarityRare, 3: RarityLegendary, 4: RarityLegendary}
+	if len(catalog) != len(expected) {
+		t.Fatalf("Expected %d badges, got %d", len(expected), len(catalog))
+	}
+	for _, entry := range catalog {
+		if entry.HolderCount != int64(holders[entry.ID]) {
+			t.Errorf("Badge %d: expected %d holders, got %d", entry.ID, holders[entry.ID], entry.HolderCount)
+		}
+		if entry.Rarity != expected[entry.ID] {
+			t.Errorf("Badge %d: expected rarity %s, got %s", entry.ID, expected[entry.ID], entry.Rarity)
+		}
+	}
+
+	// The computed fields sit next to the badge fields in the response
+	data, err := json.Marshal(catalog[0])
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	for _, key := range []string{"id", "name", "holder_count", "rarity"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("Expected catalog entry field %q in %s", key, data)
+		}
+	}
+}
+
+func TestRarity(t *testing.T) {
+	tests := []struct {
+		holders, users int64
+		expected       string
+	}{
+		{holders: 0, users: 0, expected: RarityLegendary},
+		{holders: 0, users: 10, expected: RarityLegendary},
+		{holders: 1, users: 100, expected: RarityLegendary},
+		{holders: 6, users: 100, expected: RarityRare},
+		{holders: 25, users: 100, expected: RarityRare},
+		{holders: 26, users: 100, expected: RarityCommon},
+		{holders: 3, users: 3, expected: RarityCommon},
+	}
+
+	for _, tt := range tests {
+		if got := rarity(tt.holders, tt.users); got != tt.expected {
+			t.Errorf("rarity(%d, %d) = %s, expected %s", tt.holders, tt.users, got, tt.expected)
+		}
+	}
+}
+
 func TestEvaluateTopRanking(t *testing.T) {
 	service, _, metricsRepo, _ := setupTestService()
 
@@ -527,8 +1103,10 @@ func TestEvaluateTopRanking(t *testing.T) {
 		{UserID: &user3, CompletedReviews: 20},
 	}
 
+	startDate, endDate := service.calculatePeriodRange("all_time")
+
 	// Check if user2 is in top 1 for completed_reviews
-	result, err := service.evaluateTopRanking(context.Background(), "completed_reviews", 1, "all_time", user2)
+	result, err := service.evaluateTopRanking(context.Background(), "completed_reviews", 1, startDate, endDate, user2)
 	if err != nil {
 		t.Fatalf("evaluateTopRanking failed: %v", err)
 	}
@@ -538,7 +1116,7 @@ func TestEvaluateTopRanking(t *testing.T) {
 	}
 
 	// Check if user1 is in top 2
-	result, err = service.evaluateTopRanking(context.Background(), "completed_reviews", 2, "all_time", user1)
+	result, err = service.evaluateTopRanking(context.Background(), "completed_reviews", 2, startDate, endDate, user1)
 	if err != nil {
 		t.Fatalf("evaluateTopRanking failed: %v", err)
 	}
@@ -548,7 +1126,7 @@ func TestEvaluateTopRanking(t *testing.T) {
 	}
 
 	// Check if user3 is NOT in top 2
-	result, err = service.evaluateTopRanking(context.Background(), "completed_reviews", 2, "all_time", user3)
+	result, err = service.evaluateTopRanking(context.Background(), "completed_reviews", 2, startDate, endDate, user3)
 	if err != nil {
 		t.Fatalf("evaluateTopRanking failed: %v", err)
 	}
@@ -557,3 +1135,641 @@ func TestEvaluateTopRanking(t *testing.T) {
 		t.Error("Expected user3 to NOT be in top 2")
 	}
 }
+
+func TestEvaluateUserBadges_SendsMattermostNotification(t *testing.T) {
+	service, badgeRepo, metricsRepo, userRepo := setupTestService()
+
+	var messages []mattermost.Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg mattermost.Message
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		messages = append(messages, msg)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	service.mattermost = mattermost.NewClient(&config.MattermostConfig{
+		WebhookURL: server.URL,
+		Enabled:    true,
+	}, nil, service.log)
+
+	userID := uint(1)
+	ttfr := 60
+	userRepo.users = []models.User{{ID: userID, Username: "alice", Team: "team-a"}}
+	metricsRepo.metrics = []models.ReviewMetrics{{UserID: &userID, AvgTTFR: &ttfr}}
+	badgeRepo.badges[1] = &models.Badge{
+		ID:       1,
+		Name:     "Speed Demon",
+		Icon:     "⚡",
+		Criteria: json.RawMessage(`{"metric":"avg_ttfr","operator":"<","value":120}`),
+	}
+
+	earned, err := service.EvaluateUserBadges(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("EvaluateUserBadges failed: %v", err)
+	}
+	if len(earned) != 1 {
+		t.Fatalf("Expected 1 newly earned badge, got %d", len(earned))
+	}
+
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 notification, got %d", len(messages))
+	}
+	text := messages[0].Text
+	if !strings.Contains(text, "@alice") || !strings.Contains(text, "Speed Demon") || !strings.Contains(text, "⚡") {
+		t.Errorf("Notification text = %q, want username, badge name and icon", text)
+	}
+
+	// Already earned badges must not be announced again
+	if _, err := service.EvaluateUserBadges(context.Background(), userID); err != nil {
+		t.Fatalf("EvaluateUserBadges failed: %v", err)
+	}
+	if len(messages) != 1 {
+		t.Errorf("Expected no additional notification, got %d total", len(messages))
+	}
+}
+
+func TestEvaluateAllBadges_NilMattermostClient(t *testing.T) {
+	service, badgeRepo, metricsRepo, userRepo := setupTestService()
+
+	userID := uint(1)
+	ttfr := 60
+	userRepo.users = []models.User{{ID: userID, Username: "alice", Team: "team-a"}}
+	metricsRepo.metrics = []models.ReviewMetrics{{UserID: &userID, AvgTTFR: &ttfr}}
+	badgeRepo.badges[1] = &models.Badge{
+		ID:       1,
+		Name:     "Speed Demon",
+		Criteria: json.RawMessage(`{"metric":"avg_ttfr","operator":"<","value":120}`),
+	}
+
+	awarded, _, err := service.EvaluateAllBadges(context.Background())
+	if err != nil {
+		t.Fatalf("EvaluateAllBadges failed: %v", err)
+	}
+	if awarded != 1 {
+		t.Errorf("Expected 1 badge awarded, got %d", awarded)
+	}
+}
+
+func TestEvaluateAllBadges_AwardsByBadge(t *testing.T) {
+	service, badgeRepo, metricsRepo, userRepo := setupTestService()
+
+	aliceID, bobID, carolID := uint(1), uint(2), uint(3)
+	fastTTFR, slowTTFR := 60, 300
+	reviews, fewReviews := 25, 5
+	userRepo.users = []models.User{
+		{ID: aliceID, Username: "alice", Team: "team-a"},
+		{ID: bobID, Username: "bob", Team: "team-a"},
+		{ID: carolID, Username: "carol", Team: "team-b"},
+	}
+	metricsRepo.metrics = []models.ReviewMetrics{
+		{UserID: &aliceID, AvgTTFR: &fastTTFR, CompletedReviews: reviews},
+		{UserID: &bobID, AvgTTFR: &fastTTFR, CompletedReviews: fewReviews},
+		{UserID: &carolID, AvgTTFR: &slowTTFR, CompletedReviews: fewReviews},
+	}
+	badgeRepo.badges[1] = &models.Badge{
+		ID:       1,
+		Name:     "Speed Demon",
+		Criteria: json.RawMessage(`{"metric":"avg_ttfr","operator":"<","value":120}`),
+	}
+	badgeRepo.badges[2] = &models.Badge{
+		ID:       2,
+		Name:     "Review Veteran",
+		Criteria: json.RawMessage(`{"metric":"completed_reviews","operator":">=","value":20}`),
+	}
+	badgeRepo.badges[3] = &models.Badge{
+		ID:       3,
+		Name:     "Lightning",
+		Criteria: json.RawMessage(`{"metric":"avg_ttfr","operator":"<","value":10}`),
+	}
+
+	awarded, byBadge, err := service.EvaluateAllBadges(context.Background())
+	if err != nil {
+		t.Fatalf("EvaluateAllBadges failed: %v", err)
+	}
+
+	expected := map[string]int{"Speed Demon": 2, "Review Veteran": 1}
+	if !reflect.DeepEqual(byBadge, expected) {
+		t.Errorf("Awards by badge = %v, want %v", byBadge, expected)
+	}
+	if awarded != 3 {
+		t.Errorf("Expected 3 badges awarded, got %d", awarded)
+	}
+
+	// A second run awards nothing, since every qualifying badge is already earned
+	awarded, byBadge, err = service.EvaluateAllBadges(context.Background())
+	if err != nil {
+		t.Fatalf("EvaluateAllBadges failed: %v", err)
+	}
+	if awarded != 0 || len(byBadge) != 0 {
+		t.Errorf("Second run awarded %d (%v), want none", awarded, byBadge)
+	}
+}
+
+func TestEvaluateAllBadges_ParallelMatchesSequential(t *testing.T) {
+	run := func(concurrency int) (int, map[string]int, map[uint]map[uint]bool) {
+		service, badgeRepo, metricsRepo, userRepo := setupTestService()
+		service.log = logger.New("error", "text", "stdout")
+		service.SetConcurrency(concurrency)
+
+		for i := uint(1); i <= 200; i++ {
+			userID := i
+			ttfr := int(i % 240)
+			engagement := float64(i % 100)
+			userRepo.users = append(userRepo.users, models.User{ID: userID, Username: fmt.Sprintf("user-%d", userID), Team: "team-a"})
+			metricsRepo.metrics = append(metricsRepo.metrics, models.ReviewMetrics{
+				UserID:           &userID,
+				AvgTTFR:          &ttfr,
+				CompletedReviews: int(i % 30),
+				EngagementScore:  &engagement,
+			})
+		}
+		criteria := []string{
+			`{"metric":"avg_ttfr","operator":"<","value":120}`,
+			`{"metric":"completed_reviews","operator":">=","value":20}`,
+			`{"metric":"engagement_score","operator":">","value":75}`,
+			`{"metric":"avg_ttfr","operator":"<","value":0}`,
+		}
+		for i, c := range criteria {
+			id := uint(i + 1)
+			badgeRepo.badges[id] = &models.Badge{ID: id, Name: fmt.Sprintf("badge-%d", id), Criteria: json.RawMessage(c)}
+		}
+
+		awarded, byBadge, err := service.EvaluateAllBadges(context.Background())
+		if err != nil {
+			t.Fatalf("EvaluateAllBadges with concurrency %d failed: %v", concurrency, err)
+		}
+		return awarded, byBadge, badgeRepo.userBadges
+	}
+
+	seqAwarded, seqByBadge, seqUserBadges := run(1)
+	parAwarded, parByBadge, parUserBadges := run(8)
+
+	if seqAwarded == 0 {
+		t.Fatal("Expected the fixtures to award some badges")
+	}
+	if parAwarded != seqAwarded {
+		t.Errorf("Parallel run awarded %d badges, sequential run awarded %d", parAwarded, seqAwarded)
+	}
+	if !reflect.DeepEqual(parByBadge, seqByBadge) {
+		t.Errorf("Parallel awards by badge = %v, sequential = %v", parByBadge, seqByBadge)
+	}
+	if !reflect.DeepEqual(parUserBadges, seqUserBadges) {
+		t.Error("Parallel run awarded a different set of user badges than the sequential run")
+	}
+}
+
+func TestEvaluateAllBadges_ReportsProgressInBatches(t *testing.T) {
+	service, badgeRepo, metricsRepo, userRepo := setupTestService()
+	service.log = logger.New("error", "text", "stdout")
+	service.SetConcurrency(4)
+	service.SetBatchSize(100)
+
+	for i := uint(1); i <= 250; i++ {
+		userID := i
+		ttfr := 60
+		userRepo.users = append(userRepo.users, models.User{ID: userID, Username: fmt.Sprintf("user-%d", userID)})
+		metricsRepo.metrics = append(metricsRepo.metrics, models.ReviewMetrics{UserID: &userID, AvgTTFR: &ttfr})
+	}
+	badgeRepo.badges[1] = &models.Badge{
+		ID:       1,
+		Name:     "Speed Demon",
+		Criteria: json.RawMessage(`{"metric":"avg_ttfr","operator":"<","value":120}`),
+	}
+
+	var progress [][2]int
+	service.onProgress = func(processed, total int) {
+		progress = append(progress, [2]int{processed, total})
+	}
+
+	awarded, _, err := service.EvaluateAllBadges(context.Background())
+	if err != nil {
+		t.Fatalf("EvaluateAllBadges failed: %v", err)
+	}
+	if awarded != 250 {
+		t.Errorf("Expected 250 badges awarded, got %d", awarded)
+	}
+
+	want := [][2]int{{0, 250}, {100, 250}, {200, 250}, {250, 250}}
+	if !reflect.DeepEqual(progress, want) {
+		t.Errorf("Progress reports = %v, want %v", progress, want)
+	}
+	if processed := testutil.ToFloat64(prommetrics.BadgeEvaluationProgress.WithLabelValues("processed")); processed != 250 {
+		t.Errorf("Expected processed gauge = 250, got %f", processed)
+	}
+	if total := testutil.ToFloat64(prommetrics.BadgeEvaluationProgress.WithLabelValues("total")); total != 250 {
+		t.Errorf("Expected total gauge = 250, got %f", total)
+	}
+}
+
+// cancellingMetricsRepository cancels a context the first time metrics are read.
+type cancellingMetricsRepository struct {
+	*mockMetricsRepository
+	cancel context.CancelFunc
+	calls  int
+}
+
+func (m *cancellingMetricsRepository) GetMetricsByUser(userID uint, startDate, endDate time.Time) ([]models.ReviewMetrics, error) {
+	m.calls++
+	m.cancel()
+	return m.mockMetricsRepository.GetMetricsByUser(userID, startDate, endDate)
+}
+
+func TestEvaluateAllBadges_Cancelled(t *testing.T) {
+	t.Run("already cancelled", func(t *testing.T) {
+		service, badgeRepo, metricsRepo, userRepo := setupTestService()
+
+		userID := uint(1)
+		ttfr := 60
+		userRepo.users = []models.User{{ID: userID, Username: "alice"}}
+		metricsRepo.metrics = []models.ReviewMetrics{{UserID: &userID, AvgTTFR: &ttfr}}
+		badgeRepo.badges[1] = &models.Badge{
+			ID:       1,
+			Name:     "Speed Demon",
+			Criteria: json.RawMessage(`{"metric":"avg_ttfr","operator":"<","value":120}`),
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		awarded, _, err := service.EvaluateAllBadges(ctx)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("EvaluateAllBadges() error = %v, want %v", err, context.Canceled)
+		}
+		if awarded != 0 || len(badgeRepo.userBadges) != 0 {
+			t.Errorf("Expected no badges awarded after cancellation, got %d", awarded)
+		}
+	})
+
+	t.Run("cancelled mid-run", func(t *testing.T) {
+		service, badgeRepo, metricsRepo, userRepo := setupTestService()
+		service.log = logger.New("error", "text", "stdout")
+		service.SetConcurrency(1)
+
+		for i := uint(1); i <= 50; i++ {
+			userID := i
+			ttfr := 60
+			userRepo.users = append(userRepo.users, models.User{ID: userID, Username: fmt.Sprintf("user-%d", userID)})
+			metricsRepo.metrics = append(metricsRepo.metrics, models.ReviewMetrics{UserID: &userID, AvgTTFR: &ttfr})
+		}
+		badgeRepo.badges[1] = &models.Badge{
+			ID:       1,
+			Name:     "Speed Demon",
+			Criteria: json.RawMessage(`{"metric":"avg_ttfr","operator":"<","value":120}`),
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		cancelling := &cancellingMetricsRepository{mockMetricsRepository: metricsRepo, cancel: cancel}
+		service.metricsRepo = cancelling
+
+		_, _, err := service.EvaluateAllBadges(ctx)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("EvaluateAllBadges() error = %v, want %v", err, context.Canceled)
+		}
+		// No badge is evaluated after the context is cancelled
+		if cancelling.calls != 1 {
+			t.Errorf("Expected evaluation to stop promptly, metrics were read %d times", cancelling.calls)
+		}
+	})
+}
+
+func TestWorkerCount(t *testing.T) {
+	service, _, _, _ := setupTestService()
+
+	tests := []struct {
+		name        string
+		concurrency int
+		users       int
+		expected    int
+	}{
+		{"configured", 4, 100, 4},
+		{"capped by users", 8, 3, 3},
+		{"default uses GOMAXPROCS", 0, 1 << 20, runtime.GOMAXPROCS(0)},
+		{"no users", 4, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service.SetConcurrency(tt.concurrency)
+			if got := service.workerCount(tt.users); got != tt.expected {
+				t.Errorf("workerCount(%d) = %d, want %d", tt.users, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckCriteria_Streak(t *testing.T) {
+	service, _, metricsRepo, _ := setupTestService()
+
+	userID := uint(1)
+	otherUserID := uint(2)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	// Active 3 days, a gap, then 4 days in a row; one inactive row inside the run
+	// and another user's activity filling the gap must not count.
+	metricsRepo.metrics = []models.ReviewMetrics{
+		{UserID: &userID, Date: today.AddDate(0, 0, -9), CompletedReviews: 1},
+		{UserID: &userID, Date: today.AddDate(0, 0, -8), CompletedReviews: 2},
+		{UserID: &userID, Date: today.AddDate(0, 0, -7), CompletedReviews: 1},
+		{UserID: &otherUserID, Date: today.AddDate(0, 0, -6), CompletedReviews: 1},
+		{UserID: &userID, Date: today.AddDate(0, 0, -5), CompletedReviews: 1},
+		{UserID: &userID, Date: today.AddDate(0, 0, -4), CompletedReviews: 1},
+		{UserID: &userID, Date: today.AddDate(0, 0, -3), CompletedReviews: 1},
+		{UserID: &userID, Date: today.AddDate(0, 0, -2), CompletedReviews: 3},
+		{UserID: &userID, Date: today.AddDate(0, 0, -1), CompletedReviews: 0},
+	}
+
+	tests := []struct {
+		days     int
+		expected bool
+	}{
+		{3, true},
+		{4, true},
+		{5, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d days", tt.days), func(t *testing.T) {
+			var criteria models.BadgeCriteria
+			raw := fmt.Sprintf(`{"type":"streak","days":%d,"period":"month"}`, tt.days)
+			if err := json.Unmarshal([]byte(raw), &criteria); err != nil {
+				t.Fatalf("Failed to parse criteria: %v", err)
+			}
+
+			result, err := service.checkCriteria(context.Background(), &criteria, userID)
+			if err != nil {
+				t.Fatalf("checkCriteria failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %v for %d-day streak, got %v", tt.expected, tt.days, result)
+			}
+		})
+	}
+}
+
+func TestCheckCriteria_StreakInvalidDays(t *testing.T) {
+	service, _, _, _ := setupTestService()
+
+	criteria := &models.BadgeCriteria{Type: models.BadgeCriteriaTypeStreak, Days: 0}
+	if _, err := service.checkCriteria(context.Background(), criteria, 1); err == nil {
+		t.Error("Expected error for streak criteria without days")
+	}
+}
+
+func TestSimulateCriteria(t *testing.T) {
+	service, _, _, _ := setupTestService()
+
+	tests := []struct {
+		name     string
+		criteria *models.BadgeCriteria
+		input    SimulationInput
+		expected bool
+	}{
+		{
+			name:     "Metric passes",
+			criteria: &models.BadgeCriteria{Metric: "avg_ttfr", Operator: "<", Value: 120.0},
+			input:    SimulationInput{Metrics: map[string]float64{"avg_ttfr": 90}},
+			expected: true,
+		},
+		{
+			name:     "Metric missing",
+			criteria: &models.BadgeCriteria{Metric: "avg_ttfr", Operator: "<", Value: 120.0},
+			input:    SimulationInput{Metrics: map[string]float64{"engagement_score": 90}},
+			expected: false,
+		},
+		{
+			name:     "Top within rank",
+			criteria: &models.BadgeCriteria{Metric: "engagement_score", Operator: "top", Value: 3.0},
+			input:    SimulationInput{Rank: 2},
+			expected: true,
+		},
+		{
+			name:     "Top without rank",
+			criteria: &models.BadgeCriteria{Metric: "engagement_score", Operator: "top", Value: 3.0},
+			input:    SimulationInput{},
+			expected: false,
+		},
+		{
+			name:     "Streak long enough",
+			criteria: &models.BadgeCriteria{Type: models.BadgeCriteriaTypeStreak, Days: 5},
+			input:    SimulationInput{StreakDays: 5},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.SimulateCriteria(tt.criteria, tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+
+	if _, err := service.SimulateCriteria(&models.BadgeCriteria{Metric: "avg_ttfr", Operator: "!=", Value: 1.0},
+		SimulationInput{Metrics: map[string]float64{"avg_ttfr": 1}}); err == nil {
+		t.Error("Expected error for unsupported operator")
+	}
+}
+
+func TestGetBadgeProgress(t *testing.T) {
+	service, badgeRepo, metricsRepo, _ := setupTestService()
+
+	userID := uint(1)
+	otherUserID := uint(2)
+	badgeRepo.badges[1] = &models.Badge{ID: 1, Name: "Reviewer", Criteria: json.RawMessage(`{"metric":"completed_reviews","operator":">=","value":10}`)}
+	badgeRepo.badges[2] = &models.Badge{ID: 2, Name: "Speed Demon", Criteria: json.RawMessage(`{"metric":"avg_ttfr","operator":"<","value":60}`)}
+	badgeRepo.badges[3] = &models.Badge{ID: 3, Name: "Top Reviewer", Criteria: json.RawMessage(`{"metric":"completed_reviews","operator":"top","value":1}`)}
+	badgeRepo.badges[4] = &models.Badge{ID: 4, Name: "Streak", Criteria: json.RawMessage(`{"type":"streak","days":4}`)}
+	badgeRepo.badges[5] = &models.Badge{ID: 5, Name: "First Review", Criteria: json.RawMessage(`{"metric":"completed_reviews","operator":">=","value":1}`)}
+	badgeRepo.badges[6] = &models.Badge{ID: 6, Name: "Broken", Criteria: json.RawMessage(`{"metric":"completed_reviews","operator":"~","value":1}`)}
+	if err := badgeRepo.AwardBadge(userID, 5); err != nil {
+		t.Fatalf("AwardBadge failed: %v", err)
+	}
+
+	ttfr := 120
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	metricsRepo.metrics = []models.ReviewMetrics{
+		{UserID: &userID, Date: today.AddDate(0, 0, -2), CompletedReviews: 5, AvgTTFR: &ttfr},
+		{UserID: &userID, Date: today.AddDate(0, 0, -1), CompletedReviews: 3, AvgTTFR: &ttfr},
+		{UserID: &otherUserID, Date: today.AddDate(0, 0, -1), CompletedReviews: 20},
+	}
+
+	progress, err := service.GetBadgeProgress(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("GetBadgeProgress failed: %v", err)
+	}
+
+	// Earned and unparsable badges are left out
+	if len(progress) != 4 {
+		t.Fatalf("Expected progress for 4 badges, got %d", len(progress))
+	}
+
+	byName := make(map[string]BadgeProgress, len(progress))
+	for _, p := range progress {
+		byName[p.Badge.Name] = p
+	}
+
+	expected := map[string]BadgeProgress{
+		"Reviewer":     {Current: 8, Target: 10, Operator: ">=", Percent: 80, Applicable: true},
+		"Speed Demon":  {Current: 120, Target: 60, Operator: "<", Percent: 50, Applicable: true},
+		"Streak":       {Current: 2, Target: 4, Operator: "streak", Percent: 50, Applicable: true},
+		"Top Reviewer": {Current: 2, Target: 1, Operator: "top", Percent: 0, Applicable: false},
+	}
+	for name, want := range expected {
+		got, ok := byName[name]
+		if !ok {
+			t.Errorf("Missing progress for %s", name)
+			continue
+		}
+		if got.Current != want.Current || got.Target != want.Target || got.Operator != want.Operator ||
+			got.Percent != want.Percent || got.Applicable != want.Applicable {
+			t.Errorf("%s: expected %+v, got current=%v target=%v operator=%s percent=%v applicable=%v",
+				name, want, got.Current, got.Target, got.Operator, got.Percent, got.Applicable)
+		}
+	}
+
+	// Closest first, with not-applicable badges last
+	if progress[0].Badge.Name != "Reviewer" {
+		t.Errorf("Expected Reviewer first, got %s", progress[0].Badge.Name)
+	}
+	if progress[len(progress)-1].Badge.Name != "Top Reviewer" {
+		t.Errorf("Expected Top Reviewer last, got %s", progress[len(progress)-1].Badge.Name)
+	}
+}
+
+func TestGetBadgeProgress_NoMetrics(t *testing.T) {
+	service, badgeRepo, _, _ := setupTestService()
+
+	badgeRepo.badges[1] = &models.Badge{ID: 1, Name: "Speed Demon", Criteria: json.RawMessage(`{"metric":"avg_ttfr","operator":"<","value":60}`)}
+
+	progress, err := service.GetBadgeProgress(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("GetBadgeProgress failed: %v", err)
+	}
+	if len(progress) != 1 {
+		t.Fatalf("Expected progress for 1 badge, got %d", len(progress))
+	}
+	if progress[0].Current != 0 || progress[0].Percent != 0 {
+		t.Errorf("Expected no progress without metrics, got current=%v percent=%v", progress[0].Current, progress[0].Percent)
+	}
+}
+
+func TestGetBadgeProgress_SkipsHidden(t *testing.T) {
+	service, badgeRepo, metricsRepo, _ := setupTestService()
+
+	userID := uint(1)
+	badgeRepo.badges[1] = &models.Badge{ID: 1, Name: "Reviewer", Criteria: json.RawMessage(`{"metric":"completed_reviews","operator":">=","value":10}`)}
+	badgeRepo.badges[2] = &models.Badge{ID: 2, Name: "Secret", Hidden: true, Criteria: json.RawMessage(`{"metric":"completed_reviews","operator":">=","value":5}`)}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	metricsRepo.metrics = []models.ReviewMetrics{
+		{UserID: &userID, Date: today.AddDate(0, 0, -1), CompletedReviews: 3},
+	}
+
+	progress, err := service.GetBadgeProgress(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("GetBadgeProgress failed: %v", err)
+	}
+	if len(progress) != 1 || progress[0].Badge.Name != "Reviewer" {
+		t.Fatalf("Expected progress for Reviewer only, got %+v", progress)
+	}
+}
+
+func TestGetBadgeProgress_LowerIsBetterWithoutSamples(t *testing.T) {
+	service, badgeRepo, metricsRepo, _ := setupTestService()
+
+	userID := uint(1)
+	badgeRepo.badges[1] = &models.Badge{ID: 1, Name: "Speed Demon", Criteria: json.RawMessage(`{"metric":"avg_ttfr","operator":"<","value":60}`)}
+
+	// Completed reviews but no first response sample: avg_ttfr aggregates to 0
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	metricsRepo.metrics = []models.ReviewMetrics{
+		{UserID: &userID, Date: today.AddDate(0, 0, -1), CompletedReviews: 2},
+	}
+
+	progress, err := service.GetBadgeProgress(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("GetBadgeProgress failed: %v", err)
+	}
+	if len(progress) != 1 {
+		t.Fatalf("Expected progress for 1 badge, got %d", len(progress))
+	}
+	if progress[0].Percent != 0 {
+		t.Errorf("Expected 0%% without TTFR samples, got %v", progress[0].Percent)
+	}
+}
+
+func TestProgressPercent(t *testing.T) {
+	tests := []struct {
+		operator string
+		current  float64
+		target   float64
+		expected float64
+	}{
+		{">=", 8, 10, 80},
+		{">=", 15, 10, 100},
+		{">", 1, 3, 33.3},
+		{"<", 120, 60, 50},
+		{"<=", 30, 60, 100},
+		{"<=", 60, 60, 100},
+		{"<", 0, 60, 0},
+		{"<=", 0, 60, 0},
+		{"<", 90, 0, 0},
+		{"==", 5, 10, 50},
+		{"==", 20, 10, 50},
+		{">=", 0, 10, 0},
+		{">=", 5, 0, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v %s %v", tt.current, tt.operator, tt.target), func(t *testing.T) {
+			if got := progressPercent(tt.operator, tt.current, tt.target); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetPublicBadgeCatalog(t *testing.T) {
+	service, badgeRepo, _, _ := setupTestService()
+
+	badgeRepo.badges[1] = &models.Badge{ID: 1, Name: "Listed"}
+	badgeRepo.badges[2] = &models.Badge{ID: 2, Name: "Hidden", Hidden: true}
+	badgeRepo.badges[3] = &models.Badge{ID: 3, Name: "Rare", MinHolders: 2}
+	badgeRepo.badges[4] = &models.Badge{ID: 4, Name: "Popular", MinHolders: 2}
+	for _, award := range [][2]uint{{1, 2}, {1, 3}, {1, 4}, {2, 4}} {
+		if err := badgeRepo.AwardBadge(award[0], award[1]); err != nil {
+			t.Fatalf("AwardBadge failed: %v", err)
+		}
+	}
+
+	catalog, err := service.GetPublicBadgeCatalog(context.Background())
+	if err != nil {
+		t.Fatalf("GetPublicBadgeCatalog failed: %v", err)
+	}
+
+	names := make(map[string]bool, len(catalog))
+	for _, badge := range catalog {
+		names[badge.Name] = true
+	}
+	expected := map[string]bool{"Listed": true, "Popular": true}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected public catalog %v, got %v", expected, names)
+	}
+
+	// The full catalog still lists every badge
+	all, err := service.GetBadgeCatalog(context.Background())
+	if err != nil {
+		t.Fatalf("GetBadgeCatalog failed: %v", err)
+	}
+	if len(all) != 4 {
+		t.Errorf("Expected 4 badges in the full catalog, got %d", len(all))
+	}
+}
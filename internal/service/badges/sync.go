@@ -0,0 +1,117 @@
+package badges
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+)
+
+// SyncFromConfig makes the badge catalog match the configured badges: badges are
+// matched by name, created when missing and updated when their description, icon
+// or criteria changed. Badges no longer in the config are kept, as users may hold them.
+// When announcements are enabled, created badges are announced once the sync is done,
+// except on the first sync into an empty catalog.
+func (s *Service) SyncFromConfig(ctx context.Context, configured []config.BadgeConfig) error {
+	existing, err := s.badgeRepo.GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to get badges: %w", err)
+	}
+
+	byName := make(map[string]*models.Badge, len(existing))
+	for i := range existing {
+		byName[existing[i].Name] = &existing[i]
+	}
+
+	created, updated := 0, 0
+	var announce []*models.Badge
+	for _, badgeCfg := range configured {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		criteria, err := json.Marshal(badgeCfg.Criteria)
+		if err != nil {
+			return fmt.Errorf("failed to encode criteria of badge %s: %w", badgeCfg.Name, err)
+		}
+
+		badge, ok := byName[badgeCfg.Name]
+		if !ok {
+			badge = &models.Badge{
+				Name:        badgeCfg.Name,
+				Description: badgeCfg.Description,
+				Icon:        badgeCfg.Icon,
+				Criteria:    criteria,
+			}
+			if err := s.badgeRepo.Create(badge); err != nil {
+				return fmt.Errorf("failed to create badge %s: %w", badgeCfg.Name, err)
+			}
+			created++
+			if s.announceNew && len(existing) > 0 {
+				announce = append(announce, badge)
+			}
+			continue
+		}
+
+		if badge.Description == badgeCfg.Description && badge.Icon == badgeCfg.Icon && sameJSON(badge.Criteria, criteria) {
+			continue
+		}
+		badge.Description = badgeCfg.Description
+		badge.Icon = badgeCfg.Icon
+		badge.Criteria = criteria
+		if err := s.badgeRepo.Update(badge); err != nil {
+			return fmt.Errorf("failed to update badge %s: %w", badgeCfg.Name, err)
+		}
+		updated++
+	}
+
+	configuredNames := make(map[string]bool, len(configured))
+	for _, badgeCfg := range configured {
+		configuredNames[badgeCfg.Name] = true
+	}
+	for name := range byName {
+		if !configuredNames[name] {
+			s.log.Info().Str("badge", name).Msg("Badge is not in the config, keeping it")
+		}
+	}
+
+	s.log.Info().
+		Int("configured", len(configured)).
+		Int("created", created).
+		Int("updated", updated).
+		Msg("Badges synced from config")
+
+	for _, badge := range announce {
+		s.notifyNewBadge(badge)
+	}
+
+	return nil
+}
+
+// notifyNewBadge posts a Mattermost announcement for a badge added to the catalog.
+// Failures are logged and never affect the sync.
+func (s *Service) notifyNewBadge(badge *models.Badge) {
+	if s.mattermost == nil {
+		return
+	}
+
+	if err := s.mattermost.SendNewBadgeAnnouncement(badge.Name, badge.Description, badge.Icon); err != nil {
+		s.log.Warn().
+			Err(err).
+			Str("badge", badge.Name).
+			Msg("Failed to send new badge announcement")
+	}
+}
+
+// sameJSON reports whether two JSON documents hold the same value, ignoring key order
+// and formatting (the database may normalize stored JSON).
+func sameJSON(a, b json.RawMessage) bool {
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -0,0 +1,184 @@
+// Package gauges rebuilds Prometheus gauges from database state.
+package gauges
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
+	prommetrics "github.com/aimd54/gitlab-reviewer-roulette/internal/metrics"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+)
+
+// BadgeRepository interface for badge holder counts.
+type BadgeRepository interface {
+	GetAll() ([]models.Badge, error)
+	GetHolderCounts() (map[uint]int64, error)
+}
+
+// ReviewRepository interface for active review counts.
+type ReviewRepository interface {
+	CountActiveReviewsByUser() (map[uint]int64, error)
+}
+
+// UserRepository interface for user lookups.
+type UserRepository interface {
+	List(team, role string) ([]models.User, error)
+}
+
+// OOORepository interface for out-of-office lookups.
+type OOORepository interface {
+	GetAllActive() ([]models.OOOStatus, error)
+}
+
+// Result reports how many gauge series a rebuild set.
+type Result struct {
+	BadgeHolders       int `json:"badge_holders"`       // Badges with a holder count
+	ActiveReviews      int `json:"active_reviews"`      // Users with at least one active review
+	AvailableReviewers int `json:"available_reviewers"` // Team/role pairs with a reviewer count
+}
+
+// Service recomputes gauges that are otherwise only updated as events happen,
+// so they are correct right after a restart.
+type Service struct {
+	badgeRepo     BadgeRepository
+	reviewRepo    ReviewRepository
+	userRepo      UserRepository
+	oooRepo       OOORepository
+	excludedUsers config.ExcludedUsersConfig
+	log           *logger.Logger
+}
+
+// NewService creates a new gauge rebuild service.
+func NewService(
+	cfg *config.Config,
+	badgeRepo *repository.BadgeRepository,
+	reviewRepo *repository.ReviewRepository,
+	userRepo *repository.UserRepository,
+	oooRepo *repository.OOORepository,
+	log *logger.Logger,
+) *Service {
+	return NewServiceWithInterfaces(cfg, badgeRepo, reviewRepo, userRepo, oooRepo, log)
+}
+
+// NewServiceWithInterfaces creates a new gauge rebuild service with interface dependencies (useful for testing).
+func NewServiceWithInterfaces(
+	cfg *config.Config,
+	badgeRepo BadgeRepository,
+	reviewRepo ReviewRepository,
+	userRepo UserRepository,
+	oooRepo OOORepository,
+	log *logger.Logger,
+) *Service {
+	return &Service{
+		badgeRepo:     badgeRepo,
+		reviewRepo:    reviewRepo,
+		userRepo:      userRepo,
+		oooRepo:       oooRepo,
+		excludedUsers: cfg.ExcludedUsers,
+		log:           log,
+	}
+}
+
+// gaugeValues holds every gauge value computed from the database before any is published.
+type gaugeValues struct {
+	badgeHolders       map[string]int64
+	activeReviews      map[[2]string]int64 // team, username
+	availableReviewers map[[2]string]int   // team, role
+}
+
+// Rebuild recomputes the active review, available reviewer and badge holder gauges
+// from the database. All values are read before the gauges are reset, so a failed
+// rebuild leaves the current values in place.
+func (s *Service) Rebuild(ctx context.Context) (*Result, error) {
+	values, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	prommetrics.ResetDatabaseGauges()
+	for badgeName, count := range values.badgeHolders {
+		prommetrics.SetActiveBadgeHolders(badgeName, int(count))
+	}
+	for key, count := range values.activeReviews {
+		prommetrics.SetActiveReviews(key[0], key[1], int(count))
+	}
+	for key, count := range values.availableReviewers {
+		prommetrics.SetAvailableReviewers(key[0], key[1], count)
+	}
+
+	result := &Result{
+		BadgeHolders:       len(values.badgeHolders),
+		ActiveReviews:      len(values.activeReviews),
+		AvailableReviewers: len(values.availableReviewers),
+	}
+	s.log.Info().
+		Int("badge_holders", result.BadgeHolders).
+		Int("active_reviews", result.ActiveReviews).
+		Int("available_reviewers", result.AvailableReviewers).
+		Msg("Rebuilt Prometheus gauges from database")
+
+	return result, nil
+}
+
+// load reads every gauge value from the database.
+func (s *Service) load(ctx context.Context) (*gaugeValues, error) {
+	badges, err := s.badgeRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get badges: %w", err)
+	}
+	holderCounts, err := s.badgeRepo.GetHolderCounts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get badge holder counts: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	users, err := s.userRepo.List("", "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	activeCounts, err := s.reviewRepo.CountActiveReviewsByUser()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count active reviews: %w", err)
+	}
+	activeOOO, err := s.oooRepo.GetAllActive()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active OOO statuses: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	outOfOffice := make(map[uint]bool, len(activeOOO))
+	for _, status := range activeOOO {
+		outOfOffice[status.UserID] = true
+	}
+
+	values := &gaugeValues{
+		badgeHolders:       make(map[string]int64, len(badges)),
+		activeReviews:      make(map[[2]string]int64),
+		availableReviewers: make(map[[2]string]int),
+	}
+	// Every badge is exported, including those nobody holds yet
+	for _, badge := range badges {
+		values.badgeHolders[badge.Name] = holderCounts[badge.ID]
+	}
+	for _, user := range users {
+		if s.excludedUsers.ExcludesUser(&user) {
+			continue
+		}
+		if count := activeCounts[user.ID]; count > 0 {
+			values.activeReviews[[2]string{user.Team, user.Username}] = count
+		}
+		// Users outside any team are never selected as reviewers
+		if user.Team != "" && !outOfOffice[user.ID] {
+			values.availableReviewers[[2]string{user.Team, user.Role}]++
+		}
+	}
+
+	return values, nil
+}
@@ -0,0 +1,133 @@
+package gauges
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
+	prommetrics "github.com/aimd54/gitlab-reviewer-roulette/internal/metrics"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+)
+
+func setupTestService(t *testing.T, cfg *config.Config) (*Service, *repository.DB) {
+	t.Helper()
+
+	gormDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gormDB.AutoMigrate(
+		&models.User{},
+		&models.Badge{},
+		&models.UserBadge{},
+		&models.MRReview{},
+		&models.ReviewerAssignment{},
+		&models.OOOStatus{},
+	))
+	t.Cleanup(func() {
+		sqlDB, _ := gormDB.DB()
+		_ = sqlDB.Close()
+	})
+
+	db := &repository.DB{DB: gormDB}
+	service := NewService(
+		cfg,
+		repository.NewBadgeRepository(db),
+		repository.NewReviewRepository(db),
+		repository.NewUserRepository(db),
+		repository.NewOOORepository(db),
+		logger.New("error", "text", "stdout"),
+	)
+	return service, db
+}
+
+func createUser(t *testing.T, db *repository.DB, gitlabID int, username, team, role string) *models.User {
+	t.Helper()
+	user := &models.User{GitLabID: gitlabID, Username: username, Team: team, Role: role}
+	require.NoError(t, db.Create(user).Error)
+	return user
+}
+
+func createBadge(t *testing.T, db *repository.DB, name string) *models.Badge {
+	t.Helper()
+	badge := &models.Badge{Name: name, Criteria: json.RawMessage(`{"metric":"completed_reviews","operator":">=","value":10}`)}
+	require.NoError(t, db.Create(badge).Error)
+	return badge
+}
+
+func createReview(t *testing.T, db *repository.DB, mrIID int, status string, reviewers ...*models.User) {
+	t.Helper()
+	review := &models.MRReview{GitLabMRIID: mrIID, GitLabProjectID: 1, Status: status, Team: "team-backend"}
+	require.NoError(t, db.Create(review).Error)
+	for _, reviewer := range reviewers {
+		require.NoError(t, db.Create(&models.ReviewerAssignment{MRReviewID: review.ID, UserID: reviewer.ID, Role: models.ReviewerRoleTeamMember}).Error)
+	}
+}
+
+func TestRebuild_BadgeHoldersMatchDatabase(t *testing.T) {
+	service, db := setupTestService(t, &config.Config{})
+	badgeRepo := repository.NewBadgeRepository(db)
+
+	alice := createUser(t, db, 1, "alice", "team-backend", "dev")
+	bob := createUser(t, db, 2, "bob", "team-backend", "dev")
+	speedDemon := createBadge(t, db, "speed_demon")
+	mentor := createBadge(t, db, "mentor")
+	createBadge(t, db, "consistency")
+	require.NoError(t, badgeRepo.AwardBadge(alice.ID, speedDemon.ID))
+	require.NoError(t, badgeRepo.AwardBadge(bob.ID, speedDemon.ID))
+	require.NoError(t, badgeRepo.AwardBadge(bob.ID, mentor.ID))
+
+	// Simulate a restart: gauges start at zero, with a leftover series for a deleted badge
+	prommetrics.ResetDatabaseGauges()
+	prommetrics.SetActiveBadgeHolders("retired_badge", 7)
+
+	result, err := service.Rebuild(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.BadgeHolders)
+	// Every current badge is exported and the deleted badge's series is gone
+	assert.Equal(t, 3, testutil.CollectAndCount(prommetrics.ActiveBadgeHolders))
+
+	for _, badge := range []*models.Badge{speedDemon, mentor} {
+		count, err := badgeRepo.GetBadgeHoldersCount(badge.ID)
+		require.NoError(t, err)
+		assert.Equal(t, float64(count), testutil.ToFloat64(prommetrics.ActiveBadgeHolders.WithLabelValues(badge.Name)), badge.Name)
+	}
+	assert.Equal(t, float64(0), testutil.ToFloat64(prommetrics.ActiveBadgeHolders.WithLabelValues("consistency")))
+}
+
+func TestRebuild_ActiveReviewsAndAvailableReviewers(t *testing.T) {
+	cfg := &config.Config{ExcludedUsers: config.ExcludedUsersConfig{Usernames: []string{"renovate-bot"}}}
+	service, db := setupTestService(t, cfg)
+
+	alice := createUser(t, db, 1, "alice", "team-backend", "dev")
+	bob := createUser(t, db, 2, "bob", "team-backend", "dev")
+	carol := createUser(t, db, 3, "carol", "team-backend", "ops")
+	bot := createUser(t, db, 4, "renovate-bot", "team-backend", "dev")
+
+	createReview(t, db, 1, models.MRStatusPending, alice, bob)
+	createReview(t, db, 2, models.MRStatusInReview, alice, bot)
+	createReview(t, db, 3, models.MRStatusMerged, bob)
+
+	// Carol is out of office today
+	now := time.Now()
+	require.NoError(t, db.Create(&models.OOOStatus{UserID: carol.ID, StartDate: now.Add(-time.Hour), EndDate: now.Add(24 * time.Hour)}).Error)
+
+	prommetrics.ResetDatabaseGauges()
+	result, err := service.Rebuild(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.ActiveReviews)
+	assert.Equal(t, float64(2), testutil.ToFloat64(prommetrics.ActiveReviews.WithLabelValues("team-backend", "alice")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(prommetrics.ActiveReviews.WithLabelValues("team-backend", "bob")))
+
+	assert.Equal(t, 1, result.AvailableReviewers)
+	assert.Equal(t, float64(2), testutil.ToFloat64(prommetrics.AvailableReviewers.WithLabelValues("team-backend", "dev")))
+}
@@ -0,0 +1,353 @@
+// Package importer seeds review history and metrics from existing GitLab merge requests,
+// so new deployments do not start with empty leaderboards.
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	gitlabapi "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/gitlab"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/aggregator"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+)
+
+// GitLabClient interface for the GitLab calls used by the importer.
+type GitLabClient interface {
+	ListMergedMergeRequests(projectID int, since time.Time, page int) ([]*gitlabapi.BasicMergeRequest, int, error)
+	GetMergeRequestNotes(projectID, mrIID int) ([]*gitlabapi.Note, error)
+}
+
+// ReviewRepository interface for review operations.
+type ReviewRepository interface {
+	GetMRReview(projectID, mrIID int) (*models.MRReview, error)
+	CreateMRReview(review *models.MRReview) error
+}
+
+// UserRepository interface for user operations.
+type UserRepository interface {
+	GetByGitLabID(gitlabID int) (*models.User, error)
+}
+
+// CheckpointRepository interface for persisting import progress.
+type CheckpointRepository interface {
+	GetValue(key string, dest interface{}) (bool, error)
+	SetValue(key string, value interface{}) error
+}
+
+// Aggregator interface for backfilling metrics.
+type Aggregator interface {
+	AggregateRange(ctx context.Context, start, end time.Time) error
+}
+
+// Checkpoint records import progress for a project so interrupted imports can resume.
+// NextPage never moves past a page with failed merge requests, so they are retried on the next run.
+type Checkpoint struct {
+	Since     time.Time `json:"since"`
+	NextPage  int       `json:"next_page"`
+	Completed bool      `json:"completed"`
+	Imported  int       `json:"imported"`
+}
+
+// Result summarizes an import run.
+type Result struct {
+	Imported int
+	Skipped  int // Already tracked or not merged
+	Failed   int // Could not be imported, retried on the next run
+}
+
+// Service imports merged merge requests from GitLab and backfills metrics.
+type Service struct {
+	gitlabClient   GitLabClient
+	reviewRepo     ReviewRepository
+	userRepo       UserRepository
+	checkpointRepo CheckpointRepository
+	aggregator     Aggregator
+	now            func() time.Time
+	log            *logger.Logger
+}
+
+// NewService creates a new importer service with concrete types.
+func NewService(
+	gitlabClient *gitlab.Client,
+	reviewRepo *repository.ReviewRepository,
+	userRepo *repository.UserRepository,
+	checkpointRepo *repository.ConfigurationRepository,
+	aggregatorService *aggregator.Service,
+	log *logger.Logger,
+) *Service {
+	return NewServiceWithInterfaces(gitlabClient, reviewRepo, userRepo, checkpointRepo, aggregatorService, log)
+}
+
+// NewServiceWithInterfaces creates a new importer service with interface dependencies (useful for testing).
+func NewServiceWithInterfaces(
+	gitlabClient GitLabClient,
+	reviewRepo ReviewRepository,
+	userRepo UserRepository,
+	checkpointRepo CheckpointRepository,
+	aggregatorService Aggregator,
+	log *logger.Logger,
+) *Service {
+	return &Service{
+		gitlabClient:   gitlabClient,
+		reviewRepo:     reviewRepo,
+		userRepo:       userRepo,
+		checkpointRepo: checkpointRepo,
+		aggregator:     aggregatorService,
+		now:            time.Now,
+		log:            log,
+	}
+}
+
+// Import imports merged MRs for each project since the given time, then backfills
+// daily metrics for the whole range. Projects already fully imported for the same
+// start time are skipped; interrupted imports resume from their last page.
+func (s *Service) Import(ctx context.Context, projectIDs []int, since time.Time) (*Result, error) {
+	total := &Result{}
+
+	for _, projectID := range projectIDs {
+		result, err := s.ImportProject(ctx, projectID, since)
+		if err != nil {
+			return total, fmt.Errorf("failed to import project %d: %w", projectID, err)
+		}
+		total.Imported += result.Imported
+		total.Skipped += result.Skipped
+		total.Failed += result.Failed
+	}
+
+	if err := s.aggregator.AggregateRange(ctx, since, s.now()); err != nil {
+		return total, fmt.Errorf("failed to backfill metrics: %w", err)
+	}
+
+	s.log.Info().
+		Int("projects", len(projectIDs)).
+		Int("imported", total.Imported).
+		Int("skipped", total.Skipped).
+		Int("failed", total.Failed).
+		Msg("History import completed")
+
+	return total, nil
+}
+
+// ImportProject imports merged MRs of one project page by page, saving a checkpoint after each page.
+// Once a page has failures the checkpoint stays on it: later pages are still imported, and the
+// next run resumes from the failed page, skipping the MRs imported since.
+func (s *Service) ImportProject(ctx context.Context, projectID int, since time.Time) (*Result, error) {
+	key := checkpointKey(projectID)
+	result := &Result{}
+
+	checkpoint := Checkpoint{Since: since, NextPage: 1}
+	var saved Checkpoint
+	found, err := s.checkpointRepo.GetValue(key, &saved)
+	if err != nil {
+		return nil, err
+	}
+	if found && saved.Since.Equal(since) {
+		if saved.Completed {
+			s.log.Info().Int("project_id", projectID).Msg("Project history already imported, skipping")
+			return result, nil
+		}
+		checkpoint = saved
+		s.log.Info().
+			Int("project_id", projectID).
+			Int("page", checkpoint.NextPage).
+			Msg("Resuming project history import")
+	}
+
+	failedPage := false
+	for page := checkpoint.NextPage; page > 0; {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		mrs, nextPage, err := s.gitlabClient.ListMergedMergeRequests(projectID, since, page)
+		if err != nil {
+			return result, err
+		}
+
+		pageImported, pageFailed := 0, 0
+		for _, mr := range mrs {
+			imported, err := s.importMergeRequest(projectID, mr)
+			switch {
+			case err != nil:
+				s.log.Warn().
+					Err(err).
+					Int("project_id", projectID).
+					Int("mr_iid", mr.IID).
+					Msg("Failed to import merge request, will retry on the next run")
+				pageFailed++
+			case imported:
+				pageImported++
+			default:
+				result.Skipped++
+			}
+		}
+
+		result.Imported += pageImported
+		result.Failed += pageFailed
+		// After a failure NextPage keeps pointing at the first failed page
+		failedPage = failedPage || pageFailed > 0
+		if !failedPage {
+			checkpoint.NextPage = nextPage
+			checkpoint.Completed = nextPage == 0
+		}
+		checkpoint.Imported += pageImported
+		if err := s.checkpointRepo.SetValue(key, checkpoint); err != nil {
+			return result, err
+		}
+
+		s.log.Info().
+			Int("project_id", projectID).
+			Int("page", page).
+			Int("merge_requests", len(mrs)).
+			Msg("Imported merge request page")
+
+		page = nextPage
+	}
+
+	return result, nil
+}
+
+// importMergeRequest stores a merged MR with its reviewer assignments.
+// MRs already tracked (imported earlier or recorded live) are left untouched.
+func (s *Service) importMergeRequest(projectID int, mr *gitlabapi.BasicMergeRequest) (bool, error) {
+	if mr.MergedAt == nil || mr.CreatedAt == nil {
+		return false, nil
+	}
+
+	_, err := s.reviewRepo.GetMRReview(projectID, mr.IID)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return false, err
+	}
+
+	notes, err := s.gitlabClient.GetMergeRequestNotes(projectID, mr.IID)
+	if err != nil {
+		return false, err
+	}
+
+	authorID := 0
+	if mr.Author != nil {
+		authorID = mr.Author.ID
+	}
+	activity := collectReviewerActivity(notes, authorID)
+
+	review := &models.MRReview{
+		GitLabMRIID:         mr.IID,
+		GitLabProjectID:     projectID,
+		MRURL:               mr.WebURL,
+		MRTitle:             mr.Title,
+		RouletteTriggeredAt: mr.CreatedAt,
+		MergedAt:            mr.MergedAt,
+		Status:              models.MRStatusMerged,
+	}
+
+	if author := s.lookupUser(authorID); author != nil {
+		review.MRAuthorID = &author.ID
+		review.Team = author.Team
+	}
+
+	for _, gitlabID := range reviewerIDs(mr, activity, authorID) {
+		user := s.lookupUser(gitlabID)
+		if user == nil {
+			continue
+		}
+		if review.Team == "" {
+			review.Team = user.Team
+		}
+
+		assignment := models.ReviewerAssignment{
+			UserID:     user.ID,
+			Role:       models.ReviewerRoleTeamMember,
+			AssignedAt: *mr.CreatedAt,
+		}
+		if a, ok := activity[gitlabID]; ok {
+			firstCommentAt := a.firstCommentAt
+			assignment.FirstCommentAt = &firstCommentAt
+			assignment.CommentCount = a.count
+			assignment.CommentLength = a.length
+
+			if review.FirstReviewAt == nil || firstCommentAt.Before(*review.FirstReviewAt) {
+				review.FirstReviewAt = &firstCommentAt
+			}
+		}
+		review.Assignments = append(review.Assignments, assignment)
+	}
+
+	// Assignments are created together with the review, so a partial import never leaves orphans
+	if err := s.reviewRepo.CreateMRReview(review); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// lookupUser returns the known user for a GitLab ID, or nil.
+func (s *Service) lookupUser(gitlabID int) *models.User {
+	if gitlabID == 0 {
+		return nil
+	}
+	user, err := s.userRepo.GetByGitLabID(gitlabID)
+	if err != nil {
+		return nil
+	}
+	return user
+}
+
+// reviewerActivity is the comment activity of one reviewer on an MR.
+type reviewerActivity struct {
+	count          int
+	length         int
+	firstCommentAt time.Time
+}
+
+// collectReviewerActivity groups non-system comments by author, ignoring the MR author.
+func collectReviewerActivity(notes []*gitlabapi.Note, authorID int) map[int]reviewerActivity {
+	activity := make(map[int]reviewerActivity)
+	for _, note := range notes {
+		if note.System || note.CreatedAt == nil || note.Author.ID == authorID {
+			continue
+		}
+
+		a := activity[note.Author.ID]
+		a.count++
+		a.length += len(note.Body)
+		if a.firstCommentAt.IsZero() || note.CreatedAt.Before(a.firstCommentAt) {
+			a.firstCommentAt = *note.CreatedAt
+		}
+		activity[note.Author.ID] = a
+	}
+	return activity
+}
+
+// reviewerIDs returns the MR's reviewers, falling back to commenters when none were assigned.
+func reviewerIDs(mr *gitlabapi.BasicMergeRequest, activity map[int]reviewerActivity, authorID int) []int {
+	var ids []int
+	for _, reviewer := range mr.Reviewers {
+		if reviewer != nil && reviewer.ID != authorID {
+			ids = append(ids, reviewer.ID)
+		}
+	}
+
+	if len(ids) == 0 {
+		for id := range activity {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+	}
+
+	return ids
+}
+
+// checkpointKey returns the configuration key holding a project's import checkpoint.
+func checkpointKey(projectID int) string {
+	return fmt.Sprintf("history_import:project:%d", projectID)
+}
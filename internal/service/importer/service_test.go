@@ -0,0 +1,251 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gitlabapi "gitlab.com/gitlab-org/api/client-go"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/aggregator"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+)
+
+// mockGitLabClient serves merged MRs in fixed pages.
+type mockGitLabClient struct {
+	pages         [][]*gitlabapi.BasicMergeRequest
+	notes         map[int][]*gitlabapi.Note
+	failPage      int          // page returning an error once
+	failNotes     map[int]bool // MR IIDs whose notes fail to load once
+	requestedPage []int
+}
+
+func (m *mockGitLabClient) ListMergedMergeRequests(projectID int, since time.Time, page int) ([]*gitlabapi.BasicMergeRequest, int, error) {
+	m.requestedPage = append(m.requestedPage, page)
+	if page == m.failPage {
+		m.failPage = 0
+		return nil, 0, errors.New("gitlab unavailable")
+	}
+
+	nextPage := page + 1
+	if nextPage > len(m.pages) {
+		nextPage = 0
+	}
+	return m.pages[page-1], nextPage, nil
+}
+
+func (m *mockGitLabClient) GetMergeRequestNotes(projectID, mrIID int) ([]*gitlabapi.Note, error) {
+	if m.failNotes[mrIID] {
+		delete(m.failNotes, mrIID)
+		return nil, errors.New("notes unavailable")
+	}
+	return m.notes[mrIID], nil
+}
+
+func setupImporter(t *testing.T, client *mockGitLabClient) (*Service, *gorm.DB, *repository.MetricsRepository) {
+	t.Helper()
+
+	gormDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gormDB.AutoMigrate(
+		&models.User{},
+		&models.MRReview{},
+		&models.ReviewerAssignment{},
+		&models.ReviewMetrics{},
+		&models.Configuration{},
+	))
+	require.NoError(t, repository.CreateMetricsIndexes(gormDB))
+	db := &repository.DB{DB: gormDB}
+
+	reviewRepo := repository.NewReviewRepository(db)
+	metricsRepo := repository.NewMetricsRepository(db)
+	zl := zerolog.Nop()
+
+	service := NewServiceWithInterfaces(
+		client,
+		reviewRepo,
+		repository.NewUserRepository(db),
+		repository.NewConfigurationRepository(db),
+		aggregator.NewService(reviewRepo, metricsRepo, &zl),
+		logger.New("error", "json", "stdout"),
+	)
+
+	return service, gormDB, metricsRepo
+}
+
+func mergedMR(iid, authorID, reviewerID int, createdAt time.Time) *gitlabapi.BasicMergeRequest {
+	mergedAt := createdAt.Add(4 * time.Hour)
+	return &gitlabapi.BasicMergeRequest{
+		IID:       iid,
+		Title:     fmt.Sprintf("MR %d", iid),
+		WebURL:    fmt.Sprintf("https://gitlab.example.com/project/mr/%d", iid),
+		CreatedAt: &createdAt,
+		MergedAt:  &mergedAt,
+		Author:    &gitlabapi.BasicUser{ID: authorID},
+		Reviewers: []*gitlabapi.BasicUser{{ID: reviewerID}},
+	}
+}
+
+func comment(authorID int, body string, at time.Time) *gitlabapi.Note {
+	return &gitlabapi.Note{Author: gitlabapi.NoteAuthor{ID: authorID}, Body: body, CreatedAt: &at}
+}
+
+func TestImport_BackfillsMetrics(t *testing.T) {
+	day1 := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	client := &mockGitLabClient{
+		pages: [][]*gitlabapi.BasicMergeRequest{
+			{mergedMR(1, 101, 102, day1), mergedMR(2, 102, 101, day1)},
+			{mergedMR(3, 101, 102, day2)},
+		},
+		notes: map[int][]*gitlabapi.Note{
+			1: {comment(102, "Looks good, one nit", day1.Add(30*time.Minute)), comment(101, "Fixed", day1.Add(time.Hour))},
+			3: {comment(102, "Please add a test", day2.Add(time.Hour))},
+		},
+		failPage: 2,
+	}
+
+	service, gormDB, metricsRepo := setupImporter(t, client)
+	require.NoError(t, gormDB.Create(&models.User{GitLabID: 101, Username: "alice", Team: "team-frontend"}).Error)
+	require.NoError(t, gormDB.Create(&models.User{GitLabID: 102, Username: "bob", Team: "team-frontend"}).Error)
+
+	since := day1.AddDate(0, 0, -1)
+	service.now = func() time.Time { return day2.Add(12 * time.Hour) }
+
+	// The first run stops at the failing page after checkpointing page 1
+	_, err := service.Import(context.Background(), []int{42}, since)
+	require.Error(t, err)
+
+	// The second run resumes at page 2 instead of starting over
+	result, err := service.Import(context.Background(), []int{42}, since)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Imported)
+	assert.Equal(t, []int{1, 2, 2}, client.requestedPage)
+
+	var reviews []models.MRReview
+	require.NoError(t, gormDB.Preload("Assignments").Find(&reviews).Error)
+	require.Len(t, reviews, 3)
+	for _, review := range reviews {
+		assert.Equal(t, "team-frontend", review.Team)
+		assert.Equal(t, models.MRStatusMerged, review.Status)
+		assert.Len(t, review.Assignments, 1)
+	}
+
+	// Daily team metrics exist for both days
+	teamDay1, err := metricsRepo.GetByDate(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), "team-frontend", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, teamDay1.TotalReviews)
+	assert.Equal(t, 2, teamDay1.CompletedReviews)
+	require.NotNil(t, teamDay1.AvgTTFR)
+
+	teamDay2, err := metricsRepo.GetByDate(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), "team-frontend", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, teamDay2.TotalReviews)
+
+	// Re-running a completed import is a no-op
+	result, err = service.Import(context.Background(), []int{42}, since)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Imported)
+	assert.Equal(t, []int{1, 2, 2}, client.requestedPage)
+
+	var count int64
+	require.NoError(t, gormDB.Model(&models.MRReview{}).Count(&count).Error)
+	assert.Equal(t, int64(3), count)
+	require.NoError(t, gormDB.Model(&models.User{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count, "import must not create users")
+}
+
+func TestImport_RetriesFailedMergeRequests(t *testing.T) {
+	day := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	client := &mockGitLabClient{
+		pages: [][]*gitlabapi.BasicMergeRequest{
+			{mergedMR(1, 101, 102, day), mergedMR(2, 102, 101, day)},
+			{mergedMR(3, 101, 102, day)},
+		},
+		failNotes: map[int]bool{2: true},
+	}
+
+	service, gormDB, _ := setupImporter(t, client)
+	require.NoError(t, gormDB.Create(&models.User{GitLabID: 101, Username: "alice", Team: "team-frontend"}).Error)
+	require.NoError(t, gormDB.Create(&models.User{GitLabID: 102, Username: "bob", Team: "team-frontend"}).Error)
+	since := day.AddDate(0, 0, -1)
+	service.now = func() time.Time { return day.Add(12 * time.Hour) }
+
+	// Later pages are still imported, but the failure is not counted as a skip
+	result, err := service.Import(context.Background(), []int{42}, since)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Imported: 2, Failed: 1}, *result)
+
+	var checkpoint Checkpoint
+	found, err := repository.NewConfigurationRepository(&repository.DB{DB: gormDB}).GetValue(checkpointKey(42), &checkpoint)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, 1, checkpoint.NextPage, "checkpoint must not move past the failed page")
+	assert.False(t, checkpoint.Completed)
+
+	// The next run resumes at the failed page and imports the MR
+	result, err = service.Import(context.Background(), []int{42}, since)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Imported: 1, Skipped: 2}, *result)
+	assert.Equal(t, []int{1, 2, 1, 2}, client.requestedPage)
+
+	var count int64
+	require.NoError(t, gormDB.Model(&models.MRReview{}).Count(&count).Error)
+	assert.Equal(t, int64(3), count)
+
+	// Now complete, a further run is a no-op
+	_, err = service.Import(context.Background(), []int{42}, since)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 1, 2}, client.requestedPage)
+}
+
+func TestImportMergeRequest_SkipsExisting(t *testing.T) {
+	createdAt := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	client := &mockGitLabClient{}
+	service, gormDB, _ := setupImporter(t, client)
+
+	existing := &models.MRReview{GitLabMRIID: 7, GitLabProjectID: 42, MRURL: "https://gitlab.example.com/mr/7", Status: models.MRStatusApproved}
+	require.NoError(t, gormDB.Create(existing).Error)
+
+	imported, err := service.importMergeRequest(42, mergedMR(7, 101, 102, createdAt))
+	require.NoError(t, err)
+	assert.False(t, imported)
+
+	var review models.MRReview
+	require.NoError(t, gormDB.First(&review, existing.ID).Error)
+	assert.Equal(t, models.MRStatusApproved, review.Status, "live data must not be overwritten")
+}
+
+// failingReviewRepository fails every MR lookup with a database error.
+type failingReviewRepository struct {
+	*repository.ReviewRepository
+}
+
+func (failingReviewRepository) GetMRReview(projectID, mrIID int) (*models.MRReview, error) {
+	return nil, errors.New("connection reset")
+}
+
+func TestImportMergeRequest_LookupErrorIsNotNotFound(t *testing.T) {
+	createdAt := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	client := &mockGitLabClient{}
+	service, gormDB, _ := setupImporter(t, client)
+	service.reviewRepo = failingReviewRepository{repository.NewReviewRepository(&repository.DB{DB: gormDB})}
+
+	imported, err := service.importMergeRequest(42, mergedMR(7, 101, 102, createdAt))
+	require.ErrorContains(t, err, "connection reset")
+	assert.False(t, imported)
+
+	var count int64
+	require.NoError(t, gormDB.Model(&models.MRReview{}).Count(&count).Error)
+	assert.Zero(t, count, "no review must be inserted when the lookup fails")
+}
@@ -0,0 +1,56 @@
+package leaderboard
+
+import (
+	"context"
+)
+
+// TeamSummary is a team's standing on the global leaderboard.
+type TeamSummary struct {
+	Team               string  `json:"team"`
+	Members            int     `json:"members"` // Team members on the global leaderboard
+	CompletedReviews   int     `json:"completed_reviews"`
+	AvgEngagementScore float64 `json:"avg_engagement_score"`
+	Points             float64 `json:"points"`
+	BestRank           int     `json:"best_rank"`  // Global rank of the team's top member, 0 when no member is ranked
+	TopMember          string  `json:"top_member"` // Display name of the team's top member, empty when no member is ranked
+}
+
+// GetFocusTeamSummary summarizes the configured focus team's standing on the global
+// leaderboard. It returns nil when no focus team is configured. Members keep their
+// global ranks; the summary is built from the full board, not only the returned page.
+func (s *Service) GetFocusTeamSummary(ctx context.Context, filters Filters, period, metric string) (*TeamSummary, error) {
+	if s.focusTeam == "" {
+		return nil, nil
+	}
+
+	entries, err := s.getLeaderboard(ctx, "", filters, period, metric, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return summarizeTeam(s.focusTeam, entries), nil
+}
+
+// summarizeTeam totals a team's entries from a ranked leaderboard.
+func summarizeTeam(team string, entries []Entry) *TeamSummary {
+	summary := &TeamSummary{Team: team}
+	totalEngagement := 0.0
+	for _, entry := range entries {
+		if entry.Team != team {
+			continue
+		}
+		summary.Members++
+		summary.CompletedReviews += entry.CompletedReviews
+		summary.Points += entry.Points
+		totalEngagement += entry.EngagementScore
+		// Entries are sorted, so the first member found is the best ranked
+		if summary.BestRank == 0 {
+			summary.BestRank = entry.Rank
+			summary.TopMember = entry.DisplayName
+		}
+	}
+	if summary.Members > 0 {
+		summary.AvgEngagementScore = totalEngagement / float64(summary.Members)
+	}
+	return summary
+}
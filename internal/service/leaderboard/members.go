@@ -0,0 +1,51 @@
+package leaderboard
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+)
+
+// TeamMemberRepository interface for listing the members of a team.
+type TeamMemberRepository interface {
+	List(team, role string) ([]models.User, error)
+}
+
+// SetTeamMemberRepository enables listing inactive members on team leaderboards.
+func (s *Service) SetTeamMemberRepository(repo TeamMemberRepository) {
+	s.memberRepo = repo
+}
+
+// inactiveMembers returns zeroed entries for the team members passing the filters
+// that have no metrics in the period, ordered by username.
+// Without a team member repository no member is added.
+func (s *Service) inactiveMembers(team string, filters Filters, active map[uint]aggregatedMetrics, outOfOffice map[uint]bool) ([]Entry, error) {
+	if s.memberRepo == nil {
+		return nil, nil
+	}
+
+	members, err := s.memberRepo.List(team, filters.Role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list team members: %w", err)
+	}
+
+	var entries []Entry
+	for i := range members {
+		user := &members[i]
+		if _, ok := active[user.ID]; ok || s.isExcluded(user) || outOfOffice[user.ID] {
+			continue
+		}
+		entries = append(entries, Entry{
+			UserID:      user.ID,
+			Username:    user.Username,
+			DisplayName: user.PreferredName(),
+			Team:        user.Team,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Username < entries[j].Username
+	})
+	return entries, nil
+}
@@ -0,0 +1,36 @@
+package leaderboard
+
+import (
+	"fmt"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+)
+
+// OOORepository interface for out-of-office lookups.
+type OOORepository interface {
+	GetAllActive() ([]models.OOOStatus, error)
+}
+
+// SetOOORepository enables excluding out-of-office users from leaderboards.
+func (s *Service) SetOOORepository(repo OOORepository) {
+	s.oooRepo = repo
+}
+
+// usersOutOfOffice returns the IDs of users with an active OOO status.
+// Without an OOO repository nobody is considered out of office.
+func (s *Service) usersOutOfOffice() (map[uint]bool, error) {
+	if s.oooRepo == nil {
+		return nil, nil
+	}
+
+	statuses, err := s.oooRepo.GetAllActive()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active OOO statuses: %w", err)
+	}
+
+	users := make(map[uint]bool, len(statuses))
+	for _, status := range statuses {
+		users[status.UserID] = true
+	}
+	return users, nil
+}
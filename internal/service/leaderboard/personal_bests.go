@@ -0,0 +1,120 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+)
+
+// PersonalBestRepository interface for personal best operations.
+type PersonalBestRepository interface {
+	GetByUserID(userID uint) ([]models.PersonalBest, error)
+	GetByUserMetricPeriod(userID uint, metric, period string) (*models.PersonalBest, error)
+	Save(best *models.PersonalBest) error
+}
+
+// PersonalBestPeriods are the periods personal bests are tracked for.
+var PersonalBestPeriods = []string{"week", "month"}
+
+// GetPersonalBests returns a user's best-ever period values.
+func (s *Service) GetPersonalBests(_ context.Context, userID uint) ([]models.PersonalBest, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if s.isExcluded(user) {
+		return nil, fmt.Errorf("user %d is excluded from statistics", userID)
+	}
+
+	bests, err := s.personalBestRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get personal bests: %w", err)
+	}
+
+	return bests, nil
+}
+
+// UpdatePersonalBests compares each user's values for the period ending now
+// with their stored personal bests and records the ones that beat them.
+// Returns the number of personal bests set.
+func (s *Service) UpdatePersonalBests(_ context.Context, period string) (int, error) {
+	startDate, endDate := s.calculatePeriodRange(period, time.Now())
+
+	metrics, err := s.metricsRepo.GetByDateRange(startDate, endDate, map[string]interface{}{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get metrics: %w", err)
+	}
+
+	updated := 0
+	for userID, agg := range s.aggregateMetricsByUser(metrics) {
+		values := map[string]float64{
+			models.PersonalBestMetricEngagementScore: agg.EngagementScore,
+		}
+		// Rows without TTFR don't count towards the period's average
+		if agg.TTFRSamples > 0 {
+			values[models.PersonalBestMetricAvgTTFR] = agg.AvgTTFR
+		}
+
+		for metric, value := range values {
+			isNewBest, err := s.recordPersonalBest(userID, metric, period, value, startDate, endDate)
+			if err != nil {
+				s.log.Warn().
+					Err(err).
+					Uint("user_id", userID).
+					Str("metric", metric).
+					Msg("Failed to record personal best")
+				continue
+			}
+			if isNewBest {
+				updated++
+			}
+		}
+	}
+
+	s.log.Info().
+		Str("period", period).
+		Int("updated", updated).
+		Msg("Personal bests updated")
+
+	return updated, nil
+}
+
+// recordPersonalBest stores value as the user's personal best if it beats the previous one.
+func (s *Service) recordPersonalBest(userID uint, metric, period string, value float64, startDate, endDate time.Time) (bool, error) {
+	best, err := s.personalBestRepo.GetByUserMetricPeriod(userID, metric, period)
+	if err != nil {
+		return false, err
+	}
+
+	if best != nil && !beatsPersonalBest(metric, value, best.Value) {
+		return false, nil
+	}
+
+	if best == nil {
+		best = &models.PersonalBest{
+			UserID: userID,
+			Metric: metric,
+			Period: period,
+		}
+	}
+	best.Value = value
+	best.PeriodStart = startDate
+	best.PeriodEnd = endDate
+	best.AchievedAt = time.Now()
+
+	if err := s.personalBestRepo.Save(best); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// beatsPersonalBest reports whether value is strictly better than best for the metric.
+func beatsPersonalBest(metric string, value, best float64) bool {
+	if metric == models.PersonalBestMetricAvgTTFR {
+		return value < best
+	}
+	return value > best
+}
@@ -0,0 +1,53 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+)
+
+// ProjectRepository interface for project metadata lookups.
+type ProjectRepository interface {
+	GetByGitLabID(gitlabProjectID int) (*models.Project, error)
+}
+
+// ProjectLeaderboard is a leaderboard scoped to a single GitLab project.
+type ProjectLeaderboard struct {
+	ProjectID   int     `json:"project_id"`
+	ProjectName string  `json:"project_name"` // Empty when the project has not been seen yet
+	ProjectPath string  `json:"project_path"`
+	Entries     []Entry `json:"leaderboard"`
+}
+
+// SetProjectRepository enables project name lookups for project-scoped leaderboards.
+func (s *Service) SetProjectRepository(repo ProjectRepository) {
+	s.projectRepo = repo
+}
+
+// GetProjectLeaderboard returns the leaderboard of reviews on a GitLab project, with its name and path.
+func (s *Service) GetProjectLeaderboard(ctx context.Context, projectID int, filters Filters, period, metric string, limit int) (*ProjectLeaderboard, error) {
+	filters.ProjectID = projectID
+	entries, err := s.getLeaderboard(ctx, "", filters, period, metric, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	board := &ProjectLeaderboard{
+		ProjectID: projectID,
+		Entries:   entries,
+	}
+
+	if s.projectRepo != nil {
+		project, err := s.projectRepo.GetByGitLabID(projectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get project: %w", err)
+		}
+		if project != nil {
+			board.ProjectName = project.Name
+			board.ProjectPath = project.Path
+		}
+	}
+
+	return board, nil
+}
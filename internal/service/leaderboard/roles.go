@@ -0,0 +1,153 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/metrics"
+)
+
+// AssignmentRepository interface for reviewer assignment lookups.
+type AssignmentRepository interface {
+	GetCompletedAssignmentsByDateRange(startDate, endDate time.Time) ([]models.ReviewerAssignment, error)
+}
+
+// ErrRoleStatsUnavailable is returned by GetStatsByRole without an assignment repository.
+var ErrRoleStatsUnavailable = errors.New("role stats are not available")
+
+// RoleStats aggregates the reviews done in one reviewer role across the organization.
+type RoleStats struct {
+	Role              string  `json:"role"`      // Reviewer role of the assignment: codeowner, team_member or external
+	Reviewers         int     `json:"reviewers"` // Distinct users who reviewed in this role
+	Assignments       int     `json:"assignments"`
+	CompletedReviews  int     `json:"completed_reviews"` // Assignments on merged MRs
+	CompletionRate    float64 `json:"completion_rate"`   // completed / assignments, 0-1
+	AvgTTFR           float64 `json:"avg_ttfr"`          // in minutes
+	AvgTimeToApproval float64 `json:"avg_time_to_approval"`
+	AvgCommentCount   float64 `json:"avg_comment_count"`
+}
+
+// roleOrder lists the known reviewer roles first; other roles follow alphabetically.
+var roleOrder = map[string]int{
+	models.ReviewerRoleCodeowner:  0,
+	models.ReviewerRoleTeamMember: 1,
+	models.ReviewerRoleExternal:   2,
+}
+
+// SetAssignmentRepository enables aggregate stats by reviewer role.
+func (s *Service) SetAssignmentRepository(repo AssignmentRepository) {
+	s.assignmentRepo = repo
+}
+
+// SetReviewClock sets how TTFR and time to approval are measured in stats by role.
+func (s *Service) SetReviewClock(clock *metrics.ReviewClock) {
+	s.clock = clock
+}
+
+// GetStatsByRole aggregates the assignments of reviews completed in a period by reviewer role.
+// Metrics rows carry no reviewer role, so stats are computed from the assignments themselves.
+// Durations are measured with the review clock from the assignment, or from the roulette
+// trigger for assignments without an assignment time, like the daily aggregation.
+func (s *Service) GetStatsByRole(ctx context.Context, period string) ([]RoleStats, error) {
+	if s.assignmentRepo == nil {
+		return nil, ErrRoleStatsUnavailable
+	}
+
+	startDate, endDate := s.calculatePeriodRange(period, time.Now())
+	assignments, err := s.assignmentRepo.GetCompletedAssignmentsByDateRange(startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assignments: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	type roleTotals struct {
+		reviewers                    map[uint]bool
+		assignments, completed       int
+		ttfr, approval, comments     float64
+		ttfrSamples, approvalSamples int
+	}
+	totals := make(map[string]*roleTotals)
+	for i := range assignments {
+		assignment := &assignments[i]
+		if s.isExcluded(&assignment.User) {
+			continue
+		}
+
+		t, ok := totals[assignment.Role]
+		if !ok {
+			t = &roleTotals{reviewers: make(map[uint]bool)}
+			totals[assignment.Role] = t
+		}
+		t.reviewers[assignment.UserID] = true
+		t.assignments++
+		// Reviews below the engagement floor don't count as completed, as in the aggregator
+		if assignment.MRReview.Status == models.MRStatusMerged && assignment.CommentCount >= s.minReviewComments {
+			t.completed++
+		}
+		t.comments += float64(assignment.CommentCount)
+
+		start, ok := assignmentStart(assignment)
+		if !ok {
+			continue
+		}
+		if ttfr := s.clock.TTFR(start, assignment.FirstCommentAt); ttfr != nil {
+			t.ttfr += float64(*ttfr) / 60
+			t.ttfrSamples++
+		}
+		if approval := s.clock.TimeToApproval(start, assignment.ApprovedAt); approval != nil {
+			t.approval += float64(*approval) / 60
+			t.approvalSamples++
+		}
+	}
+
+	stats := make([]RoleStats, 0, len(totals))
+	for role, t := range totals {
+		item := RoleStats{
+			Role:             role,
+			Reviewers:        len(t.reviewers),
+			Assignments:      t.assignments,
+			CompletedReviews: t.completed,
+			CompletionRate:   completionRate(t.completed, t.assignments),
+			AvgCommentCount:  t.comments / float64(t.assignments),
+		}
+		if t.ttfrSamples > 0 {
+			item.AvgTTFR = t.ttfr / float64(t.ttfrSamples)
+		}
+		if t.approvalSamples > 0 {
+			item.AvgTimeToApproval = t.approval / float64(t.approvalSamples)
+		}
+		stats = append(stats, item)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		oi, iKnown := roleOrder[stats[i].Role]
+		oj, jKnown := roleOrder[stats[j].Role]
+		if iKnown != jKnown {
+			return iKnown
+		}
+		if iKnown {
+			return oi < oj
+		}
+		return stats[i].Role < stats[j].Role
+	})
+
+	return stats, nil
+}
+
+// assignmentStart returns when the reviewer was assigned, falling back to the roulette
+// trigger for older assignments without an assignment time.
+func assignmentStart(assignment *models.ReviewerAssignment) (time.Time, bool) {
+	if assignment.AssignedAt.Unix() > 0 {
+		return assignment.AssignedAt, true
+	}
+	if assignment.MRReview.RouletteTriggeredAt != nil {
+		return *assignment.MRReview.RouletteTriggeredAt, true
+	}
+	return time.Time{}, false
+}
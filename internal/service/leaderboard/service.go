@@ -3,12 +3,17 @@ package leaderboard
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
+	"sync"
 	"time"
 
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/metrics"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/period"
 	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
 )
 
@@ -31,77 +36,152 @@ type UserRepository interface {
 
 // Entry represents a single entry in a leaderboard.
 type Entry struct {
-	UserID           uint    `json:"user_id"`
-	Username         string  `json:"username"`
-	Team             string  `json:"team"`
-	CompletedReviews int     `json:"completed_reviews"`
-	AvgTTFR          float64 `json:"avg_ttfr"` // in minutes
-	AvgCommentCount  float64 `json:"avg_comment_count"`
-	EngagementScore  float64 `json:"engagement_score"`
-	BadgeCount       int     `json:"badge_count"`
-	Rank             int     `json:"rank"`
+	UserID            uint    `json:"user_id"`
+	Username          string  `json:"username"`
+	DisplayName       string  `json:"display_name"` // Falls back to username
+	Team              string  `json:"team"`
+	CompletedReviews  int     `json:"completed_reviews"`
+	CompletionRate    float64 `json:"completion_rate"`      // completed / total, 0-1
+	AvgTTFR           float64 `json:"avg_ttfr"`             // in minutes
+	AvgTimeToApproval float64 `json:"avg_time_to_approval"` // in minutes
+	AvgCommentCount   float64 `json:"avg_comment_count"`
+	AvgCommentLength  float64 `json:"avg_comment_length"` // in characters
+	EngagementScore   float64 `json:"engagement_score"`
+	LongestStreak     int     `json:"longest_streak"` // Most consecutive days with a completed review in the period
+	BadgeCount        int     `json:"badge_count"`
+	Points            float64 `json:"points"` // Weighted combination of reviews, engagement and badges
+	Rank              int     `json:"rank"`
+}
+
+// Filters narrows which users and metrics appear on a leaderboard. The zero value keeps everyone.
+type Filters struct {
+	Role       string    // Keep only users with this role
+	ExcludeOOO bool      // Drop users who are currently out of office
+	ProjectID  int       // Count only reviews on this GitLab project; 0 counts every project
+	AsOf       time.Time // Reference time the period ends at; zero means now
+	// IncludeInactive lists team members without metrics in the period, with zero stats
+	// and ranked last. Only team leaderboards honor it.
+	IncludeInactive bool
+}
+
+// referenceTime returns the time the period ends at: AsOf, or now when unset.
+func (f Filters) referenceTime() time.Time {
+	if f.AsOf.IsZero() {
+		return time.Now()
+	}
+	return f.AsOf
 }
 
 // Service handles leaderboard generation and user statistics.
 type Service struct {
-	metricsRepo MetricsRepository
-	badgeRepo   BadgeRepository
-	userRepo    UserRepository
-	log         *logger.Logger
+	metricsRepo       MetricsRepository
+	badgeRepo         BadgeRepository
+	userRepo          UserRepository
+	personalBestRepo  PersonalBestRepository
+	projectRepo       ProjectRepository
+	oooRepo           OOORepository
+	memberRepo        TeamMemberRepository
+	assignmentRepo    AssignmentRepository
+	pointsWeights     config.PointsWeightsConfig
+	weightsMu         sync.RWMutex
+	teamPointsWeights map[string]config.PointsWeightsConfig
+	excludedUsers     config.ExcludedUsersConfig
+	focusTeam         string
+	calendarPeriods   bool
+	minReviewComments int
+	maxQueryRangeDays int
+	clock             *metrics.ReviewClock
+	log               *logger.Logger
+}
+
+// DefaultPointsWeights are used when no points weights are configured.
+var DefaultPointsWeights = config.PointsWeightsConfig{
+	CompletedReviews: 10,
+	Engagement:       1,
+	Badges:           25,
 }
 
 // NewService creates a new leaderboard service with concrete repository types.
 func NewService(
+	cfg *config.Config,
 	metricsRepo *repository.MetricsRepository,
 	badgeRepo *repository.BadgeRepository,
 	userRepo *repository.UserRepository,
+	personalBestRepo *repository.PersonalBestRepository,
 	log *logger.Logger,
 ) *Service {
 	return &Service{
-		metricsRepo: metricsRepo,
-		badgeRepo:   badgeRepo,
-		userRepo:    userRepo,
-		log:         log,
+		metricsRepo:       metricsRepo,
+		badgeRepo:         badgeRepo,
+		userRepo:          userRepo,
+		personalBestRepo:  personalBestRepo,
+		pointsWeights:     resolvePointsWeights(cfg.Leaderboard.Points),
+		teamPointsWeights: teamPointsWeights(cfg.Teams),
+		excludedUsers:     cfg.ExcludedUsers,
+		focusTeam:         cfg.Leaderboard.FocusTeam,
+		calendarPeriods:   cfg.Metrics.CalendarPeriods,
+		minReviewComments: cfg.Metrics.MinReviewComments,
+		maxQueryRangeDays: cfg.Metrics.MaxQueryRangeDays,
+		clock:             metrics.NewReviewClock(false, nil),
+		log:               log,
 	}
 }
 
 // NewServiceWithInterfaces creates a new leaderboard service with interface dependencies (useful for testing).
 func NewServiceWithInterfaces(
+	cfg *config.Config,
 	metricsRepo MetricsRepository,
 	badgeRepo BadgeRepository,
 	userRepo UserRepository,
+	personalBestRepo PersonalBestRepository,
 	log *logger.Logger,
 ) *Service {
 	return &Service{
-		metricsRepo: metricsRepo,
-		badgeRepo:   badgeRepo,
-		userRepo:    userRepo,
-		log:         log,
+		metricsRepo:       metricsRepo,
+		badgeRepo:         badgeRepo,
+		userRepo:          userRepo,
+		personalBestRepo:  personalBestRepo,
+		pointsWeights:     resolvePointsWeights(cfg.Leaderboard.Points),
+		teamPointsWeights: teamPointsWeights(cfg.Teams),
+		excludedUsers:     cfg.ExcludedUsers,
+		focusTeam:         cfg.Leaderboard.FocusTeam,
+		calendarPeriods:   cfg.Metrics.CalendarPeriods,
+		minReviewComments: cfg.Metrics.MinReviewComments,
+		maxQueryRangeDays: cfg.Metrics.MaxQueryRangeDays,
+		clock:             metrics.NewReviewClock(false, nil),
+		log:               log,
 	}
 }
 
 // GetGlobalLeaderboard returns the global leaderboard for a given period and metric.
-func (s *Service) GetGlobalLeaderboard(ctx context.Context, period, metric string, limit int) ([]Entry, error) {
-	return s.getLeaderboard(ctx, "", period, metric, limit)
+func (s *Service) GetGlobalLeaderboard(ctx context.Context, filters Filters, period, metric string, limit int) ([]Entry, error) {
+	return s.getLeaderboard(ctx, "", filters, period, metric, limit)
 }
 
 // GetTeamLeaderboard returns the leaderboard for a specific team.
-func (s *Service) GetTeamLeaderboard(ctx context.Context, team, period, metric string, limit int) ([]Entry, error) {
-	return s.getLeaderboard(ctx, team, period, metric, limit)
+func (s *Service) GetTeamLeaderboard(ctx context.Context, team string, filters Filters, period, metric string, limit int) ([]Entry, error) {
+	return s.getLeaderboard(ctx, team, filters, period, metric, limit)
 }
 
-// getLeaderboard is the internal method that builds leaderboards.
-//
-//nolint:revive,unparam // ctx reserved for future context-aware operations (tracing, cancellation)
-func (s *Service) getLeaderboard(ctx context.Context, team, period, metric string, limit int) ([]Entry, error) {
+// getLeaderboard is the internal method that builds leaderboards,
+// optionally scoped to a team and/or a GitLab project and narrowed by user filters.
+// Building stops with ctx.Err() once ctx is cancelled.
+func (s *Service) getLeaderboard(ctx context.Context, team string, userFilters Filters, period, metric string, limit int) ([]Entry, error) {
 	// Calculate date range
-	startDate, endDate := calculatePeriodRange(period)
+	startDate, endDate := s.calculatePeriodRange(period, userFilters.referenceTime())
 
 	// Build filters
 	filters := make(map[string]interface{})
 	if team != "" {
 		filters["team"] = team
 	}
+	if userFilters.ProjectID > 0 {
+		filters["project_id"] = &userFilters.ProjectID
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// Get metrics from database
 	metrics, err := s.metricsRepo.GetByDateRange(startDate, endDate, filters)
@@ -112,12 +192,25 @@ func (s *Service) getLeaderboard(ctx context.Context, team, period, metric strin
 	// Aggregate metrics by user
 	userMetrics := s.aggregateMetricsByUser(metrics)
 
+	// Look up everyone out of office in one query rather than per user
+	var outOfOffice map[uint]bool
+	if userFilters.ExcludeOOO {
+		outOfOffice, err = s.usersOutOfOffice()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Get badge counts for all users
 	badgeCounts := make(map[uint]int)
 	for userID := range userMetrics {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		count, err := s.badgeRepo.GetUserBadgeCount(userID)
 		if err != nil {
 			s.log.Warn().Err(err).Uint("user_id", userID).Msg("Failed to get badge count")
+			addWarning(ctx, WarningBadges)
 			badgeCounts[userID] = 0
 		} else {
 			badgeCounts[userID] = int(count)
@@ -127,23 +220,48 @@ func (s *Service) getLeaderboard(ctx context.Context, team, period, metric strin
 	// Build leaderboard entries
 	entries := make([]Entry, 0, len(userMetrics))
 	for userID, aggMetrics := range userMetrics {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		// Get user info
 		user, err := s.userRepo.GetByID(userID)
 		if err != nil {
 			s.log.Warn().Err(err).Uint("user_id", userID).Msg("Failed to get user")
 			continue
 		}
+		if s.isExcluded(user) {
+			continue
+		}
+		// Metrics rows carry no role, so the role filter is applied per user
+		if userFilters.Role != "" && user.Role != userFilters.Role {
+			continue
+		}
+		if outOfOffice[userID] {
+			continue
+		}
 
 		entry := Entry{
-			UserID:           userID,
-			Username:         user.Username,
-			Team:             user.Team,
-			CompletedReviews: aggMetrics.CompletedReviews,
-			AvgTTFR:          aggMetrics.AvgTTFR,
-			AvgCommentCount:  aggMetrics.AvgCommentCount,
-			EngagementScore:  aggMetrics.EngagementScore,
-			BadgeCount:       badgeCounts[userID],
+			UserID:            userID,
+			Username:          user.Username,
+			DisplayName:       user.PreferredName(),
+			Team:              user.Team,
+			CompletedReviews:  aggMetrics.CompletedReviews,
+			CompletionRate:    completionRate(aggMetrics.CompletedReviews, aggMetrics.TotalReviews),
+			AvgTTFR:           aggMetrics.AvgTTFR,
+			AvgTimeToApproval: aggMetrics.AvgTimeToApproval,
+			AvgCommentCount:   aggMetrics.AvgCommentCount,
+			AvgCommentLength:  aggMetrics.AvgCommentLength,
+			EngagementScore:   aggMetrics.EngagementScore,
+			LongestStreak:     aggMetrics.LongestStreak,
+			BadgeCount:        badgeCounts[userID],
 		}
+		// Team leaderboards use the team's weights; global entries use each user's team
+		weightsTeam := team
+		if weightsTeam == "" {
+			weightsTeam = user.Team
+		}
+		entry.Points = s.calculatePoints(&entry, s.pointsWeightsForTeam(weightsTeam))
 
 		entries = append(entries, entry)
 	}
@@ -151,6 +269,16 @@ func (s *Service) getLeaderboard(ctx context.Context, team, period, metric strin
 	// Sort entries by the specified metric
 	s.sortLeaderboard(entries, metric)
 
+	// Members without activity come last whatever the metric, as their zeroed
+	// averages would otherwise lead lower-is-better metrics
+	if team != "" && userFilters.IncludeInactive {
+		inactive, err := s.inactiveMembers(team, userFilters, userMetrics, outOfOffice)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, inactive...)
+	}
+
 	// Assign ranks
 	for i := range entries {
 		entries[i].Rank = i + 1
@@ -166,45 +294,71 @@ func (s *Service) getLeaderboard(ctx context.Context, team, period, metric strin
 
 // aggregateMetricsByUser aggregates metrics by user ID.
 func (s *Service) aggregateMetricsByUser(metrics []models.ReviewMetrics) map[uint]aggregatedMetrics {
-	userMetrics := make(map[uint]aggregatedMetrics)
-
-	for _, m := range metrics {
+	return aggregateMetricsBy(metrics, func(m *models.ReviewMetrics) (uint, bool) {
 		if m.UserID == nil {
+			return 0, false
+		}
+		return *m.UserID, true
+	})
+}
+
+// aggregateMetricsBy aggregates metrics rows under the key returned for each row,
+// skipping rows for which key reports false. Each average is weighted by the samples
+// behind it, so rows without a value don't drag it down, and the longest streak counts
+// consecutive days with a completed review under the key.
+func aggregateMetricsBy[K comparable](metrics []models.ReviewMetrics, key func(*models.ReviewMetrics) (K, bool)) map[K]aggregatedMetrics {
+	grouped := make(map[K]aggregatedMetrics)
+
+	for i := range metrics {
+		m := &metrics[i]
+		k, ok := key(m)
+		if !ok {
 			continue
 		}
 
-		userID := *m.UserID
-		agg := userMetrics[userID]
+		agg := grouped[k]
 
 		// Aggregate totals
+		agg.TotalReviews += m.TotalReviews
 		agg.CompletedReviews += m.CompletedReviews
 		agg.MetricsCount++
+		if m.CompletedReviews > 0 {
+			agg.ActiveDays = append(agg.ActiveDays, m.Date)
+		}
 
 		// Aggregate averages
 		if m.AvgTTFR != nil {
-			agg.TotalTTFR += float64(*m.AvgTTFR)
+			agg.ttfr.Add(float64(*m.AvgTTFR), m.TTFRSamples)
+		}
+		if m.AvgTimeToApproval != nil {
+			agg.timeToApproval.Add(float64(*m.AvgTimeToApproval), m.ApprovalSamples)
 		}
 		if m.AvgCommentCount != nil {
-			agg.TotalCommentCount += *m.AvgCommentCount
+			agg.commentCount.Add(*m.AvgCommentCount, m.CommentSamples)
+		}
+		if m.AvgCommentLength != nil {
+			agg.commentLength.Add(*m.AvgCommentLength, m.CommentSamples)
 		}
 		if m.EngagementScore != nil {
-			agg.TotalEngagementScore += *m.EngagementScore
+			agg.engagement.Add(*m.EngagementScore, m.EngagementSamples)
 		}
 
-		userMetrics[userID] = agg
+		grouped[k] = agg
 	}
 
 	// Calculate averages
-	for userID, agg := range userMetrics {
-		if agg.MetricsCount > 0 {
-			agg.AvgTTFR = agg.TotalTTFR / float64(agg.MetricsCount)
-			agg.AvgCommentCount = agg.TotalCommentCount / float64(agg.MetricsCount)
-			agg.EngagementScore = agg.TotalEngagementScore / float64(agg.MetricsCount)
-			userMetrics[userID] = agg
-		}
+	for k, agg := range grouped {
+		agg.TTFRSamples = agg.ttfr.Samples
+		agg.AvgTTFR = agg.ttfr.Mean()
+		agg.AvgTimeToApproval = agg.timeToApproval.Mean()
+		agg.AvgCommentCount = agg.commentCount.Mean()
+		agg.AvgCommentLength = agg.commentLength.Mean()
+		agg.EngagementScore = agg.engagement.Mean()
+		agg.LongestStreak = period.LongestStreak(agg.ActiveDays)
+		grouped[k] = agg
 	}
 
-	return userMetrics
+	return grouped
 }
 
 // sortLeaderboard sorts leaderboard entries by the specified metric.
@@ -223,10 +377,30 @@ func (s *Service) sortLeaderboard(entries []Entry, metric string) {
 		sort.Slice(entries, func(i, j int) bool {
 			return entries[i].AvgTTFR < entries[j].AvgTTFR
 		})
+	case "avg_time_to_approval":
+		// Lower is better for time to approval
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].AvgTimeToApproval < entries[j].AvgTimeToApproval
+		})
 	case "avg_comment_count":
 		sort.Slice(entries, func(i, j int) bool {
 			return entries[i].AvgCommentCount > entries[j].AvgCommentCount
 		})
+	case "avg_comment_length":
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].AvgCommentLength > entries[j].AvgCommentLength
+		})
+	case "points":
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].Points > entries[j].Points
+		})
+	case "streak":
+		sort.Slice(entries, func(i, j int) bool {
+			if entries[i].LongestStreak != entries[j].LongestStreak {
+				return entries[i].LongestStreak > entries[j].LongestStreak
+			}
+			return entries[i].Username < entries[j].Username
+		})
 	default:
 		// Default to completed_reviews
 		sort.Slice(entries, func(i, j int) bool {
@@ -235,10 +409,63 @@ func (s *Service) sortLeaderboard(entries []Entry, metric string) {
 	}
 }
 
+// calculatePoints combines completed reviews, engagement and badges using the given weights.
+func (s *Service) calculatePoints(entry *Entry, weights config.PointsWeightsConfig) float64 {
+	return float64(entry.CompletedReviews)*weights.CompletedReviews +
+		entry.EngagementScore*weights.Engagement +
+		float64(entry.BadgeCount)*weights.Badges
+}
+
+// SetTeams replaces the per-team points weight overrides, e.g. after a config reload.
+func (s *Service) SetTeams(teams []config.TeamConfig) {
+	weights := teamPointsWeights(teams)
+	s.weightsMu.Lock()
+	defer s.weightsMu.Unlock()
+	s.teamPointsWeights = weights
+}
+
+// pointsWeightsForTeam returns the team's points weights, falling back to the global weights.
+func (s *Service) pointsWeightsForTeam(team string) config.PointsWeightsConfig {
+	s.weightsMu.RLock()
+	defer s.weightsMu.RUnlock()
+	if weights, ok := s.teamPointsWeights[team]; ok {
+		return weights
+	}
+	return s.pointsWeights
+}
+
+// teamPointsWeights collects per-team points weight overrides, ignoring empty ones.
+func teamPointsWeights(teams []config.TeamConfig) map[string]config.PointsWeightsConfig {
+	weights := make(map[string]config.PointsWeightsConfig)
+	for _, team := range teams {
+		if team.PointsWeights == nil || *team.PointsWeights == (config.PointsWeightsConfig{}) {
+			continue
+		}
+		weights[team.Name] = *team.PointsWeights
+	}
+	return weights
+}
+
+// resolvePointsWeights falls back to DefaultPointsWeights when no weight is configured.
+func resolvePointsWeights(weights config.PointsWeightsConfig) config.PointsWeightsConfig {
+	if weights.CompletedReviews == 0 && weights.Engagement == 0 && weights.Badges == 0 {
+		return DefaultPointsWeights
+	}
+	return weights
+}
+
+// isExcluded reports whether a user is configured to be kept off leaderboards and stats.
+func (s *Service) isExcluded(user *models.User) bool {
+	return s.excludedUsers.ExcludesUser(user)
+}
+
+// errUserNotRanked is returned when a user has no entry on the leaderboard used for ranking.
+var errUserNotRanked = errors.New("user not found in leaderboard")
+
 // GetUserRank returns the rank of a user for a specific metric in a period.
 func (s *Service) GetUserRank(ctx context.Context, userID uint, period, metric string) (int, error) {
 	// Get global leaderboard (no limit)
-	leaderboard, err := s.GetGlobalLeaderboard(ctx, period, metric, 0)
+	leaderboard, err := s.GetGlobalLeaderboard(ctx, Filters{}, period, metric, 0)
 	if err != nil {
 		return 0, err
 	}
@@ -250,43 +477,39 @@ func (s *Service) GetUserRank(ctx context.Context, userID uint, period, metric s
 		}
 	}
 
-	// User not found in leaderboard
-	return 0, fmt.Errorf("user not found in leaderboard")
+	return 0, errUserNotRanked
 }
 
 // aggregatedMetrics holds aggregated metrics for a user.
 type aggregatedMetrics struct {
-	CompletedReviews     int
-	TotalTTFR            float64
-	TotalCommentCount    float64
-	TotalEngagementScore float64
-	MetricsCount         int
-	AvgTTFR              float64
-	AvgCommentCount      float64
-	EngagementScore      float64
+	TotalReviews      int
+	CompletedReviews  int
+	TTFRSamples       int // Samples behind AvgTTFR, weighted by each row's TTFRSamples
+	MetricsCount      int
+	ActiveDays        []time.Time // Days with at least one completed review
+	AvgTTFR           float64
+	AvgTimeToApproval float64
+	AvgCommentCount   float64
+	AvgCommentLength  float64
+	EngagementScore   float64
+	LongestStreak     int
+
+	ttfr, timeToApproval, commentCount, commentLength, engagement metrics.SampleMean
 }
 
-// calculatePeriodRange calculates the start and end dates for a period.
-func calculatePeriodRange(period string) (startDate, endDate time.Time) {
-	now := time.Now()
-	endDate = now
-
-	switch period {
-	case "day":
-		startDate = now.Add(-24 * time.Hour)
-	case "week":
-		startDate = now.Add(-7 * 24 * time.Hour)
-	case "month":
-		startDate = now.Add(-30 * 24 * time.Hour)
-	case "year":
-		startDate = now.Add(-365 * 24 * time.Hour)
-	case "all_time", "":
-		// All time: use a very old date
-		startDate = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
-	default:
-		// Default to all time
-		startDate = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+// completionRate returns completed/total as a fraction, or 0 when there are no reviews.
+func completionRate(completed, total int) float64 {
+	if total <= 0 {
+		return 0
 	}
+	return float64(completed) / float64(total)
+}
 
-	return startDate, endDate
+// calculatePeriodRange calculates the start and end dates for a period ending at now,
+// snapped to calendar boundaries when calendar periods are configured.
+func (s *Service) calculatePeriodRange(name string, now time.Time) (startDate, endDate time.Time) {
+	if s.calendarPeriods {
+		return period.Calendar(name, now)
+	}
+	return period.Rolling(name, now)
 }
@@ -2,10 +2,16 @@ package leaderboard
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/metrics"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/period"
 	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
 )
 
@@ -21,25 +27,37 @@ func newMockMetricsRepository() *mockMetricsRepository {
 }
 
 func (m *mockMetricsRepository) GetByDateRange(startDate, endDate time.Time, filters map[string]interface{}) ([]models.ReviewMetrics, error) {
-	// Filter by team if specified
-	if team, ok := filters["team"]; ok {
-		var filtered []models.ReviewMetrics
-		for _, metric := range m.metrics {
-			if metric.Team == team.(string) {
-				filtered = append(filtered, metric)
-			}
+	team, filterTeam := filters["team"].(string)
+	projectID, filterProject := filters["project_id"].(*int)
+
+	var filtered []models.ReviewMetrics
+	for _, metric := range m.metrics {
+		if filterTeam && metric.Team != team {
+			continue
+		}
+		if filterProject && (metric.ProjectID == nil || *metric.ProjectID != *projectID) {
+			continue
 		}
-		return filtered, nil
+		// Undated fixtures match any range
+		if !metric.Date.IsZero() && (metric.Date.Before(startDate) || metric.Date.After(endDate)) {
+			continue
+		}
+		filtered = append(filtered, metric)
 	}
-	return m.metrics, nil
+	return filtered, nil
 }
 
 func (m *mockMetricsRepository) GetMetricsByUser(userID uint, startDate, endDate time.Time) ([]models.ReviewMetrics, error) {
 	var result []models.ReviewMetrics
 	for _, metric := range m.metrics {
-		if metric.UserID != nil && *metric.UserID == userID {
-			result = append(result, metric)
+		if metric.UserID == nil || *metric.UserID != userID {
+			continue
+		}
+		// Undated fixtures match any range
+		if !metric.Date.IsZero() && (metric.Date.Before(startDate) || metric.Date.After(endDate)) {
+			continue
 		}
+		result = append(result, metric)
 	}
 	return result, nil
 }
@@ -47,6 +65,7 @@ func (m *mockMetricsRepository) GetMetricsByUser(userID uint, startDate, endDate
 type mockBadgeRepository struct {
 	userBadgeCounts map[uint]int64
 	userBadges      map[uint][]models.UserBadge
+	err             error // Returned by every call when set, simulating a degraded backend
 }
 
 func newMockBadgeRepository() *mockBadgeRepository {
@@ -57,6 +76,9 @@ func newMockBadgeRepository() *mockBadgeRepository {
 }
 
 func (m *mockBadgeRepository) GetUserBadgeCount(userID uint) (int64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
 	count, ok := m.userBadgeCounts[userID]
 	if !ok {
 		return 0, nil
@@ -65,6 +87,9 @@ func (m *mockBadgeRepository) GetUserBadgeCount(userID uint) (int64, error) {
 }
 
 func (m *mockBadgeRepository) GetUserBadges(userID uint) ([]models.UserBadge, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
 	badges, ok := m.userBadges[userID]
 	if !ok {
 		return []models.UserBadge{}, nil
@@ -82,14 +107,65 @@ func newMockUserRepository() *mockUserRepository {
 	}
 }
 
+func (m *mockUserRepository) List(team, role string) ([]models.User, error) {
+	var users []models.User
+	for _, user := range m.users {
+		if (team == "" || user.Team == team) && (role == "" || user.Role == role) {
+			users = append(users, *user)
+		}
+	}
+	return users, nil
+}
+
 func (m *mockUserRepository) GetByID(id uint) (*models.User, error) {
 	user, ok := m.users[id]
 	if !ok {
-		return nil, nil
+		return nil, fmt.Errorf("user %d: %w", id, repository.ErrNotFound)
 	}
 	return user, nil
 }
 
+type mockPersonalBestRepository struct {
+	bests map[string]*models.PersonalBest
+	saves int
+}
+
+func newMockPersonalBestRepository() *mockPersonalBestRepository {
+	return &mockPersonalBestRepository{
+		bests: make(map[string]*models.PersonalBest),
+	}
+}
+
+func personalBestKey(userID uint, metric, period string) string {
+	return fmt.Sprintf("%d:%s:%s", userID, metric, period)
+}
+
+func (m *mockPersonalBestRepository) GetByUserID(userID uint) ([]models.PersonalBest, error) {
+	var result []models.PersonalBest
+	for _, best := range m.bests {
+		if best.UserID == userID {
+			result = append(result, *best)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockPersonalBestRepository) GetByUserMetricPeriod(userID uint, metric, period string) (*models.PersonalBest, error) {
+	best, ok := m.bests[personalBestKey(userID, metric, period)]
+	if !ok {
+		return nil, nil
+	}
+	stored := *best
+	return &stored, nil
+}
+
+func (m *mockPersonalBestRepository) Save(best *models.PersonalBest) error {
+	stored := *best
+	m.bests[personalBestKey(best.UserID, best.Metric, best.Period)] = &stored
+	m.saves++
+	return nil
+}
+
 // Test setup helper
 func setupTestService() (*Service, *mockMetricsRepository, *mockBadgeRepository, *mockUserRepository) {
 	metricsRepo := newMockMetricsRepository()
@@ -97,11 +173,66 @@ func setupTestService() (*Service, *mockMetricsRepository, *mockBadgeRepository,
 	userRepo := newMockUserRepository()
 	log := logger.New("debug", "text", "stdout")
 
-	service := NewServiceWithInterfaces(metricsRepo, badgeRepo, userRepo, log)
+	service := NewServiceWithInterfaces(&config.Config{}, metricsRepo, badgeRepo, userRepo, newMockPersonalBestRepository(), log)
 
 	return service, metricsRepo, badgeRepo, userRepo
 }
 
+// cancellingUserRepository cancels a context the first time a user is looked up.
+type cancellingUserRepository struct {
+	*mockUserRepository
+	cancel context.CancelFunc
+	calls  int
+}
+
+func (m *cancellingUserRepository) GetByID(id uint) (*models.User, error) {
+	m.calls++
+	m.cancel()
+	return m.mockUserRepository.GetByID(id)
+}
+
+func TestGetGlobalLeaderboard_Cancelled(t *testing.T) {
+	service, metricsRepo, _, userRepo := setupTestService()
+
+	for i := uint(1); i <= 20; i++ {
+		userID := i
+		userRepo.users[userID] = &models.User{ID: userID, Username: fmt.Sprintf("user-%d", userID)}
+		metricsRepo.metrics = append(metricsRepo.metrics, models.ReviewMetrics{
+			UserID:           &userID,
+			Date:             time.Now(),
+			CompletedReviews: int(userID),
+		})
+	}
+
+	t.Run("already cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		entries, err := service.GetGlobalLeaderboard(ctx, Filters{}, "all_time", "completed_reviews", 10)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("GetGlobalLeaderboard() error = %v, want %v", err, context.Canceled)
+		}
+		if entries != nil {
+			t.Errorf("Expected no entries, got %d", len(entries))
+		}
+	})
+
+	t.Run("cancelled while building entries", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		cancelling := &cancellingUserRepository{mockUserRepository: userRepo, cancel: cancel}
+		service.userRepo = cancelling
+
+		_, err := service.GetGlobalLeaderboard(ctx, Filters{}, "all_time", "completed_reviews", 10)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("GetGlobalLeaderboard() error = %v, want %v", err, context.Canceled)
+		}
+		if cancelling.calls != 1 {
+			t.Errorf("Expected building to stop after the cancelling lookup, got %d user lookups", cancelling.calls)
+		}
+	})
+}
+
 func TestGetGlobalLeaderboard(t *testing.T) {
 	service, metricsRepo, badgeRepo, userRepo := setupTestService()
 
@@ -170,7 +301,7 @@ func TestGetGlobalLeaderboard(t *testing.T) {
 	badgeRepo.userBadgeCounts[user3ID] = 2
 
 	// Get global leaderboard sorted by completed_reviews
-	leaderboard, err := service.GetGlobalLeaderboard(context.Background(), "all_time", "completed_reviews", 10)
+	leaderboard, err := service.GetGlobalLeaderboard(context.Background(), Filters{}, "all_time", "completed_reviews", 10)
 	if err != nil {
 		t.Fatalf("GetGlobalLeaderboard failed: %v", err)
 	}
@@ -196,6 +327,250 @@ func TestGetGlobalLeaderboard(t *testing.T) {
 	}
 }
 
+func TestGetLeaderboard_FilterByRole(t *testing.T) {
+	service, metricsRepo, _, userRepo := setupTestService()
+
+	devID := uint(1)
+	opsID := uint(2)
+	userRepo.users[devID] = &models.User{ID: devID, Username: "alice", Role: models.RoleDev, Team: "team-platform"}
+	userRepo.users[opsID] = &models.User{ID: opsID, Username: "bob", Role: models.RoleOps, Team: "team-platform"}
+	metricsRepo.metrics = []models.ReviewMetrics{
+		{UserID: &devID, Team: "team-platform", CompletedReviews: 10},
+		{UserID: &opsID, Team: "team-platform", CompletedReviews: 20},
+	}
+
+	all, err := service.GetGlobalLeaderboard(context.Background(), Filters{}, "all_time", "completed_reviews", 0)
+	if err != nil {
+		t.Fatalf("GetGlobalLeaderboard failed: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("Expected 2 entries without a role filter, got %d", len(all))
+	}
+
+	ops, err := service.GetGlobalLeaderboard(context.Background(), Filters{Role: models.RoleOps}, "all_time", "completed_reviews", 0)
+	if err != nil {
+		t.Fatalf("GetGlobalLeaderboard failed: %v", err)
+	}
+	if len(ops) != 1 || ops[0].Username != "bob" || ops[0].Rank != 1 {
+		t.Errorf("Expected only bob ranked 1 on the ops board, got %+v", ops)
+	}
+
+	devs, err := service.GetTeamLeaderboard(context.Background(), "team-platform", Filters{Role: models.RoleDev}, "all_time", "completed_reviews", 0)
+	if err != nil {
+		t.Fatalf("GetTeamLeaderboard failed: %v", err)
+	}
+	if len(devs) != 1 || devs[0].Username != "alice" || devs[0].Rank != 1 {
+		t.Errorf("Expected only alice ranked 1 on the dev board, got %+v", devs)
+	}
+}
+
+type mockOOORepository struct {
+	active []models.OOOStatus
+	calls  int
+}
+
+func (m *mockOOORepository) GetAllActive() ([]models.OOOStatus, error) {
+	m.calls++
+	return m.active, nil
+}
+
+func TestGetLeaderboard_ExcludeOOO(t *testing.T) {
+	service, metricsRepo, _, userRepo := setupTestService()
+	oooRepo := &mockOOORepository{active: []models.OOOStatus{{UserID: 2}}}
+	service.SetOOORepository(oooRepo)
+
+	presentID := uint(1)
+	awayID := uint(2)
+	userRepo.users[presentID] = &models.User{ID: presentID, Username: "alice", Team: "team-platform"}
+	userRepo.users[awayID] = &models.User{ID: awayID, Username: "bob", Team: "team-platform"}
+	metricsRepo.metrics = []models.ReviewMetrics{
+		{UserID: &presentID, Team: "team-platform", CompletedReviews: 10},
+		{UserID: &awayID, Team: "team-platform", CompletedReviews: 20},
+	}
+
+	all, err := service.GetGlobalLeaderboard(context.Background(), Filters{}, "all_time", "completed_reviews", 0)
+	if err != nil {
+		t.Fatalf("GetGlobalLeaderboard failed: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("Expected OOO users to be ranked without exclude_ooo, got %d entries", len(all))
+	}
+	if oooRepo.calls != 0 {
+		t.Errorf("Expected no OOO lookup without exclude_ooo, got %d", oooRepo.calls)
+	}
+
+	active, err := service.GetTeamLeaderboard(context.Background(), "team-platform", Filters{ExcludeOOO: true}, "all_time", "completed_reviews", 0)
+	if err != nil {
+		t.Fatalf("GetTeamLeaderboard failed: %v", err)
+	}
+	if len(active) != 1 || active[0].Username != "alice" || active[0].Rank != 1 {
+		t.Errorf("Expected only alice ranked 1 with OOO users excluded, got %+v", active)
+	}
+	if oooRepo.calls != 1 {
+		t.Errorf("Expected a single batched OOO lookup, got %d", oooRepo.calls)
+	}
+}
+
+func TestGetLeaderboard_IncludeInactive(t *testing.T) {
+	service, metricsRepo, _, userRepo := setupTestService()
+	service.SetTeamMemberRepository(userRepo)
+
+	aliceID := uint(1)
+	bobID := uint(2)
+	userRepo.users[aliceID] = &models.User{ID: aliceID, Username: "alice", Team: "team-platform"}
+	userRepo.users[bobID] = &models.User{ID: bobID, Username: "bob", Team: "team-platform"}
+	userRepo.users[3] = &models.User{ID: 3, Username: "zoe", Team: "team-platform"}
+	userRepo.users[4] = &models.User{ID: 4, Username: "carol", Team: "team-platform"}
+	userRepo.users[5] = &models.User{ID: 5, Username: "dave", Team: "team-other"}
+	ttfr := 30
+	metricsRepo.metrics = []models.ReviewMetrics{
+		{UserID: &aliceID, Team: "team-platform", CompletedReviews: 10, AvgTTFR: &ttfr},
+		{UserID: &bobID, Team: "team-platform", CompletedReviews: 5, AvgTTFR: &ttfr},
+	}
+
+	active, err := service.GetTeamLeaderboard(context.Background(), "team-platform", Filters{}, "all_time", "completed_reviews", 0)
+	if err != nil {
+		t.Fatalf("GetTeamLeaderboard failed: %v", err)
+	}
+	if len(active) != 2 {
+		t.Errorf("Expected only active members without include_inactive, got %+v", active)
+	}
+
+	// Inactive members come last even for lower-is-better metrics
+	for _, metric := range []string{"completed_reviews", "avg_ttfr"} {
+		entries, err := service.GetTeamLeaderboard(context.Background(), "team-platform", Filters{IncludeInactive: true}, "all_time", metric, 0)
+		if err != nil {
+			t.Fatalf("GetTeamLeaderboard failed: %v", err)
+		}
+		usernames := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			usernames = append(usernames, entry.Username)
+		}
+		if len(usernames) != 4 || usernames[2] != "carol" || usernames[3] != "zoe" {
+			t.Fatalf("%s: expected carol and zoe ranked last, got %v", metric, usernames)
+		}
+		for _, entry := range entries[2:] {
+			if entry.Rank < 3 || entry.CompletedReviews != 0 || entry.AvgTTFR != 0 || entry.Points != 0 {
+				t.Errorf("%s: expected %s ranked last with zero stats, got %+v", metric, entry.Username, entry)
+			}
+		}
+	}
+
+	// The global leaderboard only lists users with metrics
+	global, err := service.GetGlobalLeaderboard(context.Background(), Filters{IncludeInactive: true}, "all_time", "completed_reviews", 0)
+	if err != nil {
+		t.Fatalf("GetGlobalLeaderboard failed: %v", err)
+	}
+	if len(global) != 2 {
+		t.Errorf("Expected include_inactive to be ignored on the global leaderboard, got %+v", global)
+	}
+}
+
+func TestGetLeaderboard_FilterByProject(t *testing.T) {
+	service, metricsRepo, _, userRepo := setupTestService()
+
+	aliceID := uint(1)
+	bobID := uint(2)
+	charlieID := uint(3)
+	userRepo.users[aliceID] = &models.User{ID: aliceID, Username: "alice", Team: "team-a"}
+	userRepo.users[bobID] = &models.User{ID: bobID, Username: "bob", Team: "team-a"}
+	userRepo.users[charlieID] = &models.User{ID: charlieID, Username: "charlie", Team: "team-b"}
+
+	api := 42
+	web := 43
+	metricsRepo.metrics = []models.ReviewMetrics{
+		{UserID: &aliceID, Team: "team-a", ProjectID: &api, CompletedReviews: 3},
+		{UserID: &aliceID, Team: "team-a", ProjectID: &web, CompletedReviews: 20},
+		{UserID: &bobID, Team: "team-a", ProjectID: &web, CompletedReviews: 10},
+		{UserID: &charlieID, Team: "team-b", ProjectID: &api, CompletedReviews: 5},
+	}
+
+	global, err := service.GetGlobalLeaderboard(context.Background(), Filters{ProjectID: api}, "all_time", "completed_reviews", 10)
+	if err != nil {
+		t.Fatalf("GetGlobalLeaderboard failed: %v", err)
+	}
+	// Reviews on other projects are excluded: bob drops out and alice only counts project 42
+	if len(global) != 2 || global[0].Username != "charlie" || global[1].Username != "alice" || global[1].CompletedReviews != 3 {
+		t.Errorf("Expected charlie then alice with 3 reviews on project %d, got %+v", api, global)
+	}
+
+	team, err := service.GetTeamLeaderboard(context.Background(), "team-a", Filters{ProjectID: web}, "all_time", "completed_reviews", 10)
+	if err != nil {
+		t.Fatalf("GetTeamLeaderboard failed: %v", err)
+	}
+	if len(team) != 2 || team[0].Username != "alice" || team[0].CompletedReviews != 20 || team[1].Username != "bob" {
+		t.Errorf("Expected alice (20) then bob on project %d for team-a, got %+v", web, team)
+	}
+
+	all, err := service.GetGlobalLeaderboard(context.Background(), Filters{}, "all_time", "completed_reviews", 10)
+	if err != nil {
+		t.Fatalf("GetGlobalLeaderboard failed: %v", err)
+	}
+	if len(all) != 3 || all[0].Username != "alice" || all[0].CompletedReviews != 23 {
+		t.Errorf("Expected every project to count without a filter, got %+v", all)
+	}
+}
+
+func TestGetFocusTeamSummary(t *testing.T) {
+	metricsRepo := newMockMetricsRepository()
+	userRepo := newMockUserRepository()
+	cfg := &config.Config{Leaderboard: config.LeaderboardConfig{FocusTeam: "team-b"}}
+	service := NewServiceWithInterfaces(cfg, metricsRepo, newMockBadgeRepository(), userRepo, newMockPersonalBestRepository(), logger.New("debug", "text", "stdout"))
+
+	aliceID := uint(1)
+	bobID := uint(2)
+	charlieID := uint(3)
+	userRepo.users[aliceID] = &models.User{ID: aliceID, Username: "alice", Team: "team-a"}
+	userRepo.users[bobID] = &models.User{ID: bobID, Username: "bob", DisplayName: "Bob B.", Team: "team-b"}
+	userRepo.users[charlieID] = &models.User{ID: charlieID, Username: "charlie", Team: "team-b"}
+
+	aliceScore, bobScore, charlieScore := 30.0, 20.0, 10.0
+	metricsRepo.metrics = []models.ReviewMetrics{
+		{UserID: &aliceID, Team: "team-a", CompletedReviews: 15, EngagementScore: &aliceScore},
+		{UserID: &bobID, Team: "team-b", CompletedReviews: 10, EngagementScore: &bobScore},
+		{UserID: &charlieID, Team: "team-b", CompletedReviews: 5, EngagementScore: &charlieScore},
+	}
+
+	global, err := service.GetGlobalLeaderboard(context.Background(), Filters{}, "all_time", "completed_reviews", 1)
+	if err != nil {
+		t.Fatalf("GetGlobalLeaderboard failed: %v", err)
+	}
+	// The focus team does not change global ranks
+	if len(global) != 1 || global[0].Username != "alice" || global[0].Rank != 1 {
+		t.Errorf("Expected alice ranked first, got %+v", global)
+	}
+
+	summary, err := service.GetFocusTeamSummary(context.Background(), Filters{}, "all_time", "completed_reviews")
+	if err != nil {
+		t.Fatalf("GetFocusTeamSummary failed: %v", err)
+	}
+	// Built from the full board, even though the team is outside the top 1
+	expected := &TeamSummary{
+		Team:               "team-b",
+		Members:            2,
+		CompletedReviews:   15,
+		AvgEngagementScore: 15,
+		Points:             150 + 30, // (10 + 5) * 10 + (20 + 10) * 1
+		BestRank:           2,
+		TopMember:          "Bob B.",
+	}
+	if *summary != *expected {
+		t.Errorf("Expected summary %+v, got %+v", expected, summary)
+	}
+}
+
+func TestGetFocusTeamSummary_NotConfigured(t *testing.T) {
+	service, _, _, _ := setupTestService()
+
+	summary, err := service.GetFocusTeamSummary(context.Background(), Filters{}, "all_time", "completed_reviews")
+	if err != nil {
+		t.Fatalf("GetFocusTeamSummary failed: %v", err)
+	}
+	if summary != nil {
+		t.Errorf("Expected no summary without a focus team, got %+v", summary)
+	}
+}
+
 func TestGetTeamLeaderboard(t *testing.T) {
 	service, metricsRepo, _, userRepo := setupTestService()
 
@@ -247,7 +622,7 @@ func TestGetTeamLeaderboard(t *testing.T) {
 	}
 
 	// Get team leaderboard for team-frontend
-	leaderboard, err := service.GetTeamLeaderboard(context.Background(), "team-frontend", "all_time", "engagement_score", 10)
+	leaderboard, err := service.GetTeamLeaderboard(context.Background(), "team-frontend", Filters{}, "all_time", "engagement_score", 10)
 	if err != nil {
 		t.Fatalf("GetTeamLeaderboard failed: %v", err)
 	}
@@ -312,6 +687,52 @@ func TestSortLeaderboard_AvgTTFR(t *testing.T) {
 	}
 }
 
+func TestSortLeaderboard_AvgTimeToApproval(t *testing.T) {
+	service, _, _, _ := setupTestService()
+
+	entries := []Entry{
+		{UserID: 1, Username: "alice", AvgTimeToApproval: 240},
+		{UserID: 2, Username: "bob", AvgTimeToApproval: 480},
+		{UserID: 3, Username: "charlie", AvgTimeToApproval: 120},
+	}
+
+	service.sortLeaderboard(entries, "avg_time_to_approval")
+
+	// Lower is better for time to approval
+	if entries[0].Username != "charlie" {
+		t.Errorf("Expected charlie first (fastest approval), got %s", entries[0].Username)
+	}
+	if entries[1].Username != "alice" {
+		t.Errorf("Expected alice second, got %s", entries[1].Username)
+	}
+	if entries[2].Username != "bob" {
+		t.Errorf("Expected bob third, got %s", entries[2].Username)
+	}
+}
+
+func TestSortLeaderboard_AvgCommentLength(t *testing.T) {
+	service, _, _, _ := setupTestService()
+
+	entries := []Entry{
+		{UserID: 1, Username: "alice", AvgCommentLength: 80},
+		{UserID: 2, Username: "bob", AvgCommentLength: 150},
+		{UserID: 3, Username: "charlie", AvgCommentLength: 40},
+	}
+
+	service.sortLeaderboard(entries, "avg_comment_length")
+
+	// Higher is better for comment length
+	if entries[0].Username != "bob" {
+		t.Errorf("Expected bob first (longest comments), got %s", entries[0].Username)
+	}
+	if entries[1].Username != "alice" {
+		t.Errorf("Expected alice second, got %s", entries[1].Username)
+	}
+	if entries[2].Username != "charlie" {
+		t.Errorf("Expected charlie third, got %s", entries[2].Username)
+	}
+}
+
 func TestSortLeaderboard_EngagementScore(t *testing.T) {
 	service, _, _, _ := setupTestService()
 
@@ -335,6 +756,26 @@ func TestSortLeaderboard_EngagementScore(t *testing.T) {
 	}
 }
 
+func TestSortLeaderboard_StreakTieBreaksByUsername(t *testing.T) {
+	service, _, _, _ := setupTestService()
+
+	entries := []Entry{
+		{UserID: 1, Username: "charlie", LongestStreak: 3},
+		{UserID: 2, Username: "alice", LongestStreak: 3},
+		{UserID: 3, Username: "bob", LongestStreak: 5},
+		{UserID: 4, Username: "dave", LongestStreak: 3},
+	}
+
+	service.sortLeaderboard(entries, "streak")
+
+	expected := []string{"bob", "alice", "charlie", "dave"}
+	for i, username := range expected {
+		if entries[i].Username != username {
+			t.Errorf("Expected %s at position %d, got %s", username, i, entries[i].Username)
+		}
+	}
+}
+
 func TestGetUserRank(t *testing.T) {
 	service, metricsRepo, _, userRepo := setupTestService()
 
@@ -384,25 +825,33 @@ func TestAggregateMetricsByUser(t *testing.T) {
 	userID := uint(1)
 	ttfr1 := 60
 	ttfr2 := 120
+	approval1 := 180
+	approval2 := 300
 	commentCount1 := 5.0
 	commentCount2 := 7.0
+	commentLength1 := 40.0
+	commentLength2 := 100.0
 	engagementScore1 := 8.0
 	engagementScore2 := 9.0
 
 	metrics := []models.ReviewMetrics{
 		{
-			UserID:           &userID,
-			CompletedReviews: 10,
-			AvgTTFR:          &ttfr1,
-			AvgCommentCount:  &commentCount1,
-			EngagementScore:  &engagementScore1,
+			UserID:            &userID,
+			CompletedReviews:  10,
+			AvgTTFR:           &ttfr1,
+			AvgTimeToApproval: &approval1,
+			AvgCommentCount:   &commentCount1,
+			AvgCommentLength:  &commentLength1,
+			EngagementScore:   &engagementScore1,
 		},
 		{
-			UserID:           &userID,
-			CompletedReviews: 15,
-			AvgTTFR:          &ttfr2,
-			AvgCommentCount:  &commentCount2,
-			EngagementScore:  &engagementScore2,
+			UserID:            &userID,
+			CompletedReviews:  15,
+			AvgTTFR:           &ttfr2,
+			AvgTimeToApproval: &approval2,
+			AvgCommentCount:   &commentCount2,
+			AvgCommentLength:  &commentLength2,
+			EngagementScore:   &engagementScore2,
 		},
 	}
 
@@ -429,11 +878,21 @@ func TestAggregateMetricsByUser(t *testing.T) {
 		t.Errorf("Expected avg TTFR %f, got %f", expectedAvgTTFR, userMetrics.AvgTTFR)
 	}
 
+	expectedAvgTimeToApproval := (180.0 + 300.0) / 2
+	if userMetrics.AvgTimeToApproval != expectedAvgTimeToApproval {
+		t.Errorf("Expected avg time to approval %f, got %f", expectedAvgTimeToApproval, userMetrics.AvgTimeToApproval)
+	}
+
 	expectedAvgCommentCount := (5.0 + 7.0) / 2
 	if userMetrics.AvgCommentCount != expectedAvgCommentCount {
 		t.Errorf("Expected avg comment count %f, got %f", expectedAvgCommentCount, userMetrics.AvgCommentCount)
 	}
 
+	expectedAvgCommentLength := (40.0 + 100.0) / 2
+	if userMetrics.AvgCommentLength != expectedAvgCommentLength {
+		t.Errorf("Expected avg comment length %f, got %f", expectedAvgCommentLength, userMetrics.AvgCommentLength)
+	}
+
 	expectedEngagementScore := (8.0 + 9.0) / 2
 	if userMetrics.EngagementScore != expectedEngagementScore {
 		t.Errorf("Expected engagement score %f, got %f", expectedEngagementScore, userMetrics.EngagementScore)
@@ -486,6 +945,9 @@ func TestGetUserStats(t *testing.T) {
 	if stats.Username != "alice" {
 		t.Errorf("Expected username 'alice', got %s", stats.Username)
 	}
+	if stats.DisplayName != "alice" {
+		t.Errorf("Expected display name to fall back to 'alice', got %s", stats.DisplayName)
+	}
 	if stats.Team != "team-frontend" {
 		t.Errorf("Expected team 'team-frontend', got %s", stats.Team)
 	}
@@ -495,6 +957,9 @@ func TestGetUserStats(t *testing.T) {
 	if stats.CompletedReviews != 35 {
 		t.Errorf("Expected 35 completed reviews, got %d", stats.CompletedReviews)
 	}
+	if stats.CompletionRate != 0.875 {
+		t.Errorf("Expected completion rate 0.875, got %f", stats.CompletionRate)
+	}
 	if stats.AvgTTFR != 90.0 {
 		t.Errorf("Expected avg TTFR 90, got %f", stats.AvgTTFR)
 	}
@@ -503,48 +968,593 @@ func TestGetUserStats(t *testing.T) {
 	}
 }
 
-func TestCalculatePeriodRange(t *testing.T) {
+func TestGetUserStats_AtRiskWhenEngagementHalves(t *testing.T) {
+	service, metricsRepo, _, userRepo := setupTestService()
+
+	userID := uint(1)
+	userRepo.users[userID] = &models.User{ID: userID, Username: "alice", Team: "team-frontend"}
+
 	now := time.Now()
+	currentScore := 40.0
+	previousScore := 80.0
+	metricsRepo.metrics = []models.ReviewMetrics{
+		{UserID: &userID, Team: "team-frontend", Date: now.AddDate(0, 0, -2), EngagementScore: &currentScore},
+		{UserID: &userID, Team: "team-frontend", Date: now.AddDate(0, 0, -10), EngagementScore: &previousScore},
+	}
 
-	tests := []struct {
-		period         string
-		expectedDelta  time.Duration
-		toleranceDelta time.Duration
-	}{
-		{"day", 24 * time.Hour, 1 * time.Minute},
-		{"week", 7 * 24 * time.Hour, 1 * time.Minute},
-		{"month", 30 * 24 * time.Hour, 1 * time.Minute},
-		{"year", 365 * 24 * time.Hour, 1 * time.Minute},
+	stats, err := service.GetUserStats(context.Background(), userID, "week")
+	if err != nil {
+		t.Fatalf("GetUserStats failed: %v", err)
+	}
+	if stats.EngagementScore != currentScore {
+		t.Errorf("Expected current engagement %v, got %v", currentScore, stats.EngagementScore)
+	}
+	if stats.Trend != TrendDown || !stats.AtRisk {
+		t.Errorf("Expected trend down and at risk, got trend=%s at_risk=%v", stats.Trend, stats.AtRisk)
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.period, func(t *testing.T) {
-			startDate, endDate := calculatePeriodRange(tt.period)
+	// all_time has no previous window to compare against
+	stats, err = service.GetUserStats(context.Background(), userID, "all_time")
+	if err != nil {
+		t.Fatalf("GetUserStats failed: %v", err)
+	}
+	if stats.Trend != TrendFlat || stats.AtRisk {
+		t.Errorf("Expected flat trend for all_time, got trend=%s at_risk=%v", stats.Trend, stats.AtRisk)
+	}
+}
 
-			// End date should be approximately now
-			if endDate.Sub(now) > 1*time.Second {
-				t.Errorf("End date not close to now: %v", endDate.Sub(now))
-			}
+func TestGetUserStats_PreviousPeriodDeltas(t *testing.T) {
+	service, metricsRepo, _, userRepo := setupTestService()
 
-			// Start date should be approximately expectedDelta ago
-			actualDelta := now.Sub(startDate)
-			deltaError := actualDelta - tt.expectedDelta
+	userID := uint(1)
+	userRepo.users[userID] = &models.User{ID: userID, Username: "alice", Team: "team-frontend"}
 
-			if deltaError < -tt.toleranceDelta || deltaError > tt.toleranceDelta {
-				t.Errorf("Period '%s': expected delta ~%v, got %v (error: %v)", tt.period, tt.expectedDelta, actualDelta, deltaError)
-			}
-		})
+	// Two months of data: this month alice reviews more, faster, with less engagement
+	now := time.Now()
+	ttfr := func(minutes int) *int { return &minutes }
+	score := func(value float64) *float64 { return &value }
+	metricsRepo.metrics = []models.ReviewMetrics{
+		{UserID: &userID, Date: now.AddDate(0, 0, -5), CompletedReviews: 6, AvgTTFR: ttfr(30), EngagementScore: score(40)},
+		{UserID: &userID, Date: now.AddDate(0, 0, -20), CompletedReviews: 4, AvgTTFR: ttfr(50), EngagementScore: score(60)},
+		{UserID: &userID, Date: now.AddDate(0, 0, -40), CompletedReviews: 3, AvgTTFR: ttfr(90), EngagementScore: score(70)},
+		{UserID: &userID, Date: now.AddDate(0, 0, -50), CompletedReviews: 2, AvgTTFR: ttfr(110), EngagementScore: score(70)},
 	}
 
-	// Test all_time
-	t.Run("all_time", func(t *testing.T) {
-		startDate, _ := calculatePeriodRange("all_time")
-		expected := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
-		if !startDate.Equal(expected) {
-			t.Errorf("Expected start date %v, got %v", expected, startDate)
-		}
-	})
-}
+	stats, err := service.GetUserStats(context.Background(), userID, "month")
+	if err != nil {
+		t.Fatalf("GetUserStats failed: %v", err)
+	}
+
+	// Current: 10 reviews, 40 min TTFR, 50 engagement. Previous: 5 reviews, 100 min TTFR, 70 engagement.
+	if stats.CompletedReviewsDelta == nil || *stats.CompletedReviewsDelta != 5 {
+		t.Errorf("Expected completed reviews delta +5, got %v", stats.CompletedReviewsDelta)
+	}
+	if stats.AvgTTFRDelta == nil || *stats.AvgTTFRDelta != -60 {
+		t.Errorf("Expected avg TTFR delta -60 (faster), got %v", stats.AvgTTFRDelta)
+	}
+	if stats.EngagementScoreDelta == nil || *stats.EngagementScoreDelta != -20 {
+		t.Errorf("Expected engagement delta -20, got %v", stats.EngagementScoreDelta)
+	}
+
+	// all_time has no previous period
+	stats, err = service.GetUserStats(context.Background(), userID, "all_time")
+	if err != nil {
+		t.Fatalf("GetUserStats failed: %v", err)
+	}
+	if stats.CompletedReviewsDelta != nil || stats.AvgTTFRDelta != nil || stats.EngagementScoreDelta != nil {
+		t.Errorf("Expected no deltas for all_time, got %v, %v, %v",
+			stats.CompletedReviewsDelta, stats.AvgTTFRDelta, stats.EngagementScoreDelta)
+	}
+}
+
+func TestGetUserStats_DayPeriodDeltas(t *testing.T) {
+	today := startOfDay(time.Now())
+	yesterday := today.AddDate(0, 0, -1)
+
+	for _, calendar := range []bool{false, true} {
+		t.Run(fmt.Sprintf("calendar=%v", calendar), func(t *testing.T) {
+			service, metricsRepo, _, userRepo := setupTestService()
+			service.calendarPeriods = calendar
+
+			userID := uint(1)
+			userRepo.users[userID] = &models.User{ID: userID, Username: "alice", Team: "team-frontend"}
+
+			// Rows are dated at midnight; only yesterday's belongs to the previous day
+			metricsRepo.metrics = []models.ReviewMetrics{
+				{UserID: &userID, Date: today, CompletedReviews: 3},
+				{UserID: &userID, Date: yesterday, CompletedReviews: 1},
+				{UserID: &userID, Date: yesterday.AddDate(0, 0, -1), CompletedReviews: 10},
+			}
+
+			stats, err := service.GetUserStats(context.Background(), userID, "day")
+			if err != nil {
+				t.Fatalf("GetUserStats failed: %v", err)
+			}
+			if stats.CompletedReviews != 3 {
+				t.Errorf("Expected 3 completed reviews today, got %d", stats.CompletedReviews)
+			}
+			if stats.CompletedReviewsDelta == nil || *stats.CompletedReviewsDelta != 2 {
+				t.Errorf("Expected completed reviews delta +2 against yesterday, got %v", stats.CompletedReviewsDelta)
+			}
+		})
+	}
+}
+
+func TestAggregateMetricsBy_PerFieldSamples(t *testing.T) {
+	service, _, _, _ := setupTestService()
+
+	userID := uint(1)
+	ttfr := func(minutes int) *int { return &minutes }
+	score := func(value float64) *float64 { return &value }
+
+	aggregated := service.aggregateMetricsByUser([]models.ReviewMetrics{
+		{UserID: &userID, AvgTTFR: ttfr(30), TTFRSamples: 3, AvgTimeToApproval: ttfr(120), ApprovalSamples: 2,
+			AvgCommentCount: score(4), AvgCommentLength: score(50), CommentSamples: 2, EngagementScore: score(80), EngagementSamples: 1},
+		{UserID: &userID, AvgTTFR: ttfr(90), TTFRSamples: 1},
+		{UserID: &userID, CompletedReviews: 1}, // No averages recorded
+	})
+
+	agg := aggregated[userID]
+	if agg.MetricsCount != 3 {
+		t.Errorf("Expected 3 rows, got %d", agg.MetricsCount)
+	}
+	if agg.AvgTTFR != 45 || agg.TTFRSamples != 4 {
+		t.Errorf("Expected AvgTTFR 45 over 4 samples, got %.2f over %d", agg.AvgTTFR, agg.TTFRSamples)
+	}
+	if agg.AvgTimeToApproval != 120 {
+		t.Errorf("Expected time to approval 120 from its only row, got %.2f", agg.AvgTimeToApproval)
+	}
+	if agg.AvgCommentCount != 4 || agg.AvgCommentLength != 50 {
+		t.Errorf("Expected comment averages 4 and 50, got %.2f and %.2f", agg.AvgCommentCount, agg.AvgCommentLength)
+	}
+	if agg.EngagementScore != 80 {
+		t.Errorf("Expected engagement 80 from its only sample, got %.2f", agg.EngagementScore)
+	}
+}
+
+func TestAggregateUserPeriod_PerFieldSamples(t *testing.T) {
+	ttfr := func(minutes int) *int { return &minutes }
+	score := func(value float64) *float64 { return &value }
+
+	totals := aggregateUserPeriod([]models.ReviewMetrics{
+		{AvgTTFR: ttfr(30), TTFRSamples: 3, EngagementScore: score(80), EngagementSamples: 1},
+		{AvgTTFR: ttfr(90), TTFRSamples: 1},
+		{CompletedReviews: 1}, // No averages recorded
+	})
+
+	if totals.AvgTTFR != 45 {
+		t.Errorf("Expected AvgTTFR 45 over 4 samples, got %.2f", totals.AvgTTFR)
+	}
+	if totals.EngagementScore != 80 {
+		t.Errorf("Expected engagement 80 from its only sample, got %.2f", totals.EngagementScore)
+	}
+	if totals.AvgTimeToApproval != 0 {
+		t.Errorf("Expected no time to approval, got %.2f", totals.AvgTimeToApproval)
+	}
+}
+
+func TestGetUserStats_NoPreviousTTFR(t *testing.T) {
+	service, metricsRepo, _, userRepo := setupTestService()
+
+	userID := uint(1)
+	userRepo.users[userID] = &models.User{ID: userID, Username: "alice", Team: "team-frontend"}
+
+	ttfr := 30
+	metricsRepo.metrics = []models.ReviewMetrics{
+		{UserID: &userID, Date: time.Now().AddDate(0, 0, -5), CompletedReviews: 2, AvgTTFR: &ttfr},
+	}
+
+	stats, err := service.GetUserStats(context.Background(), userID, "month")
+	if err != nil {
+		t.Fatalf("GetUserStats failed: %v", err)
+	}
+	// A first month compares against nothing: counts still have a delta, TTFR does not
+	if stats.CompletedReviewsDelta == nil || *stats.CompletedReviewsDelta != 2 {
+		t.Errorf("Expected completed reviews delta +2, got %v", stats.CompletedReviewsDelta)
+	}
+	if stats.AvgTTFRDelta != nil {
+		t.Errorf("Expected no TTFR delta without a previous TTFR, got %v", *stats.AvgTTFRDelta)
+	}
+}
+
+func TestGetUserStats_UserNotFound(t *testing.T) {
+	service, _, _, _ := setupTestService()
+
+	_, err := service.GetUserStats(context.Background(), 999, "month")
+	if !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestBadgeRepositoryDown_PartialResults(t *testing.T) {
+	service, metricsRepo, badgeRepo, userRepo := setupTestService()
+
+	aliceID := uint(1)
+	bobID := uint(2)
+	userRepo.users[aliceID] = &models.User{ID: aliceID, Username: "alice", Team: "team-a"}
+	userRepo.users[bobID] = &models.User{ID: bobID, Username: "bob", Team: "team-a"}
+
+	aliceScore := 9.0
+	bobScore := 7.0
+	metricsRepo.metrics = []models.ReviewMetrics{
+		{UserID: &aliceID, Team: "team-a", CompletedReviews: 10, EngagementScore: &aliceScore},
+		{UserID: &bobID, Team: "team-a", CompletedReviews: 5, EngagementScore: &bobScore},
+	}
+	badgeRepo.userBadgeCounts[aliceID] = 3
+	badgeRepo.err = errors.New("badge database unavailable")
+
+	t.Run("leaderboard", func(t *testing.T) {
+		ctx, warnings := WithWarnings(context.Background())
+		entries, err := service.GetGlobalLeaderboard(ctx, Filters{}, "all_time", "completed_reviews", 10)
+		if err != nil {
+			t.Fatalf("GetGlobalLeaderboard failed: %v", err)
+		}
+		if len(entries) != 2 {
+			t.Fatalf("Expected 2 entries, got %d", len(entries))
+		}
+		for _, entry := range entries {
+			if entry.BadgeCount != 0 {
+				t.Errorf("Expected zeroed badge count for %s, got %d", entry.Username, entry.BadgeCount)
+			}
+		}
+		if got := warnings.List(); len(got) != 1 || got[0] != WarningBadges {
+			t.Errorf("Expected warnings [%s], got %v", WarningBadges, got)
+		}
+	})
+
+	t.Run("user stats", func(t *testing.T) {
+		ctx, warnings := WithWarnings(context.Background())
+		stats, err := service.GetUserStats(ctx, bobID, "all_time")
+		if err != nil {
+			t.Fatalf("GetUserStats failed: %v", err)
+		}
+		if len(stats.Badges) != 0 {
+			t.Errorf("Expected no badges, got %d", len(stats.Badges))
+		}
+		// Ranks by engagement do not depend on badges and are still computed
+		if stats.GlobalRank != 2 || stats.TeamRank != 2 {
+			t.Errorf("Expected global and team rank 2, got %d and %d", stats.GlobalRank, stats.TeamRank)
+		}
+		if got := warnings.List(); len(got) != 1 || got[0] != WarningBadges {
+			t.Errorf("Expected warnings [%s], got %v", WarningBadges, got)
+		}
+	})
+
+	t.Run("without collector", func(t *testing.T) {
+		if _, err := service.GetGlobalLeaderboard(context.Background(), Filters{}, "all_time", "completed_reviews", 10); err != nil {
+			t.Fatalf("GetGlobalLeaderboard failed: %v", err)
+		}
+	})
+}
+
+func TestGetUserStats_RanksUnavailable(t *testing.T) {
+	service, metricsRepo, _, userRepo := setupTestService()
+
+	userID := uint(1)
+	userRepo.users[userID] = &models.User{ID: userID, Username: "alice", Team: "team-a"}
+	score := 8.0
+	metricsRepo.metrics = []models.ReviewMetrics{{UserID: &userID, CompletedReviews: 4, EngagementScore: &score}}
+
+	// The user's own metrics load, but the leaderboards used for ranking fail
+	service.metricsRepo = &rankingFailureMetricsRepository{mockMetricsRepository: metricsRepo}
+
+	ctx, warnings := WithWarnings(context.Background())
+	stats, err := service.GetUserStats(ctx, userID, "all_time")
+	if err != nil {
+		t.Fatalf("GetUserStats failed: %v", err)
+	}
+	if stats.CompletedReviews != 4 {
+		t.Errorf("Expected 4 completed reviews, got %d", stats.CompletedReviews)
+	}
+	if stats.GlobalRank != 0 || stats.TeamRank != 0 {
+		t.Errorf("Expected zeroed ranks, got %d and %d", stats.GlobalRank, stats.TeamRank)
+	}
+	if got := warnings.List(); len(got) != 1 || got[0] != WarningRanks {
+		t.Errorf("Expected warnings [%s], got %v", WarningRanks, got)
+	}
+}
+
+func TestGetUserStats_UnrankedUserHasNoWarnings(t *testing.T) {
+	service, _, _, userRepo := setupTestService()
+
+	userID := uint(1)
+	userRepo.users[userID] = &models.User{ID: userID, Username: "alice", Team: "team-a"}
+
+	ctx, warnings := WithWarnings(context.Background())
+	stats, err := service.GetUserStats(ctx, userID, "all_time")
+	if err != nil {
+		t.Fatalf("GetUserStats failed: %v", err)
+	}
+	if stats.GlobalRank != 0 {
+		t.Errorf("Expected unranked user, got rank %d", stats.GlobalRank)
+	}
+	if got := warnings.List(); got != nil {
+		t.Errorf("Expected no warnings for a user without metrics, got %v", got)
+	}
+}
+
+// rankingFailureMetricsRepository fails leaderboard queries while per-user queries succeed.
+type rankingFailureMetricsRepository struct {
+	*mockMetricsRepository
+}
+
+func (m *rankingFailureMetricsRepository) GetByDateRange(startDate, endDate time.Time, filters map[string]interface{}) ([]models.ReviewMetrics, error) {
+	return nil, errors.New("metrics replica unavailable")
+}
+
+func TestEngagementTrend(t *testing.T) {
+	tests := []struct {
+		name       string
+		current    float64
+		previous   float64
+		wantTrend  string
+		wantAtRisk bool
+	}{
+		{"halved", 40, 80, TrendDown, true},
+		{"slight drop", 70, 80, TrendDown, false},
+		{"stable", 78, 80, TrendFlat, false},
+		{"improved", 100, 80, TrendUp, false},
+		{"no previous activity", 50, 0, TrendUp, false},
+		{"no activity", 0, 0, TrendFlat, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trend, atRisk := engagementTrend(tt.current, tt.previous)
+			if trend != tt.wantTrend || atRisk != tt.wantAtRisk {
+				t.Errorf("engagementTrend(%v, %v) = (%s, %v), want (%s, %v)",
+					tt.current, tt.previous, trend, atRisk, tt.wantTrend, tt.wantAtRisk)
+			}
+		})
+	}
+}
+
+func TestGetUserMetricsHistory(t *testing.T) {
+	service, metricsRepo, _, userRepo := setupTestService()
+
+	userID := uint(1)
+	otherID := uint(2)
+	userRepo.users[userID] = &models.User{ID: userID, Username: "alice"}
+
+	metricsRepo.metrics = []models.ReviewMetrics{
+		{UserID: &userID, TotalReviews: 3},
+		{UserID: &userID, TotalReviews: 5},
+		{UserID: &otherID, TotalReviews: 7},
+	}
+
+	end := time.Now()
+	history, err := service.GetUserMetricsHistory(context.Background(), userID, end.AddDate(0, 0, -30), end)
+	if err != nil {
+		t.Fatalf("GetUserMetricsHistory failed: %v", err)
+	}
+	if len(history) != 2 {
+		t.Errorf("Expected 2 metrics rows for alice, got %d", len(history))
+	}
+
+	if _, err := service.GetUserMetricsHistory(context.Background(), 999, end.AddDate(0, 0, -30), end); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound for an unknown user, got %v", err)
+	}
+}
+
+func TestGetUserMetricsHistory_MaxQueryRange(t *testing.T) {
+	metricsRepo := newMockMetricsRepository()
+	userRepo := newMockUserRepository()
+	cfg := &config.Config{Metrics: config.MetricsConfig{MaxQueryRangeDays: config.DefaultMaxQueryRangeDays}}
+	service := NewServiceWithInterfaces(cfg, metricsRepo, newMockBadgeRepository(), userRepo, newMockPersonalBestRepository(), logger.New("error", "json", "stdout"))
+
+	userID := uint(1)
+	userRepo.users[userID] = &models.User{ID: userID, Username: "alice"}
+	metricsRepo.metrics = []models.ReviewMetrics{{UserID: &userID, TotalReviews: 3}}
+
+	end := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+
+	// A 10-year range is rejected
+	if _, err := service.GetUserMetricsHistory(context.Background(), userID, end.AddDate(-10, 0, 0), end); !errors.Is(err, metrics.ErrDateRangeTooLarge) {
+		t.Errorf("Expected ErrDateRangeTooLarge for a 10-year range, got %v", err)
+	}
+
+	// A 90-day range succeeds
+	history, err := service.GetUserMetricsHistory(context.Background(), userID, end.AddDate(0, 0, -90), end)
+	if err != nil {
+		t.Fatalf("Expected 90-day range to succeed, got %v", err)
+	}
+	if len(history) != 1 {
+		t.Errorf("Expected 1 metrics row, got %d", len(history))
+	}
+}
+
+type mockProjectRepository struct {
+	projects map[int]*models.Project
+}
+
+func (m *mockProjectRepository) GetByGitLabID(gitlabProjectID int) (*models.Project, error) {
+	return m.projects[gitlabProjectID], nil
+}
+
+func TestGetProjectLeaderboard(t *testing.T) {
+	service, metricsRepo, _, userRepo := setupTestService()
+	service.SetProjectRepository(&mockProjectRepository{projects: map[int]*models.Project{
+		42: {GitLabProjectID: 42, Name: "payments-api", Path: "backend/payments-api"},
+	}})
+
+	aliceID := uint(1)
+	bobID := uint(2)
+	userRepo.users[aliceID] = &models.User{ID: aliceID, Username: "alice", Team: "backend"}
+	userRepo.users[bobID] = &models.User{ID: bobID, Username: "bob", Team: "backend"}
+
+	paymentsID := 42
+	otherID := 7
+	metricsRepo.metrics = []models.ReviewMetrics{
+		{UserID: &aliceID, Team: "backend", ProjectID: &paymentsID, CompletedReviews: 5},
+		{UserID: &bobID, Team: "backend", ProjectID: &otherID, CompletedReviews: 9},
+	}
+
+	board, err := service.GetProjectLeaderboard(context.Background(), paymentsID, Filters{}, "all_time", "completed_reviews", 10)
+	if err != nil {
+		t.Fatalf("GetProjectLeaderboard failed: %v", err)
+	}
+	if board.ProjectID != 42 || board.ProjectName != "payments-api" || board.ProjectPath != "backend/payments-api" {
+		t.Errorf("Expected project 42 named payments-api, got %+v", board)
+	}
+	if len(board.Entries) != 1 || board.Entries[0].Username != "alice" {
+		t.Errorf("Expected only alice on the project board, got %+v", board.Entries)
+	}
+
+	// Unknown projects still return their board, without a name
+	board, err = service.GetProjectLeaderboard(context.Background(), otherID, Filters{}, "all_time", "completed_reviews", 10)
+	if err != nil {
+		t.Fatalf("GetProjectLeaderboard failed: %v", err)
+	}
+	if board.ProjectID != otherID || board.ProjectName != "" || len(board.Entries) != 1 {
+		t.Errorf("Expected unnamed board for project %d, got %+v", otherID, board)
+	}
+}
+
+func TestCompletionRate(t *testing.T) {
+	tests := []struct {
+		name      string
+		completed int
+		total     int
+		want      float64
+	}{
+		{"partial", 35, 40, 0.875},
+		{"all completed", 10, 10, 1},
+		{"zero total", 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := completionRate(tt.completed, tt.total); got != tt.want {
+				t.Errorf("completionRate(%d, %d) = %f, want %f", tt.completed, tt.total, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLeaderboard_DisplayName(t *testing.T) {
+	service, metricsRepo, _, userRepo := setupTestService()
+
+	user1ID := uint(1)
+	user2ID := uint(2)
+
+	userRepo.users[user1ID] = &models.User{
+		ID:          user1ID,
+		Username:    "alice",
+		DisplayName: "Alice Martin",
+		Team:        "team-frontend",
+	}
+	userRepo.users[user2ID] = &models.User{
+		ID:       user2ID,
+		Username: "bob",
+		Team:     "team-frontend",
+	}
+
+	metricsRepo.metrics = []models.ReviewMetrics{
+		{UserID: &user1ID, Team: "team-frontend", CompletedReviews: 10},
+		{UserID: &user2ID, Team: "team-frontend", CompletedReviews: 5},
+	}
+
+	entries, err := service.GetGlobalLeaderboard(context.Background(), Filters{}, "all_time", "completed_reviews", 0)
+	if err != nil {
+		t.Fatalf("GetGlobalLeaderboard failed: %v", err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	if entries[0].Username != "alice" || entries[0].DisplayName != "Alice Martin" {
+		t.Errorf("Expected alice shown as 'Alice Martin', got %s shown as %s", entries[0].Username, entries[0].DisplayName)
+	}
+	if entries[1].Username != "bob" || entries[1].DisplayName != "bob" {
+		t.Errorf("Expected bob shown as 'bob', got %s shown as %s", entries[1].Username, entries[1].DisplayName)
+	}
+}
+
+func TestCalculatePeriodRange(t *testing.T) {
+	service, _, _, _ := setupTestService()
+	now := time.Date(2024, time.May, 15, 14, 30, 0, 0, time.UTC)
+
+	// Rolling windows come from the shared period package, ending at the reference time
+	for _, name := range []string{"day", "week", "month", "year", "all_time", "", "unknown"} {
+		t.Run(name, func(t *testing.T) {
+			startDate, endDate := service.calculatePeriodRange(name, now)
+			wantStart, wantEnd := period.Rolling(name, now)
+			if !startDate.Equal(wantStart) || !endDate.Equal(wantEnd) {
+				t.Errorf("Expected %v - %v, got %v - %v", wantStart, wantEnd, startDate, endDate)
+			}
+		})
+	}
+
+	t.Run("all_time starts at the epoch", func(t *testing.T) {
+		startDate, _ := service.calculatePeriodRange("all_time", now)
+		expected := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+		if !startDate.Equal(expected) {
+			t.Errorf("Expected start date %v, got %v", expected, startDate)
+		}
+	})
+}
+
+func TestCalculatePeriodRange_CalendarPeriods(t *testing.T) {
+	service, _, _, _ := setupTestService()
+	service.calendarPeriods = true
+
+	now := time.Date(2024, time.May, 15, 14, 30, 0, 0, time.UTC)
+	startDate, endDate := service.calculatePeriodRange("month", now)
+	if !startDate.Equal(time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)) || !endDate.Equal(now) {
+		t.Errorf("Expected the calendar month up to now, got %v - %v", startDate, endDate)
+	}
+}
+
+func TestGetLeaderboard_AsOf(t *testing.T) {
+	service, metricsRepo, _, userRepo := setupTestService()
+
+	alice, bob := uint(1), uint(2)
+	userRepo.users[alice] = &models.User{ID: alice, Username: "alice", Team: "team-platform"}
+	userRepo.users[bob] = &models.User{ID: bob, Username: "bob", Team: "team-platform"}
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	metricsRepo.metrics = []models.ReviewMetrics{
+		// February: alice leads
+		{Date: day(2024, time.February, 10), UserID: &alice, CompletedReviews: 12},
+		{Date: day(2024, time.February, 20), UserID: &bob, CompletedReviews: 4},
+		// April: bob leads
+		{Date: day(2024, time.April, 10), UserID: &alice, CompletedReviews: 3},
+		{Date: day(2024, time.April, 15), UserID: &bob, CompletedReviews: 9},
+	}
+
+	marchStart, marchEnd := service.calculatePeriodRange("month", day(2024, time.March, 1))
+	mayStart, mayEnd := service.calculatePeriodRange("month", day(2024, time.May, 1))
+	if !marchEnd.Equal(day(2024, time.March, 1)) || marchStart.Equal(mayStart) || marchEnd.Equal(mayEnd) {
+		t.Fatalf("Expected windows ending at each as_of, got %v-%v and %v-%v", marchStart, marchEnd, mayStart, mayEnd)
+	}
+
+	march, err := service.GetGlobalLeaderboard(context.Background(), Filters{AsOf: day(2024, time.March, 1)}, "month", "completed_reviews", 0)
+	if err != nil {
+		t.Fatalf("GetGlobalLeaderboard failed: %v", err)
+	}
+	if len(march) != 2 || march[0].Username != "alice" || march[0].CompletedReviews != 12 {
+		t.Errorf("Expected alice to lead February with 12 reviews, got %+v", march)
+	}
+
+	may, err := service.GetGlobalLeaderboard(context.Background(), Filters{AsOf: day(2024, time.May, 1)}, "month", "completed_reviews", 0)
+	if err != nil {
+		t.Fatalf("GetGlobalLeaderboard failed: %v", err)
+	}
+	if len(may) != 2 || may[0].Username != "bob" || may[0].CompletedReviews != 9 {
+		t.Errorf("Expected bob to lead April with 9 reviews, got %+v", may)
+	}
+
+	// Without as_of the month ends now, long after these fixtures
+	live, err := service.GetGlobalLeaderboard(context.Background(), Filters{}, "month", "completed_reviews", 0)
+	if err != nil {
+		t.Fatalf("GetGlobalLeaderboard failed: %v", err)
+	}
+	if len(live) != 0 {
+		t.Errorf("Expected an empty live board, got %+v", live)
+	}
+}
 
 func TestLeaderboard_WithLimit(t *testing.T) {
 	service, metricsRepo, _, userRepo := setupTestService()
@@ -564,7 +1574,7 @@ func TestLeaderboard_WithLimit(t *testing.T) {
 	}
 
 	// Get leaderboard with limit 3
-	leaderboard, err := service.GetGlobalLeaderboard(context.Background(), "all_time", "completed_reviews", 3)
+	leaderboard, err := service.GetGlobalLeaderboard(context.Background(), Filters{}, "all_time", "completed_reviews", 3)
 	if err != nil {
 		t.Fatalf("GetGlobalLeaderboard failed: %v", err)
 	}
@@ -574,3 +1584,586 @@ func TestLeaderboard_WithLimit(t *testing.T) {
 		t.Errorf("Expected 3 entries (limit), got %d", len(leaderboard))
 	}
 }
+
+func TestLeaderboard_PointsOrdering(t *testing.T) {
+	metricsRepo := newMockMetricsRepository()
+	badgeRepo := newMockBadgeRepository()
+	userRepo := newMockUserRepository()
+	cfg := &config.Config{
+		Leaderboard: config.LeaderboardConfig{
+			Points: config.PointsWeightsConfig{CompletedReviews: 5, Engagement: 0.5, Badges: 20},
+		},
+	}
+	service := NewServiceWithInterfaces(cfg, metricsRepo, badgeRepo, userRepo, newMockPersonalBestRepository(), logger.New("debug", "text", "stdout"))
+
+	aliceID, bobID, charlieID := uint(1), uint(2), uint(3)
+	userRepo.users[aliceID] = &models.User{ID: aliceID, Username: "alice"}
+	userRepo.users[bobID] = &models.User{ID: bobID, Username: "bob"}
+	userRepo.users[charlieID] = &models.User{ID: charlieID, Username: "charlie"}
+
+	aliceEngagement, bobEngagement, charlieEngagement := 5.0, 100.0, 10.0
+	metricsRepo.metrics = []models.ReviewMetrics{
+		{UserID: &aliceID, CompletedReviews: 10, EngagementScore: &aliceEngagement},
+		{UserID: &bobID, CompletedReviews: 2, EngagementScore: &bobEngagement},
+		{UserID: &charlieID, CompletedReviews: 4, EngagementScore: &charlieEngagement},
+	}
+	badgeRepo.userBadgeCounts[charlieID] = 3
+
+	// alice: 10*5 + 5*0.5 = 52.5, bob: 2*5 + 100*0.5 = 60, charlie: 4*5 + 10*0.5 + 3*20 = 85
+	entries, err := service.GetGlobalLeaderboard(context.Background(), Filters{}, "all_time", "points", 0)
+	if err != nil {
+		t.Fatalf("GetGlobalLeaderboard failed: %v", err)
+	}
+
+	order := []string{entries[0].Username, entries[1].Username, entries[2].Username}
+	expected := []string{"charlie", "bob", "alice"}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("Expected points order %v, got %v", expected, order)
+		}
+	}
+	if entries[0].Points != 85 || entries[1].Points != 60 || entries[2].Points != 52.5 {
+		t.Errorf("Unexpected points: %.1f, %.1f, %.1f", entries[0].Points, entries[1].Points, entries[2].Points)
+	}
+
+	// Points ranking must differ from every single-metric ranking
+	for _, metric := range []string{"completed_reviews", "engagement_score"} {
+		single, err := service.GetGlobalLeaderboard(context.Background(), Filters{}, "all_time", metric, 0)
+		if err != nil {
+			t.Fatalf("GetGlobalLeaderboard(%s) failed: %v", metric, err)
+		}
+		if single[0].Username == "charlie" && single[1].Username == "bob" && single[2].Username == "alice" {
+			t.Errorf("Expected points ordering to differ from %s ordering", metric)
+		}
+	}
+}
+
+func TestLeaderboard_StreakOrdering(t *testing.T) {
+	service, metricsRepo, _, userRepo := setupTestService()
+
+	aliceID, bobID := uint(1), uint(2)
+	userRepo.users[aliceID] = &models.User{ID: aliceID, Username: "alice"}
+	userRepo.users[bobID] = &models.User{ID: bobID, Username: "bob"}
+
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	metricsRepo.metrics = []models.ReviewMetrics{
+		// alice: many reviews on scattered days, longest streak 1
+		{UserID: &aliceID, Date: day(1), CompletedReviews: 10},
+		{UserID: &aliceID, Date: day(3), CompletedReviews: 10},
+		{UserID: &aliceID, Date: day(5), CompletedReviews: 10},
+		// bob: one review a day for four days, with an idle day not breaking the count
+		{UserID: &bobID, Date: day(1), CompletedReviews: 1},
+		{UserID: &bobID, Date: day(2), CompletedReviews: 1},
+		{UserID: &bobID, Date: day(3), CompletedReviews: 1},
+		{UserID: &bobID, Date: day(4), CompletedReviews: 1},
+		{UserID: &bobID, Date: day(6), CompletedReviews: 0},
+	}
+
+	entries, err := service.GetGlobalLeaderboard(context.Background(), Filters{}, "all_time", "streak", 0)
+	if err != nil {
+		t.Fatalf("GetGlobalLeaderboard failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	if entries[0].Username != "bob" || entries[0].LongestStreak != 4 {
+		t.Errorf("Expected bob first with a 4 day streak, got %s with %d", entries[0].Username, entries[0].LongestStreak)
+	}
+	if entries[1].Username != "alice" || entries[1].LongestStreak != 1 {
+		t.Errorf("Expected alice second with a 1 day streak, got %s with %d", entries[1].Username, entries[1].LongestStreak)
+	}
+	if entries[1].CompletedReviews <= entries[0].CompletedReviews {
+		t.Errorf("Expected alice to have more completed reviews than bob")
+	}
+}
+
+func TestLeaderboard_DefaultPointsWeights(t *testing.T) {
+	service, _, _, _ := setupTestService()
+
+	if service.pointsWeights != DefaultPointsWeights {
+		t.Errorf("Expected default points weights %+v, got %+v", DefaultPointsWeights, service.pointsWeights)
+	}
+}
+
+func TestLeaderboard_TeamPointsWeights(t *testing.T) {
+	metricsRepo := newMockMetricsRepository()
+	badgeRepo := newMockBadgeRepository()
+	userRepo := newMockUserRepository()
+	cfg := &config.Config{
+		Teams: []config.TeamConfig{
+			// Ops values throughput, product values thoroughness
+			{Name: "team-ops", PointsWeights: &config.PointsWeightsConfig{CompletedReviews: 10, Engagement: 0.1}},
+			{Name: "team-product", PointsWeights: &config.PointsWeightsConfig{CompletedReviews: 1, Engagement: 2}},
+			{Name: "team-other"},
+		},
+	}
+	service := NewServiceWithInterfaces(cfg, metricsRepo, badgeRepo, userRepo, newMockPersonalBestRepository(), logger.New("debug", "text", "stdout"))
+
+	// Identical underlying data for every team
+	fastID, thoroughID := uint(1), uint(2)
+	userRepo.users[fastID] = &models.User{ID: fastID, Username: "fast", Team: "team-ops"}
+	userRepo.users[thoroughID] = &models.User{ID: thoroughID, Username: "thorough", Team: "team-ops"}
+	fastEngagement, thoroughEngagement := 10.0, 80.0
+	metricsRepo.metrics = []models.ReviewMetrics{
+		{UserID: &fastID, CompletedReviews: 8, EngagementScore: &fastEngagement},
+		{UserID: &thoroughID, CompletedReviews: 3, EngagementScore: &thoroughEngagement},
+	}
+
+	// The mock filters by team, so move the same rows to each team in turn
+	for i := range metricsRepo.metrics {
+		metricsRepo.metrics[i].Team = "team-ops"
+	}
+	ops, err := service.GetTeamLeaderboard(context.Background(), "team-ops", Filters{}, "all_time", "points", 0)
+	if err != nil {
+		t.Fatalf("GetTeamLeaderboard(team-ops) failed: %v", err)
+	}
+
+	for i := range metricsRepo.metrics {
+		metricsRepo.metrics[i].Team = "team-product"
+	}
+	product, err := service.GetTeamLeaderboard(context.Background(), "team-product", Filters{}, "all_time", "points", 0)
+	if err != nil {
+		t.Fatalf("GetTeamLeaderboard(team-product) failed: %v", err)
+	}
+
+	// ops: fast = 80 + 1 = 81, thorough = 30 + 8 = 38
+	if ops[0].Username != "fast" || ops[0].Points != 81 || ops[1].Points != 38 {
+		t.Errorf("Unexpected team-ops points: %s=%.1f, %s=%.1f", ops[0].Username, ops[0].Points, ops[1].Username, ops[1].Points)
+	}
+	// product: fast = 8 + 20 = 28, thorough = 3 + 160 = 163
+	if product[0].Username != "thorough" || product[0].Points != 163 || product[1].Points != 28 {
+		t.Errorf("Unexpected team-product points: %s=%.1f, %s=%.1f", product[0].Username, product[0].Points, product[1].Username, product[1].Points)
+	}
+
+	// Teams without overrides use the global weights
+	if got := service.pointsWeightsForTeam("team-other"); got != DefaultPointsWeights {
+		t.Errorf("Expected default weights for team-other, got %+v", got)
+	}
+}
+
+func TestLeaderboard_SetTeamsReloadsPointsWeights(t *testing.T) {
+	cfg := &config.Config{
+		Teams: []config.TeamConfig{
+			{Name: "team-ops", PointsWeights: &config.PointsWeightsConfig{CompletedReviews: 10, Engagement: 0.1}},
+		},
+	}
+	service := NewServiceWithInterfaces(cfg, newMockMetricsRepository(), newMockBadgeRepository(), newMockUserRepository(), newMockPersonalBestRepository(), logger.New("debug", "text", "stdout"))
+
+	service.SetTeams([]config.TeamConfig{
+		{Name: "team-ops"},
+		{Name: "team-product", PointsWeights: &config.PointsWeightsConfig{CompletedReviews: 1, Engagement: 2}},
+	})
+
+	if got := service.pointsWeightsForTeam("team-ops"); got != DefaultPointsWeights {
+		t.Errorf("Expected team-ops override to be dropped, got %+v", got)
+	}
+	want := config.PointsWeightsConfig{CompletedReviews: 1, Engagement: 2}
+	if got := service.pointsWeightsForTeam("team-product"); got != want {
+		t.Errorf("Expected reloaded team-product weights %+v, got %+v", want, got)
+	}
+}
+
+func TestLeaderboard_ExcludedUsers(t *testing.T) {
+	metricsRepo := newMockMetricsRepository()
+	badgeRepo := newMockBadgeRepository()
+	userRepo := newMockUserRepository()
+	cfg := &config.Config{
+		ExcludedUsers: config.ExcludedUsersConfig{GitLabIDs: []int{99}},
+	}
+	service := NewServiceWithInterfaces(cfg, metricsRepo, badgeRepo, userRepo, newMockPersonalBestRepository(), logger.New("debug", "text", "stdout"))
+
+	aliceID, botID := uint(1), uint(2)
+	userRepo.users[aliceID] = &models.User{ID: aliceID, GitLabID: 1, Username: "alice", Team: "team-frontend"}
+	userRepo.users[botID] = &models.User{ID: botID, GitLabID: 99, Username: "renovate-bot", Team: "team-frontend"}
+	metricsRepo.metrics = []models.ReviewMetrics{
+		{UserID: &aliceID, Team: "team-frontend", CompletedReviews: 2},
+		{UserID: &botID, Team: "team-frontend", CompletedReviews: 50},
+	}
+
+	global, err := service.GetGlobalLeaderboard(context.Background(), Filters{}, "all_time", "completed_reviews", 0)
+	if err != nil {
+		t.Fatalf("GetGlobalLeaderboard failed: %v", err)
+	}
+	team, err := service.GetTeamLeaderboard(context.Background(), "team-frontend", Filters{}, "all_time", "completed_reviews", 0)
+	if err != nil {
+		t.Fatalf("GetTeamLeaderboard failed: %v", err)
+	}
+
+	for name, entries := range map[string][]Entry{"global": global, "team": team} {
+		if len(entries) != 1 || entries[0].Username != "alice" || entries[0].Rank != 1 {
+			t.Errorf("Expected only alice on the %s leaderboard, got %+v", name, entries)
+		}
+	}
+
+	if _, err := service.GetUserStats(context.Background(), botID, "all_time"); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected GetUserStats to return ErrUserNotFound for an excluded user, got %v", err)
+	}
+	if _, err := service.GetUserMetricsHistory(context.Background(), botID, time.Time{}, time.Now()); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected GetUserMetricsHistory to return ErrUserNotFound for an excluded user, got %v", err)
+	}
+	if _, err := service.GetUserStats(context.Background(), aliceID, "all_time"); err != nil {
+		t.Errorf("Expected GetUserStats to succeed for alice, got %v", err)
+	}
+}
+
+func TestUpdatePersonalBests(t *testing.T) {
+	metricsRepo := newMockMetricsRepository()
+	userRepo := newMockUserRepository()
+	bestRepo := newMockPersonalBestRepository()
+	service := NewServiceWithInterfaces(&config.Config{}, metricsRepo, newMockBadgeRepository(), userRepo, bestRepo, logger.New("debug", "text", "stdout"))
+
+	userID := uint(1)
+	userRepo.users[userID] = &models.User{ID: userID, Username: "alice"}
+
+	setPeriod := func(ttfr int, engagement float64) {
+		metricsRepo.metrics = []models.ReviewMetrics{
+			{UserID: &userID, AvgTTFR: &ttfr, EngagementScore: &engagement},
+		}
+	}
+	best := func(metric string) float64 {
+		t.Helper()
+		stored, _ := bestRepo.GetByUserMetricPeriod(userID, metric, "week")
+		if stored == nil {
+			t.Fatalf("No personal best stored for %s", metric)
+		}
+		return stored.Value
+	}
+
+	// First period sets both bests
+	setPeriod(60, 50)
+	updated, err := service.UpdatePersonalBests(context.Background(), "week")
+	if err != nil {
+		t.Fatalf("UpdatePersonalBests failed: %v", err)
+	}
+	if updated != 2 {
+		t.Errorf("Expected 2 personal bests set, got %d", updated)
+	}
+
+	// Faster TTFR and higher engagement beat the stored bests
+	setPeriod(30, 80)
+	if updated, _ = service.UpdatePersonalBests(context.Background(), "week"); updated != 2 {
+		t.Errorf("Expected 2 personal bests improved, got %d", updated)
+	}
+	if got := best(models.PersonalBestMetricAvgTTFR); got != 30 {
+		t.Errorf("Expected best avg TTFR 30, got %.1f", got)
+	}
+	if got := best(models.PersonalBestMetricEngagementScore); got != 80 {
+		t.Errorf("Expected best engagement 80, got %.1f", got)
+	}
+
+	// Worse values leave the bests untouched
+	saves := bestRepo.saves
+	setPeriod(90, 20)
+	if updated, _ = service.UpdatePersonalBests(context.Background(), "week"); updated != 0 {
+		t.Errorf("Expected no personal bests updated, got %d", updated)
+	}
+	if bestRepo.saves != saves {
+		t.Errorf("Expected no saves for worse values, got %d", bestRepo.saves-saves)
+	}
+	if got := best(models.PersonalBestMetricAvgTTFR); got != 30 {
+		t.Errorf("Expected best avg TTFR to stay 30, got %.1f", got)
+	}
+	if got := best(models.PersonalBestMetricEngagementScore); got != 80 {
+		t.Errorf("Expected best engagement to stay 80, got %.1f", got)
+	}
+
+	bests, err := service.GetPersonalBests(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("GetPersonalBests failed: %v", err)
+	}
+	if len(bests) != 2 {
+		t.Errorf("Expected 2 personal bests, got %d", len(bests))
+	}
+}
+
+type mockAssignmentRepository struct {
+	assignments []models.ReviewerAssignment
+}
+
+func (m *mockAssignmentRepository) GetCompletedAssignmentsByDateRange(startDate, endDate time.Time) ([]models.ReviewerAssignment, error) {
+	return m.assignments, nil
+}
+
+func TestGetStatsByRole(t *testing.T) {
+	service, _, _, _ := setupTestService()
+
+	if _, err := service.GetStatsByRole(context.Background(), "month"); !errors.Is(err, ErrRoleStatsUnavailable) {
+		t.Fatalf("Expected ErrRoleStatsUnavailable without an assignment repository, got %v", err)
+	}
+
+	assigned := time.Now().Add(-48 * time.Hour)
+	after := func(d time.Duration) *time.Time {
+		at := assigned.Add(d)
+		return &at
+	}
+	alice := models.User{ID: 1, Username: "alice"}
+	bob := models.User{ID: 2, Username: "bob"}
+	merged := models.MRReview{Status: models.MRStatusMerged}
+	closed := models.MRReview{Status: models.MRStatusClosed}
+
+	repo := &mockAssignmentRepository{assignments: []models.ReviewerAssignment{
+		// Code owners: two merged reviews by alice and bob
+		{UserID: 1, User: alice, MRReview: merged, Role: models.ReviewerRoleCodeowner, AssignedAt: assigned,
+			FirstCommentAt: after(30 * time.Minute), ApprovedAt: after(2 * time.Hour), CommentCount: 4},
+		{UserID: 2, User: bob, MRReview: merged, Role: models.ReviewerRoleCodeowner, AssignedAt: assigned,
+			FirstCommentAt: after(90 * time.Minute), ApprovedAt: after(4 * time.Hour), CommentCount: 2},
+		// Team members: alice twice, one merged and one closed without comments
+		{UserID: 1, User: alice, MRReview: merged, Role: models.ReviewerRoleTeamMember, AssignedAt: assigned,
+			FirstCommentAt: after(4 * time.Hour), CommentCount: 1},
+		{UserID: 1, User: alice, MRReview: closed, Role: models.ReviewerRoleTeamMember, AssignedAt: assigned},
+	}}
+	service.SetAssignmentRepository(repo)
+
+	stats, err := service.GetStatsByRole(context.Background(), "month")
+	if err != nil {
+		t.Fatalf("GetStatsByRole failed: %v", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("Expected stats for 2 roles, got %+v", stats)
+	}
+
+	owners, members := stats[0], stats[1]
+	if owners.Role != models.ReviewerRoleCodeowner || members.Role != models.ReviewerRoleTeamMember {
+		t.Fatalf("Expected codeowner then team_member, got %s and %s", owners.Role, members.Role)
+	}
+
+	if owners.Reviewers != 2 || owners.Assignments != 2 || owners.CompletedReviews != 2 {
+		t.Errorf("Unexpected codeowner counts: %+v", owners)
+	}
+	if owners.CompletionRate != 1 {
+		t.Errorf("Expected codeowner completion rate 1, got %.2f", owners.CompletionRate)
+	}
+	if owners.AvgTTFR != 60 || owners.AvgTimeToApproval != 180 || owners.AvgCommentCount != 3 {
+		t.Errorf("Unexpected codeowner averages: %+v", owners)
+	}
+
+	if members.Reviewers != 1 || members.Assignments != 2 || members.CompletedReviews != 1 {
+		t.Errorf("Unexpected team_member counts: %+v", members)
+	}
+	if members.CompletionRate != 0.5 {
+		t.Errorf("Expected team_member completion rate 0.5, got %.2f", members.CompletionRate)
+	}
+	if members.AvgTTFR != 240 || members.AvgTimeToApproval != 0 || members.AvgCommentCount != 0.5 {
+		t.Errorf("Unexpected team_member averages: %+v", members)
+	}
+
+	// With an engagement floor of 2 comments, alice's one-comment team member review no longer counts
+	service.minReviewComments = 2
+	stats, err = service.GetStatsByRole(context.Background(), "month")
+	if err != nil {
+		t.Fatalf("GetStatsByRole failed: %v", err)
+	}
+	if stats[0].CompletedReviews != 2 || stats[1].CompletedReviews != 0 {
+		t.Errorf("Expected 2 codeowner and 0 team_member completed reviews with the floor, got %d and %d",
+			stats[0].CompletedReviews, stats[1].CompletedReviews)
+	}
+}
+
+func TestGetStatsByRole_ReviewClock(t *testing.T) {
+	service, _, _, _ := setupTestService()
+
+	// Assigned Friday 18:00, first comment Monday 09:00 and approval Monday 10:00
+	friday := time.Date(2025, 11, 21, 18, 0, 0, 0, time.UTC)
+	monday := func(hour int) *time.Time {
+		at := time.Date(2025, 11, 24, hour, 0, 0, 0, time.UTC)
+		return &at
+	}
+	// First comment and approval recorded before the assignment, e.g. a reassigned reviewer
+	early := friday.Add(-time.Hour)
+	alice := models.User{ID: 1, Username: "alice"}
+	service.SetAssignmentRepository(&mockAssignmentRepository{assignments: []models.ReviewerAssignment{
+		{UserID: 1, User: alice, Role: models.ReviewerRoleCodeowner, AssignedAt: friday,
+			FirstCommentAt: monday(9), ApprovedAt: monday(10)},
+		{UserID: 1, User: alice, Role: models.ReviewerRoleExternal, AssignedAt: friday,
+			FirstCommentAt: &early, ApprovedAt: &early},
+	}})
+
+	stats, err := service.GetStatsByRole(context.Background(), "month")
+	if err != nil {
+		t.Fatalf("GetStatsByRole failed: %v", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("Expected stats for 2 roles, got %+v", stats)
+	}
+	if stats[0].AvgTTFR != 63*60 || stats[0].AvgTimeToApproval != 64*60 {
+		t.Errorf("Expected wall-clock TTFR 3780 and approval 3840 minutes, got %.0f and %.0f",
+			stats[0].AvgTTFR, stats[0].AvgTimeToApproval)
+	}
+	if stats[1].AvgTTFR != 0 || stats[1].AvgTimeToApproval != 0 {
+		t.Errorf("Expected negative durations clamped to 0, got %.0f and %.0f",
+			stats[1].AvgTTFR, stats[1].AvgTimeToApproval)
+	}
+
+	// Leaving the weekend out counts Friday 18:00-24:00 and Monday until the comment
+	service.SetReviewClock(metrics.NewReviewClock(true, time.UTC))
+	stats, err = service.GetStatsByRole(context.Background(), "month")
+	if err != nil {
+		t.Fatalf("GetStatsByRole failed: %v", err)
+	}
+	if stats[0].AvgTTFR != 15*60 || stats[0].AvgTimeToApproval != 16*60 {
+		t.Errorf("Expected TTFR 900 and approval 960 minutes without the weekend, got %.0f and %.0f",
+			stats[0].AvgTTFR, stats[0].AvgTimeToApproval)
+	}
+}
+
+func TestGetSummary(t *testing.T) {
+	service, metricsRepo, _, userRepo := setupTestService()
+	service.excludedUsers = config.ExcludedUsersConfig{Usernames: []string{"renovate-bot"}}
+
+	alice, bob, carol, bot, idle := uint(1), uint(2), uint(3), uint(4), uint(5)
+	userRepo.users[alice] = &models.User{ID: alice, Username: "alice", Team: "team-backend"}
+	userRepo.users[bob] = &models.User{ID: bob, Username: "bob", Team: "team-frontend"}
+	userRepo.users[carol] = &models.User{ID: carol, Username: "carol", Team: "team-platform"}
+	userRepo.users[bot] = &models.User{ID: bot, Username: "renovate-bot", Team: "team-platform"}
+	userRepo.users[idle] = &models.User{ID: idle, Username: "dave", Team: "team-data"}
+
+	ttfr := func(minutes int) *int { return &minutes }
+	metricsRepo.metrics = []models.ReviewMetrics{
+		// Team rows count each MR once
+		{Team: "team-backend", TotalReviews: 10, CompletedReviews: 8, AvgTTFR: ttfr(30), TTFRSamples: 3},
+		{Team: "team-backend", TotalReviews: 5, CompletedReviews: 4, AvgTTFR: ttfr(90), TTFRSamples: 1},
+		{Team: "team-frontend", TotalReviews: 8, CompletedReviews: 6},
+		{Team: "team-platform", TotalReviews: 7, CompletedReviews: 7, AvgTTFR: ttfr(60), TTFRSamples: 2},
+		{Team: "team-data", TotalReviews: 3, CompletedReviews: 1},
+		// User rows only count towards active reviewers
+		{Team: "team-backend", UserID: &alice, TotalReviews: 6, CompletedReviews: 5},
+		{Team: "team-backend", UserID: &alice, TotalReviews: 2, CompletedReviews: 2},
+		{Team: "team-frontend", UserID: &bob, TotalReviews: 4, CompletedReviews: 3},
+		{Team: "team-platform", UserID: &carol, TotalReviews: 7, CompletedReviews: 7},
+		{Team: "team-platform", UserID: &bot, TotalReviews: 9, CompletedReviews: 9},
+		{Team: "team-data", UserID: &idle, TotalReviews: 0},
+	}
+
+	summary, err := service.GetSummary(context.Background(), "month")
+	if err != nil {
+		t.Fatalf("GetSummary failed: %v", err)
+	}
+
+	if summary.TotalReviews != 33 {
+		t.Errorf("Expected 33 total reviews, got %d", summary.TotalReviews)
+	}
+	if summary.CompletedReviews != 26 {
+		t.Errorf("Expected 26 completed reviews, got %d", summary.CompletedReviews)
+	}
+	// (30*3 + 90*1 + 60*2) / 6 samples
+	if summary.AvgTTFR != 50 {
+		t.Errorf("Expected avg TTFR 50, got %.2f", summary.AvgTTFR)
+	}
+	// alice, bob and carol; the bot is excluded and dave did no reviews
+	if summary.ActiveReviewers != 3 {
+		t.Errorf("Expected 3 active reviewers, got %d", summary.ActiveReviewers)
+	}
+
+	want := []TeamTotal{
+		{Team: "team-backend", CompletedReviews: 12},
+		{Team: "team-platform", CompletedReviews: 7},
+		{Team: "team-frontend", CompletedReviews: 6},
+	}
+	if len(summary.TopTeams) != len(want) {
+		t.Fatalf("Expected top %d teams, got %+v", len(want), summary.TopTeams)
+	}
+	for i, team := range want {
+		if summary.TopTeams[i] != team {
+			t.Errorf("Top team %d = %+v, want %+v", i+1, summary.TopTeams[i], team)
+		}
+	}
+}
+
+func TestGetSummary_NoMetrics(t *testing.T) {
+	service, _, _, _ := setupTestService()
+
+	summary, err := service.GetSummary(context.Background(), "week")
+	if err != nil {
+		t.Fatalf("GetSummary failed: %v", err)
+	}
+	if summary.TotalReviews != 0 || summary.ActiveReviewers != 0 || summary.AvgTTFR != 0 {
+		t.Errorf("Expected an empty summary, got %+v", summary)
+	}
+	if summary.TopTeams == nil || len(summary.TopTeams) != 0 {
+		t.Errorf("Expected an empty top teams list, got %v", summary.TopTeams)
+	}
+}
+
+func TestCompareTeams(t *testing.T) {
+	service, metricsRepo, badgeRepo, userRepo := setupTestService()
+	service.excludedUsers = config.ExcludedUsersConfig{Usernames: []string{"renovate-bot"}}
+
+	alice, bob, carol, dave, bot := uint(1), uint(2), uint(3), uint(4), uint(5)
+	for id, name := range map[uint]string{alice: "alice", bob: "bob", carol: "carol", dave: "dave", bot: "renovate-bot"} {
+		userRepo.users[id] = &models.User{ID: id, Username: name}
+	}
+	badgeRepo.userBadgeCounts[alice] = 2
+	badgeRepo.userBadgeCounts[carol] = 1
+
+	ttfr := func(minutes int) *int { return &minutes }
+	score := func(v float64) *float64 { return &v }
+	metricsRepo.metrics = []models.ReviewMetrics{
+		// Backend: two rows for alice, one for bob
+		{Team: "team-backend", UserID: &alice, TotalReviews: 4, CompletedReviews: 4, AvgTTFR: ttfr(60), EngagementScore: score(70)},
+		{Team: "team-backend", UserID: &alice, TotalReviews: 2, CompletedReviews: 1, AvgTTFR: ttfr(120), EngagementScore: score(50)},
+		{Team: "team-backend", UserID: &bob, TotalReviews: 2, CompletedReviews: 2, AvgTTFR: ttfr(30), EngagementScore: score(90)},
+		// A day with no completed review has no averages and does not dilute them
+		{Team: "team-backend", UserID: &bob, TotalReviews: 1},
+		// Frontend: carol is slow but engaged
+		{Team: "team-frontend", UserID: &carol, TotalReviews: 3, CompletedReviews: 3, AvgTTFR: ttfr(240), EngagementScore: score(95)},
+		// Platform: dave is fast; the bot's reviews are excluded
+		{Team: "team-platform", UserID: &dave, TotalReviews: 1, CompletedReviews: 1, AvgTTFR: ttfr(15), EngagementScore: score(40)},
+		{Team: "team-platform", UserID: &bot, TotalReviews: 50, CompletedReviews: 50, AvgTTFR: ttfr(1), EngagementScore: score(10)},
+		// Team-level rows are not counted twice
+		{Team: "team-backend", TotalReviews: 6, CompletedReviews: 5},
+	}
+
+	byTTFR, err := service.CompareTeams(context.Background(), "month", "avg_ttfr")
+	if err != nil {
+		t.Fatalf("CompareTeams failed: %v", err)
+	}
+	if len(byTTFR) != 3 {
+		t.Fatalf("Expected 3 teams, got %+v", byTTFR)
+	}
+
+	// Lower is better for TTFR
+	wantOrder := []string{"team-platform", "team-backend", "team-frontend"}
+	for i, team := range wantOrder {
+		if byTTFR[i].Team != team || byTTFR[i].Rank != i+1 {
+			t.Errorf("Rank %d = %s (rank %d), want %s", i+1, byTTFR[i].Team, byTTFR[i].Rank, team)
+		}
+	}
+
+	backend := byTTFR[1]
+	if backend.Reviewers != 2 || backend.CompletedReviews != 7 || backend.BadgeCount != 2 {
+		t.Errorf("Unexpected backend totals: %+v", backend)
+	}
+	if backend.AvgTTFR != 70 || backend.EngagementScore != 70 {
+		t.Errorf("Expected backend averages over its rows (TTFR 70, engagement 70), got %+v", backend)
+	}
+	if backend.CompletionRate != 7.0/9.0 {
+		t.Errorf("Expected backend completion rate 7/9, got %.3f", backend.CompletionRate)
+	}
+	platform := byTTFR[0]
+	if platform.Reviewers != 1 || platform.CompletedReviews != 1 || platform.AvgTTFR != 15 {
+		t.Errorf("Expected excluded users left out of platform, got %+v", platform)
+	}
+
+	byReviews, err := service.CompareTeams(context.Background(), "month", "completed_reviews")
+	if err != nil {
+		t.Fatalf("CompareTeams failed: %v", err)
+	}
+	wantOrder = []string{"team-backend", "team-frontend", "team-platform"}
+	for i, team := range wantOrder {
+		if byReviews[i].Team != team {
+			t.Errorf("By completed reviews, rank %d = %s, want %s", i+1, byReviews[i].Team, team)
+		}
+	}
+
+	byEngagement, err := service.CompareTeams(context.Background(), "month", "engagement_score")
+	if err != nil {
+		t.Fatalf("CompareTeams failed: %v", err)
+	}
+	wantOrder = []string{"team-frontend", "team-backend", "team-platform"}
+	for i, team := range wantOrder {
+		if byEngagement[i].Team != team {
+			t.Errorf("By engagement, rank %d = %s, want %s", i+1, byEngagement[i].Team, team)
+		}
+	}
+}
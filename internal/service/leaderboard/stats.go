@@ -2,38 +2,87 @@ package leaderboard
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/metrics"
 )
 
+// ErrUserNotFound is returned when statistics are requested for a user that does not exist,
+// or that is excluded from statistics (e.g. a bot) and so hidden like one.
+var ErrUserNotFound = errors.New("user not found")
+
 // UserStats represents comprehensive statistics for a user.
 type UserStats struct {
 	UserID            uint           `json:"user_id"`
 	Username          string         `json:"username"`
+	DisplayName       string         `json:"display_name"` // Falls back to username
 	Team              string         `json:"team"`
 	Period            string         `json:"period"`
 	TotalReviews      int            `json:"total_reviews"`
 	CompletedReviews  int            `json:"completed_reviews"`
+	CompletionRate    float64        `json:"completion_rate"`      // completed / total, 0-1
 	AvgTTFR           float64        `json:"avg_ttfr"`             // in minutes
 	AvgTimeToApproval float64        `json:"avg_time_to_approval"` // in minutes
 	AvgCommentCount   float64        `json:"avg_comment_count"`
 	EngagementScore   float64        `json:"engagement_score"`
+	Trend             string         `json:"trend"`   // Engagement vs. the previous period: up, down or flat
+	AtRisk            bool           `json:"at_risk"` // Engagement dropped sharply vs. the previous period
 	Badges            []models.Badge `json:"badges"`
 	GlobalRank        int            `json:"global_rank"`
 	TeamRank          int            `json:"team_rank"`
+
+	// Changes vs. the immediately preceding period of the same length (current - previous).
+	// All are nil for all_time; AvgTTFRDelta is also nil when either period has no TTFR.
+	CompletedReviewsDelta *int     `json:"completed_reviews_delta"`
+	AvgTTFRDelta          *float64 `json:"avg_ttfr_delta"` // in minutes; negative means faster
+	EngagementScoreDelta  *float64 `json:"engagement_score_delta"`
 }
 
+// userPeriodTotals is a user's metrics aggregated over a period.
+type userPeriodTotals struct {
+	TotalReviews      int
+	CompletedReviews  int
+	AvgTTFR           float64
+	AvgTimeToApproval float64
+	AvgCommentCount   float64
+	EngagementScore   float64
+	HasTTFR           bool // At least one row recorded a TTFR
+}
+
+// Engagement trend values.
+const (
+	TrendUp   = "up"
+	TrendDown = "down"
+	TrendFlat = "flat"
+)
+
+const (
+	// trendThreshold is the relative engagement change below which the trend is flat.
+	trendThreshold = 0.1
+	// atRiskDrop is the relative engagement drop at which a user is flagged at risk.
+	atRiskDrop = 0.4
+)
+
 // GetUserStats returns comprehensive statistics for a user.
 func (s *Service) GetUserStats(ctx context.Context, userID uint, period string) (*UserStats, error) {
 	// Get user info
 	user, err := s.userRepo.GetByID(userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("user %d: %w", userID, ErrUserNotFound)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
+	if s.isExcluded(user) {
+		return nil, fmt.Errorf("user %d is excluded from statistics: %w", userID, ErrUserNotFound)
+	}
 
 	// Calculate date range
-	startDate, endDate := calculatePeriodRange(period)
+	startDate, endDate := s.calculatePeriodRange(period, time.Now())
 
 	// Get user metrics
 	metrics, err := s.metricsRepo.GetMetricsByUser(userID, startDate, endDate)
@@ -42,52 +91,43 @@ func (s *Service) GetUserStats(ctx context.Context, userID uint, period string)
 	}
 
 	// Aggregate metrics
+	current := aggregateUserPeriod(metrics)
 	stats := &UserStats{
-		UserID:   userID,
-		Username: user.Username,
-		Team:     user.Team,
-		Period:   period,
+		UserID:            userID,
+		Username:          user.Username,
+		DisplayName:       user.PreferredName(),
+		Team:              user.Team,
+		Period:            period,
+		TotalReviews:      current.TotalReviews,
+		CompletedReviews:  current.CompletedReviews,
+		CompletionRate:    completionRate(current.CompletedReviews, current.TotalReviews),
+		AvgTTFR:           current.AvgTTFR,
+		AvgTimeToApproval: current.AvgTimeToApproval,
+		AvgCommentCount:   current.AvgCommentCount,
+		EngagementScore:   current.EngagementScore,
 	}
 
-	var (
-		totalTTFR            float64
-		totalTimeToApproval  float64
-		totalCommentCount    float64
-		totalEngagementScore float64
-		metricsCount         int
-	)
-
-	for _, m := range metrics {
-		stats.TotalReviews += m.TotalReviews
-		stats.CompletedReviews += m.CompletedReviews
-
-		if m.AvgTTFR != nil {
-			totalTTFR += float64(*m.AvgTTFR)
-		}
-		if m.AvgTimeToApproval != nil {
-			totalTimeToApproval += float64(*m.AvgTimeToApproval)
-		}
-		if m.AvgCommentCount != nil {
-			totalCommentCount += *m.AvgCommentCount
+	// Compare with the previous equal-length window
+	if period != "all_time" && period != "" {
+		prevStart, prevEnd := previousPeriodRange(startDate, endDate)
+		prevMetrics, err := s.metricsRepo.GetMetricsByUser(userID, prevStart, prevEnd)
+		if err != nil {
+			s.log.Warn().Err(err).Uint("user_id", userID).Msg("Failed to get previous period metrics")
+		} else {
+			previous := aggregateUserPeriod(prevMetrics)
+			stats.Trend, stats.AtRisk = engagementTrend(current.EngagementScore, previous.EngagementScore)
+			setPeriodDeltas(stats, current, previous)
 		}
-		if m.EngagementScore != nil {
-			totalEngagementScore += *m.EngagementScore
-		}
-		metricsCount++
 	}
-
-	// Calculate averages
-	if metricsCount > 0 {
-		stats.AvgTTFR = totalTTFR / float64(metricsCount)
-		stats.AvgTimeToApproval = totalTimeToApproval / float64(metricsCount)
-		stats.AvgCommentCount = totalCommentCount / float64(metricsCount)
-		stats.EngagementScore = totalEngagementScore / float64(metricsCount)
+	if stats.Trend == "" {
+		stats.Trend = TrendFlat
 	}
 
 	// Get user badges
 	userBadges, err := s.badgeRepo.GetUserBadges(userID)
 	if err != nil {
 		s.log.Warn().Err(err).Uint("user_id", userID).Msg("Failed to get user badges")
+		addWarning(ctx, WarningBadges)
 	} else {
 		// Extract badge details
 		for _, ub := range userBadges {
@@ -97,10 +137,16 @@ func (s *Service) GetUserStats(ctx context.Context, userID uint, period string)
 		}
 	}
 
-	// Get global rank
+	// Get global rank; an unranked user is expected, anything else means ranks are unavailable
 	globalRank, err := s.GetUserRank(ctx, userID, period, "engagement_score")
 	if err != nil {
-		s.log.Warn().Err(err).Uint("user_id", userID).Msg("Failed to get global rank")
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if !errors.Is(err, errUserNotRanked) {
+			s.log.Warn().Err(err).Uint("user_id", userID).Msg("Failed to get global rank")
+			addWarning(ctx, WarningRanks)
+		}
 		stats.GlobalRank = 0
 	} else {
 		stats.GlobalRank = globalRank
@@ -109,7 +155,13 @@ func (s *Service) GetUserStats(ctx context.Context, userID uint, period string)
 	// Get team rank
 	teamRank, err := s.getUserTeamRank(ctx, userID, user.Team, period, "engagement_score")
 	if err != nil {
-		s.log.Warn().Err(err).Uint("user_id", userID).Str("team", user.Team).Msg("Failed to get team rank")
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if !errors.Is(err, errUserNotRanked) {
+			s.log.Warn().Err(err).Uint("user_id", userID).Str("team", user.Team).Msg("Failed to get team rank")
+			addWarning(ctx, WarningRanks)
+		}
 		stats.TeamRank = 0
 	} else {
 		stats.TeamRank = teamRank
@@ -118,10 +170,134 @@ func (s *Service) GetUserStats(ctx context.Context, userID uint, period string)
 	return stats, nil
 }
 
+// GetUserMetricsHistory returns a user's raw per-day metrics between startDate and endDate.
+// Ranges wider than metrics.max_query_range_days fail with metrics.ErrDateRangeTooLarge.
+func (s *Service) GetUserMetricsHistory(_ context.Context, userID uint, startDate, endDate time.Time) ([]models.ReviewMetrics, error) {
+	if err := metrics.ValidateRange(startDate, endDate, s.maxQueryRangeDays); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("user %d: %w", userID, ErrUserNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if s.isExcluded(user) {
+		return nil, fmt.Errorf("user %d is excluded from statistics: %w", userID, ErrUserNotFound)
+	}
+
+	history, err := s.metricsRepo.GetMetricsByUser(userID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user metrics: %w", err)
+	}
+
+	return history, nil
+}
+
+// aggregateUserPeriod sums review counts and averages each per-row average over
+// the samples behind it, so rows without a value don't drag the average down.
+func aggregateUserPeriod(rows []models.ReviewMetrics) userPeriodTotals {
+	var (
+		totals                                     userPeriodTotals
+		ttfr, timeToApproval, comments, engagement metrics.SampleMean
+	)
+
+	for _, m := range rows {
+		totals.TotalReviews += m.TotalReviews
+		totals.CompletedReviews += m.CompletedReviews
+
+		if m.AvgTTFR != nil {
+			ttfr.Add(float64(*m.AvgTTFR), m.TTFRSamples)
+		}
+		if m.AvgTimeToApproval != nil {
+			timeToApproval.Add(float64(*m.AvgTimeToApproval), m.ApprovalSamples)
+		}
+		if m.AvgCommentCount != nil {
+			comments.Add(*m.AvgCommentCount, m.CommentSamples)
+		}
+		if m.EngagementScore != nil {
+			engagement.Add(*m.EngagementScore, m.EngagementSamples)
+		}
+	}
+
+	totals.HasTTFR = ttfr.Samples > 0
+	totals.AvgTTFR = ttfr.Mean()
+	totals.AvgTimeToApproval = timeToApproval.Mean()
+	totals.AvgCommentCount = comments.Mean()
+	totals.EngagementScore = engagement.Mean()
+	return totals
+}
+
+// previousPeriodRange returns the window covering as many days as startDate..endDate
+// and ending on the day before the first one in it. Metrics rows are dated at midnight
+// and ranges are inclusive, so no day is counted in both windows.
+func previousPeriodRange(startDate, endDate time.Time) (prevStart, prevEnd time.Time) {
+	firstDay := startOfDay(startDate)
+	if firstDay.Before(startDate) {
+		firstDay = firstDay.AddDate(0, 0, 1)
+	}
+	days := max(daysBetween(firstDay, startOfDay(endDate))+1, 1)
+
+	prevEnd = firstDay.AddDate(0, 0, -1)
+	return prevEnd.AddDate(0, 0, -(days - 1)), prevEnd
+}
+
+// startOfDay returns midnight of t's day in t's location.
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// daysBetween returns the number of calendar days from a to b, ignoring DST shifts.
+func daysBetween(a, b time.Time) int {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	from := time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)
+	to := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// setPeriodDeltas fills the changes from the previous period into stats.
+func setPeriodDeltas(stats *UserStats, current, previous userPeriodTotals) {
+	completedDelta := current.CompletedReviews - previous.CompletedReviews
+	engagementDelta := current.EngagementScore - previous.EngagementScore
+	stats.CompletedReviewsDelta = &completedDelta
+	stats.EngagementScoreDelta = &engagementDelta
+	if current.HasTTFR && previous.HasTTFR {
+		ttfrDelta := current.AvgTTFR - previous.AvgTTFR
+		stats.AvgTTFRDelta = &ttfrDelta
+	}
+}
+
+// engagementTrend classifies the change from previous to current engagement
+// and reports whether the drop is large enough to flag the user at risk.
+func engagementTrend(current, previous float64) (trend string, atRisk bool) {
+	if previous <= 0 {
+		if current > 0 {
+			return TrendUp, false
+		}
+		return TrendFlat, false
+	}
+
+	change := (current - previous) / previous
+	switch {
+	case change <= -atRiskDrop:
+		return TrendDown, true
+	case change < -trendThreshold:
+		return TrendDown, false
+	case change > trendThreshold:
+		return TrendUp, false
+	default:
+		return TrendFlat, false
+	}
+}
+
 // getUserTeamRank returns the rank of a user within their team.
 func (s *Service) getUserTeamRank(ctx context.Context, userID uint, team, period, metric string) (int, error) {
 	// Get team leaderboard (no limit)
-	leaderboard, err := s.GetTeamLeaderboard(ctx, team, period, metric, 0)
+	leaderboard, err := s.GetTeamLeaderboard(ctx, team, Filters{}, period, metric, 0)
 	if err != nil {
 		return 0, err
 	}
@@ -133,6 +309,5 @@ func (s *Service) getUserTeamRank(ctx context.Context, userID uint, team, period
 		}
 	}
 
-	// User not found in leaderboard
-	return 0, fmt.Errorf("user not found in team leaderboard")
+	return 0, errUserNotRanked
 }
@@ -0,0 +1,89 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/metrics"
+)
+
+// summaryTopTeams is the number of teams listed in a Summary.
+const summaryTopTeams = 3
+
+// Summary holds org-wide headline numbers for a period.
+type Summary struct {
+	TotalReviews     int         `json:"total_reviews"`
+	CompletedReviews int         `json:"completed_reviews"`
+	AvgTTFR          float64     `json:"avg_ttfr"`         // in minutes, weighted by reviews with a first review
+	ActiveReviewers  int         `json:"active_reviewers"` // Distinct users with at least one review
+	TopTeams         []TeamTotal `json:"top_teams"`        // Most completed reviews first
+}
+
+// TeamTotal is a team's completed review count in a Summary.
+type TeamTotal struct {
+	Team             string `json:"team"`
+	CompletedReviews int    `json:"completed_reviews"`
+}
+
+// GetSummary aggregates the metrics of a period across the organization.
+// Review totals and TTFR come from team-level rows, which count each MR once;
+// active reviewers come from user-level rows.
+func (s *Service) GetSummary(ctx context.Context, period string) (*Summary, error) {
+	startDate, endDate := s.calculatePeriodRange(period, time.Now())
+	rows, err := s.metricsRepo.GetByDateRange(startDate, endDate, map[string]interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metrics: %w", err)
+	}
+
+	summary := &Summary{TopTeams: []TeamTotal{}}
+	teamCompleted := make(map[string]int)
+	reviewers := make(map[uint]bool)
+	var ttfr metrics.SampleMean
+	for _, m := range rows {
+		if m.UserID != nil {
+			if m.TotalReviews > 0 {
+				reviewers[*m.UserID] = true
+			}
+			continue
+		}
+
+		summary.TotalReviews += m.TotalReviews
+		summary.CompletedReviews += m.CompletedReviews
+		teamCompleted[m.Team] += m.CompletedReviews
+		if m.AvgTTFR != nil {
+			ttfr.Add(float64(*m.AvgTTFR), m.TTFRSamples)
+		}
+	}
+	summary.AvgTTFR = ttfr.Mean()
+
+	for userID := range reviewers {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil {
+			s.log.Warn().Err(err).Uint("user_id", userID).Msg("Failed to get user")
+			continue
+		}
+		if !s.isExcluded(user) {
+			summary.ActiveReviewers++
+		}
+	}
+
+	for team, completed := range teamCompleted {
+		summary.TopTeams = append(summary.TopTeams, TeamTotal{Team: team, CompletedReviews: completed})
+	}
+	sort.Slice(summary.TopTeams, func(i, j int) bool {
+		if summary.TopTeams[i].CompletedReviews != summary.TopTeams[j].CompletedReviews {
+			return summary.TopTeams[i].CompletedReviews > summary.TopTeams[j].CompletedReviews
+		}
+		return summary.TopTeams[i].Team < summary.TopTeams[j].Team
+	})
+	if len(summary.TopTeams) > summaryTopTeams {
+		summary.TopTeams = summary.TopTeams[:summaryTopTeams]
+	}
+
+	return summary, nil
+}
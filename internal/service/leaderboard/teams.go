@@ -0,0 +1,125 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+)
+
+// TeamEntry is a team's aggregated metrics in a team comparison.
+type TeamEntry struct {
+	Team              string  `json:"team"`
+	Reviewers         int     `json:"reviewers"` // Distinct users with metrics on the team's MRs
+	CompletedReviews  int     `json:"completed_reviews"`
+	CompletionRate    float64 `json:"completion_rate"`      // completed / total, 0-1
+	AvgTTFR           float64 `json:"avg_ttfr"`             // in minutes
+	AvgTimeToApproval float64 `json:"avg_time_to_approval"` // in minutes
+	AvgCommentCount   float64 `json:"avg_comment_count"`
+	AvgCommentLength  float64 `json:"avg_comment_length"` // in characters
+	EngagementScore   float64 `json:"engagement_score"`
+	LongestStreak     int     `json:"longest_streak"` // Most consecutive days any reviewer completed a review
+	BadgeCount        int     `json:"badge_count"`    // Badges held by the team's reviewers
+	Points            float64 `json:"points"`
+	Rank              int     `json:"rank"`
+}
+
+// CompareTeams ranks teams by a metric over a period. User-level metrics rows are
+// grouped by the team of the MR they belong to and aggregated like a user's
+// leaderboard entry; excluded users are left out.
+func (s *Service) CompareTeams(ctx context.Context, period, metric string) ([]TeamEntry, error) {
+	startDate, endDate := s.calculatePeriodRange(period, time.Now())
+	metrics, err := s.metricsRepo.GetByDateRange(startDate, endDate, map[string]interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metrics: %w", err)
+	}
+
+	// Look up each reviewer once to drop excluded users and count badges
+	included := make(map[uint]bool)
+	badgeCounts := make(map[uint]int)
+	for _, m := range metrics {
+		if m.UserID == nil {
+			continue
+		}
+		userID := *m.UserID
+		if _, seen := included[userID]; seen {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil {
+			s.log.Warn().Err(err).Uint("user_id", userID).Msg("Failed to get user")
+			included[userID] = false
+			continue
+		}
+		included[userID] = !s.isExcluded(user)
+
+		count, err := s.badgeRepo.GetUserBadgeCount(userID)
+		if err != nil {
+			s.log.Warn().Err(err).Uint("user_id", userID).Msg("Failed to get badge count")
+			addWarning(ctx, WarningBadges)
+			continue
+		}
+		badgeCounts[userID] = int(count)
+	}
+
+	reviewers := make(map[string]map[uint]bool)
+	teamMetrics := aggregateMetricsBy(metrics, func(m *models.ReviewMetrics) (string, bool) {
+		if m.UserID == nil || !included[*m.UserID] {
+			return "", false
+		}
+		if reviewers[m.Team] == nil {
+			reviewers[m.Team] = make(map[uint]bool)
+		}
+		reviewers[m.Team][*m.UserID] = true
+		return m.Team, true
+	})
+
+	// Build entries as leaderboard entries to share points and sorting with user boards
+	entries := make([]Entry, 0, len(teamMetrics))
+	for team, agg := range teamMetrics {
+		entry := Entry{
+			Team:              team,
+			CompletedReviews:  agg.CompletedReviews,
+			CompletionRate:    completionRate(agg.CompletedReviews, agg.TotalReviews),
+			AvgTTFR:           agg.AvgTTFR,
+			AvgTimeToApproval: agg.AvgTimeToApproval,
+			AvgCommentCount:   agg.AvgCommentCount,
+			AvgCommentLength:  agg.AvgCommentLength,
+			EngagementScore:   agg.EngagementScore,
+			LongestStreak:     agg.LongestStreak,
+		}
+		for userID := range reviewers[team] {
+			entry.BadgeCount += badgeCounts[userID]
+		}
+		entry.Points = s.calculatePoints(&entry, s.pointsWeightsForTeam(team))
+		entries = append(entries, entry)
+	}
+
+	s.sortLeaderboard(entries, metric)
+
+	teams := make([]TeamEntry, 0, len(entries))
+	for i, e := range entries {
+		teams = append(teams, TeamEntry{
+			Team:              e.Team,
+			Reviewers:         len(reviewers[e.Team]),
+			CompletedReviews:  e.CompletedReviews,
+			CompletionRate:    e.CompletionRate,
+			AvgTTFR:           e.AvgTTFR,
+			AvgTimeToApproval: e.AvgTimeToApproval,
+			AvgCommentCount:   e.AvgCommentCount,
+			AvgCommentLength:  e.AvgCommentLength,
+			EngagementScore:   e.EngagementScore,
+			LongestStreak:     e.LongestStreak,
+			BadgeCount:        e.BadgeCount,
+			Points:            e.Points,
+			Rank:              i + 1,
+		})
+	}
+
+	return teams, nil
+}
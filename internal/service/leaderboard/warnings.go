@@ -0,0 +1,54 @@
+package leaderboard
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Enrichments that can be missing from a partial result when their backend is degraded.
+const (
+	WarningBadges = "badges" // Badge counts or badges could not be loaded and are zeroed
+	WarningRanks  = "ranks"  // Global or team ranks could not be computed and are zeroed
+)
+
+// Warnings collects the enrichments left out of a partial result.
+type Warnings struct {
+	mu       sync.Mutex
+	warnings map[string]bool
+}
+
+type warningsKey struct{}
+
+// WithWarnings returns a context that collects warnings from leaderboard and stats calls made with it.
+func WithWarnings(ctx context.Context) (context.Context, *Warnings) {
+	w := &Warnings{warnings: make(map[string]bool)}
+	return context.WithValue(ctx, warningsKey{}, w), w
+}
+
+// List returns the collected warnings in sorted order, or nil when the result is complete.
+func (w *Warnings) List() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.warnings) == 0 {
+		return nil
+	}
+	list := make([]string, 0, len(w.warnings))
+	for warning := range w.warnings {
+		list = append(list, warning)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// addWarning records a missing enrichment when ctx collects warnings.
+func addWarning(ctx context.Context, warning string) {
+	w, ok := ctx.Value(warningsKey{}).(*Warnings)
+	if !ok {
+		return
+	}
+	w.mu.Lock()
+	w.warnings[warning] = true
+	w.mu.Unlock()
+}
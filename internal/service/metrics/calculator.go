@@ -4,6 +4,7 @@ package metrics
 import (
 	"time"
 
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
 )
 
@@ -41,27 +42,179 @@ func CalculateTimeToApproval(triggeredAt time.Time, approvedAt *time.Time) *int
 	return &seconds
 }
 
-// CalculateEngagementScore calculates reviewer engagement based on comments. Formula: (comment_count * 10) + (comment_length / 100).
-func CalculateEngagementScore(assignment *models.ReviewerAssignment, _ *models.MRReview) float64 {
-	if assignment == nil {
-		return 0.0
+// ReviewClock measures TTFR and time to approval, optionally leaving weekends out
+// so an MR triggered on Friday evening is not penalized for the weekend.
+type ReviewClock struct {
+	excludeWeekends bool
+	location        *time.Location // Where Saturdays and Sundays are observed
+}
+
+// wallClock measures elapsed wall-clock time, the default.
+var wallClock = &ReviewClock{}
+
+// NewReviewClock creates a review clock. With excludeWeekends, time falling on a Saturday
+// or Sunday in location (UTC when nil) is not counted.
+func NewReviewClock(excludeWeekends bool, location *time.Location) *ReviewClock {
+	if location == nil {
+		location = time.UTC
 	}
+	return &ReviewClock{excludeWeekends: excludeWeekends, location: location}
+}
+
+// TTFR is CalculateTTFR measured with this clock.
+func (c *ReviewClock) TTFR(triggeredAt time.Time, firstReviewAt *time.Time) *int {
+	return c.seconds(triggeredAt, firstReviewAt)
+}
 
-	score := 0.0
+// TimeToApproval is CalculateTimeToApproval measured with this clock.
+func (c *ReviewClock) TimeToApproval(triggeredAt time.Time, approvedAt *time.Time) *int {
+	return c.seconds(triggeredAt, approvedAt)
+}
 
-	// Comment count contribution (10 points per comment)
-	score += float64(assignment.CommentCount) * 10.0
+// seconds returns the whole seconds from start to end, 0 for negative spans (clock skew),
+// or nil when end is nil.
+func (c *ReviewClock) seconds(start time.Time, end *time.Time) *int {
+	if end == nil {
+		return nil
+	}
 
-	// Comment length contribution (1 point per 100 characters)
-	score += float64(assignment.CommentLength) / 100.0
+	elapsed := end.Sub(start)
+	if c.excludeWeekends {
+		elapsed = WithoutWeekends(start.In(c.location), *end)
+	}
+	seconds := max(int(elapsed.Seconds()), 0)
+	return &seconds
+}
 
-	// TODO: Add response time bonus
-	// If first_comment_at is within 1 hour of assignment: +10 bonus
-	// If within 4 hours: +5 bonus
+// WithoutWeekends returns the time elapsed from start to end, leaving out the parts that
+// fall on a Saturday or Sunday in start's location. It returns 0 when end is before start.
+func WithoutWeekends(start, end time.Time) time.Duration {
+	var elapsed time.Duration
+	for day := start; day.Before(end); {
+		year, month, d := day.Date()
+		next := time.Date(year, month, d+1, 0, 0, 0, 0, day.Location())
+		if next.After(end) {
+			next = end
+		}
+		if weekday := day.Weekday(); weekday != time.Saturday && weekday != time.Sunday {
+			elapsed += next.Sub(day)
+		}
+		day = next
+	}
+	return elapsed
+}
+
+// SecondsToMinutes converts a duration in seconds, as returned by CalculateTTFR and
+// CalculateTimeToApproval, to whole minutes, the unit ReviewMetrics durations are stored in.
+// Returns nil if seconds is nil.
+func SecondsToMinutes(seconds *int) *int {
+	if seconds == nil {
+		return nil
+	}
+	minutes := *seconds / 60
+	return &minutes
+}
+
+// DefaultEngagementConfig is the engagement formula used when no coefficient is configured:
+// (comment_count * 10) + (comment_length / 100), +10 for a first comment within 1 hour, +5 within 4 hours.
+var DefaultEngagementConfig = config.EngagementConfig{
+	CommentWeight:       10,
+	LengthDivisor:       100,
+	FastResponseHours:   1,
+	FastResponseBonus:   10,
+	PromptResponseHours: 4,
+	PromptResponseBonus: 5,
+}
+
+// defaultEngagementCalculator backs CalculateEngagementScore.
+var defaultEngagementCalculator = NewEngagementCalculator(DefaultEngagementConfig)
+
+// EngagementCalculator scores reviewer engagement from comments and responsiveness
+// with configurable coefficients.
+type EngagementCalculator struct {
+	cfg config.EngagementConfig
+}
+
+// NewEngagementCalculator creates a calculator, falling back to DefaultEngagementConfig
+// when no coefficient is configured.
+func NewEngagementCalculator(cfg config.EngagementConfig) *EngagementCalculator {
+	if cfg == (config.EngagementConfig{}) {
+		cfg = DefaultEngagementConfig
+	}
+	return &EngagementCalculator{cfg: cfg}
+}
+
+// Score calculates a reviewer's engagement on an assignment from its comments and response time.
+func (c *EngagementCalculator) Score(assignment *models.ReviewerAssignment) float64 {
+	return c.Breakdown(assignment).Score
+}
 
+// EngagementBreakdown is an engagement score split into its components.
+type EngagementBreakdown struct {
+	CommentPoints float64 `json:"comment_points"` // comment_count * comment_weight
+	LengthPoints  float64 `json:"length_points"`  // comment_length / length_divisor
+	ResponseBonus float64 `json:"response_bonus"` // Fast or prompt first comment bonus
+	Score         float64 `json:"score"`          // Sum of the components
+}
+
+// Breakdown calculates a reviewer's engagement on an assignment component by component.
+func (c *EngagementCalculator) Breakdown(assignment *models.ReviewerAssignment) EngagementBreakdown {
+	if assignment == nil {
+		return EngagementBreakdown{}
+	}
+
+	b := EngagementBreakdown{
+		CommentPoints: float64(assignment.CommentCount) * c.cfg.CommentWeight,
+		ResponseBonus: c.responseTimeBonus(assignment),
+	}
+	if c.cfg.LengthDivisor > 0 {
+		b.LengthPoints = float64(assignment.CommentLength) / c.cfg.LengthDivisor
+	}
+	b.Score = b.CommentPoints + b.LengthPoints + b.ResponseBonus
+	return b
+}
+
+// CommentScore is the comment part of the engagement score. Aggregated team rows,
+// which have no per-reviewer response times, are scored with it alone.
+func (c *EngagementCalculator) CommentScore(commentCount, commentLength float64) float64 {
+	score := commentCount * c.cfg.CommentWeight
+	if c.cfg.LengthDivisor > 0 {
+		score += commentLength / c.cfg.LengthDivisor
+	}
 	return score
 }
 
+// responseTimeBonus rewards reviewers who comment quickly after being assigned:
+// the fast bonus within the fast window, otherwise the prompt bonus within the prompt window.
+func (c *EngagementCalculator) responseTimeBonus(assignment *models.ReviewerAssignment) float64 {
+	if assignment.FirstCommentAt == nil || assignment.AssignedAt.IsZero() {
+		return 0.0
+	}
+
+	// Negative durations (clock skew) count as an immediate response
+	elapsed := max(assignment.FirstCommentAt.Sub(assignment.AssignedAt), 0)
+
+	switch {
+	case elapsed <= hours(c.cfg.FastResponseHours):
+		return c.cfg.FastResponseBonus
+	case elapsed <= hours(c.cfg.PromptResponseHours):
+		return c.cfg.PromptResponseBonus
+	default:
+		return 0.0
+	}
+}
+
+// hours converts a configured number of hours to a duration.
+func hours(h float64) time.Duration {
+	return time.Duration(h * float64(time.Hour))
+}
+
+// CalculateEngagementScore calculates reviewer engagement based on comments and responsiveness
+// using DefaultEngagementConfig. Services use an EngagementCalculator built from configuration.
+func CalculateEngagementScore(assignment *models.ReviewerAssignment, _ *models.MRReview) float64 {
+	return defaultEngagementCalculator.Score(assignment)
+}
+
 // CalculateTTFRForMR is a helper function that wraps CalculateTTFR for MR reviews.
 func CalculateTTFRForMR(mrReview *models.MRReview) *int {
 	if mrReview == nil || mrReview.RouletteTriggeredAt == nil {
@@ -77,3 +230,25 @@ func CalculateTimeToApprovalForMR(mrReview *models.MRReview) *int {
 	}
 	return CalculateTimeToApproval(*mrReview.RouletteTriggeredAt, mrReview.ApprovedAt)
 }
+
+// SampleMean averages per-row averages weighted by the samples behind each row, so that
+// rows covering more reviews count more and rows without a value can simply be skipped.
+type SampleMean struct {
+	Total   float64
+	Samples int
+}
+
+// Add adds a row's average; rows written before samples were tracked count as one sample.
+func (m *SampleMean) Add(value float64, samples int) {
+	samples = max(samples, 1)
+	m.Total += value * float64(samples)
+	m.Samples += samples
+}
+
+// Mean returns the weighted mean, or 0 without samples.
+func (m SampleMean) Mean() float64 {
+	if m.Samples == 0 {
+		return 0
+	}
+	return m.Total / float64(m.Samples)
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
 )
 
@@ -71,6 +72,34 @@ func TestCalculateTTFR(t *testing.T) {
 	}
 }
 
+func TestSecondsToMinutes(t *testing.T) {
+	tests := []struct {
+		name     string
+		seconds  *int
+		expected *int
+	}{
+		{name: "nil", seconds: nil, expected: nil},
+		{name: "two hours", seconds: intPtr(7200), expected: intPtr(120)},
+		{name: "partial minute is truncated", seconds: intPtr(119), expected: intPtr(1)},
+		{name: "under a minute", seconds: intPtr(59), expected: intPtr(0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := SecondsToMinutes(tt.seconds)
+			if tt.expected == nil {
+				if result != nil {
+					t.Errorf("Expected nil, got %d", *result)
+				}
+				return
+			}
+			if result == nil || *result != *tt.expected {
+				t.Errorf("Expected %d minutes, got %v", *tt.expected, result)
+			}
+		})
+	}
+}
+
 func TestCalculateTimeToApproval(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -203,6 +232,55 @@ func TestCalculateEngagementScore(t *testing.T) {
 			expectedScoreRange: [2]float64{60, 80},
 			description:        "Thorough reviewer with detailed comments",
 		},
+		{
+			name: "fast first comment - within 1 hour",
+			assignment: &models.ReviewerAssignment{
+				CommentCount:   5,
+				CommentLength:  500,
+				AssignedAt:     time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+				FirstCommentAt: timePtr(time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC)),
+			},
+			mrReview: &models.MRReview{
+				RouletteTriggeredAt: timePtr(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)),
+			},
+			expectedScoreRange: [2]float64{65, 65},
+			description:        "Quick responder gets +10 bonus",
+		},
+		{
+			name: "first comment within 4 hours",
+			assignment: &models.ReviewerAssignment{
+				CommentCount:   5,
+				CommentLength:  500,
+				AssignedAt:     time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+				FirstCommentAt: timePtr(time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC)),
+			},
+			mrReview: &models.MRReview{
+				RouletteTriggeredAt: timePtr(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)),
+			},
+			expectedScoreRange: [2]float64{60, 60},
+			description:        "Same-morning responder gets +5 bonus",
+		},
+		{
+			name: "slow first comment - no bonus",
+			assignment: &models.ReviewerAssignment{
+				CommentCount:   5,
+				CommentLength:  500,
+				AssignedAt:     time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+				FirstCommentAt: timePtr(time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)),
+			},
+			mrReview: &models.MRReview{
+				RouletteTriggeredAt: timePtr(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)),
+			},
+			expectedScoreRange: [2]float64{55, 55},
+			description:        "Slow responder gets no bonus",
+		},
+		{
+			name:               "nil assignment",
+			assignment:         nil,
+			mrReview:           &models.MRReview{},
+			expectedScoreRange: [2]float64{0, 0},
+			description:        "Missing assignment scores zero",
+		},
 	}
 
 	for _, tt := range tests {
@@ -217,6 +295,96 @@ func TestCalculateEngagementScore(t *testing.T) {
 	}
 }
 
+func TestEngagementCalculator_CustomCoefficients(t *testing.T) {
+	assigned := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	assignment := &models.ReviewerAssignment{
+		CommentCount:   5,
+		CommentLength:  500,
+		AssignedAt:     assigned,
+		FirstCommentAt: timePtr(assigned.Add(90 * time.Minute)),
+	}
+
+	tests := []struct {
+		name     string
+		cfg      config.EngagementConfig
+		expected float64
+	}{
+		{
+			name:     "zero config uses the default formula",
+			cfg:      config.EngagementConfig{},
+			expected: 60, // 5*10 + 500/100 + 5 (within 4 hours)
+		},
+		{
+			name:     "default config matches zero config",
+			cfg:      DefaultEngagementConfig,
+			expected: 60,
+		},
+		{
+			name: "custom comment weight and length divisor",
+			cfg: config.EngagementConfig{
+				CommentWeight:       2,
+				LengthDivisor:       50,
+				FastResponseHours:   1,
+				FastResponseBonus:   10,
+				PromptResponseHours: 4,
+				PromptResponseBonus: 5,
+			},
+			expected: 25, // 5*2 + 500/50 + 5
+		},
+		{
+			name: "zero length divisor ignores comment length",
+			cfg: config.EngagementConfig{
+				CommentWeight: 10,
+			},
+			expected: 50,
+		},
+		{
+			name: "wider fast response window earns the fast bonus",
+			cfg: config.EngagementConfig{
+				CommentWeight:       10,
+				LengthDivisor:       100,
+				FastResponseHours:   2,
+				FastResponseBonus:   30,
+				PromptResponseHours: 4,
+				PromptResponseBonus: 5,
+			},
+			expected: 85, // 5*10 + 500/100 + 30
+		},
+		{
+			name: "response outside both windows earns no bonus",
+			cfg: config.EngagementConfig{
+				CommentWeight:       10,
+				LengthDivisor:       100,
+				FastResponseHours:   0.5,
+				FastResponseBonus:   10,
+				PromptResponseHours: 1,
+				PromptResponseBonus: 5,
+			},
+			expected: 55,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := NewEngagementCalculator(tt.cfg).Score(assignment)
+			if score != tt.expected {
+				t.Errorf("Expected score %.2f, got %.2f", tt.expected, score)
+			}
+		})
+	}
+}
+
+func TestEngagementCalculator_CommentScore(t *testing.T) {
+	calculator := NewEngagementCalculator(config.EngagementConfig{CommentWeight: 3, LengthDivisor: 10})
+
+	if score := calculator.CommentScore(2, 100); score != 16 {
+		t.Errorf("Expected comment score 16.00, got %.2f", score)
+	}
+	if score := NewEngagementCalculator(config.EngagementConfig{}).CommentScore(2, 100); score != 21 {
+		t.Errorf("Expected default comment score 21.00, got %.2f", score)
+	}
+}
+
 // Helper functions
 
 func timePtr(t time.Time) *time.Time {
@@ -226,3 +394,117 @@ func timePtr(t time.Time) *time.Time {
 func intPtr(i int) *int {
 	return &i
 }
+
+func TestWithoutWeekends(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    time.Time
+		end      time.Time
+		expected time.Duration
+	}{
+		{
+			name:     "within a weekday",
+			start:    time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), // Monday
+			end:      time.Date(2025, 1, 6, 12, 0, 0, 0, time.UTC),
+			expected: 2 * time.Hour,
+		},
+		{
+			name:     "Friday evening to Monday morning",
+			start:    time.Date(2025, 1, 10, 18, 0, 0, 0, time.UTC), // Friday
+			end:      time.Date(2025, 1, 13, 9, 0, 0, 0, time.UTC),  // Monday
+			expected: 15 * time.Hour,
+		},
+		{
+			name:     "entirely on a weekend",
+			start:    time.Date(2025, 1, 11, 9, 0, 0, 0, time.UTC), // Saturday
+			end:      time.Date(2025, 1, 12, 17, 0, 0, 0, time.UTC),
+			expected: 0,
+		},
+		{
+			name:     "two weeks",
+			start:    time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC),
+			end:      time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC),
+			expected: 10 * 24 * time.Hour,
+		},
+		{
+			name:     "end before start",
+			start:    time.Date(2025, 1, 6, 12, 0, 0, 0, time.UTC),
+			end:      time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC),
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WithoutWeekends(tt.start, tt.end); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestReviewClock_ExcludeWeekends(t *testing.T) {
+	triggeredAt := time.Date(2025, 1, 10, 17, 30, 0, 0, time.UTC)  // Friday evening
+	firstReviewAt := time.Date(2025, 1, 13, 8, 45, 0, 0, time.UTC) // Monday morning
+
+	wall := NewReviewClock(false, nil).TTFR(triggeredAt, &firstReviewAt)
+	if wall == nil || *wall != int((63*time.Hour+15*time.Minute).Seconds()) {
+		t.Errorf("Expected the wall-clock TTFR to include the weekend, got %v", wall)
+	}
+
+	weekdays := NewReviewClock(true, nil).TTFR(triggeredAt, &firstReviewAt)
+	if weekdays == nil || *weekdays != int((15*time.Hour+15*time.Minute).Seconds()) {
+		t.Errorf("Expected a weekend-excluded TTFR of 15h15m, got %v", weekdays)
+	}
+
+	// Weekends are observed in the clock's location: in Tokyo (UTC+9) the MR is
+	// triggered on Saturday and reviewed at 17:45 on Monday
+	tokyo := time.FixedZone("JST", 9*60*60)
+	local := NewReviewClock(true, tokyo).TTFR(triggeredAt, &firstReviewAt)
+	if local == nil || *local != int((17*time.Hour+45*time.Minute).Seconds()) {
+		t.Errorf("Expected 17h45m of weekday time in Tokyo, got %v", local)
+	}
+}
+
+func TestEngagementCalculator_Breakdown(t *testing.T) {
+	assignedAt := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+	firstCommentAt := assignedAt.Add(2 * time.Hour) // Prompt response
+	assignment := &models.ReviewerAssignment{
+		AssignedAt:     assignedAt,
+		FirstCommentAt: &firstCommentAt,
+		CommentCount:   3,
+		CommentLength:  250,
+	}
+
+	calc := NewEngagementCalculator(DefaultEngagementConfig)
+	b := calc.Breakdown(assignment)
+
+	if b.CommentPoints != 30 || b.LengthPoints != 2.5 || b.ResponseBonus != 5 {
+		t.Errorf("Unexpected components: %+v", b)
+	}
+	if b.Score != calc.Score(assignment) || b.Score != 37.5 {
+		t.Errorf("Expected the components to add up to the score 37.5, got %+v", b)
+	}
+	if (calc.Breakdown(nil) != EngagementBreakdown{}) {
+		t.Error("Expected an empty breakdown for a nil assignment")
+	}
+}
+
+func TestSampleMean(t *testing.T) {
+	var mean SampleMean
+	if got := mean.Mean(); got != 0 {
+		t.Errorf("Expected 0 without samples, got %v", got)
+	}
+
+	mean.Add(60, 3)
+	mean.Add(120, 1)
+	// Rows without samples recorded count as one
+	mean.Add(20, 0)
+
+	if mean.Samples != 5 {
+		t.Errorf("Expected 5 samples, got %d", mean.Samples)
+	}
+	if got := mean.Mean(); got != 64 {
+		t.Errorf("Expected weighted mean 64, got %v", got)
+	}
+}
@@ -2,29 +2,90 @@ package metrics
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"time"
 
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
 )
 
 // Repository interface defines the methods needed for metrics storage.
 type Repository interface {
 	CreateOrUpdate(metric *models.ReviewMetrics) error
-	GetByDate(date time.Time, team string, userID *uint) (*models.ReviewMetrics, error)
+	GetByDate(date time.Time, team string, userID *uint, projectID *int) (*models.ReviewMetrics, error)
 	GetByDateRange(startDate, endDate time.Time, filters map[string]interface{}) ([]models.ReviewMetrics, error)
 }
 
+// ErrDateRangeTooLarge is returned when a query spans more than the configured maximum range.
+var ErrDateRangeTooLarge = errors.New("date range exceeds maximum allowed")
+
+// ValidateRange checks that a custom date range is ordered and spans at most maxDays
+// days (metrics.max_query_range_days), so services reject overly broad queries before
+// scanning the metrics table. A non-positive maxDays disables the limit.
+func ValidateRange(startDate, endDate time.Time, maxDays int) error {
+	if endDate.Before(startDate) {
+		return fmt.Errorf("end date %s is before start date %s", endDate.Format(time.DateOnly), startDate.Format(time.DateOnly))
+	}
+	if maxDays > 0 && endDate.Sub(startDate) > time.Duration(maxDays)*24*time.Hour {
+		return fmt.Errorf("%w: %s to %s spans more than %d days", ErrDateRangeTooLarge,
+			startDate.Format(time.DateOnly), endDate.Format(time.DateOnly), maxDays)
+	}
+	return nil
+}
+
 // Service handles metrics calculation and storage.
 type Service struct {
-	repo Repository
+	repo          Repository
+	engagement    *EngagementCalculator
+	clock         *ReviewClock
+	excludedUsers *config.ExcludedUsersConfig
+	minComments   int
 }
 
 // NewService creates a new metrics service.
 func NewService(repo Repository) *Service {
 	return &Service{
-		repo: repo,
+		repo:       repo,
+		engagement: defaultEngagementCalculator,
+		clock:      wallClock,
+	}
+}
+
+// SetReviewClock sets how TTFR and time to approval are measured.
+func (s *Service) SetReviewClock(clock *ReviewClock) {
+	s.clock = clock
+}
+
+// SetEngagementCalculator sets the formula used to score reviewer engagement.
+func (s *Service) SetEngagementCalculator(calculator *EngagementCalculator) {
+	s.engagement = calculator
+}
+
+// SetExcludedUsers sets the users whose MRs and reviews are not recorded.
+func (s *Service) SetExcludedUsers(excludedUsers *config.ExcludedUsersConfig) {
+	s.excludedUsers = excludedUsers
+}
+
+// SetMinReviewComments sets the engagement floor: reviews with fewer comments are
+// not recorded as completed and get no engagement score (0 records every review).
+func (s *Service) SetMinReviewComments(minComments int) {
+	s.minComments = minComments
+}
+
+// belowEngagementFloor reports whether an assignment has too few comments to count as a review.
+func (s *Service) belowEngagementFloor(assignment *models.ReviewerAssignment) bool {
+	return assignment != nil && assignment.CommentCount < s.minComments
+}
+
+// isExcluded reports whether the MR author or the assigned reviewer is an excluded user,
+// matching how the aggregator filters reviews and assignments.
+func (s *Service) isExcluded(mrReview *models.MRReview, assignment *models.ReviewerAssignment) bool {
+	if s.excludedUsers.ExcludesUser(mrReview.MRAuthor) {
+		return true
 	}
+	return assignment != nil && s.excludedUsers.ExcludesUser(&assignment.User)
 }
 
 // RecordReviewTriggered records when a review is triggered. This increments the total_reviews counter for the team on the given date.
@@ -33,10 +94,14 @@ func (s *Service) RecordReviewTriggered(_ context.Context, mrReview *models.MRRe
 		return fmt.Errorf("roulette_triggered_at is required")
 	}
 
+	if s.isExcluded(mrReview, nil) {
+		return nil
+	}
+
 	date := mrReview.RouletteTriggeredAt.Truncate(24 * time.Hour) // Get date only
 
 	// Get or create metric for this team on this date
-	metric, err := s.repo.GetByDate(date, mrReview.Team, nil)
+	metric, err := s.repo.GetByDate(date, mrReview.Team, nil, nil)
 	if err != nil || metric == nil {
 		// Metric doesn't exist, create new one
 		metric = &models.ReviewMetrics{
@@ -54,15 +119,19 @@ func (s *Service) RecordReviewTriggered(_ context.Context, mrReview *models.MRRe
 }
 
 // RecordReviewStarted records when a reviewer starts reviewing. This updates TTFR metrics.
-func (s *Service) RecordReviewStarted(_ context.Context, mrReview *models.MRReview, _ *models.ReviewerAssignment) error {
+func (s *Service) RecordReviewStarted(_ context.Context, mrReview *models.MRReview, assignment *models.ReviewerAssignment) error {
 	if mrReview.RouletteTriggeredAt == nil {
 		return fmt.Errorf("roulette_triggered_at is required")
 	}
 
+	if s.isExcluded(mrReview, assignment) {
+		return nil
+	}
+
 	date := mrReview.RouletteTriggeredAt.Truncate(24 * time.Hour)
 
 	// Get metric for this team
-	metric, err := s.repo.GetByDate(date, mrReview.Team, nil)
+	metric, err := s.repo.GetByDate(date, mrReview.Team, nil, nil)
 	if err != nil || metric == nil {
 		// Create new metric if doesn't exist
 		metric = &models.ReviewMetrics{
@@ -73,18 +142,11 @@ func (s *Service) RecordReviewStarted(_ context.Context, mrReview *models.MRRevi
 		}
 	}
 
-	// Calculate TTFR if we have first_review_at
+	// Calculate TTFR if we have first_review_at; metrics store minutes
 	if mrReview.FirstReviewAt != nil {
-		ttfr := CalculateTTFRForMR(mrReview)
+		ttfr := SecondsToMinutes(s.clock.TTFR(*mrReview.RouletteTriggeredAt, mrReview.FirstReviewAt))
 		if ttfr != nil {
-			// Update average TTFR (simple average for now, can be improved with weighted average)
-			if metric.AvgTTFR == nil {
-				metric.AvgTTFR = ttfr
-			} else {
-				// Running average: new_avg = (old_avg + new_value) / 2
-				newAvg := (*metric.AvgTTFR + *ttfr) / 2
-				metric.AvgTTFR = &newAvg
-			}
+			metric.AvgTTFR = updateIntMean(metric.AvgTTFR, &metric.TTFRSamples, *ttfr)
 		}
 	}
 
@@ -97,10 +159,14 @@ func (s *Service) RecordReviewCompleted(_ context.Context, mrReview *models.MRRe
 		return fmt.Errorf("roulette_triggered_at is required")
 	}
 
+	if s.isExcluded(mrReview, assignment) || s.belowEngagementFloor(assignment) {
+		return nil
+	}
+
 	date := mrReview.RouletteTriggeredAt.Truncate(24 * time.Hour)
 
 	// Get metric for this team
-	metric, err := s.repo.GetByDate(date, mrReview.Team, nil)
+	metric, err := s.repo.GetByDate(date, mrReview.Team, nil, nil)
 	if err != nil || metric == nil {
 		// Create new metric if doesn't exist
 		metric = &models.ReviewMetrics{
@@ -116,43 +182,24 @@ func (s *Service) RecordReviewCompleted(_ context.Context, mrReview *models.MRRe
 
 	// Calculate Time to Approval if we have approved_at
 	if mrReview.ApprovedAt != nil {
-		timeToApproval := CalculateTimeToApprovalForMR(mrReview)
+		timeToApproval := SecondsToMinutes(s.clock.TimeToApproval(*mrReview.RouletteTriggeredAt, mrReview.ApprovedAt))
 		if timeToApproval != nil {
-			if metric.AvgTimeToApproval == nil {
-				metric.AvgTimeToApproval = timeToApproval
-			} else {
-				// Running average
-				newAvg := (*metric.AvgTimeToApproval + *timeToApproval) / 2
-				metric.AvgTimeToApproval = &newAvg
-			}
+			metric.AvgTimeToApproval = updateIntMean(metric.AvgTimeToApproval, &metric.ApprovalSamples, *timeToApproval)
 		}
 	}
 
 	// Calculate TTFR if not already set
 	if metric.AvgTTFR == nil && mrReview.FirstReviewAt != nil {
-		ttfr := CalculateTTFRForMR(mrReview)
+		ttfr := SecondsToMinutes(s.clock.TTFR(*mrReview.RouletteTriggeredAt, mrReview.FirstReviewAt))
 		if ttfr != nil {
 			metric.AvgTTFR = ttfr
+			metric.TTFRSamples = 1
 		}
 	}
 
 	// Update comment metrics
 	if assignment != nil {
-		commentCount := float64(assignment.CommentCount)
-		if metric.AvgCommentCount == nil {
-			metric.AvgCommentCount = &commentCount
-		} else {
-			newAvg := (*metric.AvgCommentCount + commentCount) / 2
-			metric.AvgCommentCount = &newAvg
-		}
-
-		commentLength := float64(assignment.CommentLength)
-		if metric.AvgCommentLength == nil {
-			metric.AvgCommentLength = &commentLength
-		} else {
-			newAvg := (*metric.AvgCommentLength + commentLength) / 2
-			metric.AvgCommentLength = &newAvg
-		}
+		updateCommentMeans(metric, assignment)
 	}
 
 	return s.repo.CreateOrUpdate(metric)
@@ -163,6 +210,10 @@ func (s *Service) RecordReviewEngagement(_ context.Context, mrReview *models.MRR
 	if mrReview.RouletteTriggeredAt == nil {
 		return fmt.Errorf("roulette_triggered_at is required")
 	}
+
+	if s.isExcluded(mrReview, assignment) || s.belowEngagementFloor(assignment) {
+		return nil
+	}
 	if assignment == nil {
 		return fmt.Errorf("assignment is required")
 	}
@@ -170,7 +221,7 @@ func (s *Service) RecordReviewEngagement(_ context.Context, mrReview *models.MRR
 	date := mrReview.RouletteTriggeredAt.Truncate(24 * time.Hour)
 
 	// Get or create metric for this user
-	metric, err := s.repo.GetByDate(date, mrReview.Team, &assignment.UserID)
+	metric, err := s.repo.GetByDate(date, mrReview.Team, &assignment.UserID, nil)
 	if err != nil || metric == nil {
 		// Create new user-level metric
 		metric = &models.ReviewMetrics{
@@ -181,33 +232,54 @@ func (s *Service) RecordReviewEngagement(_ context.Context, mrReview *models.MRR
 	}
 
 	// Calculate engagement score
-	engagementScore := CalculateEngagementScore(assignment, mrReview)
-	if metric.EngagementScore == nil {
-		metric.EngagementScore = &engagementScore
-	} else {
-		// Running average for engagement score
-		newAvg := (*metric.EngagementScore + engagementScore) / 2
-		metric.EngagementScore = &newAvg
-	}
+	engagementScore := s.engagement.Score(assignment)
+	metric.EngagementScore = updateMean(metric.EngagementScore, &metric.EngagementSamples, engagementScore)
 
 	// Update comment metrics
-	commentCount := float64(assignment.CommentCount)
-	if metric.AvgCommentCount == nil {
-		metric.AvgCommentCount = &commentCount
-	} else {
-		newAvg := (*metric.AvgCommentCount + commentCount) / 2
-		metric.AvgCommentCount = &newAvg
+	updateCommentMeans(metric, assignment)
+
+	return s.repo.CreateOrUpdate(metric)
+}
+
+// updateCommentMeans folds an assignment's comment count and length into the running means.
+// Both averages share CommentSamples, so the count is advanced once.
+func updateCommentMeans(metric *models.ReviewMetrics, assignment *models.ReviewerAssignment) {
+	samples := metric.CommentSamples
+	metric.AvgCommentCount = updateMean(metric.AvgCommentCount, &samples, float64(assignment.CommentCount))
+	samples = metric.CommentSamples
+	metric.AvgCommentLength = updateMean(metric.AvgCommentLength, &samples, float64(assignment.CommentLength))
+	metric.CommentSamples = samples
+}
+
+// updateMean folds value into the running mean avg over *samples samples and
+// increments the count: new_avg = old_avg + (value - old_avg) / n.
+// Rows written before sample counts were tracked have an average but no count;
+// their average is treated as a single sample.
+func updateMean(avg *float64, samples *int, value float64) *float64 {
+	if avg == nil {
+		*samples = 1
+		return &value
 	}
 
-	commentLength := float64(assignment.CommentLength)
-	if metric.AvgCommentLength == nil {
-		metric.AvgCommentLength = &commentLength
-	} else {
-		newAvg := (*metric.AvgCommentLength + commentLength) / 2
-		metric.AvgCommentLength = &newAvg
+	if *samples < 1 {
+		*samples = 1
 	}
+	*samples++
 
-	return s.repo.CreateOrUpdate(metric)
+	newAvg := *avg + (value-*avg)/float64(*samples)
+	return &newAvg
+}
+
+// updateIntMean is updateMean for integer averages, rounding to the nearest integer.
+func updateIntMean(avg *int, samples *int, value int) *int {
+	var current *float64
+	if avg != nil {
+		f := float64(*avg)
+		current = &f
+	}
+
+	rounded := int(math.Round(*updateMean(current, samples, float64(value))))
+	return &rounded
 }
 
 // CalculateMetricsForPeriod recalculates metrics for a date range. This is useful for backfilling or recalculating metrics after bugs/changes.
@@ -2,16 +2,18 @@ package metrics
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
 )
 
 // MockMetricsRepository implements the repository interface for testing
 type MockMetricsRepository struct {
 	CreateOrUpdateFunc func(metric *models.ReviewMetrics) error
-	GetByDateFunc      func(date time.Time, team string, userID *uint) (*models.ReviewMetrics, error)
+	GetByDateFunc      func(date time.Time, team string, userID *uint, projectID *int) (*models.ReviewMetrics, error)
 	GetByDateRangeFunc func(startDate, endDate time.Time, filters map[string]interface{}) ([]models.ReviewMetrics, error)
 }
 
@@ -22,9 +24,9 @@ func (m *MockMetricsRepository) CreateOrUpdate(metric *models.ReviewMetrics) err
 	return nil
 }
 
-func (m *MockMetricsRepository) GetByDate(date time.Time, team string, userID *uint) (*models.ReviewMetrics, error) {
+func (m *MockMetricsRepository) GetByDate(date time.Time, team string, userID *uint, projectID *int) (*models.ReviewMetrics, error) {
 	if m.GetByDateFunc != nil {
-		return m.GetByDateFunc(date, team, userID)
+		return m.GetByDateFunc(date, team, userID, projectID)
 	}
 	return nil, nil
 }
@@ -66,9 +68,64 @@ func TestService_RecordReviewTriggered(t *testing.T) {
 	}
 }
 
+func TestService_RecordReviewTriggered_ExcludedAuthor(t *testing.T) {
+	repo := &MockMetricsRepository{
+		CreateOrUpdateFunc: func(metric *models.ReviewMetrics) error {
+			t.Errorf("Expected no metric for an excluded author, got %+v", metric)
+			return nil
+		},
+	}
+
+	svc := NewService(repo)
+	svc.SetExcludedUsers(&config.ExcludedUsersConfig{Usernames: []string{"renovate-bot"}})
+
+	mrReview := &models.MRReview{
+		Team:                "team-frontend",
+		RouletteTriggeredAt: timePtr(time.Now()),
+		MRAuthor:            &models.User{GitLabID: 42, Username: "renovate-bot"},
+	}
+
+	if err := svc.RecordReviewTriggered(context.Background(), mrReview); err != nil {
+		t.Fatalf("RecordReviewTriggered failed: %v", err)
+	}
+}
+
+func TestService_RecordReviewCompleted_EngagementFloor(t *testing.T) {
+	writes := 0
+	repo := &MockMetricsRepository{
+		CreateOrUpdateFunc: func(_ *models.ReviewMetrics) error {
+			writes++
+			return nil
+		},
+	}
+
+	svc := NewService(repo)
+	svc.SetMinReviewComments(1)
+
+	mrReview := &models.MRReview{Team: "team-frontend", RouletteTriggeredAt: timePtr(time.Now())}
+	silent := &models.ReviewerAssignment{UserID: 1, CommentCount: 0}
+	if err := svc.RecordReviewCompleted(context.Background(), mrReview, silent); err != nil {
+		t.Fatalf("RecordReviewCompleted failed: %v", err)
+	}
+	if err := svc.RecordReviewEngagement(context.Background(), mrReview, silent); err != nil {
+		t.Fatalf("RecordReviewEngagement failed: %v", err)
+	}
+	if writes != 0 {
+		t.Errorf("Expected no metrics for a review below the floor, got %d writes", writes)
+	}
+
+	commented := &models.ReviewerAssignment{UserID: 1, CommentCount: 2}
+	if err := svc.RecordReviewCompleted(context.Background(), mrReview, commented); err != nil {
+		t.Fatalf("RecordReviewCompleted failed: %v", err)
+	}
+	if writes != 1 {
+		t.Errorf("Expected 1 write for a review meeting the floor, got %d", writes)
+	}
+}
+
 func TestService_RecordReviewStarted(t *testing.T) {
 	repo := &MockMetricsRepository{
-		GetByDateFunc: func(date time.Time, team string, userID *uint) (*models.ReviewMetrics, error) {
+		GetByDateFunc: func(date time.Time, team string, userID *uint, projectID *int) (*models.ReviewMetrics, error) {
 			// Return existing metric
 			return &models.ReviewMetrics{
 				ID:               1,
@@ -110,7 +167,7 @@ func TestService_RecordReviewStarted(t *testing.T) {
 
 func TestService_RecordReviewCompleted(t *testing.T) {
 	repo := &MockMetricsRepository{
-		GetByDateFunc: func(date time.Time, team string, userID *uint) (*models.ReviewMetrics, error) {
+		GetByDateFunc: func(date time.Time, team string, userID *uint, projectID *int) (*models.ReviewMetrics, error) {
 			return &models.ReviewMetrics{
 				ID:               1,
 				Date:             date,
@@ -165,7 +222,7 @@ func TestService_RecordReviewCompleted(t *testing.T) {
 
 func TestService_RecordReviewEngagement(t *testing.T) {
 	repo := &MockMetricsRepository{
-		GetByDateFunc: func(date time.Time, team string, userID *uint) (*models.ReviewMetrics, error) {
+		GetByDateFunc: func(date time.Time, team string, userID *uint, projectID *int) (*models.ReviewMetrics, error) {
 			if userID == nil {
 				t.Error("Expected userID to be provided for engagement tracking")
 			}
@@ -213,6 +270,173 @@ func TestService_RecordReviewEngagement(t *testing.T) {
 	}
 }
 
+func TestService_RecordReviewEngagement_CustomFormula(t *testing.T) {
+	repo, stored := newStoringRepository()
+	svc := NewService(repo)
+	svc.SetEngagementCalculator(NewEngagementCalculator(config.EngagementConfig{CommentWeight: 1, LengthDivisor: 1000}))
+
+	mrReview := &models.MRReview{
+		Team:                "team-frontend",
+		RouletteTriggeredAt: timePtr(time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)),
+	}
+	assignment := &models.ReviewerAssignment{UserID: 10, CommentCount: 8, CommentLength: 2000}
+
+	if err := svc.RecordReviewEngagement(context.Background(), mrReview, assignment); err != nil {
+		t.Fatalf("RecordReviewEngagement failed: %v", err)
+	}
+
+	// 8*1 + 2000/1000, instead of 8*10 + 2000/100 with the default formula
+	if score := stored().EngagementScore; score == nil || *score != 10 {
+		t.Errorf("Expected engagement score 10, got %v", score)
+	}
+}
+
+// newStoringRepository returns a mock repository that keeps the last stored metric,
+// so consecutive record calls build on each other like the real repository.
+func newStoringRepository() (*MockMetricsRepository, func() *models.ReviewMetrics) {
+	var stored *models.ReviewMetrics
+	repo := &MockMetricsRepository{
+		GetByDateFunc: func(date time.Time, team string, userID *uint, projectID *int) (*models.ReviewMetrics, error) {
+			if stored == nil {
+				return nil, nil
+			}
+			metric := *stored
+			return &metric, nil
+		},
+		CreateOrUpdateFunc: func(metric *models.ReviewMetrics) error {
+			saved := *metric
+			stored = &saved
+			return nil
+		},
+	}
+	return repo, func() *models.ReviewMetrics { return stored }
+}
+
+func TestService_RunningAverageIsTrueMean(t *testing.T) {
+	triggeredAt := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+
+	t.Run("ttfr", func(t *testing.T) {
+		repo, stored := newStoringRepository()
+		svc := NewService(repo)
+
+		for _, offset := range []time.Duration{60 * time.Minute, 120 * time.Minute, 300 * time.Minute} {
+			firstReviewAt := triggeredAt.Add(offset)
+			mrReview := &models.MRReview{Team: "team-frontend", RouletteTriggeredAt: &triggeredAt, FirstReviewAt: &firstReviewAt}
+			if err := svc.RecordReviewStarted(context.Background(), mrReview, nil); err != nil {
+				t.Fatalf("RecordReviewStarted failed: %v", err)
+			}
+		}
+
+		metric := stored()
+		if metric.AvgTTFR == nil || *metric.AvgTTFR != 160 {
+			t.Errorf("Expected AvgTTFR = 160 minutes (mean of 60, 120, 300), got %v", metric.AvgTTFR)
+		}
+		if metric.TTFRSamples != 3 {
+			t.Errorf("Expected TTFRSamples = 3, got %d", metric.TTFRSamples)
+		}
+	})
+
+	t.Run("completed", func(t *testing.T) {
+		repo, stored := newStoringRepository()
+		svc := NewService(repo)
+
+		samples := []struct {
+			approval      time.Duration
+			commentCount  int
+			commentLength int
+		}{
+			{600 * time.Minute, 2, 100},
+			{1200 * time.Minute, 4, 200},
+			{3000 * time.Minute, 9, 600},
+		}
+		for _, sample := range samples {
+			approvedAt := triggeredAt.Add(sample.approval)
+			mrReview := &models.MRReview{Team: "team-frontend", RouletteTriggeredAt: &triggeredAt, ApprovedAt: &approvedAt}
+			assignment := &models.ReviewerAssignment{CommentCount: sample.commentCount, CommentLength: sample.commentLength}
+			if err := svc.RecordReviewCompleted(context.Background(), mrReview, assignment); err != nil {
+				t.Fatalf("RecordReviewCompleted failed: %v", err)
+			}
+		}
+
+		metric := stored()
+		if metric.AvgTimeToApproval == nil || *metric.AvgTimeToApproval != 1600 {
+			t.Errorf("Expected AvgTimeToApproval = 1600 minutes, got %v", metric.AvgTimeToApproval)
+		}
+		if metric.AvgCommentCount == nil || *metric.AvgCommentCount != 5 {
+			t.Errorf("Expected AvgCommentCount = 5, got %v", metric.AvgCommentCount)
+		}
+		if metric.AvgCommentLength == nil || *metric.AvgCommentLength != 300 {
+			t.Errorf("Expected AvgCommentLength = 300, got %v", metric.AvgCommentLength)
+		}
+		if metric.ApprovalSamples != 3 || metric.CommentSamples != 3 {
+			t.Errorf("Expected 3 approval and comment samples, got %d and %d", metric.ApprovalSamples, metric.CommentSamples)
+		}
+	})
+
+	t.Run("engagement", func(t *testing.T) {
+		repo, stored := newStoringRepository()
+		svc := NewService(repo)
+
+		// Engagement scores 10, 20, 60 (10 points per comment)
+		for _, count := range []int{1, 2, 6} {
+			mrReview := &models.MRReview{Team: "team-frontend", RouletteTriggeredAt: &triggeredAt}
+			assignment := &models.ReviewerAssignment{UserID: 10, CommentCount: count}
+			if err := svc.RecordReviewEngagement(context.Background(), mrReview, assignment); err != nil {
+				t.Fatalf("RecordReviewEngagement failed: %v", err)
+			}
+		}
+
+		metric := stored()
+		if metric.EngagementScore == nil || *metric.EngagementScore != 30 {
+			t.Errorf("Expected EngagementScore = 30, got %v", metric.EngagementScore)
+		}
+		if metric.AvgCommentCount == nil || *metric.AvgCommentCount != 3 {
+			t.Errorf("Expected AvgCommentCount = 3, got %v", metric.AvgCommentCount)
+		}
+		if metric.EngagementSamples != 3 || metric.CommentSamples != 3 {
+			t.Errorf("Expected 3 engagement and comment samples, got %d and %d", metric.EngagementSamples, metric.CommentSamples)
+		}
+	})
+}
+
+func TestUpdateMean_LegacyRowWithoutSamples(t *testing.T) {
+	avg := 10.0
+	samples := 0
+
+	result := updateMean(&avg, &samples, 20)
+
+	if *result != 15 {
+		t.Errorf("Expected legacy average to count as one sample (15), got %v", *result)
+	}
+	if samples != 2 {
+		t.Errorf("Expected 2 samples, got %d", samples)
+	}
+}
+
+func TestValidateRange(t *testing.T) {
+	endDate := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+
+	// A 10-year range is rejected
+	if err := ValidateRange(endDate.AddDate(-10, 0, 0), endDate, 366); !errors.Is(err, ErrDateRangeTooLarge) {
+		t.Errorf("Expected ErrDateRangeTooLarge for a 10-year range, got %v", err)
+	}
+
+	// A 90-day range succeeds
+	if err := ValidateRange(endDate.AddDate(0, 0, -90), endDate, 366); err != nil {
+		t.Errorf("Expected 90-day range to succeed, got %v", err)
+	}
+
+	// Without a limit any range is allowed
+	if err := ValidateRange(endDate.AddDate(-10, 0, 0), endDate, 0); err != nil {
+		t.Errorf("Expected unlimited range to succeed, got %v", err)
+	}
+
+	// Inverted ranges are rejected
+	if err := ValidateRange(endDate, endDate.AddDate(0, 0, -1), 366); err == nil {
+		t.Error("Expected error when end date is before start date")
+	}
+}
+
 func TestService_CalculateMetricsForPeriod(t *testing.T) {
 	// This test will be implemented when we have a review repository
 	// For now, just verify the method signature
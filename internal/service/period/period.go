@@ -0,0 +1,98 @@
+// Package period resolves named reporting periods ("day", "week", "month", ...) to date ranges
+// and measures runs of consecutive days within them.
+package period
+
+import (
+	"sort"
+	"time"
+)
+
+// Epoch is the start of the "all_time" period, which unknown and empty periods default to.
+var Epoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Range returns the rolling window of a named period ending now.
+func Range(name string) (start, end time.Time) {
+	return Rolling(name, time.Now())
+}
+
+// Rolling returns the window of a named period ending at now: the last 24 hours for
+// "day", 7 days for "week", 30 for "month", 90 for "quarter" and 365 for "year".
+func Rolling(name string, now time.Time) (start, end time.Time) {
+	switch name {
+	case "day":
+		start = now.Add(-24 * time.Hour)
+	case "week":
+		start = now.Add(-7 * 24 * time.Hour)
+	case "month":
+		start = now.Add(-30 * 24 * time.Hour)
+	case "quarter":
+		start = now.Add(-90 * 24 * time.Hour)
+	case "year":
+		start = now.Add(-365 * 24 * time.Hour)
+	default:
+		start = Epoch
+	}
+	return start, now
+}
+
+// Calendar returns the range from the start of the calendar period containing now to now:
+// midnight for "day", Monday for "week", the 1st for "month", the first month of the quarter
+// for "quarter" and January 1st for "year", in now's location. Requests made minutes apart
+// share the same start, unlike Rolling windows.
+func Calendar(name string, now time.Time) (start, end time.Time) {
+	year, month, day := now.Date()
+	switch name {
+	case "day":
+		start = time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+	case "week":
+		// Weekday counts from Sunday; weeks start on Monday
+		sinceMonday := (int(now.Weekday()) + 6) % 7
+		start = time.Date(year, month, day-sinceMonday, 0, 0, 0, 0, now.Location())
+	case "month":
+		start = time.Date(year, month, 1, 0, 0, 0, 0, now.Location())
+	case "quarter":
+		start = time.Date(year, month-(month-1)%3, 1, 0, 0, 0, 0, now.Location())
+	case "year":
+		start = time.Date(year, time.January, 1, 0, 0, 0, 0, now.Location())
+	default:
+		start = Epoch
+	}
+	return start, now
+}
+
+// LongestStreak returns the longest run of consecutive calendar days.
+// Dates are bucketed by calendar day, so duplicates and times of day are ignored.
+func LongestStreak(dates []time.Time) int {
+	if len(dates) == 0 {
+		return 0
+	}
+
+	// Bucket by calendar day
+	seen := make(map[time.Time]bool, len(dates))
+	days := make([]time.Time, 0, len(dates))
+	for _, d := range dates {
+		day := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+		if !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Before(days[j])
+	})
+
+	longest, current := 1, 1
+	for i := 1; i < len(days); i++ {
+		if days[i].Equal(days[i-1].AddDate(0, 0, 1)) {
+			current++
+		} else {
+			current = 1
+		}
+		if current > longest {
+			longest = current
+		}
+	}
+
+	return longest
+}
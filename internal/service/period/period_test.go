@@ -0,0 +1,170 @@
+package period
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRolling(t *testing.T) {
+	now := time.Date(2024, time.May, 15, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		start time.Time
+	}{
+		{"day", now.Add(-24 * time.Hour)},
+		{"week", now.Add(-7 * 24 * time.Hour)},
+		{"month", now.Add(-30 * 24 * time.Hour)},
+		{"quarter", now.Add(-90 * 24 * time.Hour)},
+		{"year", now.Add(-365 * 24 * time.Hour)},
+		{"all_time", Epoch},
+		{"", Epoch},
+		{"fortnight", Epoch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := Rolling(tt.name, now)
+			if !start.Equal(tt.start) {
+				t.Errorf("Expected start %v, got %v", tt.start, start)
+			}
+			if !end.Equal(now) {
+				t.Errorf("Expected end %v, got %v", now, end)
+			}
+		})
+	}
+}
+
+func TestRange(t *testing.T) {
+	before := time.Now()
+	start, end := Range("week")
+	after := time.Now()
+
+	if end.Before(before) || end.After(after) {
+		t.Errorf("Expected the range to end now, got %v", end)
+	}
+	if got := end.Sub(start); got != 7*24*time.Hour {
+		t.Errorf("Expected a 7-day window, got %v", got)
+	}
+
+	if start, _ := Range("all_time"); !start.Equal(Epoch) {
+		t.Errorf("Expected all_time to start at %v, got %v", Epoch, start)
+	}
+}
+
+func TestCalendar(t *testing.T) {
+	// Wednesday
+	now := time.Date(2024, time.May, 15, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		start time.Time
+	}{
+		{"day", time.Date(2024, time.May, 15, 0, 0, 0, 0, time.UTC)},
+		{"week", time.Date(2024, time.May, 13, 0, 0, 0, 0, time.UTC)},
+		{"month", time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)},
+		{"quarter", time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"year", time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"all_time", Epoch},
+		{"", Epoch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := Calendar(tt.name, now)
+			if !start.Equal(tt.start) {
+				t.Errorf("Expected start %v, got %v", tt.start, start)
+			}
+			if !end.Equal(now) {
+				t.Errorf("Expected end %v, got %v", now, end)
+			}
+		})
+	}
+}
+
+func TestCalendar_WeekBoundaries(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	tests := []struct {
+		desc  string
+		now   time.Time
+		start time.Time
+	}{
+		{"Monday starts its own week", time.Date(2024, time.May, 13, 8, 0, 0, 0, paris), time.Date(2024, time.May, 13, 0, 0, 0, 0, paris)},
+		{"Sunday belongs to the week before", time.Date(2024, time.May, 19, 23, 0, 0, 0, paris), time.Date(2024, time.May, 13, 0, 0, 0, 0, paris)},
+		{"week spanning a month boundary", time.Date(2024, time.March, 1, 9, 0, 0, 0, paris), time.Date(2024, time.February, 26, 0, 0, 0, 0, paris)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			start, _ := Calendar("week", tt.now)
+			if !start.Equal(tt.start) {
+				t.Errorf("Expected week to start %v, got %v", tt.start, start)
+			}
+		})
+	}
+}
+
+func TestCalendar_StableWithinPeriod(t *testing.T) {
+	first, _ := Calendar("month", time.Date(2024, time.May, 15, 14, 30, 0, 0, time.UTC))
+	second, _ := Calendar("month", time.Date(2024, time.May, 15, 14, 35, 0, 0, time.UTC))
+	if !first.Equal(second) {
+		t.Errorf("Expected requests minutes apart to share a start, got %v and %v", first, second)
+	}
+}
+
+func TestLongestStreak(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		dates    []time.Time
+		expected int
+	}{
+		{"No activity", nil, 0},
+		{"Single day", []time.Time{day(2025, 3, 10)}, 1},
+		{
+			"Gap breaks streak",
+			[]time.Time{day(2025, 3, 1), day(2025, 3, 2), day(2025, 3, 4), day(2025, 3, 5), day(2025, 3, 6)},
+			3,
+		},
+		{
+			"Unordered with duplicates",
+			[]time.Time{day(2025, 3, 3), day(2025, 3, 1), day(2025, 3, 2), day(2025, 3, 2), day(2025, 3, 1).Add(15 * time.Hour)},
+			3,
+		},
+		{
+			"Across month edge",
+			[]time.Time{day(2025, 1, 30), day(2025, 1, 31), day(2025, 2, 1), day(2025, 2, 2)},
+			4,
+		},
+		{
+			"Across February in a leap year",
+			[]time.Time{day(2024, 2, 28), day(2024, 2, 29), day(2024, 3, 1)},
+			3,
+		},
+		{
+			"Missing leap day breaks streak",
+			[]time.Time{day(2024, 2, 28), day(2024, 3, 1), day(2024, 3, 2)},
+			2,
+		},
+		{
+			"Across year edge",
+			[]time.Time{day(2024, 12, 31), day(2025, 1, 1)},
+			2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LongestStreak(tt.dates); got != tt.expected {
+				t.Errorf("Expected streak %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package roulette
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/cache"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/mattermost"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+)
+
+// setupFallbackTestService builds a service backed by SQLite and miniredis.
+// Only OOO users are used, so availability never reaches the GitLab client.
+func setupFallbackTestService(t *testing.T, cfg *config.Config, mmClient *mattermost.Client) (*Service, *repository.DB) {
+	t.Helper()
+
+	gormDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open in-memory database: %v", err)
+	}
+	if err := gormDB.AutoMigrate(&models.User{}, &models.OOOStatus{}, &models.MRReview{}, &models.ReviewerAssignment{}); err != nil {
+		t.Fatalf("Failed to auto-migrate tables: %v", err)
+	}
+	db := &repository.DB{DB: gormDB}
+
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("Failed to start miniredis: %v", err)
+	}
+	t.Cleanup(mr.Close)
+
+	log := logger.New("error", "json", "stdout")
+	redisCache, err := cache.NewCache(&config.RedisConfig{
+		Host:     mr.Host(),
+		Port:     mr.Server().Addr().Port,
+		PoolSize: 10,
+	}, log)
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	t.Cleanup(func() { _ = redisCache.Close() })
+
+	service := NewService(
+		cfg,
+		nil,
+		repository.NewUserRepository(db),
+		repository.NewOOORepository(db),
+		repository.NewReviewRepository(db),
+		redisCache,
+		mmClient,
+		log,
+	)
+
+	return service, db
+}
+
+func createFallbackTestUser(t *testing.T, db *repository.DB, gitlabID int, username, team string, ooo bool) *models.User {
+	t.Helper()
+
+	user := &models.User{GitLabID: gitlabID, Username: username, Email: username + "@example.com", Role: "dev", Team: team}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("Failed to create user %s: %v", username, err)
+	}
+
+	if ooo {
+		status := &models.OOOStatus{
+			UserID:    user.ID,
+			StartDate: time.Now().Add(-24 * time.Hour),
+			EndDate:   time.Now().Add(24 * time.Hour),
+			Reason:    "vacation",
+		}
+		if err := db.Create(status).Error; err != nil {
+			t.Fatalf("Failed to create OOO status for %s: %v", username, err)
+		}
+	}
+
+	return user
+}
+
+func TestSelectTeamMemberWithFallback_AllOOO(t *testing.T) {
+	var messages []mattermost.Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg mattermost.Message
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		messages = append(messages, msg)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		Teams: []config.TeamConfig{
+			{Name: "team-frontend", FallbackReviewer: "lead"},
+		},
+		Availability: config.AvailabilityConfig{CacheTTL: 300},
+	}
+	mmClient := mattermost.NewClient(&config.MattermostConfig{WebhookURL: server.URL, Enabled: true}, nil, logger.New("error", "json", "stdout"))
+
+	service, db := setupFallbackTestService(t, cfg, mmClient)
+	createFallbackTestUser(t, db, 1, "alice", "team-frontend", true)
+	createFallbackTestUser(t, db, 2, "bob", "team-frontend", true)
+	lead := createFallbackTestUser(t, db, 3, "lead", "team-leads", false)
+
+	req := &SelectionRequest{ProjectID: 100, MRIID: 1}
+	reviewer, err := service.selectTeamMemberWithFallback(context.Background(), req, "team-frontend", "", "https://gitlab.example.com/mr/1", 0, nil, nil)
+	if err != nil {
+		t.Fatalf("selectTeamMemberWithFallback failed: %v", err)
+	}
+
+	if reviewer.User.ID != lead.ID {
+		t.Errorf("Expected fallback reviewer %q, got %q", lead.Username, reviewer.User.Username)
+	}
+	if !reviewer.Fallback {
+		t.Error("Expected reviewer to be marked as fallback")
+	}
+
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 Mattermost note, got %d", len(messages))
+	}
+	if !strings.Contains(messages[0].Text, "@lead") || !strings.Contains(messages[0].Text, "team-frontend") {
+		t.Errorf("Unexpected Mattermost note: %q", messages[0].Text)
+	}
+}
+
+func TestSelectTeamMemberWithFallback_NoFallbackConfigured(t *testing.T) {
+	cfg := &config.Config{
+		Teams:        []config.TeamConfig{{Name: "team-frontend"}},
+		Availability: config.AvailabilityConfig{CacheTTL: 300},
+	}
+
+	service, db := setupFallbackTestService(t, cfg, nil)
+	createFallbackTestUser(t, db, 1, "alice", "team-frontend", true)
+
+	req := &SelectionRequest{ProjectID: 100, MRIID: 1}
+	reviewer, err := service.selectTeamMemberWithFallback(context.Background(), req, "team-frontend", "", "", 0, nil, nil)
+	if err == nil {
+		t.Fatalf("Expected error when no member or fallback is available, got reviewer %+v", reviewer)
+	}
+}
+
+func TestSelectTeamMemberWithFallback_NeverExcludedOrAuthor(t *testing.T) {
+	tests := []struct {
+		name     string
+		excluded config.ExcludedUsersConfig
+		authorID int
+	}{
+		{name: "excluded fallback", excluded: config.ExcludedUsersConfig{Usernames: []string{"lead"}}},
+		{name: "fallback is the MR author", authorID: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Teams:         []config.TeamConfig{{Name: "team-frontend", FallbackReviewer: "lead"}},
+				Availability:  config.AvailabilityConfig{CacheTTL: 300},
+				ExcludedUsers: tt.excluded,
+			}
+
+			service, db := setupFallbackTestService(t, cfg, nil)
+			createFallbackTestUser(t, db, 1, "alice", "team-frontend", true)
+			createFallbackTestUser(t, db, 3, "lead", "team-leads", false)
+
+			req := &SelectionRequest{ProjectID: 100, MRIID: 1}
+			reviewer, err := service.selectTeamMemberWithFallback(context.Background(), req, "team-frontend", "", "", tt.authorID, nil, nil)
+			if err == nil {
+				t.Fatalf("Expected no reviewer, got %q", reviewer.User.Username)
+			}
+		})
+	}
+}
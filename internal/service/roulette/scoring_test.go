@@ -2,6 +2,9 @@ package roulette
 
 import (
 	"testing"
+	"time"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
 )
 
 // Test scoring algorithm logic with known values
@@ -141,3 +144,33 @@ func TestReviewerSelectionEdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestRecencyPenalty_DecaysWithAge(t *testing.T) {
+	now := time.Now()
+	window := 48 * time.Hour
+	assignedAgo := func(d time.Duration) []models.ReviewerAssignment {
+		return []models.ReviewerAssignment{{AssignedAt: now.Add(-d)}}
+	}
+
+	veryRecent := recencyPenalty(10, window, assignedAgo(time.Hour), now)
+	older := recencyPenalty(10, window, assignedAgo(40*time.Hour), now)
+
+	if veryRecent <= older {
+		t.Errorf("Expected an assignment from 1h ago to penalize more than one from 40h ago, got %.2f <= %.2f", veryRecent, older)
+	}
+	if got := recencyPenalty(10, window, assignedAgo(24*time.Hour), now); got != 5 {
+		t.Errorf("Expected half penalty halfway through the window, got %.2f", got)
+	}
+	if got := recencyPenalty(10, window, assignedAgo(49*time.Hour), now); got != 0 {
+		t.Errorf("Expected no penalty outside the window, got %.2f", got)
+	}
+	if got := recencyPenalty(10, window, nil, now); got != 0 {
+		t.Errorf("Expected no penalty without assignments, got %.2f", got)
+	}
+
+	// The latest assignment determines the penalty
+	mixed := append(assignedAgo(40*time.Hour), assignedAgo(time.Hour)...)
+	if got := recencyPenalty(10, window, mixed, now); got != veryRecent {
+		t.Errorf("Expected penalty of the latest assignment %.2f, got %.2f", veryRecent, got)
+	}
+}
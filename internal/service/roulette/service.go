@@ -6,11 +6,13 @@ import (
 	"math/rand"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/cache"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/gitlab"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/mattermost"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
 	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
@@ -18,13 +20,18 @@ import (
 
 // Service handles reviewer selection logic.
 type Service struct {
-	config       *config.Config
-	gitlabClient *gitlab.Client
-	userRepo     *repository.UserRepository
-	oooRepo      *repository.OOORepository
-	reviewRepo   *repository.ReviewRepository
-	cache        *cache.Cache
-	log          *logger.Logger
+	config           *config.Config
+	gitlabClient     *gitlab.Client
+	userRepo         *repository.UserRepository
+	oooRepo          *repository.OOORepository
+	reviewRepo       *repository.ReviewRepository
+	cache            *cache.Cache
+	mattermostClient *mattermost.Client // optional, nil disables escalation notes
+	log              *logger.Logger
+
+	// Teams replaced on config reload; nil uses config.Teams
+	teamsMu sync.RWMutex
+	teams   []config.TeamConfig
 }
 
 // NewService creates a new roulette service.
@@ -35,19 +42,44 @@ func NewService(
 	oooRepo *repository.OOORepository,
 	reviewRepo *repository.ReviewRepository,
 	cacheClient *cache.Cache,
+	mattermostClient *mattermost.Client,
 	log *logger.Logger,
 ) *Service {
 	return &Service{
-		config:       cfg,
-		gitlabClient: gitlabClient,
-		userRepo:     userRepo,
-		oooRepo:      oooRepo,
-		reviewRepo:   reviewRepo,
-		cache:        cacheClient,
-		log:          log,
+		config:           cfg,
+		gitlabClient:     gitlabClient,
+		userRepo:         userRepo,
+		oooRepo:          oooRepo,
+		reviewRepo:       reviewRepo,
+		cache:            cacheClient,
+		mattermostClient: mattermostClient,
+		log:              log,
 	}
 }
 
+// SetTeams replaces the team configuration, e.g. after the config file was reloaded.
+func (s *Service) SetTeams(teams []config.TeamConfig) {
+	s.teamsMu.Lock()
+	defer s.teamsMu.Unlock()
+	s.teams = teams
+}
+
+// teamByName returns the current configuration of a team, or nil if it is unknown.
+func (s *Service) teamByName(name string) *config.TeamConfig {
+	s.teamsMu.RLock()
+	defer s.teamsMu.RUnlock()
+	teams := s.teams
+	if teams == nil {
+		teams = s.config.Teams
+	}
+	for i := range teams {
+		if teams[i].Name == name {
+			return &teams[i]
+		}
+	}
+	return nil
+}
+
 // SelectionRequest represents a reviewer selection request.
 type SelectionRequest struct {
 	ProjectID int
@@ -79,6 +111,7 @@ type Reviewer struct {
 	User          *models.User
 	ActiveReviews int
 	Score         float64
+	Fallback      bool // Selected as the team's fallback reviewer
 }
 
 // SelectReviewers performs the reviewer selection algorithm.
@@ -132,12 +165,19 @@ func (s *Service) SelectReviewers(ctx context.Context, req *SelectionRequest) (*
 
 	// 4. Select team member
 	if team != "" {
-		teamMember, err := s.selectTeamMember(ctx, req, team, role, result.Codeowner, modifiedFiles)
+		authorID := 0
+		if mr.Author != nil {
+			authorID = mr.Author.ID
+		}
+		teamMember, err := s.selectTeamMemberWithFallback(ctx, req, team, role, mr.WebURL, authorID, result.Codeowner, modifiedFiles)
 		if err != nil {
 			s.log.Warn().Err(err).Msg("Failed to select team member")
 			result.Warnings = append(result.Warnings, "⚠️ Could not select a team member. All team members may be unavailable.")
 		} else {
 			result.TeamMember = teamMember
+			if teamMember.Fallback {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("⚠️ No team member available. Escalated to fallback reviewer @%s.", teamMember.User.Username))
+			}
 		}
 	}
 
@@ -274,6 +314,66 @@ func (s *Service) selectTeamMember(ctx context.Context, req *SelectionRequest, t
 	return s.selectBestReviewer(ctx, candidatePtrs, req.Options, modifiedFiles)
 }
 
+// selectTeamMemberWithFallback selects a team member, escalating to the team's
+// configured fallback reviewer when no regular member is available.
+// authorID is the MR author's GitLab ID, who is never escalated to.
+func (s *Service) selectTeamMemberWithFallback(ctx context.Context, req *SelectionRequest, team, role, mrURL string, authorID int, exclude *Reviewer, modifiedFiles []string) (*Reviewer, error) {
+	reviewer, err := s.selectTeamMember(ctx, req, team, role, exclude, modifiedFiles)
+	if err == nil {
+		return reviewer, nil
+	}
+
+	fallback, fallbackErr := s.selectFallbackReviewer(ctx, team, mrURL, authorID, exclude)
+	if fallbackErr != nil {
+		s.log.Debug().Err(fallbackErr).Str("team", team).Msg("No fallback reviewer used")
+		return nil, err
+	}
+
+	return fallback, nil
+}
+
+// selectFallbackReviewer returns the team's fallback reviewer and posts a Mattermost note.
+// Availability is not checked: the fallback is the escalation path of last resort.
+// Excluded users and the MR author are still never returned.
+func (s *Service) selectFallbackReviewer(ctx context.Context, team, mrURL string, authorID int, exclude *Reviewer) (*Reviewer, error) {
+	teamCfg := s.teamByName(team)
+	if teamCfg == nil || teamCfg.FallbackReviewer == "" {
+		return nil, fmt.Errorf("no fallback reviewer configured for team %s", team)
+	}
+
+	user, err := s.userRepo.GetByUsername(teamCfg.FallbackReviewer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fallback reviewer %s: %w", teamCfg.FallbackReviewer, err)
+	}
+
+	if exclude != nil && exclude.User.ID == user.ID {
+		return nil, fmt.Errorf("fallback reviewer %s is already selected", user.Username)
+	}
+	if s.config.ExcludedUsers.ExcludesUser(user) {
+		return nil, fmt.Errorf("fallback reviewer %s is an excluded user", user.Username)
+	}
+	if authorID != 0 && user.GitLabID == authorID {
+		return nil, fmt.Errorf("fallback reviewer %s is the MR author", user.Username)
+	}
+
+	s.log.Info().
+		Str("team", team).
+		Str("username", user.Username).
+		Msg("No team member available, escalating to fallback reviewer")
+
+	if s.mattermostClient != nil {
+		if err := s.mattermostClient.SendFallbackReviewerNotice(team, user.Username, mrURL); err != nil {
+			s.log.Warn().Err(err).Str("team", team).Msg("Failed to send fallback reviewer notice")
+		}
+	}
+
+	return &Reviewer{
+		User:          user,
+		ActiveReviews: s.getActiveReviewsCount(ctx, user.ID),
+		Fallback:      true,
+	}, nil
+}
+
 // selectExternal selects an external reviewer (from other teams).
 func (s *Service) selectExternal(ctx context.Context, req *SelectionRequest, currentTeam string, exclude1, exclude2 *Reviewer, modifiedFiles []string) (*Reviewer, error) {
 	// Get all users
@@ -314,6 +414,11 @@ func (s *Service) selectBestReviewer(ctx context.Context, candidates []*models.U
 			continue
 		}
 
+		// Bots and service accounts are never selected
+		if s.config.ExcludedUsers.ExcludesUser(user) {
+			continue
+		}
+
 		// Check availability
 		isAvailable, err := s.isUserAvailable(ctx, user)
 		if err != nil {
@@ -363,13 +468,12 @@ func (s *Service) calculateScore(ctx context.Context, user *models.User, options
 	activeReviews := s.getActiveReviewsCount(ctx, user.ID)
 	score -= float64(activeReviews) * float64(s.config.Roulette.Weights.CurrentLoad)
 
-	// Penalty for recent reviews (unless force option)
-	if !options.Force {
-		since := time.Now().Add(-24 * time.Hour)
-		recentAssignments, _ := s.reviewRepo.GetRecentAssignmentsByUserID(user.ID, since)
-		if len(recentAssignments) > 0 {
-			score -= float64(s.config.Roulette.Weights.RecentReview)
-		}
+	// Penalty for recent reviews, decaying over the window (unless force option)
+	window := time.Duration(s.config.Roulette.Weights.RecentReviewWindowHours) * time.Hour
+	if !options.Force && window > 0 {
+		now := time.Now()
+		recentAssignments, _ := s.reviewRepo.GetRecentAssignmentsByUserID(user.ID, now.Add(-window))
+		score -= recencyPenalty(s.config.Roulette.Weights.RecentReview, window, recentAssignments, now)
 	}
 
 	// Expertise bonus based on file types (Phase 2)
@@ -390,6 +494,31 @@ func (s *Service) calculateScore(ctx context.Context, user *models.User, options
 	return score
 }
 
+// recencyPenalty returns the recent review penalty for the latest assignment:
+// the full weight for one assigned now, decaying linearly to 0 at the end of the window.
+func recencyPenalty(weight int, window time.Duration, assignments []models.ReviewerAssignment, now time.Time) float64 {
+	if window <= 0 || len(assignments) == 0 {
+		return 0
+	}
+
+	latest := assignments[0].AssignedAt
+	for _, a := range assignments[1:] {
+		if a.AssignedAt.After(latest) {
+			latest = a.AssignedAt
+		}
+	}
+
+	age := now.Sub(latest)
+	if age < 0 {
+		age = 0
+	}
+	if age >= window {
+		return 0
+	}
+
+	return float64(weight) * (1 - float64(age)/float64(window))
+}
+
 // hasExpertise checks if user has expertise for the modified files.
 func (s *Service) hasExpertise(role string, modifiedFiles []string) bool {
 	if len(modifiedFiles) == 0 {
@@ -0,0 +1,38 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// CacheWarmer pre-computes popular API responses into the response cache.
+type CacheWarmer interface {
+	Warm(ctx context.Context) (int, error)
+}
+
+// SetCacheWarmer enables scheduled cache warming on the given cron schedule.
+// An empty schedule disables warming.
+func (s *Service) SetCacheWarmer(warmer CacheWarmer, schedule string) {
+	s.cacheWarmer = warmer
+	s.cacheWarmSchedule = schedule
+}
+
+// runCacheWarming executes the scheduled cache warming job.
+func (s *Service) runCacheWarming(ctx context.Context) {
+	start := time.Now()
+
+	warmed, err := s.cacheWarmer.Warm(ctx)
+	if err != nil {
+		s.log.Error().
+			Err(err).
+			Int("warmed", warmed).
+			Dur("duration", time.Since(start)).
+			Msg("Cache warming job failed")
+		return
+	}
+
+	s.log.Info().
+		Int("warmed", warmed).
+		Dur("duration", time.Since(start)).
+		Msg("Cache warming job completed successfully")
+}
@@ -0,0 +1,160 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/i18n"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/mattermost"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+)
+
+// UserLister lists the users checked for inactivity.
+type UserLister interface {
+	List(team, role string) ([]models.User, error)
+}
+
+// ActivityMetricsRepository reads the metrics that show recent review activity.
+type ActivityMetricsRepository interface {
+	GetByDateRange(startDate, endDate time.Time, filters map[string]interface{}) ([]models.ReviewMetrics, error)
+}
+
+// MessageClient posts free-form messages to Mattermost.
+type MessageClient interface {
+	SendMessage(msg *mattermost.Message) error
+}
+
+// SetInactivityCheck provides what the inactivity check needs to find reviewers without
+// recent completed reviews and report them. The job runs when scheduler.inactivity_check
+// is enabled.
+func (s *Service) SetInactivityCheck(userRepo UserLister, metricsRepo ActivityMetricsRepository, client MessageClient) {
+	s.userLister = userRepo
+	s.activityMetrics = metricsRepo
+	s.messageClient = client
+}
+
+// RunInactivityCheckNow reports inactive reviewers immediately and returns how many were found.
+func (s *Service) RunInactivityCheckNow(ctx context.Context) (int, error) {
+	return s.reportInactiveReviewers(ctx)
+}
+
+// runInactivityCheck executes the scheduled inactivity check.
+func (s *Service) runInactivityCheck(ctx context.Context) {
+	// Errors are logged by the job itself
+	_, _ = s.reportInactiveReviewers(ctx)
+}
+
+// reportInactiveReviewers posts the list of inactive reviewers to Mattermost.
+// Nothing is posted when every reviewer has been active.
+func (s *Service) reportInactiveReviewers(ctx context.Context) (int, error) {
+	if s.userLister == nil || s.activityMetrics == nil || s.messageClient == nil {
+		return 0, nil
+	}
+
+	days := s.config.Scheduler.InactivityCheck.Days
+	inactive, err := s.findInactiveReviewers(ctx, days)
+	if err != nil {
+		s.log.Error().Err(err).Msg("Inactivity check failed")
+		return 0, err
+	}
+
+	if len(inactive) > 0 {
+		tr := s.translator
+		if tr == nil {
+			tr = i18n.MustNew("en")
+		}
+		err := s.messageClient.SendMessage(&mattermost.Message{
+			Channel: s.config.Scheduler.InactivityCheck.Channel,
+			Text:    buildInactivityText(tr, inactive, days),
+		})
+		if err != nil {
+			s.log.Error().Err(err).Msg("Failed to send inactivity summary")
+			return len(inactive), fmt.Errorf("failed to send inactivity summary: %w", err)
+		}
+	}
+
+	s.log.Info().
+		Int("days", days).
+		Int("inactive", len(inactive)).
+		Msg("Inactivity check completed")
+
+	return len(inactive), nil
+}
+
+// findInactiveReviewers returns the users without a completed review in the last days,
+// excluding bots and service accounts, sorted by team then username.
+func (s *Service) findInactiveReviewers(ctx context.Context, days int) ([]models.User, error) {
+	if days <= 0 {
+		return nil, fmt.Errorf("invalid inactivity window: %d days", days)
+	}
+
+	users, err := s.userLister.List("", "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	now := s.currentTime()
+	metrics, err := s.activityMetrics.GetByDateRange(now.AddDate(0, 0, -days), now, map[string]interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metrics: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	active := make(map[uint]bool)
+	for _, m := range metrics {
+		if m.UserID != nil && m.CompletedReviews > 0 {
+			active[*m.UserID] = true
+		}
+	}
+
+	var inactive []models.User
+	for _, user := range users {
+		if active[user.ID] || s.config.ExcludedUsers.ExcludesUser(&user) {
+			continue
+		}
+		inactive = append(inactive, user)
+	}
+
+	sort.Slice(inactive, func(i, j int) bool {
+		if inactive[i].Team != inactive[j].Team {
+			return inactive[i].Team < inactive[j].Team
+		}
+		return inactive[i].Username < inactive[j].Username
+	})
+	return inactive, nil
+}
+
+// buildInactivityText formats the inactive reviewers grouped by team.
+// Users must be sorted by team.
+func buildInactivityText(tr *i18n.Translator, users []models.User, days int) string {
+	var sb strings.Builder
+	sb.WriteString(tr.Get("inactivity.title"))
+	sb.WriteString("\n\n")
+	sb.WriteString(tr.Get("inactivity.summary", map[string]interface{}{
+		"Count": len(users),
+		"Days":  days,
+	}))
+	sb.WriteString("\n")
+
+	for i, user := range users {
+		if i == 0 || user.Team != users[i-1].Team {
+			team := user.Team
+			if team == "" {
+				team = tr.Get("inactivity.no_team")
+			}
+			sb.WriteString(fmt.Sprintf("\n**%s**\n", team))
+		}
+		name := "@" + user.Username
+		if user.DisplayName != "" {
+			name = fmt.Sprintf("%s (@%s)", user.DisplayName, user.Username)
+		}
+		sb.WriteString(fmt.Sprintf("- %s\n", name))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,145 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/i18n"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/mattermost"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+)
+
+type mockUserLister struct {
+	users []models.User
+}
+
+func (m *mockUserLister) List(_, _ string) ([]models.User, error) {
+	return m.users, nil
+}
+
+type mockActivityMetrics struct {
+	metrics    []models.ReviewMetrics
+	start, end time.Time
+}
+
+func (m *mockActivityMetrics) GetByDateRange(startDate, endDate time.Time, _ map[string]interface{}) ([]models.ReviewMetrics, error) {
+	m.start, m.end = startDate, endDate
+	return m.metrics, nil
+}
+
+type mockMessageClient struct {
+	messages []*mattermost.Message
+}
+
+func (m *mockMessageClient) SendMessage(msg *mattermost.Message) error {
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+func TestRunInactivityCheckNow(t *testing.T) {
+	now := time.Date(2025, 11, 24, 9, 0, 0, 0, time.UTC)
+	alice, bob, carol, dave, bot := uint(1), uint(2), uint(3), uint(4), uint(5)
+	users := &mockUserLister{users: []models.User{
+		{ID: alice, Username: "alice", Team: "team-frontend"},
+		{ID: bob, Username: "bob", Team: "team-frontend", DisplayName: "Bob Stone"},
+		{ID: carol, Username: "carol", Team: "team-backend"},
+		{ID: dave, Username: "dave", Team: "team-backend"},
+		{ID: bot, Username: "renovate-bot"},
+	}}
+	metrics := &mockActivityMetrics{metrics: []models.ReviewMetrics{
+		{UserID: &alice, CompletedReviews: 3},
+		{UserID: &carol, CompletedReviews: 0, TotalReviews: 2}, // Assigned but nothing completed
+		{UserID: &dave, CompletedReviews: 1},
+		{Team: "team-backend", CompletedReviews: 1}, // Team-level rows name no reviewer
+	}}
+	client := &mockMessageClient{}
+
+	cfg := &config.Config{
+		Scheduler: config.SchedulerConfig{
+			InactivityCheck: config.InactivityCheckConfig{Enabled: true, Days: 30, Channel: "managers"},
+		},
+		ExcludedUsers: config.ExcludedUsersConfig{Usernames: []string{"renovate-bot"}},
+	}
+	s := NewServiceWithInterfaces(cfg, &mockReviewRepository{}, nil, nil, &mockNotificationClient{}, logger.New("error", "text", "stdout"))
+	s.now = func() time.Time { return now }
+	s.SetInactivityCheck(users, metrics, client)
+
+	inactive, err := s.RunInactivityCheckNow(context.Background())
+	if err != nil {
+		t.Fatalf("RunInactivityCheckNow() error = %v", err)
+	}
+	if inactive != 2 {
+		t.Errorf("Expected bob and carol to be inactive, got %d", inactive)
+	}
+	if !metrics.start.Equal(now.AddDate(0, 0, -30)) || !metrics.end.Equal(now) {
+		t.Errorf("Expected the last 30 days, got %v - %v", metrics.start, metrics.end)
+	}
+
+	if len(client.messages) != 1 {
+		t.Fatalf("Expected one summary, got %d", len(client.messages))
+	}
+	msg := client.messages[0]
+	if msg.Channel != "managers" {
+		t.Errorf("Channel = %q, want managers", msg.Channel)
+	}
+	for _, want := range []string{"@carol", "Bob Stone (@bob)"} {
+		if !strings.Contains(msg.Text, want) {
+			t.Errorf("Summary = %q, want it to mention %s", msg.Text, want)
+		}
+	}
+	for _, active := range []string{"@alice", "@dave", "renovate-bot"} {
+		if strings.Contains(msg.Text, active) {
+			t.Errorf("Summary = %q, want no mention of %s", msg.Text, active)
+		}
+	}
+}
+
+func TestRunInactivityCheckNow_EveryoneActive(t *testing.T) {
+	alice := uint(1)
+	client := &mockMessageClient{}
+	cfg := &config.Config{Scheduler: config.SchedulerConfig{InactivityCheck: config.InactivityCheckConfig{Days: 30}}}
+	s := NewServiceWithInterfaces(cfg, &mockReviewRepository{}, nil, nil, &mockNotificationClient{}, logger.New("error", "text", "stdout"))
+	s.SetInactivityCheck(
+		&mockUserLister{users: []models.User{{ID: alice, Username: "alice"}}},
+		&mockActivityMetrics{metrics: []models.ReviewMetrics{{UserID: &alice, CompletedReviews: 1}}},
+		client,
+	)
+
+	inactive, err := s.RunInactivityCheckNow(context.Background())
+	if err != nil {
+		t.Fatalf("RunInactivityCheckNow() error = %v", err)
+	}
+	if inactive != 0 || len(client.messages) != 0 {
+		t.Errorf("Expected no summary, got %d inactive and %d messages", inactive, len(client.messages))
+	}
+}
+
+func TestBuildInactivityText(t *testing.T) {
+	users := []models.User{
+		{Username: "carol", Team: "team-backend"},
+		{Username: "bob", Team: "team-frontend", DisplayName: "Bob Stone"},
+		{Username: "erin", Team: "team-frontend"},
+		{Username: "frank"},
+	}
+	sorted := []models.User{users[3], users[0], users[1], users[2]} // No team sorts first
+
+	text := buildInactivityText(i18n.MustNew("en"), sorted, 14)
+
+	want := "### 💤 Inactive Reviewers\n\n" +
+		"**4** reviewers have not completed a review in the last 14 days:\n" +
+		"\n**No team**\n- @frank\n" +
+		"\n**team-backend**\n- @carol\n" +
+		"\n**team-frontend**\n- Bob Stone (@bob)\n- @erin\n"
+	if text != want {
+		t.Errorf("buildInactivityText() =\n%s\nwant\n%s", text, want)
+	}
+
+	fr := buildInactivityText(i18n.MustNew("fr"), users[:1], 30)
+	if !strings.Contains(fr, "Reviewers Inactifs") || !strings.Contains(fr, "30 derniers jours") {
+		t.Errorf("Expected a French summary, got %q", fr)
+	}
+}
@@ -1,6 +1,7 @@
 package scheduler
 
 import (
+	"sort"
 	"time"
 
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/mattermost"
@@ -41,3 +42,36 @@ func buildPendingMRs(reviews []models.MRReview) []mattermost.PendingMR {
 
 	return pendingMRs
 }
+
+// unassignedTeam labels pending MRs that have no team.
+const unassignedTeam = "unassigned"
+
+// teamPendingMRs holds the pending MRs of a single team.
+type teamPendingMRs struct {
+	Team string
+	MRs  []mattermost.PendingMR
+}
+
+// groupPendingMRsByTeam groups pending MRs by team after filtering out MRs younger than minAge.
+// Teams left without MRs are omitted. Groups are sorted by team name.
+func groupPendingMRsByTeam(pendingMRs []mattermost.PendingMR, minAge time.Duration) []teamPendingMRs {
+	byTeam := make(map[string][]mattermost.PendingMR)
+	for _, mr := range filterRecentMRs(pendingMRs, minAge) {
+		team := mr.Team
+		if team == "" {
+			team = unassignedTeam
+		}
+		byTeam[team] = append(byTeam[team], mr)
+	}
+
+	groups := make([]teamPendingMRs, 0, len(byTeam))
+	for team, mrs := range byTeam {
+		groups = append(groups, teamPendingMRs{Team: team, MRs: mrs})
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Team < groups[j].Team
+	})
+
+	return groups
+}
@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// retentionSchedule runs the retention job daily at 03:00 in the scheduler timezone.
+const retentionSchedule = "0 3 * * *"
+
+// RetentionReviewRepository deletes MR reviews past the retention period.
+type RetentionReviewRepository interface {
+	DeleteReviewsBefore(cutoff time.Time) (int64, error)
+}
+
+// RetentionMetricsRepository deletes metrics past the retention period.
+type RetentionMetricsRepository interface {
+	DeleteOldMetrics(retentionDays int) error
+}
+
+// SetRetention provides what the retention job needs to delete MR reviews, their
+// assignments and metrics older than metrics.retention_days. The job runs daily
+// when retention_days is positive.
+func (s *Service) SetRetention(reviewRepo RetentionReviewRepository, metricsRepo RetentionMetricsRepository) {
+	s.retentionReviews = reviewRepo
+	s.retentionMetrics = metricsRepo
+}
+
+// runRetention executes the scheduled retention job.
+func (s *Service) runRetention(ctx context.Context) {
+	// Errors are logged by the job itself
+	_, _ = s.deleteExpiredData(ctx)
+}
+
+// deleteExpiredData deletes the MR reviews and metrics older than the retention period
+// and returns how many reviews were removed.
+func (s *Service) deleteExpiredData(ctx context.Context) (int64, error) {
+	days := s.config.Metrics.RetentionDays
+	if days <= 0 || s.retentionReviews == nil {
+		return 0, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	cutoff := s.currentTime().AddDate(0, 0, -days)
+
+	deleted, err := s.retentionReviews.DeleteReviewsBefore(cutoff)
+	if err != nil {
+		s.log.Error().Err(err).Int("retention_days", days).Msg("Retention job failed to delete MR reviews")
+		return 0, fmt.Errorf("failed to delete MR reviews: %w", err)
+	}
+
+	if s.retentionMetrics != nil {
+		if err := s.retentionMetrics.DeleteOldMetrics(days); err != nil {
+			s.log.Error().Err(err).Int("retention_days", days).Msg("Retention job failed to delete metrics")
+			return deleted, fmt.Errorf("failed to delete metrics: %w", err)
+		}
+	}
+
+	s.log.Info().
+		Int("retention_days", days).
+		Time("cutoff", cutoff).
+		Int64("reviews_deleted", deleted).
+		Dur("duration", time.Since(start)).
+		Msg("Retention job completed successfully")
+
+	return deleted, nil
+}
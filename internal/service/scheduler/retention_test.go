@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+)
+
+type mockRetentionReviews struct {
+	cutoff time.Time
+	calls  int
+}
+
+func (m *mockRetentionReviews) DeleteReviewsBefore(cutoff time.Time) (int64, error) {
+	m.cutoff = cutoff
+	m.calls++
+	return 4, nil
+}
+
+type mockRetentionMetrics struct {
+	retentionDays int
+}
+
+func (m *mockRetentionMetrics) DeleteOldMetrics(retentionDays int) error {
+	m.retentionDays = retentionDays
+	return nil
+}
+
+func TestDeleteExpiredData(t *testing.T) {
+	now := time.Date(2025, 11, 24, 3, 0, 0, 0, time.UTC)
+	reviews := &mockRetentionReviews{}
+	metrics := &mockRetentionMetrics{}
+
+	cfg := &config.Config{Metrics: config.MetricsConfig{RetentionDays: 365}}
+	s := NewServiceWithInterfaces(cfg, &mockReviewRepository{}, nil, nil, &mockNotificationClient{}, logger.New("error", "text", "stdout"))
+	s.now = func() time.Time { return now }
+	s.SetRetention(reviews, metrics)
+
+	deleted, err := s.deleteExpiredData(context.Background())
+	if err != nil {
+		t.Fatalf("deleteExpiredData() error = %v", err)
+	}
+	if deleted != 4 {
+		t.Errorf("deleted = %d, want 4", deleted)
+	}
+	if want := now.AddDate(0, 0, -365); !reviews.cutoff.Equal(want) {
+		t.Errorf("cutoff = %v, want %v", reviews.cutoff, want)
+	}
+	if metrics.retentionDays != 365 {
+		t.Errorf("metrics retention = %d days, want 365", metrics.retentionDays)
+	}
+
+	// Forever retention deletes nothing
+	cfg.Metrics.RetentionDays = 0
+	if _, err := s.deleteExpiredData(context.Background()); err != nil {
+		t.Fatalf("deleteExpiredData() error = %v", err)
+	}
+	if reviews.calls != 1 {
+		t.Errorf("Expected no deletion with retention_days 0, got %d calls", reviews.calls)
+	}
+}
@@ -3,29 +3,90 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strconv"
-	"strings"
+	"sync"
 	"time"
 
 	"github.com/robfig/cron/v3"
 
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/i18n"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/mattermost"
 	prommetrics "github.com/aimd54/gitlab-reviewer-roulette/internal/metrics"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/badges"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/service/leaderboard"
 	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
 )
 
+// ReviewRepository interface for pending review queries.
+type ReviewRepository interface {
+	ListPendingMRReviews(ctx context.Context) ([]models.MRReview, error)
+	SetReminderCommentID(ctx context.Context, reviewID uint, noteID int) error
+}
+
+// contextReviewRepository runs each ReviewRepository query under the caller's context.
+type contextReviewRepository struct {
+	repo *repository.ReviewRepository
+}
+
+func (r contextReviewRepository) ListPendingMRReviews(ctx context.Context) ([]models.MRReview, error) {
+	return r.repo.WithContext(ctx).ListPendingMRReviews()
+}
+
+func (r contextReviewRepository) SetReminderCommentID(ctx context.Context, reviewID uint, noteID int) error {
+	return r.repo.WithContext(ctx).SetReminderCommentID(reviewID, noteID)
+}
+
+// BadgeService interface for badge evaluation.
+type BadgeService interface {
+	EvaluateAllBadges(ctx context.Context) (int, map[string]int, error)
+}
+
+// PersonalBestsService interface for personal best tracking.
+type PersonalBestsService interface {
+	UpdatePersonalBests(ctx context.Context, period string) (int, error)
+}
+
+// NotificationClient interface for sending daily reminders.
+type NotificationClient interface {
+	SendDailyReviewReminder(channel string, pendingMRs []mattermost.PendingMR, overdueAfter time.Duration) error
+}
+
 // Service handles daily notification scheduling.
 type Service struct {
-	config           *config.Config
-	reviewRepo       *repository.ReviewRepository
-	badgeService     *badges.Service
-	mattermostClient *mattermost.Client
-	log              *logger.Logger
-	cron             *cron.Cron
+	config             *config.Config
+	reviewRepo         ReviewRepository
+	badgeService       BadgeService
+	leaderboardService PersonalBestsService
+	mattermostClient   NotificationClient
+	gitlabClient       CommentClient
+	translator         *i18n.Translator
+	cacheWarmer        CacheWarmer
+	cacheWarmSchedule  string
+	userLister         UserLister
+	activityMetrics    ActivityMetricsRepository
+	messageClient      MessageClient
+	retentionReviews   RetentionReviewRepository
+	retentionMetrics   RetentionMetricsRepository
+	threadStore        ThreadStore
+	log                *logger.Logger
+	cron               *cron.Cron
+	now                func() time.Time
+
+	// Context passed to scheduled jobs, cancelled on Stop so long-running jobs return early
+	jobCtx    context.Context
+	cancelJob context.CancelFunc
+
+	// Root posts of today's reminder threads, when threading is enabled
+	threadMu sync.Mutex
+	threads  *reminderThreads
+
+	// Teams replaced on config reload; nil uses config.Teams
+	teamsMu sync.RWMutex
+	teams   []config.TeamConfig
 }
 
 // NewService creates a new scheduler service.
@@ -33,15 +94,38 @@ func NewService(
 	cfg *config.Config,
 	reviewRepo *repository.ReviewRepository,
 	badgeService *badges.Service,
+	leaderboardService *leaderboard.Service,
 	mattermostClient *mattermost.Client,
 	log *logger.Logger,
 ) *Service {
 	return &Service{
-		config:           cfg,
-		reviewRepo:       reviewRepo,
-		badgeService:     badgeService,
-		mattermostClient: mattermostClient,
-		log:              log,
+		config:             cfg,
+		reviewRepo:         contextReviewRepository{repo: reviewRepo},
+		badgeService:       badgeService,
+		leaderboardService: leaderboardService,
+		mattermostClient:   mattermostClient,
+		log:                log,
+		now:                time.Now,
+	}
+}
+
+// NewServiceWithInterfaces creates a new scheduler service with interface dependencies (useful for testing).
+func NewServiceWithInterfaces(
+	cfg *config.Config,
+	reviewRepo ReviewRepository,
+	badgeService BadgeService,
+	leaderboardService PersonalBestsService,
+	mattermostClient NotificationClient,
+	log *logger.Logger,
+) *Service {
+	return &Service{
+		config:             cfg,
+		reviewRepo:         reviewRepo,
+		badgeService:       badgeService,
+		leaderboardService: leaderboardService,
+		mattermostClient:   mattermostClient,
+		log:                log,
+		now:                time.Now,
 	}
 }
 
@@ -59,8 +143,16 @@ func (s *Service) Start() error {
 		return fmt.Errorf("invalid timezone %q: %w", s.config.Scheduler.Timezone, err)
 	}
 
+	// Validate holidays up front; they are checked when the job runs
+	for _, holiday := range s.config.Scheduler.Holidays {
+		if _, err := time.Parse(holidayLayout, holiday); err != nil {
+			return fmt.Errorf("invalid holiday %q (expected YYYY-MM-DD): %w", holiday, err)
+		}
+	}
+
 	// Create cron scheduler with timezone
 	s.cron = cron.New(cron.WithLocation(location))
+	s.jobCtx, s.cancelJob = context.WithCancel(context.Background())
 
 	// Build cron expression
 	cronExpr, err := s.buildCronExpression()
@@ -70,16 +162,29 @@ func (s *Service) Start() error {
 
 	// Register daily notification job
 	_, err = s.cron.AddFunc(cronExpr, func() {
-		s.runDailyNotifications(context.Background())
+		s.runDailyNotifications(s.jobCtx)
 	})
 	if err != nil {
 		return fmt.Errorf("failed to register daily notification job: %w", err)
 	}
 
+	// Register stale MR comments alongside daily notifications if configured
+	if s.config.Scheduler.StaleMRCommentHours > 0 && s.gitlabClient != nil {
+		_, err = s.cron.AddFunc(cronExpr, func() {
+			s.runStaleMRComments(s.jobCtx)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to register stale MR comment job: %w", err)
+		}
+		s.log.Info().
+			Int("threshold_hours", s.config.Scheduler.StaleMRCommentHours).
+			Msg("Stale MR comment job registered")
+	}
+
 	// Register badge evaluation job if configured
 	if s.config.Scheduler.BadgeEvaluationTime != "" && s.badgeService != nil {
 		_, err = s.cron.AddFunc(s.config.Scheduler.BadgeEvaluationTime, func() {
-			s.runBadgeEvaluation(context.Background())
+			s.runBadgeEvaluation(s.jobCtx)
 		})
 		if err != nil {
 			return fmt.Errorf("failed to register badge evaluation job: %w", err)
@@ -89,6 +194,57 @@ func (s *Service) Start() error {
 			Msg("Badge evaluation job registered")
 	}
 
+	// Register personal best tracking alongside badge evaluation
+	if s.config.Scheduler.BadgeEvaluationTime != "" && s.leaderboardService != nil {
+		_, err = s.cron.AddFunc(s.config.Scheduler.BadgeEvaluationTime, func() {
+			s.runPersonalBestsUpdate(s.jobCtx)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to register personal bests job: %w", err)
+		}
+	}
+
+	// Register the inactivity check if enabled
+	if s.config.Scheduler.InactivityCheck.Enabled && s.userLister != nil {
+		_, err = s.cron.AddFunc(s.config.Scheduler.InactivityCheck.Schedule, func() {
+			s.runInactivityCheck(s.jobCtx)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to register inactivity check job: %w", err)
+		}
+		s.log.Info().
+			Str("schedule", s.config.Scheduler.InactivityCheck.Schedule).
+			Int("days", s.config.Scheduler.InactivityCheck.Days).
+			Msg("Inactivity check job registered")
+	}
+
+	// Register the retention job if a retention period is configured
+	if s.config.Metrics.RetentionDays > 0 && s.retentionReviews != nil {
+		_, err = s.cron.AddFunc(retentionSchedule, func() {
+			s.runRetention(s.jobCtx)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to register retention job: %w", err)
+		}
+		s.log.Info().
+			Str("schedule", retentionSchedule).
+			Int("retention_days", s.config.Metrics.RetentionDays).
+			Msg("Retention job registered")
+	}
+
+	// Register cache warming if configured, usually just after aggregation
+	if s.cacheWarmSchedule != "" && s.cacheWarmer != nil {
+		_, err = s.cron.AddFunc(s.cacheWarmSchedule, func() {
+			s.runCacheWarming(s.jobCtx)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to register cache warming job: %w", err)
+		}
+		s.log.Info().
+			Str("schedule", s.cacheWarmSchedule).
+			Msg("Cache warming job registered")
+	}
+
 	// Start the scheduler
 	s.cron.Start()
 
@@ -104,6 +260,8 @@ func (s *Service) Start() error {
 		Str("timezone", s.config.Scheduler.Timezone).
 		Str("time", s.config.Scheduler.Time).
 		Bool("skip_weekends", s.config.Scheduler.SkipWeekends).
+		Bool("skip_holidays", s.config.Scheduler.SkipHolidays).
+		Int("holidays", len(s.config.Scheduler.Holidays)).
 		Str("next_run", nextRun).
 		Msg("Scheduler started successfully")
 
@@ -111,8 +269,10 @@ func (s *Service) Start() error {
 }
 
 // Stop gracefully shuts down the scheduler.
+// Running jobs are cancelled and waited for before returning.
 func (s *Service) Stop() {
 	if s.cron != nil {
+		s.cancelJob()
 		ctx := s.cron.Stop()
 		<-ctx.Done()
 		s.log.Info().Msg("Scheduler stopped")
@@ -121,20 +281,9 @@ func (s *Service) Stop() {
 
 // buildCronExpression generates a cron expression from config.
 func (s *Service) buildCronExpression() (string, error) {
-	// Parse time string (format: "HH:MM")
-	parts := strings.Split(s.config.Scheduler.Time, ":")
-	if len(parts) != 2 {
-		return "", fmt.Errorf("invalid time format %q, expected HH:MM", s.config.Scheduler.Time)
-	}
-
-	hour, err := strconv.Atoi(parts[0])
-	if err != nil || hour < 0 || hour > 23 {
-		return "", fmt.Errorf("invalid hour %q", parts[0])
-	}
-
-	minute, err := strconv.Atoi(parts[1])
-	if err != nil || minute < 0 || minute > 59 {
-		return "", fmt.Errorf("invalid minute %q", parts[1])
+	hour, minute, err := s.config.Scheduler.ParseTime()
+	if err != nil {
+		return "", err
 	}
 
 	// Build cron expression
@@ -148,8 +297,27 @@ func (s *Service) buildCronExpression() (string, error) {
 	return fmt.Sprintf("%d %d * * *", minute, hour), nil
 }
 
-// runDailyNotifications executes the daily notification job.
-func (s *Service) runDailyNotifications(_ context.Context) {
+// RunDailyNotificationsNow runs the daily notification job immediately, bypassing
+// the schedule and holiday checks, and returns its error instead of only logging it.
+func (s *Service) RunDailyNotificationsNow(ctx context.Context) error {
+	return s.sendDailyNotifications(ctx)
+}
+
+// runDailyNotifications executes the scheduled daily notification job.
+func (s *Service) runDailyNotifications(ctx context.Context) {
+	// Cron can't express arbitrary dates, so holidays are checked at run time
+	if s.isHoliday(s.currentTime()) {
+		s.log.Info().Msg("Today is a configured holiday, skipping daily notification job")
+		prommetrics.RecordSchedulerJobRun("skipped_holiday")
+		return
+	}
+
+	// Errors are logged and recorded as metrics by the job itself
+	_ = s.sendDailyNotifications(ctx)
+}
+
+// sendDailyNotifications queries pending MRs and sends one reminder per team.
+func (s *Service) sendDailyNotifications(ctx context.Context) error {
 	start := time.Now()
 
 	// Track job duration and update last run timestamp on exit
@@ -163,7 +331,7 @@ func (s *Service) runDailyNotifications(_ context.Context) {
 
 	// Query pending MRs
 	queryStart := time.Now()
-	reviews, err := s.reviewRepo.ListPendingMRReviews()
+	reviews, err := s.reviewRepo.ListPendingMRReviews(ctx)
 	queryDuration := time.Since(queryStart)
 
 	if err != nil {
@@ -173,7 +341,7 @@ func (s *Service) runDailyNotifications(_ context.Context) {
 			Msg("Failed to list pending MR reviews")
 		prommetrics.RecordSchedulerJobRun("error")
 		prommetrics.RecordSchedulerNotificationFailed("query_error")
-		return
+		return fmt.Errorf("failed to list pending MR reviews: %w", err)
 	}
 
 	s.log.Info().
@@ -184,46 +352,136 @@ func (s *Service) runDailyNotifications(_ context.Context) {
 	// Build pending MRs for Mattermost
 	pendingMRs := buildPendingMRs(reviews)
 
-	// Filter out very recent MRs (< 4 hours old)
-	filtered := filterRecentMRs(pendingMRs, 4*time.Hour)
+	// Group by team, dropping MRs younger than the configured minimum age
+	minAge := time.Duration(s.config.Scheduler.MinMRAgeHours) * time.Hour
+	overdueAfter := time.Duration(s.config.Scheduler.OverdueMRAgeHours) * time.Hour
+	groups := groupPendingMRsByTeam(pendingMRs, minAge)
 
 	s.log.Info().
 		Int("total", len(pendingMRs)).
-		Int("filtered", len(filtered)).
-		Msg("Filtered recent MRs")
+		Int("teams", len(groups)).
+		Msg("Grouped pending MRs by team")
 
-	// Send notification
-	if len(filtered) == 0 {
+	if len(groups) == 0 {
 		s.log.Debug().Msg("No pending MRs to notify about")
 		prommetrics.RecordSchedulerJobRun("success")
-		return
+		return nil
 	}
 
-	// Send to Mattermost
-	sendStart := time.Now()
-	err = s.mattermostClient.SendDailyReviewReminder(filtered)
-	sendDuration := time.Since(sendStart)
+	// Send one reminder per team
+	var sendErrs []error
+	for _, group := range groups {
+		channel := s.teamChannel(group.Team)
 
-	if err != nil {
-		s.log.Error().
-			Err(err).
+		sendStart := time.Now()
+		err := s.sendReminder(group.Team, channel, group.MRs, overdueAfter)
+		sendDuration := time.Since(sendStart)
+
+		if err != nil {
+			s.log.Error().
+				Err(err).
+				Str("team", group.Team).
+				Dur("send_duration", sendDuration).
+				Msg("Failed to send daily review reminder")
+			prommetrics.RecordSchedulerNotificationFailed("mattermost_error")
+			sendErrs = append(sendErrs, fmt.Errorf("team %s: %w", group.Team, err))
+			continue
+		}
+
+		prommetrics.RecordSchedulerNotificationSent(group.Team)
+		prommetrics.SetSchedulerPendingMRs(group.Team, len(group.MRs))
+
+		s.log.Info().
+			Str("team", group.Team).
+			Str("channel", channel).
+			Int("mr_count", len(group.MRs)).
 			Dur("send_duration", sendDuration).
-			Msg("Failed to send daily review reminder")
+			Msg("Sent daily notification")
+	}
+
+	if len(sendErrs) > 0 {
 		prommetrics.RecordSchedulerJobRun("error")
-		prommetrics.RecordSchedulerNotificationFailed("mattermost_error")
-		return
+		return fmt.Errorf("failed to send %d of %d daily reminders: %w", len(sendErrs), len(groups), errors.Join(sendErrs...))
 	}
 
-	// Success - record metrics
 	prommetrics.RecordSchedulerJobRun("success")
-	prommetrics.RecordSchedulerNotificationSent("all") // Could be team-specific if needed
-	prommetrics.SetSchedulerPendingMRs("all", len(filtered))
 
 	s.log.Info().
-		Int("mr_count", len(filtered)).
-		Dur("send_duration", sendDuration).
+		Int("teams", len(groups)).
 		Dur("total_duration", time.Since(start)).
-		Msg("Successfully sent daily notification")
+		Msg("Successfully sent daily notifications")
+
+	return nil
+}
+
+// SetTeams replaces the team configuration, e.g. after the config file was reloaded.
+func (s *Service) SetTeams(teams []config.TeamConfig) {
+	s.teamsMu.Lock()
+	defer s.teamsMu.Unlock()
+	s.teams = teams
+}
+
+// teamByName returns the current configuration of a team, or nil if it is unknown.
+func (s *Service) teamByName(name string) *config.TeamConfig {
+	s.teamsMu.RLock()
+	defer s.teamsMu.RUnlock()
+	teams := s.teams
+	if teams == nil {
+		teams = s.config.Teams
+	}
+	for i := range teams {
+		if teams[i].Name == name {
+			return &teams[i]
+		}
+	}
+	return nil
+}
+
+// teamChannel returns the Mattermost channel configured for a team, or empty for the default channel.
+func (s *Service) teamChannel(team string) string {
+	if teamCfg := s.teamByName(team); teamCfg != nil {
+		return teamCfg.Channel
+	}
+	return ""
+}
+
+// teamChannelID returns the Mattermost channel ID configured for a team's threaded
+// reminders, or empty for the default channel ID.
+func (s *Service) teamChannelID(team string) string {
+	if teamCfg := s.teamByName(team); teamCfg != nil {
+		return teamCfg.ChannelID
+	}
+	return ""
+}
+
+// holidayLayout is the date format of scheduler.holidays entries.
+const holidayLayout = "2006-01-02"
+
+// isHoliday reports whether t falls on a configured holiday in the scheduler timezone.
+func (s *Service) isHoliday(t time.Time) bool {
+	if !s.config.Scheduler.SkipHolidays || len(s.config.Scheduler.Holidays) == 0 {
+		return false
+	}
+
+	if location, err := s.config.Scheduler.GetLocation(); err == nil {
+		t = t.In(location)
+	}
+	today := t.Format(holidayLayout)
+
+	for _, holiday := range s.config.Scheduler.Holidays {
+		if holiday == today {
+			return true
+		}
+	}
+	return false
+}
+
+// currentTime returns the current time, using the injected clock when set.
+func (s *Service) currentTime() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
 }
 
 // filterRecentMRs filters out MRs that are too recent.
@@ -240,11 +498,25 @@ func filterRecentMRs(pendingMRs []mattermost.PendingMR, minAge time.Duration) []
 	return filtered
 }
 
-// runBadgeEvaluation executes the daily badge evaluation job.
+// RunBadgeEvaluationNow runs badge evaluation immediately and returns the number of badges awarded.
+func (s *Service) RunBadgeEvaluationNow(ctx context.Context) (int, error) {
+	if s.badgeService == nil {
+		return 0, fmt.Errorf("badge evaluation is not configured")
+	}
+	return s.evaluateBadges(ctx)
+}
+
+// runBadgeEvaluation executes the scheduled badge evaluation job.
 func (s *Service) runBadgeEvaluation(ctx context.Context) {
+	// Errors are logged and recorded as metrics by the job itself
+	_, _ = s.evaluateBadges(ctx)
+}
+
+// evaluateBadges evaluates badges for all users.
+func (s *Service) evaluateBadges(ctx context.Context) (int, error) {
 	start := time.Now()
 
-	// Track job duration and update last run timestamp on exit
+	// Track job duration on exit
 	defer func() {
 		duration := time.Since(start).Seconds()
 		prommetrics.ObserveBadgeEvaluationDuration(duration)
@@ -253,14 +525,14 @@ func (s *Service) runBadgeEvaluation(ctx context.Context) {
 	s.log.Info().Msg("Running badge evaluation job")
 
 	// Run badge evaluation for all users
-	awardsCount, err := s.badgeService.EvaluateAllBadges(ctx)
+	awardsCount, awardsByBadge, err := s.badgeService.EvaluateAllBadges(ctx)
 	if err != nil {
 		s.log.Error().
 			Err(err).
 			Dur("duration", time.Since(start)).
 			Msg("Badge evaluation job failed")
 		prommetrics.RecordBadgeEvaluationRun("error")
-		return
+		return 0, fmt.Errorf("badge evaluation failed: %w", err)
 	}
 
 	duration := time.Since(start)
@@ -268,6 +540,21 @@ func (s *Service) runBadgeEvaluation(ctx context.Context) {
 
 	s.log.Info().
 		Int("badges_awarded", awardsCount).
+		Interface("awards_by_badge", awardsByBadge).
 		Dur("duration", duration).
 		Msg("Badge evaluation job completed successfully")
+
+	return awardsCount, nil
+}
+
+// runPersonalBestsUpdate records new personal bests for every tracked period.
+func (s *Service) runPersonalBestsUpdate(ctx context.Context) {
+	for _, period := range leaderboard.PersonalBestPeriods {
+		if _, err := s.leaderboardService.UpdatePersonalBests(ctx, period); err != nil {
+			s.log.Error().
+				Err(err).
+				Str("period", period).
+				Msg("Failed to update personal bests")
+		}
+	}
 }
@@ -1,12 +1,23 @@
 package scheduler
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/mattermost"
+	prommetrics "github.com/aimd54/gitlab-reviewer-roulette/internal/metrics"
 	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
 )
 
 func TestBuildCronExpression(t *testing.T) {
@@ -294,3 +305,479 @@ func TestFilterRecentMRs(t *testing.T) {
 		})
 	}
 }
+
+func TestGroupPendingMRsByTeam(t *testing.T) {
+	pendingMR := func(title, team string, age time.Duration) mattermost.PendingMR {
+		return mattermost.PendingMR{
+			Title: title,
+			Team:  team,
+			Age: func() time.Duration {
+				return age
+			},
+		}
+	}
+
+	pendingMRs := []mattermost.PendingMR{
+		pendingMR("Backend MR 1", "backend", 6*time.Hour),
+		pendingMR("Frontend MR", "frontend", 10*time.Hour),
+		pendingMR("Backend MR 2", "backend", 50*time.Hour),
+		pendingMR("Recent Mobile MR", "mobile", 1*time.Hour),
+	}
+
+	groups := groupPendingMRsByTeam(pendingMRs, 4*time.Hour)
+
+	if len(groups) != 2 {
+		t.Fatalf("groupPendingMRsByTeam() returned %d groups, want 2 (mobile group should be skipped)", len(groups))
+	}
+
+	if groups[0].Team != "backend" || len(groups[0].MRs) != 2 {
+		t.Errorf("groups[0] = %s with %d MRs, want backend with 2 MRs", groups[0].Team, len(groups[0].MRs))
+	}
+	if groups[1].Team != "frontend" || len(groups[1].MRs) != 1 {
+		t.Errorf("groups[1] = %s with %d MRs, want frontend with 1 MR", groups[1].Team, len(groups[1].MRs))
+	}
+
+	for _, group := range groups {
+		for _, mr := range group.MRs {
+			if mr.Team != group.Team {
+				t.Errorf("MR %q of team %q grouped under %q", mr.Title, mr.Team, group.Team)
+			}
+		}
+	}
+}
+
+func TestGroupPendingMRsByTeam_EmptyAndUnassigned(t *testing.T) {
+	if groups := groupPendingMRsByTeam(nil, 4*time.Hour); len(groups) != 0 {
+		t.Errorf("groupPendingMRsByTeam(nil) returned %d groups, want 0", len(groups))
+	}
+
+	groups := groupPendingMRsByTeam([]mattermost.PendingMR{
+		{Title: "No team", Age: func() time.Duration { return 5 * time.Hour }},
+	}, 4*time.Hour)
+
+	if len(groups) != 1 || groups[0].Team != unassignedTeam {
+		t.Errorf("groupPendingMRsByTeam() = %+v, want a single %q group", groups, unassignedTeam)
+	}
+}
+
+func TestTeamChannel(t *testing.T) {
+	s := &Service{config: &config.Config{
+		Teams: []config.TeamConfig{
+			{Name: "backend", Channel: "#backend-reviews"},
+			{Name: "frontend"},
+		},
+	}}
+
+	if got := s.teamChannel("backend"); got != "#backend-reviews" {
+		t.Errorf("teamChannel(backend) = %q, want #backend-reviews", got)
+	}
+	if got := s.teamChannel("frontend"); got != "" {
+		t.Errorf("teamChannel(frontend) = %q, want default channel", got)
+	}
+	if got := s.teamChannel("unknown"); got != "" {
+		t.Errorf("teamChannel(unknown) = %q, want default channel", got)
+	}
+}
+
+func setupSchedulerDB(t *testing.T) *repository.ReviewRepository {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.User{}, &models.MRReview{}, &models.ReviewerAssignment{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		_ = sqlDB.Close()
+	})
+	return repository.NewReviewRepository(&repository.DB{DB: db})
+}
+
+func TestRunDailyNotifications_Holidays(t *testing.T) {
+	cfg := &config.Config{
+		Scheduler: config.SchedulerConfig{
+			Timezone:     "Europe/Paris",
+			SkipHolidays: true,
+			Holidays:     []string{"2025-12-25", "2026-01-01"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		wantStatus string
+	}{
+		{
+			name:       "holiday skips",
+			now:        time.Date(2025, 12, 25, 9, 0, 0, 0, time.UTC),
+			wantStatus: "skipped_holiday",
+		},
+		{
+			// 23:30 UTC on Dec 31 is already Jan 1 in Paris
+			name:       "holiday in scheduler timezone skips",
+			now:        time.Date(2025, 12, 31, 23, 30, 0, 0, time.UTC),
+			wantStatus: "skipped_holiday",
+		},
+		{
+			name:       "regular day proceeds",
+			now:        time.Date(2025, 12, 26, 9, 0, 0, 0, time.UTC),
+			wantStatus: "success",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{
+				config:     cfg,
+				reviewRepo: contextReviewRepository{repo: setupSchedulerDB(t)},
+				log:        logger.New("error", "text", "stdout"),
+				now:        func() time.Time { return tt.now },
+			}
+
+			before := testutil.ToFloat64(prommetrics.SchedulerJobsRunTotal.WithLabelValues(tt.wantStatus))
+			s.runDailyNotifications(context.Background())
+			after := testutil.ToFloat64(prommetrics.SchedulerJobsRunTotal.WithLabelValues(tt.wantStatus))
+
+			if after-before != 1 {
+				t.Errorf("scheduler job status %q recorded %v times, want 1", tt.wantStatus, after-before)
+			}
+		})
+	}
+}
+
+func TestIsHoliday_RequiresSkipHolidays(t *testing.T) {
+	s := &Service{config: &config.Config{
+		Scheduler: config.SchedulerConfig{
+			Timezone: "UTC",
+			Holidays: []string{"2025-12-25"},
+		},
+	}}
+
+	if s.isHoliday(time.Date(2025, 12, 25, 9, 0, 0, 0, time.UTC)) {
+		t.Error("isHoliday() = true with skip_holidays disabled, want false")
+	}
+}
+
+type mockReviewRepository struct {
+	reviews []models.MRReview
+	err     error
+	calls   int
+}
+
+func (m *mockReviewRepository) ListPendingMRReviews(_ context.Context) ([]models.MRReview, error) {
+	m.calls++
+	// Return a copy, as the database would
+	reviews := make([]models.MRReview, len(m.reviews))
+	copy(reviews, m.reviews)
+	return reviews, m.err
+}
+
+func (m *mockReviewRepository) SetReminderCommentID(_ context.Context, reviewID uint, noteID int) error {
+	for i := range m.reviews {
+		if m.reviews[i].ID == reviewID {
+			m.reviews[i].ReminderCommentID = &noteID
+		}
+	}
+	return nil
+}
+
+type mockBadgeService struct {
+	awarded int
+	byBadge map[string]int
+	err     error
+	calls   int
+}
+
+func (m *mockBadgeService) EvaluateAllBadges(_ context.Context) (int, map[string]int, error) {
+	m.calls++
+	return m.awarded, m.byBadge, m.err
+}
+
+type mockNotificationClient struct {
+	channels []string
+	err      error
+}
+
+func (m *mockNotificationClient) SendDailyReviewReminder(channel string, _ []mattermost.PendingMR, _ time.Duration) error {
+	m.channels = append(m.channels, channel)
+	return m.err
+}
+
+type mockThreadedClient struct {
+	mockNotificationClient
+	rootIDs    []string
+	channelIDs []string
+	posts      int
+	threadErr  error
+}
+
+func (m *mockThreadedClient) ThreadingEnabled() bool { return true }
+
+func (m *mockThreadedClient) SendThreadedReminder(_, channelID, rootID string, _ []mattermost.PendingMR, _ time.Duration) (string, error) {
+	if m.threadErr != nil {
+		return rootID, m.threadErr
+	}
+	m.rootIDs = append(m.rootIDs, rootID)
+	m.channelIDs = append(m.channelIDs, channelID)
+	m.posts++
+	if rootID == "" {
+		return fmt.Sprintf("root-%d", m.posts), nil
+	}
+	return rootID, nil
+}
+
+// mockThreadStore is an in-memory ThreadStore that round-trips values through JSON.
+type mockThreadStore struct {
+	values map[string][]byte
+}
+
+func (m *mockThreadStore) GetValue(key string, dest interface{}) (bool, error) {
+	data, ok := m.values[key]
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(data, dest)
+}
+
+func (m *mockThreadStore) SetValue(key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if m.values == nil {
+		m.values = make(map[string][]byte)
+	}
+	m.values[key] = data
+	return nil
+}
+
+func TestRunDailyNotificationsNow_ThreadsRemindersPerDay(t *testing.T) {
+	triggeredAt := time.Now().Add(-6 * time.Hour)
+	reviewRepo := &mockReviewRepository{
+		reviews: []models.MRReview{
+			{MRTitle: "Backend MR", Team: "backend", RouletteTriggeredAt: &triggeredAt},
+			{MRTitle: "Frontend MR", Team: "frontend", RouletteTriggeredAt: &triggeredAt},
+		},
+	}
+	client := &mockThreadedClient{}
+	s := NewServiceWithInterfaces(&config.Config{}, reviewRepo, nil, nil, client, logger.New("error", "text", "stdout"))
+
+	day := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return day }
+
+	if err := s.RunDailyNotificationsNow(context.Background()); err != nil {
+		t.Fatalf("RunDailyNotificationsNow() error = %v", err)
+	}
+	// A second run on the same day keeps replying in the same thread
+	if err := s.RunDailyNotificationsNow(context.Background()); err != nil {
+		t.Fatalf("RunDailyNotificationsNow() error = %v", err)
+	}
+
+	day = day.AddDate(0, 0, 1)
+	if err := s.RunDailyNotificationsNow(context.Background()); err != nil {
+		t.Fatalf("RunDailyNotificationsNow() error = %v", err)
+	}
+
+	want := []string{"", "root-1", "root-1", "root-1", "", "root-5"}
+	if fmt.Sprint(client.rootIDs) != fmt.Sprint(want) {
+		t.Errorf("root IDs = %q, want %q", client.rootIDs, want)
+	}
+	if len(client.channels) != 0 {
+		t.Errorf("expected no webhook reminders when threading, got %q", client.channels)
+	}
+}
+
+func TestRunDailyNotificationsNow_ThreadsPerTeamChannel(t *testing.T) {
+	triggeredAt := time.Now().Add(-6 * time.Hour)
+	reviewRepo := &mockReviewRepository{
+		reviews: []models.MRReview{
+			{MRTitle: "Backend MR", Team: "backend", RouletteTriggeredAt: &triggeredAt},
+			{MRTitle: "Frontend MR", Team: "frontend", RouletteTriggeredAt: &triggeredAt},
+		},
+	}
+	cfg := &config.Config{
+		Teams: []config.TeamConfig{{Name: "backend", Channel: "#backend-reviews", ChannelID: "backend-id"}},
+	}
+	store := &mockThreadStore{}
+	day := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	log := logger.New("error", "text", "stdout")
+
+	client := &mockThreadedClient{}
+	s := NewServiceWithInterfaces(cfg, reviewRepo, nil, nil, client, log)
+	s.SetThreadStore(store)
+	s.now = func() time.Time { return day }
+	if err := s.RunDailyNotificationsNow(context.Background()); err != nil {
+		t.Fatalf("RunDailyNotificationsNow() error = %v", err)
+	}
+
+	// A restarted scheduler replies in the threads stored by the previous one
+	client = &mockThreadedClient{}
+	s = NewServiceWithInterfaces(cfg, reviewRepo, nil, nil, client, log)
+	s.SetThreadStore(store)
+	s.now = func() time.Time { return day }
+	if err := s.RunDailyNotificationsNow(context.Background()); err != nil {
+		t.Fatalf("RunDailyNotificationsNow() error = %v", err)
+	}
+
+	if want := []string{"backend-id", ""}; fmt.Sprint(client.channelIDs) != fmt.Sprint(want) {
+		t.Errorf("channel IDs = %q, want %q", client.channelIDs, want)
+	}
+	if want := []string{"root-1", "root-2"}; fmt.Sprint(client.rootIDs) != fmt.Sprint(want) {
+		t.Errorf("root IDs after restart = %q, want %q", client.rootIDs, want)
+	}
+}
+
+func TestRunDailyNotificationsNow_ThreadFailureUsesWebhook(t *testing.T) {
+	triggeredAt := time.Now().Add(-6 * time.Hour)
+	reviewRepo := &mockReviewRepository{
+		reviews: []models.MRReview{{MRTitle: "Backend MR", Team: "backend", RouletteTriggeredAt: &triggeredAt}},
+	}
+	cfg := &config.Config{Teams: []config.TeamConfig{{Name: "backend", Channel: "#backend-reviews"}}}
+	client := &mockThreadedClient{threadErr: errors.New("mattermost returned status 503")}
+	s := NewServiceWithInterfaces(cfg, reviewRepo, nil, nil, client, logger.New("error", "text", "stdout"))
+
+	if err := s.RunDailyNotificationsNow(context.Background()); err != nil {
+		t.Fatalf("RunDailyNotificationsNow() error = %v", err)
+	}
+	if len(client.channels) != 1 || client.channels[0] != "#backend-reviews" {
+		t.Errorf("webhook reminders sent to %q, want [#backend-reviews]", client.channels)
+	}
+}
+
+func TestRunDailyNotificationsNow(t *testing.T) {
+	triggeredAt := time.Now().Add(-6 * time.Hour)
+	reviewRepo := &mockReviewRepository{
+		reviews: []models.MRReview{
+			{MRTitle: "Backend MR", Team: "backend", RouletteTriggeredAt: &triggeredAt},
+			{MRTitle: "Frontend MR", Team: "frontend", RouletteTriggeredAt: &triggeredAt},
+		},
+	}
+	client := &mockNotificationClient{}
+	cfg := &config.Config{
+		Teams: []config.TeamConfig{{Name: "backend", Channel: "#backend-reviews"}},
+		Scheduler: config.SchedulerConfig{
+			Timezone:     "UTC",
+			SkipHolidays: true,
+			Holidays:     []string{time.Now().UTC().Format(holidayLayout)},
+		},
+	}
+	s := NewServiceWithInterfaces(cfg, reviewRepo, nil, nil, client, logger.New("error", "text", "stdout"))
+
+	// Manual runs ignore the holiday list
+	if err := s.RunDailyNotificationsNow(context.Background()); err != nil {
+		t.Fatalf("RunDailyNotificationsNow() error = %v", err)
+	}
+
+	if reviewRepo.calls != 1 {
+		t.Errorf("ListPendingMRReviews called %d times, want 1", reviewRepo.calls)
+	}
+	if len(client.channels) != 2 || client.channels[0] != "#backend-reviews" || client.channels[1] != "" {
+		t.Errorf("reminders sent to %q, want [#backend-reviews, default]", client.channels)
+	}
+}
+
+func TestRunDailyNotificationsNow_PropagatesErrors(t *testing.T) {
+	triggeredAt := time.Now().Add(-6 * time.Hour)
+	log := logger.New("error", "text", "stdout")
+
+	queryErr := errors.New("database unavailable")
+	s := NewServiceWithInterfaces(&config.Config{}, &mockReviewRepository{err: queryErr}, nil, nil, &mockNotificationClient{}, log)
+	if err := s.RunDailyNotificationsNow(context.Background()); !errors.Is(err, queryErr) {
+		t.Errorf("RunDailyNotificationsNow() error = %v, want %v", err, queryErr)
+	}
+
+	sendErr := errors.New("mattermost unavailable")
+	reviewRepo := &mockReviewRepository{
+		reviews: []models.MRReview{{MRTitle: "MR", Team: "backend", RouletteTriggeredAt: &triggeredAt}},
+	}
+	s = NewServiceWithInterfaces(&config.Config{}, reviewRepo, nil, nil, &mockNotificationClient{err: sendErr}, log)
+	if err := s.RunDailyNotificationsNow(context.Background()); !errors.Is(err, sendErr) {
+		t.Errorf("RunDailyNotificationsNow() error = %v, want %v", err, sendErr)
+	}
+}
+
+func TestRunBadgeEvaluationNow(t *testing.T) {
+	log := logger.New("error", "text", "stdout")
+
+	badgeService := &mockBadgeService{awarded: 3, byBadge: map[string]int{"Speed Demon": 2, "Team Player": 1}}
+	s := NewServiceWithInterfaces(&config.Config{}, nil, badgeService, nil, nil, log)
+
+	awarded, err := s.RunBadgeEvaluationNow(context.Background())
+	if err != nil {
+		t.Fatalf("RunBadgeEvaluationNow() error = %v", err)
+	}
+	if awarded != 3 || badgeService.calls != 1 {
+		t.Errorf("RunBadgeEvaluationNow() = %d after %d calls, want 3 after 1 call", awarded, badgeService.calls)
+	}
+
+	evalErr := errors.New("evaluation failed")
+	s = NewServiceWithInterfaces(&config.Config{}, nil, &mockBadgeService{err: evalErr}, nil, nil, log)
+	if _, err := s.RunBadgeEvaluationNow(context.Background()); !errors.Is(err, evalErr) {
+		t.Errorf("RunBadgeEvaluationNow() error = %v, want %v", err, evalErr)
+	}
+
+	s = NewServiceWithInterfaces(&config.Config{}, nil, nil, nil, nil, log)
+	if _, err := s.RunBadgeEvaluationNow(context.Background()); err == nil {
+		t.Error("RunBadgeEvaluationNow() without badge service should fail")
+	}
+}
+
+func TestStop_CancelsRunningJobs(t *testing.T) {
+	log := logger.New("error", "text", "stdout")
+	cfg := &config.Config{
+		Scheduler: config.SchedulerConfig{
+			Enabled:  true,
+			Time:     "09:00",
+			Timezone: "UTC",
+		},
+	}
+
+	s := NewServiceWithInterfaces(cfg, nil, nil, nil, nil, log)
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := s.jobCtx.Err(); err != nil {
+		t.Fatalf("job context cancelled before Stop: %v", err)
+	}
+
+	s.Stop()
+
+	if !errors.Is(s.jobCtx.Err(), context.Canceled) {
+		t.Errorf("job context error after Stop = %v, want %v", s.jobCtx.Err(), context.Canceled)
+	}
+}
+
+func TestRunDailyNotificationsNow_MinMRAge(t *testing.T) {
+	twoHoursAgo := time.Now().Add(-2 * time.Hour)
+	sixHoursAgo := time.Now().Add(-6 * time.Hour)
+	reviews := []models.MRReview{
+		{MRTitle: "Recent MR", Team: "frontend", RouletteTriggeredAt: &twoHoursAgo},
+		{MRTitle: "Older MR", Team: "backend", RouletteTriggeredAt: &sixHoursAgo},
+	}
+
+	tests := []struct {
+		name          string
+		minMRAgeHours int
+		wantTeams     int
+	}{
+		{name: "default threshold filters recent MR", minMRAgeHours: 4, wantTeams: 1},
+		{name: "lower threshold keeps recent MR", minMRAgeHours: 1, wantTeams: 2},
+		{name: "higher threshold filters both", minMRAgeHours: 8, wantTeams: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Scheduler: config.SchedulerConfig{MinMRAgeHours: tt.minMRAgeHours}}
+			client := &mockNotificationClient{}
+			s := NewServiceWithInterfaces(cfg, &mockReviewRepository{reviews: reviews}, nil, nil, client, logger.New("error", "text", "stdout"))
+
+			if err := s.RunDailyNotificationsNow(context.Background()); err != nil {
+				t.Fatalf("RunDailyNotificationsNow() error = %v", err)
+			}
+			if len(client.channels) != tt.wantTeams {
+				t.Errorf("sent %d reminders, want %d", len(client.channels), tt.wantTeams)
+			}
+		})
+	}
+}
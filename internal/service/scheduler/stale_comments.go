@@ -0,0 +1,141 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/i18n"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+)
+
+// CommentClient interface for posting and updating GitLab MR comments.
+type CommentClient interface {
+	PostComment(ctx context.Context, projectID, mrIID int, comment string) (int, error)
+	UpdateComment(ctx context.Context, projectID, mrIID, noteID int, comment string) error
+}
+
+// SetGitLabCommenter enables "still awaiting review" comments on MRs older than
+// scheduler.stale_mr_comment_hours. Comments are in the translator's language.
+func (s *Service) SetGitLabCommenter(client CommentClient, translator *i18n.Translator) {
+	s.gitlabClient = client
+	s.translator = translator
+}
+
+// RunStaleMRCommentsNow comments on stale MRs immediately and returns how many were commented on.
+func (s *Service) RunStaleMRCommentsNow(ctx context.Context) (int, error) {
+	return s.commentOnStaleMRs(ctx)
+}
+
+// runStaleMRComments executes the scheduled stale MR comment job.
+func (s *Service) runStaleMRComments(ctx context.Context) {
+	if s.isHoliday(s.currentTime()) {
+		s.log.Info().Msg("Today is a configured holiday, skipping stale MR comment job")
+		return
+	}
+
+	// Errors are logged by the job itself
+	_, _ = s.commentOnStaleMRs(ctx)
+}
+
+// commentOnStaleMRs posts a reminder comment on each pending MR older than the
+// threshold, updating the previous reminder instead of posting a duplicate.
+func (s *Service) commentOnStaleMRs(ctx context.Context) (int, error) {
+	threshold := time.Duration(s.config.Scheduler.StaleMRCommentHours) * time.Hour
+	if threshold <= 0 || s.gitlabClient == nil {
+		return 0, nil
+	}
+
+	reviews, err := s.reviewRepo.ListPendingMRReviews(ctx)
+	if err != nil {
+		s.log.Error().Err(err).Msg("Failed to list pending MR reviews for stale comments")
+		return 0, fmt.Errorf("failed to list pending MR reviews: %w", err)
+	}
+
+	tr := s.translator
+	if tr == nil {
+		tr = i18n.MustNew("en")
+	}
+
+	now := s.currentTime()
+	commented := 0
+	for i := range reviews {
+		if err := ctx.Err(); err != nil {
+			return commented, err
+		}
+		review := &reviews[i]
+		if review.RouletteTriggeredAt == nil || now.Sub(*review.RouletteTriggeredAt) < threshold {
+			continue
+		}
+
+		comment := buildStaleMRComment(tr, review, now.Sub(*review.RouletteTriggeredAt))
+		if err := s.postStaleMRComment(ctx, review, comment); err != nil {
+			s.log.Error().
+				Err(err).
+				Int("project_id", review.GitLabProjectID).
+				Int("mr_iid", review.GitLabMRIID).
+				Msg("Failed to comment on stale MR")
+			continue
+		}
+		commented++
+	}
+
+	s.log.Info().
+		Int("pending", len(reviews)).
+		Int("commented", commented).
+		Msg("Commented on stale MRs")
+
+	return commented, nil
+}
+
+// postStaleMRComment updates the MR's existing reminder comment, or posts a new
+// one (and stores its note ID) when there is none or it can't be updated.
+func (s *Service) postStaleMRComment(ctx context.Context, review *models.MRReview, comment string) error {
+	if review.ReminderCommentID != nil && *review.ReminderCommentID > 0 {
+		err := s.gitlabClient.UpdateComment(ctx, review.GitLabProjectID, review.GitLabMRIID, *review.ReminderCommentID, comment)
+		if err == nil {
+			return nil
+		}
+		// The comment may have been deleted; post a new one
+		s.log.Warn().
+			Err(err).
+			Int("note_id", *review.ReminderCommentID).
+			Msg("Failed to update stale MR comment, creating new one")
+	}
+
+	noteID, err := s.gitlabClient.PostComment(ctx, review.GitLabProjectID, review.GitLabMRIID, comment)
+	if err != nil {
+		return err
+	}
+
+	review.ReminderCommentID = &noteID
+	return s.reviewRepo.SetReminderCommentID(ctx, review.ID, noteID)
+}
+
+// buildStaleMRComment formats the reminder, mentioning reviewers who haven't approved yet.
+func buildStaleMRComment(tr *i18n.Translator, review *models.MRReview, age time.Duration) string {
+	ageStr := tr.Get("stale_review.age_hours", map[string]interface{}{
+		"Value": fmt.Sprintf("%.0f", age.Hours()),
+	})
+	if age.Hours() > 48 {
+		ageStr = tr.Get("stale_review.age_days", map[string]interface{}{
+			"Value": fmt.Sprintf("%.0f", age.Hours()/24),
+		})
+	}
+
+	var mentions []string
+	for _, assignment := range review.Assignments {
+		if assignment.ApprovedAt == nil && assignment.User.Username != "" {
+			mentions = append(mentions, "@"+assignment.User.Username)
+		}
+	}
+
+	text := tr.Get("stale_review.comment", map[string]interface{}{"Age": ageStr}) + "\n\n"
+	if len(mentions) > 0 {
+		return text + tr.Get("stale_review.reviewers", map[string]interface{}{
+			"Reviewers": strings.Join(mentions, " "),
+		})
+	}
+	return text + tr.Get("stale_review.footer")
+}
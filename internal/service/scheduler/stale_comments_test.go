@@ -0,0 +1,123 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/i18n"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+)
+
+type mockCommentClient struct {
+	posts   []string
+	updates map[int]string
+	nextID  int
+}
+
+func (m *mockCommentClient) PostComment(_ context.Context, _, _ int, comment string) (int, error) {
+	m.posts = append(m.posts, comment)
+	m.nextID++
+	return m.nextID, nil
+}
+
+func (m *mockCommentClient) UpdateComment(_ context.Context, _, _, noteID int, comment string) error {
+	if noteID > m.nextID {
+		return fmt.Errorf("note %d not found", noteID)
+	}
+	if m.updates == nil {
+		m.updates = make(map[int]string)
+	}
+	m.updates[noteID] = comment
+	return nil
+}
+
+func TestRunStaleMRCommentsNow_PostsOnceThenUpdates(t *testing.T) {
+	now := time.Date(2025, 11, 27, 9, 0, 0, 0, time.UTC)
+	stale := now.Add(-72 * time.Hour)
+	recent := now.Add(-2 * time.Hour)
+	reviewRepo := &mockReviewRepository{
+		reviews: []models.MRReview{
+			{
+				ID: 1, GitLabProjectID: 10, GitLabMRIID: 1, RouletteTriggeredAt: &stale,
+				Assignments: []models.ReviewerAssignment{{User: models.User{Username: "alice"}}},
+			},
+			{ID: 2, GitLabProjectID: 10, GitLabMRIID: 2, RouletteTriggeredAt: &recent},
+		},
+	}
+	cfg := &config.Config{Scheduler: config.SchedulerConfig{StaleMRCommentHours: 48}}
+	s := NewServiceWithInterfaces(cfg, reviewRepo, nil, nil, &mockNotificationClient{}, logger.New("error", "text", "stdout"))
+	s.now = func() time.Time { return now }
+	gitlabClient := &mockCommentClient{}
+	s.SetGitLabCommenter(gitlabClient, i18n.MustNew("en"))
+
+	commented, err := s.RunStaleMRCommentsNow(context.Background())
+	if err != nil {
+		t.Fatalf("RunStaleMRCommentsNow() error = %v", err)
+	}
+	if commented != 1 || len(gitlabClient.posts) != 1 {
+		t.Fatalf("Expected 1 comment on the stale MR, got commented=%d posts=%d", commented, len(gitlabClient.posts))
+	}
+	if !strings.Contains(gitlabClient.posts[0], "3 days") || !strings.Contains(gitlabClient.posts[0], "@alice") {
+		t.Errorf("Comment = %q, want age and reviewer mention", gitlabClient.posts[0])
+	}
+	if id := reviewRepo.reviews[0].ReminderCommentID; id == nil || *id != 1 {
+		t.Fatalf("Expected reminder comment ID 1 to be stored, got %v", id)
+	}
+
+	// The next run updates the same comment instead of posting another
+	s.now = func() time.Time { return now.Add(24 * time.Hour) }
+	if _, err := s.RunStaleMRCommentsNow(context.Background()); err != nil {
+		t.Fatalf("RunStaleMRCommentsNow() error = %v", err)
+	}
+	if len(gitlabClient.posts) != 1 {
+		t.Errorf("Expected no new comment, got %d posts", len(gitlabClient.posts))
+	}
+	if !strings.Contains(gitlabClient.updates[1], "4 days") {
+		t.Errorf("Updated comment = %q, want refreshed age", gitlabClient.updates[1])
+	}
+}
+
+func TestRunStaleMRCommentsNow_Disabled(t *testing.T) {
+	stale := time.Now().Add(-72 * time.Hour)
+	reviewRepo := &mockReviewRepository{
+		reviews: []models.MRReview{{ID: 1, RouletteTriggeredAt: &stale}},
+	}
+	s := NewServiceWithInterfaces(&config.Config{}, reviewRepo, nil, nil, &mockNotificationClient{}, logger.New("error", "text", "stdout"))
+	gitlabClient := &mockCommentClient{}
+	s.SetGitLabCommenter(gitlabClient, nil)
+
+	commented, err := s.RunStaleMRCommentsNow(context.Background())
+	if err != nil {
+		t.Fatalf("RunStaleMRCommentsNow() error = %v", err)
+	}
+	if commented != 0 || len(gitlabClient.posts) != 0 || reviewRepo.calls != 0 {
+		t.Errorf("Expected no comments with stale_mr_comment_hours unset, got %d", commented)
+	}
+}
+
+func TestRunStaleMRCommentsNow_StopsWhenContextCancelled(t *testing.T) {
+	stale := time.Now().Add(-72 * time.Hour)
+	reviewRepo := &mockReviewRepository{
+		reviews: []models.MRReview{{ID: 1, RouletteTriggeredAt: &stale}},
+	}
+	cfg := &config.Config{Scheduler: config.SchedulerConfig{StaleMRCommentHours: 48}}
+	s := NewServiceWithInterfaces(cfg, reviewRepo, nil, nil, &mockNotificationClient{}, logger.New("error", "text", "stdout"))
+	gitlabClient := &mockCommentClient{}
+	s.SetGitLabCommenter(gitlabClient, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := s.RunStaleMRCommentsNow(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("RunStaleMRCommentsNow() error = %v, want context.Canceled", err)
+	}
+	if len(gitlabClient.posts) != 0 {
+		t.Errorf("Expected no comments after cancellation, got %d", len(gitlabClient.posts))
+	}
+}
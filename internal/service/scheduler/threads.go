@@ -0,0 +1,96 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/mattermost"
+)
+
+// reminderThreadsKey is the configuration key today's reminder thread roots are stored under.
+const reminderThreadsKey = "scheduler:reminder_threads"
+
+// ThreadedNotificationClient is implemented by notification clients that can
+// thread a day's reminders under a single root post per channel.
+type ThreadedNotificationClient interface {
+	ThreadingEnabled() bool
+	SendThreadedReminder(channel, channelID, rootID string, pendingMRs []mattermost.PendingMR, overdueAfter time.Duration) (string, error)
+}
+
+// ThreadStore persists reminder thread roots so a restart keeps replying in today's threads.
+type ThreadStore interface {
+	GetValue(key string, dest interface{}) (bool, error)
+	SetValue(key string, value interface{}) error
+}
+
+// reminderThreads holds the root post of each channel's reminder thread for one day.
+type reminderThreads struct {
+	Day   string            `json:"day"`
+	Roots map[string]string `json:"roots"` // Channel ID (empty for the default) to root post ID
+}
+
+// SetThreadStore enables persisting reminder thread roots across restarts.
+func (s *Service) SetThreadStore(store ThreadStore) {
+	s.threadStore = store
+}
+
+// sendReminder sends one team's reminder, replying in today's thread of the team's
+// channel when the client supports threading and posting to the team channel otherwise.
+// A failed threaded post is sent through the webhook instead, which retries and queues.
+func (s *Service) sendReminder(team, channel string, pendingMRs []mattermost.PendingMR, overdueAfter time.Duration) error {
+	threaded, ok := s.mattermostClient.(ThreadedNotificationClient)
+	if !ok || !threaded.ThreadingEnabled() {
+		return s.mattermostClient.SendDailyReviewReminder(channel, pendingMRs, overdueAfter)
+	}
+
+	s.threadMu.Lock()
+	defer s.threadMu.Unlock()
+
+	channelID := s.teamChannelID(team)
+	threads := s.todaysThreads()
+
+	rootID, err := threaded.SendThreadedReminder(channel, channelID, threads.Roots[channelID], pendingMRs, overdueAfter)
+	if err != nil {
+		s.log.Warn().
+			Err(err).
+			Str("team", team).
+			Msg("Failed to send threaded reminder, sending it through the webhook")
+		return s.mattermostClient.SendDailyReviewReminder(channel, pendingMRs, overdueAfter)
+	}
+
+	if rootID != threads.Roots[channelID] {
+		threads.Roots[channelID] = rootID
+		s.saveThreads(threads)
+	}
+	return nil
+}
+
+// todaysThreads returns today's reminder thread roots, loading them from the thread
+// store after a restart and starting over when the day changes. Callers hold threadMu.
+func (s *Service) todaysThreads() *reminderThreads {
+	day := s.currentTime().Format(holidayLayout)
+
+	if s.threads == nil && s.threadStore != nil {
+		var saved reminderThreads
+		found, err := s.threadStore.GetValue(reminderThreadsKey, &saved)
+		if err != nil {
+			s.log.Warn().Err(err).Msg("Failed to load reminder threads")
+		} else if found {
+			s.threads = &saved
+		}
+	}
+
+	if s.threads == nil || s.threads.Day != day || s.threads.Roots == nil {
+		s.threads = &reminderThreads{Day: day, Roots: make(map[string]string)}
+	}
+	return s.threads
+}
+
+// saveThreads persists the reminder thread roots; failures only cost threading after a restart.
+func (s *Service) saveThreads(threads *reminderThreads) {
+	if s.threadStore == nil {
+		return
+	}
+	if err := s.threadStore.SetValue(reminderThreadsKey, threads); err != nil {
+		s.log.Warn().Err(err).Msg("Failed to save reminder threads")
+	}
+}
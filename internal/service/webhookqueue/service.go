@@ -0,0 +1,249 @@
+// Package webhookqueue provides a database-backed retry queue for outgoing webhooks.
+// Failed deliveries are persisted and retried with exponential backoff, so they
+// survive process restarts.
+package webhookqueue
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+)
+
+const (
+	defaultMaxAttempts  = 5
+	defaultBaseBackoff  = 30 * time.Second
+	defaultMaxBackoff   = time.Hour
+	defaultPollInterval = 30 * time.Second
+
+	// batchSize bounds the number of deliveries attempted per scan.
+	batchSize = 50
+)
+
+// DeliveryRepository interface for webhook delivery persistence.
+type DeliveryRepository interface {
+	Create(delivery *models.WebhookDelivery) error
+	GetDue(now time.Time, limit int) ([]models.WebhookDelivery, error)
+	Update(delivery *models.WebhookDelivery) error
+}
+
+// Service persists failed webhook deliveries and retries them in the background.
+type Service struct {
+	repo         DeliveryRepository
+	httpClient   *http.Client
+	maxAttempts  int
+	baseBackoff  time.Duration
+	maxBackoff   time.Duration
+	pollInterval time.Duration
+	now          func() time.Time
+	log          *logger.Logger
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+	cancel   context.CancelFunc // cancels the in-flight scan, set by Start
+}
+
+// NewService creates a new webhook retry queue with concrete repository types.
+func NewService(cfg *config.RetryQueueConfig, repo *repository.WebhookDeliveryRepository, log *logger.Logger) *Service {
+	return NewServiceWithInterfaces(cfg, repo, log)
+}
+
+// NewServiceWithInterfaces creates a new webhook retry queue with interface dependencies (useful for testing).
+func NewServiceWithInterfaces(cfg *config.RetryQueueConfig, repo DeliveryRepository, log *logger.Logger) *Service {
+	s := &Service{
+		repo:         repo,
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+		maxAttempts:  defaultMaxAttempts,
+		baseBackoff:  defaultBaseBackoff,
+		maxBackoff:   defaultMaxBackoff,
+		pollInterval: defaultPollInterval,
+		now:          time.Now,
+		log:          log,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+
+	if cfg.MaxAttempts > 0 {
+		s.maxAttempts = cfg.MaxAttempts
+	}
+	if cfg.BaseBackoff > 0 {
+		s.baseBackoff = time.Duration(cfg.BaseBackoff) * time.Second
+	}
+	if cfg.MaxBackoff > 0 {
+		s.maxBackoff = time.Duration(cfg.MaxBackoff) * time.Second
+	}
+	if cfg.PollInterval > 0 {
+		s.pollInterval = time.Duration(cfg.PollInterval) * time.Second
+	}
+
+	return s
+}
+
+// Enqueue persists a delivery whose first attempt failed with lastErr.
+// The next attempt is scheduled after the initial backoff.
+func (s *Service) Enqueue(url string, payload []byte, lastErr error) error {
+	delivery := &models.WebhookDelivery{
+		URL:         url,
+		Payload:     string(payload),
+		Status:      models.WebhookDeliveryStatusPending,
+		MaxAttempts: s.maxAttempts,
+	}
+	s.recordFailure(delivery, lastErr)
+
+	if err := s.repo.Create(delivery); err != nil {
+		return fmt.Errorf("failed to enqueue webhook delivery: %w", err)
+	}
+
+	s.log.Info().
+		Uint("delivery_id", delivery.ID).
+		Str("status", delivery.Status).
+		Time("next_attempt_at", delivery.NextAttemptAt).
+		Msg("Webhook delivery queued for retry")
+
+	return nil
+}
+
+// ProcessDue attempts every pending delivery that is due and returns how many were delivered.
+func (s *Service) ProcessDue(ctx context.Context) (int, error) {
+	deliveries, err := s.repo.GetDue(s.now(), batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			return delivered, ctx.Err()
+		}
+
+		delivery := &deliveries[i]
+		if err := s.deliver(ctx, delivery.URL, []byte(delivery.Payload)); err != nil {
+			// An attempt interrupted by shutdown is not the receiver's fault; it stays due
+			if ctx.Err() != nil {
+				return delivered, ctx.Err()
+			}
+			s.recordFailure(delivery, err)
+			s.log.Warn().
+				Err(err).
+				Uint("delivery_id", delivery.ID).
+				Int("attempts", delivery.Attempts).
+				Str("status", delivery.Status).
+				Msg("Webhook delivery retry failed")
+		} else {
+			deliveredAt := s.now()
+			delivery.Attempts++
+			delivery.Status = models.WebhookDeliveryStatusDelivered
+			delivery.DeliveredAt = &deliveredAt
+			delivery.LastError = ""
+			delivered++
+
+			s.log.Info().
+				Uint("delivery_id", delivery.ID).
+				Int("attempts", delivery.Attempts).
+				Msg("Webhook delivery retry succeeded")
+		}
+
+		if err := s.repo.Update(delivery); err != nil {
+			s.log.Error().Err(err).Uint("delivery_id", delivery.ID).Msg("Failed to update webhook delivery")
+		}
+	}
+
+	return delivered, nil
+}
+
+// Start runs the retry worker in the background until Stop is called.
+func (s *Service) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := s.ProcessDue(ctx); err != nil && ctx.Err() == nil {
+				s.log.Error().Err(err).Msg("Failed to process webhook retry queue")
+			}
+
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	s.log.Info().
+		Dur("poll_interval", s.pollInterval).
+		Int("max_attempts", s.maxAttempts).
+		Msg("Webhook retry queue started")
+}
+
+// Stop stops the retry worker, cancelling the delivery in flight, and waits for it to exit.
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() {
+		if s.cancel != nil {
+			s.cancel()
+		}
+		close(s.stop)
+		<-s.done
+		s.log.Info().Msg("Webhook retry queue stopped")
+	})
+}
+
+// recordFailure counts a failed attempt and schedules the next one,
+// or marks the delivery failed once max attempts are reached.
+func (s *Service) recordFailure(delivery *models.WebhookDelivery, err error) {
+	delivery.Attempts++
+	if err != nil {
+		delivery.LastError = err.Error()
+	}
+
+	if delivery.Attempts >= delivery.MaxAttempts {
+		delivery.Status = models.WebhookDeliveryStatusFailed
+		delivery.NextAttemptAt = s.now()
+		return
+	}
+
+	delivery.NextAttemptAt = s.now().Add(s.backoff(delivery.Attempts))
+}
+
+// backoff returns the delay after the given number of attempts: base * 2^(attempts-1), capped at maxBackoff.
+func (s *Service) backoff(attempts int) time.Duration {
+	delay := s.baseBackoff
+	for i := 1; i < attempts && delay < s.maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, s.maxBackoff)
+}
+
+// deliver posts the payload and treats any non-2xx status as a failure.
+func (s *Service) deliver(ctx context.Context, url string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package webhookqueue
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/config"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/models"
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/repository"
+	"github.com/aimd54/gitlab-reviewer-roulette/pkg/logger"
+)
+
+func setupTestQueue(t *testing.T, cfg *config.RetryQueueConfig) (*Service, *repository.WebhookDeliveryRepository, *time.Time) {
+	t.Helper()
+
+	gormDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gormDB.AutoMigrate(&models.WebhookDelivery{}))
+
+	repo := repository.NewWebhookDeliveryRepository(&repository.DB{DB: gormDB})
+	service := NewService(cfg, repo, logger.New("error", "json", "stdout"))
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	return service, repo, &now
+}
+
+func TestProcessDue_RetriesUntilDelivered(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first retry, succeed afterwards
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	service, repo, now := setupTestQueue(t, &config.RetryQueueConfig{MaxAttempts: 5, BaseBackoff: 60})
+
+	require.NoError(t, service.Enqueue(server.URL, []byte(`{"text":"hello"}`), errors.New("connection refused")))
+
+	// Not due yet
+	delivered, err := service.ProcessDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, delivered)
+	assert.Equal(t, int32(0), calls.Load())
+
+	// First retry fails and is rescheduled with a doubled backoff
+	*now = now.Add(time.Minute)
+	delivered, err = service.ProcessDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, delivered)
+
+	delivery, err := repo.GetByID(1)
+	require.NoError(t, err)
+	assert.Equal(t, models.WebhookDeliveryStatusPending, delivery.Status)
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.Equal(t, "webhook returned status 503", delivery.LastError)
+	assert.True(t, delivery.NextAttemptAt.Equal(now.Add(2*time.Minute)))
+
+	// Second retry succeeds
+	*now = now.Add(2 * time.Minute)
+	delivered, err = service.ProcessDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.Equal(t, int32(2), calls.Load())
+
+	delivery, err = repo.GetByID(1)
+	require.NoError(t, err)
+	assert.Equal(t, models.WebhookDeliveryStatusDelivered, delivery.Status)
+	assert.Equal(t, 3, delivery.Attempts)
+	assert.NotNil(t, delivery.DeliveredAt)
+	assert.Empty(t, delivery.LastError)
+
+	// Delivered items are not retried again
+	*now = now.Add(time.Hour)
+	delivered, err = service.ProcessDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, delivered)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestProcessDue_MarksFailedAfterMaxAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	service, repo, now := setupTestQueue(t, &config.RetryQueueConfig{MaxAttempts: 2, BaseBackoff: 1})
+
+	require.NoError(t, service.Enqueue(server.URL, []byte(`{}`), errors.New("timeout")))
+
+	*now = now.Add(time.Minute)
+	_, err := service.ProcessDue(context.Background())
+	require.NoError(t, err)
+
+	delivery, err := repo.GetByID(1)
+	require.NoError(t, err)
+	assert.Equal(t, models.WebhookDeliveryStatusFailed, delivery.Status)
+	assert.Equal(t, 2, delivery.Attempts)
+
+	due, err := repo.GetDue(now.Add(time.Hour), 0)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+}
+
+func TestStop_CancelsDeliveryInFlight(t *testing.T) {
+	received := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(received)
+		// Hang until the client gives up or the test ends
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	service, repo, now := setupTestQueue(t, &config.RetryQueueConfig{MaxAttempts: 5, BaseBackoff: 1})
+	require.NoError(t, service.Enqueue(server.URL, []byte(`{}`), errors.New("timeout")))
+	*now = now.Add(time.Minute)
+
+	service.Start()
+	<-received
+
+	stopped := make(chan struct{})
+	go func() {
+		service.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop did not cancel the delivery in flight")
+	}
+
+	// The interrupted attempt is not counted and the delivery stays due
+	delivery, err := repo.GetByID(1)
+	require.NoError(t, err)
+	assert.Equal(t, models.WebhookDeliveryStatusPending, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+}
+
+func TestBackoff_Capped(t *testing.T) {
+	service := NewServiceWithInterfaces(&config.RetryQueueConfig{BaseBackoff: 30, MaxBackoff: 100}, nil, logger.New("error", "json", "stdout"))
+
+	assert.Equal(t, 30*time.Second, service.backoff(1))
+	assert.Equal(t, 60*time.Second, service.backoff(2))
+	assert.Equal(t, 100*time.Second, service.backoff(3))
+	assert.Equal(t, 100*time.Second, service.backoff(10))
+}
@@ -0,0 +1,8 @@
+-- Remove hourly rows, then granularity and hour fields
+DELETE FROM review_metrics WHERE granularity <> 'daily';
+DROP INDEX IF EXISTS idx_review_metrics_granularity;
+ALTER TABLE review_metrics DROP CONSTRAINT IF EXISTS review_metrics_date_team_user_id_project_id_granularity_hour_key;
+ALTER TABLE review_metrics ADD CONSTRAINT review_metrics_date_team_user_id_project_id_key
+    UNIQUE(date, team, user_id, project_id);
+ALTER TABLE review_metrics DROP COLUMN IF EXISTS hour;
+ALTER TABLE review_metrics DROP COLUMN IF EXISTS granularity;
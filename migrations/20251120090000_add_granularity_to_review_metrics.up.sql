@@ -0,0 +1,15 @@
+-- Add granularity and hour fields so metrics can be stored per hour as well as per day
+ALTER TABLE review_metrics ADD COLUMN granularity VARCHAR(20) NOT NULL DEFAULT 'daily';
+ALTER TABLE review_metrics ADD COLUMN hour INTEGER;
+
+-- Hourly rows for the same day must not collide with each other or with the daily row
+ALTER TABLE review_metrics DROP CONSTRAINT IF EXISTS review_metrics_date_team_user_id_project_id_key;
+ALTER TABLE review_metrics ADD CONSTRAINT review_metrics_date_team_user_id_project_id_granularity_hour_key
+    UNIQUE(date, team, user_id, project_id, granularity, hour);
+
+-- Add index for filtering by granularity
+CREATE INDEX idx_review_metrics_granularity ON review_metrics(granularity);
+
+-- Add comments explaining the fields
+COMMENT ON COLUMN review_metrics.granularity IS 'Aggregation granularity: daily (default) or hourly';
+COMMENT ON COLUMN review_metrics.hour IS 'Hour of day (0-23) for hourly rows, NULL for daily rows';
@@ -0,0 +1,2 @@
+-- Remove display_name field
+ALTER TABLE users DROP COLUMN IF EXISTS display_name;
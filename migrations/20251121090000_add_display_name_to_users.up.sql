@@ -0,0 +1,5 @@
+-- Add display_name field for friendly names shown instead of the GitLab username
+ALTER TABLE users ADD COLUMN display_name VARCHAR(255);
+
+-- Add comment explaining the field
+COMMENT ON COLUMN users.display_name IS 'Friendly name shown in the UI; the username is used when empty';
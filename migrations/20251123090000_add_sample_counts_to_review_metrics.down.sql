@@ -0,0 +1,5 @@
+-- Remove sample count fields
+ALTER TABLE review_metrics DROP COLUMN IF EXISTS engagement_samples;
+ALTER TABLE review_metrics DROP COLUMN IF EXISTS comment_samples;
+ALTER TABLE review_metrics DROP COLUMN IF EXISTS approval_samples;
+ALTER TABLE review_metrics DROP COLUMN IF EXISTS ttfr_samples;
@@ -0,0 +1,11 @@
+-- Track how many samples each running average covers so event-driven updates keep a true mean
+ALTER TABLE review_metrics ADD COLUMN ttfr_samples INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE review_metrics ADD COLUMN approval_samples INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE review_metrics ADD COLUMN comment_samples INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE review_metrics ADD COLUMN engagement_samples INTEGER NOT NULL DEFAULT 0;
+
+-- Add comments explaining the fields
+COMMENT ON COLUMN review_metrics.ttfr_samples IS 'Number of samples averaged into avg_ttfr';
+COMMENT ON COLUMN review_metrics.approval_samples IS 'Number of samples averaged into avg_time_to_approval';
+COMMENT ON COLUMN review_metrics.comment_samples IS 'Number of samples averaged into avg_comment_count and avg_comment_length';
+COMMENT ON COLUMN review_metrics.engagement_samples IS 'Number of samples averaged into engagement_score';
@@ -0,0 +1,17 @@
+-- Create personal_bests table: each user's best-ever value per metric and period
+CREATE TABLE IF NOT EXISTS personal_bests (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    metric VARCHAR(50) NOT NULL,
+    period VARCHAR(20) NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    period_start TIMESTAMP NOT NULL,
+    period_end TIMESTAMP NOT NULL,
+    achieved_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_personal_bests_user_metric_period ON personal_bests(user_id, metric, period);
+
+COMMENT ON COLUMN personal_bests.metric IS 'Metric: avg_ttfr (lower is better) or engagement_score (higher is better)';
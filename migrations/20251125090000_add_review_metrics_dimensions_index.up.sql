@@ -0,0 +1,14 @@
+-- Remove duplicate metrics rows, keeping the most recent one per dimension set
+DELETE FROM review_metrics a
+USING review_metrics b
+WHERE a.id < b.id
+  AND a.date = b.date
+  AND a.team = b.team
+  AND a.granularity = b.granularity
+  AND a.user_id IS NOT DISTINCT FROM b.user_id
+  AND a.project_id IS NOT DISTINCT FROM b.project_id
+  AND a.hour IS NOT DISTINCT FROM b.hour;
+
+-- NULL-safe uniqueness for metrics upserts
+CREATE UNIQUE INDEX IF NOT EXISTS idx_review_metrics_dimensions
+    ON review_metrics (date, team, granularity, COALESCE(user_id, 0), COALESCE(project_id, 0), COALESCE(hour, -1));
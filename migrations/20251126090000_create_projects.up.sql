@@ -0,0 +1,13 @@
+-- Create projects table: GitLab project metadata captured from webhooks
+CREATE TABLE IF NOT EXISTS projects (
+    id SERIAL PRIMARY KEY,
+    gitlab_project_id INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    path VARCHAR(500),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_projects_gitlab_project_id ON projects(gitlab_project_id);
+
+COMMENT ON COLUMN projects.path IS 'GitLab path_with_namespace, e.g. group/subgroup/project';
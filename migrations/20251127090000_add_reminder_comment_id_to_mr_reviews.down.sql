@@ -0,0 +1,2 @@
+-- Remove reminder_comment_id field
+ALTER TABLE mr_reviews DROP COLUMN IF EXISTS reminder_comment_id;
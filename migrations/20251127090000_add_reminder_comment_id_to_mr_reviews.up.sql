@@ -0,0 +1,5 @@
+-- Add reminder_comment_id so stale-review reminders on GitLab are updated instead of duplicated
+ALTER TABLE mr_reviews ADD COLUMN reminder_comment_id INTEGER;
+
+-- Add comment explaining the field
+COMMENT ON COLUMN mr_reviews.reminder_comment_id IS 'GitLab note ID of the "still awaiting review" reminder comment';
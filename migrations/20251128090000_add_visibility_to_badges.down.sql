@@ -0,0 +1,3 @@
+-- Remove badge visibility fields
+ALTER TABLE badges DROP COLUMN IF EXISTS min_holders;
+ALTER TABLE badges DROP COLUMN IF EXISTS hidden;
@@ -0,0 +1,7 @@
+-- Add visibility settings so surprise or rarely earned badges can stay out of the public catalog
+ALTER TABLE badges ADD COLUMN hidden BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE badges ADD COLUMN min_holders INTEGER NOT NULL DEFAULT 0;
+
+-- Add comments explaining the fields
+COMMENT ON COLUMN badges.hidden IS 'Never listed in the public badge catalog';
+COMMENT ON COLUMN badges.min_holders IS 'Holders required before the badge is listed in the public badge catalog';
@@ -1,13 +1,17 @@
 package mocks
 
-import "github.com/aimd54/gitlab-reviewer-roulette/internal/gitlab"
+import (
+	"context"
+
+	"github.com/aimd54/gitlab-reviewer-roulette/internal/gitlab"
+)
 
 // MockGitLabClient is a simple mock for GitLab client
 type MockGitLabClient struct {
 	GetCodeownersFunc     func(projectID int, ref string) (string, error)
 	GetUserStatusFunc     func(userID int) (*gitlab.UserStatus, error)
-	PostCommentFunc       func(projectID, mrIID int, comment string) (int, error)
-	UpdateCommentFunc     func(projectID, mrIID, noteID int, comment string) error
+	PostCommentFunc       func(ctx context.Context, projectID, mrIID int, comment string) (int, error)
+	UpdateCommentFunc     func(ctx context.Context, projectID, mrIID, noteID int, comment string) error
 	GetMRChangedFilesFunc func(projectID, mrIID int) ([]string, error)
 }
 
@@ -25,16 +29,16 @@ func (m *MockGitLabClient) GetUserStatus(userID int) (*gitlab.UserStatus, error)
 	return nil, nil
 }
 
-func (m *MockGitLabClient) PostComment(projectID, mrIID int, comment string) (int, error) {
+func (m *MockGitLabClient) PostComment(ctx context.Context, projectID, mrIID int, comment string) (int, error) {
 	if m.PostCommentFunc != nil {
-		return m.PostCommentFunc(projectID, mrIID, comment)
+		return m.PostCommentFunc(ctx, projectID, mrIID, comment)
 	}
 	return 1, nil // Return a dummy note ID
 }
 
-func (m *MockGitLabClient) UpdateComment(projectID, mrIID, noteID int, comment string) error {
+func (m *MockGitLabClient) UpdateComment(ctx context.Context, projectID, mrIID, noteID int, comment string) error {
 	if m.UpdateCommentFunc != nil {
-		return m.UpdateCommentFunc(projectID, mrIID, noteID, comment)
+		return m.UpdateCommentFunc(ctx, projectID, mrIID, noteID, comment)
 	}
 	return nil
 }